@@ -0,0 +1,136 @@
+package app
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"time"
+
+	"refuel/backend/checkin"
+	refuelapi "refuel/backend/generated/go"
+	"refuel/backend/i18n"
+	"refuel/backend/models"
+)
+
+// GetAgenda - 指定した日の実施予定を目標とコンプレックスごとにまとめて取得
+func (s APIService) GetAgenda(ctx context.Context, date string, tz string) (refuelapi.ImplResponse, error) {
+	userID, resp := GetUserIDFromContext(ctx)
+	if resp != nil {
+		return *resp, nil
+	}
+
+	loc, err := loadLocation(tz)
+	if err != nil {
+		return refuelapi.ImplResponse{Code: http.StatusBadRequest, Body: NewErrorResponse(ctx, http.StatusBadRequest, i18n.InvalidTimeZone, err)}, nil
+	}
+
+	now := time.Now()
+	day := checkin.Civil(now, loc)
+	if date != "" {
+		if day, err = checkin.ParseDate(date); err != nil {
+			return refuelapi.ImplResponse{Code: http.StatusBadRequest, Body: NewErrorResponse(ctx, http.StatusBadRequest, i18n.InvalidDate, "date")}, nil
+		}
+	}
+
+	complexes, err := s.Complexes.List(ctx, userID)
+	if err != nil {
+		return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(ctx, http.StatusInternalServerError, i18n.FetchFailed, i18n.NounComplexes, err)}, nil
+	}
+	goals, err := s.Goals.List(ctx, userID)
+	if err != nil {
+		return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(ctx, http.StatusInternalServerError, i18n.FetchFailed, i18n.NounGoals, err)}, nil
+	}
+	actions, err := s.Actions.List(ctx, userID)
+	if err != nil {
+		return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(ctx, http.StatusInternalServerError, i18n.FetchFailed, i18n.NounActions, err)}, nil
+	}
+
+	actionIDs := make([]uint, len(actions))
+	for i, action := range actions {
+		actionIDs[i] = action.ID
+	}
+	completions, err := s.Actions.ListCompletions(ctx, actionIDs, day, day)
+	if err != nil {
+		return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(ctx, http.StatusInternalServerError, i18n.FetchFailed, i18n.NounCheckins, err)}, nil
+	}
+	completionsOf := make(map[uint][]models.ActionCompletion)
+	for _, c := range completions {
+		completionsOf[c.ActionID] = append(completionsOf[c.ActionID], c)
+	}
+
+	// Expand each action's schedule over the day and collect the occurrences
+	// by goal. The timeline also lists check-ins the schedule does not cover,
+	// such as those of non-recurring actions; they are not on the agenda.
+	itemsOf := make(map[uint][]agendaItem)
+	for _, action := range actions {
+		schedule, err := actionSchedule(action, loc)
+		if err != nil {
+			return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(ctx, http.StatusInternalServerError, i18n.StoredRecurrenceInvalid, err)}, nil
+		}
+		if schedule == nil {
+			continue
+		}
+		for _, occ := range checkin.Timeline(schedule, completionsOf[action.ID], day, day, now) {
+			if occ.ScheduledAt == nil {
+				continue
+			}
+			itemsOf[action.GoalID] = append(itemsOf[action.GoalID], agendaItem{action: action, occ: occ})
+		}
+	}
+
+	goalsOf := make(map[uint][]refuelapi.AgendaGoal)
+	sort.Slice(goals, func(i, j int) bool { return goals[i].ID < goals[j].ID })
+	for _, goal := range goals {
+		items := itemsOf[goal.ID]
+		if len(items) == 0 {
+			continue
+		}
+		sort.Slice(items, func(i, j int) bool { return items[i].before(items[j]) })
+		resItems := make([]refuelapi.AgendaItem, len(items))
+		for i, item := range items {
+			resItems[i] = refuelapi.AgendaItem{
+				ActionId:   int64(item.action.ID),
+				Content:    item.action.Content,
+				Occurrence: mapCheckin(item.action.ID, item.occ),
+			}
+		}
+		goalsOf[goal.ComplexID] = append(goalsOf[goal.ComplexID], refuelapi.AgendaGoal{
+			GoalId:  int64(goal.ID),
+			Content: goal.Content,
+			Items:   resItems,
+		})
+	}
+
+	resComplexes := []refuelapi.AgendaComplex{}
+	sort.Slice(complexes, func(i, j int) bool { return complexes[i].ID < complexes[j].ID })
+	for _, complex := range complexes {
+		if len(goalsOf[complex.ID]) == 0 {
+			continue
+		}
+		resComplexes = append(resComplexes, refuelapi.AgendaComplex{
+			ComplexId: int64(complex.ID),
+			Content:   complex.Content,
+			Category:  complex.Category,
+			Goals:     goalsOf[complex.ID],
+		})
+	}
+
+	return refuelapi.ImplResponse{Code: http.StatusOK, Body: refuelapi.Agenda{
+		Date:      checkin.Key(day),
+		Complexes: resComplexes,
+	}}, nil
+}
+
+// agendaItem is an occurrence of an action on the agenda's day.
+type agendaItem struct {
+	action models.Action
+	occ    checkin.Occurrence
+}
+
+// before orders agenda items by scheduled time, then by action ID.
+func (a agendaItem) before(b agendaItem) bool {
+	if !a.occ.ScheduledAt.Equal(*b.occ.ScheduledAt) {
+		return a.occ.ScheduledAt.Before(*b.occ.ScheduledAt)
+	}
+	return a.action.ID < b.action.ID
+}
@@ -0,0 +1,332 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"refuel/backend/auth"
+	"refuel/backend/auth/oidc"
+	refuelapi "refuel/backend/generated/go"
+	"refuel/backend/i18n"
+	"refuel/backend/models"
+	"refuel/backend/repository"
+)
+
+// dummyPasswordHash is compared against when a login names an unknown email,
+// so both failure paths take the time of one bcrypt comparison.
+var dummyPasswordHash, _ = auth.HashPassword("refuel-dummy-password")
+
+// Register - メールアドレスとパスワードでアカウントを登録
+func (s APIService) Register(ctx context.Context, registerInput refuelapi.RegisterInput) (refuelapi.ImplResponse, error) {
+	email := normalizeEmail(registerInput.Email)
+	if err := s.Validate.Var(email, "required,email"); err != nil {
+		return refuelapi.ImplResponse{Code: http.StatusBadRequest, Body: NewErrorResponse(ctx, http.StatusBadRequest, i18n.InvalidEmail)}, nil
+	}
+	if len(registerInput.Password) < auth.MinPasswordLength {
+		return refuelapi.ImplResponse{Code: http.StatusBadRequest, Body: NewErrorResponse(ctx, http.StatusBadRequest, i18n.PasswordTooShort, auth.MinPasswordLength)}, nil
+	}
+	if len(registerInput.Password) > auth.MaxPasswordLength {
+		return refuelapi.ImplResponse{Code: http.StatusBadRequest, Body: NewErrorResponse(ctx, http.StatusBadRequest, i18n.PasswordTooLong, auth.MaxPasswordLength)}, nil
+	}
+
+	hash, err := auth.HashPassword(registerInput.Password)
+	if err != nil {
+		return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(ctx, http.StatusInternalServerError, i18n.InternalError, err)}, nil
+	}
+	user := models.User{
+		ID:           uuid.NewString(),
+		Email:        email,
+		PasswordHash: hash,
+		DisplayName:  strings.TrimSpace(registerInput.DisplayName),
+	}
+	if err := s.Users.Create(ctx, &user); err != nil {
+		if err == repository.ErrConflict {
+			return refuelapi.ImplResponse{Code: http.StatusConflict, Body: NewErrorResponse(ctx, http.StatusConflict, i18n.EmailTaken)}, nil
+		}
+		return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(ctx, http.StatusInternalServerError, i18n.CreateFailed, i18n.NounUser, err)}, nil
+	}
+
+	pair, err := s.issueTokens(ctx, user)
+	if err != nil {
+		return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(ctx, http.StatusInternalServerError, i18n.TokenIssueFailed, err)}, nil
+	}
+	return refuelapi.ImplResponse{Code: http.StatusCreated, Body: pair}, nil
+}
+
+// Login - メールアドレスとパスワードでログイン
+func (s APIService) Login(ctx context.Context, loginInput refuelapi.LoginInput) (refuelapi.ImplResponse, error) {
+	user, err := s.Users.GetByEmail(ctx, normalizeEmail(loginInput.Email))
+	if err != nil && err != repository.ErrNotFound {
+		return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(ctx, http.StatusInternalServerError, i18n.FetchFailed, i18n.NounUser, err)}, nil
+	}
+	if err == repository.ErrNotFound {
+		_ = auth.CheckPassword(dummyPasswordHash, loginInput.Password)
+		return refuelapi.ImplResponse{Code: http.StatusUnauthorized, Body: NewErrorResponse(ctx, http.StatusUnauthorized, i18n.InvalidCredentials)}, nil
+	}
+	if err := auth.CheckPassword(user.PasswordHash, loginInput.Password); err != nil {
+		return refuelapi.ImplResponse{Code: http.StatusUnauthorized, Body: NewErrorResponse(ctx, http.StatusUnauthorized, i18n.InvalidCredentials)}, nil
+	}
+
+	pair, err := s.issueTokens(ctx, *user)
+	if err != nil {
+		return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(ctx, http.StatusInternalServerError, i18n.TokenIssueFailed, err)}, nil
+	}
+	return refuelapi.ImplResponse{Code: http.StatusOK, Body: pair}, nil
+}
+
+// RefreshToken - リフレッシュトークンでアクセストークンを再発行
+func (s APIService) RefreshToken(ctx context.Context, refreshInput refuelapi.RefreshTokenInput) (refuelapi.ImplResponse, error) {
+	unauthorized := refuelapi.ImplResponse{Code: http.StatusUnauthorized, Body: NewErrorResponse(ctx, http.StatusUnauthorized, i18n.InvalidRefreshToken)}
+
+	token, err := s.Users.GetRefreshToken(ctx, auth.HashRefreshToken(refreshInput.RefreshToken))
+	if err != nil {
+		if err == repository.ErrNotFound {
+			return unauthorized, nil
+		}
+		return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(ctx, http.StatusInternalServerError, i18n.FetchFailed, i18n.NounRefreshToken, err)}, nil
+	}
+	now := time.Now()
+	if token.RevokedAt != nil {
+		// A rotated token being replayed means it leaked; end every session of the user.
+		if err := s.Users.RevokeUserRefreshTokens(ctx, token.UserID, now); err != nil {
+			return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(ctx, http.StatusInternalServerError, i18n.TokenRevokeFailed, err)}, nil
+		}
+		return unauthorized, nil
+	}
+	if now.After(token.ExpiresAt) {
+		return unauthorized, nil
+	}
+
+	// Only one concurrent refresh may consume the token.
+	revoked, err := s.Users.RevokeRefreshToken(ctx, token.ID, now)
+	if err != nil {
+		return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(ctx, http.StatusInternalServerError, i18n.TokenRevokeFailed, err)}, nil
+	}
+	if !revoked {
+		return unauthorized, nil
+	}
+
+	user, err := s.Users.Get(ctx, token.UserID)
+	if err != nil {
+		if err == repository.ErrNotFound {
+			return unauthorized, nil
+		}
+		return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(ctx, http.StatusInternalServerError, i18n.FetchFailed, i18n.NounUser, err)}, nil
+	}
+
+	pair, err := s.issueTokens(ctx, *user)
+	if err != nil {
+		return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(ctx, http.StatusInternalServerError, i18n.TokenIssueFailed, err)}, nil
+	}
+	return refuelapi.ImplResponse{Code: http.StatusOK, Body: pair}, nil
+}
+
+// Logout - リフレッシュトークンを無効化してログアウト
+func (s APIService) Logout(ctx context.Context, refreshInput refuelapi.RefreshTokenInput) (refuelapi.ImplResponse, error) {
+	if err := s.Users.RevokeRefreshTokenByHash(ctx, auth.HashRefreshToken(refreshInput.RefreshToken), time.Now()); err != nil {
+		return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(ctx, http.StatusInternalServerError, i18n.TokenRevokeFailed, err)}, nil
+	}
+	// Unknown tokens are not reported, so logout cannot be used to probe for valid tokens.
+	return refuelapi.ImplResponse{Code: http.StatusNoContent}, nil
+}
+
+// GetMe - ログイン中のユーザー情報を取得
+func (s APIService) GetMe(ctx context.Context) (refuelapi.ImplResponse, error) {
+	userID, resp := GetUserIDFromContext(ctx)
+	if resp != nil {
+		return *resp, nil
+	}
+
+	user, err := s.Users.Get(ctx, userID)
+	if err != nil {
+		if err == repository.ErrNotFound {
+			return refuelapi.ImplResponse{Code: http.StatusNotFound, Body: NewErrorResponse(ctx, http.StatusNotFound, i18n.UserNotFound)}, nil
+		}
+		return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(ctx, http.StatusInternalServerError, i18n.FetchFailed, i18n.NounUser, err)}, nil
+	}
+	return refuelapi.ImplResponse{Code: http.StatusOK, Body: mapUser(*user)}, nil
+}
+
+// UpdateMyPreferences - ログイン中のユーザーの設定を更新
+func (s APIService) UpdateMyPreferences(ctx context.Context, userPreferencesInput refuelapi.UserPreferencesInput) (refuelapi.ImplResponse, error) {
+	userID, resp := GetUserIDFromContext(ctx)
+	if resp != nil {
+		return *resp, nil
+	}
+
+	language := userPreferencesInput.Language
+	if language != "" {
+		lang, ok := i18n.Parse(language)
+		if !ok || string(lang) != language {
+			return refuelapi.ImplResponse{Code: http.StatusBadRequest, Body: NewErrorResponse(ctx, http.StatusBadRequest, i18n.InvalidChoice, "language", "ja, en")}, nil
+		}
+	}
+	if err := s.Users.SetLanguage(ctx, userID, language); err != nil {
+		if err == repository.ErrNotFound {
+			return refuelapi.ImplResponse{Code: http.StatusNotFound, Body: NewErrorResponse(ctx, http.StatusNotFound, i18n.UserNotFound)}, nil
+		}
+		return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(ctx, http.StatusInternalServerError, i18n.UpdateFailed, i18n.NounUser, err)}, nil
+	}
+
+	user, err := s.Users.Get(ctx, userID)
+	if err != nil {
+		return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(ctx, http.StatusInternalServerError, i18n.FetchFailed, i18n.NounUser, err)}, nil
+	}
+	return refuelapi.ImplResponse{Code: http.StatusOK, Body: mapUser(*user)}, nil
+}
+
+// issueTokens signs an access token for the user and stores a new refresh token.
+func (s APIService) issueTokens(ctx context.Context, user models.User) (refuelapi.TokenPair, error) {
+	now := time.Now()
+	accessToken, expiresAt, err := s.Tokens.Issue(user.ID, now)
+	if err != nil {
+		return refuelapi.TokenPair{}, err
+	}
+	refreshToken, hash, err := auth.NewRefreshToken()
+	if err != nil {
+		return refuelapi.TokenPair{}, err
+	}
+	row := models.RefreshToken{UserID: user.ID, TokenHash: hash, ExpiresAt: now.Add(s.RefreshTTL)}
+	if err := s.Users.CreateRefreshToken(ctx, &row); err != nil {
+		return refuelapi.TokenPair{}, err
+	}
+	return refuelapi.TokenPair{
+		AccessToken:  accessToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int32(expiresAt.Sub(now).Seconds()),
+		RefreshToken: refreshToken,
+		User:         mapUser(user),
+	}, nil
+}
+
+// mapUser maps a models.User to the generated refuelapi.User model.
+func mapUser(user models.User) refuelapi.User {
+	return refuelapi.User{
+		Id:          user.ID,
+		Email:       user.Email,
+		DisplayName: user.DisplayName,
+		Language:    user.Language,
+		CreatedAt:   user.CreatedAt,
+	}
+}
+
+// normalizeEmail lower-cases an email so lookups are case-insensitive.
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// oidcStateTTL bounds how long a user may take at the identity provider.
+const oidcStateTTL = 10 * time.Minute
+
+// StartOidcLogin - 外部IDプロバイダー (OpenID Connect) でのログインを開始
+func (s APIService) StartOidcLogin(ctx context.Context) (refuelapi.ImplResponse, error) {
+	if s.OIDC == nil {
+		return refuelapi.ImplResponse{Code: http.StatusNotFound, Body: NewErrorResponse(ctx, http.StatusNotFound, i18n.OIDCNotConfigured)}, nil
+	}
+
+	state, err := oidc.RandomString(24)
+	if err != nil {
+		return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(ctx, http.StatusInternalServerError, i18n.InternalError, err)}, nil
+	}
+	nonce, err := oidc.RandomString(24)
+	if err != nil {
+		return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(ctx, http.StatusInternalServerError, i18n.InternalError, err)}, nil
+	}
+	verifier, err := oidc.NewCodeVerifier()
+	if err != nil {
+		return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(ctx, http.StatusInternalServerError, i18n.InternalError, err)}, nil
+	}
+
+	authURL, err := s.OIDC.AuthCodeURL(ctx, state, nonce, verifier)
+	if err != nil {
+		return refuelapi.ImplResponse{Code: http.StatusBadGateway, Body: NewErrorResponse(ctx, http.StatusBadGateway, i18n.IdentityProviderFailed, err)}, nil
+	}
+
+	now := time.Now()
+	// Abandoned logins are cleaned up here rather than by a background job.
+	if err := s.Users.DeleteExpiredLoginStates(ctx, now); err != nil {
+		log.Printf("⚠️ Failed to delete expired OIDC login states: %v", err)
+	}
+	loginState := models.OIDCLoginState{State: state, CodeVerifier: verifier, Nonce: nonce, ExpiresAt: now.Add(oidcStateTTL)}
+	if err := s.Users.CreateLoginState(ctx, &loginState); err != nil {
+		return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(ctx, http.StatusInternalServerError, i18n.SaveFailed, i18n.NounLoginState, err)}, nil
+	}
+
+	return refuelapi.ImplResponse{Code: http.StatusOK, Body: refuelapi.OidcAuthorization{AuthorizationUrl: authURL, State: state}}, nil
+}
+
+// CompleteOidcLogin - 外部IDプロバイダーの認可コードでログイン
+func (s APIService) CompleteOidcLogin(ctx context.Context, callbackInput refuelapi.OidcCallbackInput) (refuelapi.ImplResponse, error) {
+	if s.OIDC == nil {
+		return refuelapi.ImplResponse{Code: http.StatusNotFound, Body: NewErrorResponse(ctx, http.StatusNotFound, i18n.OIDCNotConfigured)}, nil
+	}
+
+	// A state is single-use; whoever consumes it owns the login.
+	loginState, err := s.Users.ConsumeLoginState(ctx, callbackInput.State, time.Now())
+	if err != nil {
+		if err == repository.ErrNotFound {
+			return refuelapi.ImplResponse{Code: http.StatusBadRequest, Body: NewErrorResponse(ctx, http.StatusBadRequest, i18n.UnknownLoginState)}, nil
+		}
+		return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(ctx, http.StatusInternalServerError, i18n.FetchFailed, i18n.NounLoginState, err)}, nil
+	}
+
+	claims, err := s.OIDC.Exchange(ctx, callbackInput.Code, loginState.CodeVerifier, loginState.Nonce)
+	if err != nil {
+		if errors.Is(err, oidc.ErrInvalidIDToken) {
+			return refuelapi.ImplResponse{Code: http.StatusUnauthorized, Body: NewErrorResponse(ctx, http.StatusUnauthorized, i18n.InvalidIDToken)}, nil
+		}
+		return refuelapi.ImplResponse{Code: http.StatusBadGateway, Body: NewErrorResponse(ctx, http.StatusBadGateway, i18n.IdentityProviderFailed, err)}, nil
+	}
+
+	user, resp := s.userForIdentity(ctx, claims)
+	if resp != nil {
+		return *resp, nil
+	}
+
+	pair, err := s.issueTokens(ctx, *user)
+	if err != nil {
+		return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(ctx, http.StatusInternalServerError, i18n.TokenIssueFailed, err)}, nil
+	}
+	return refuelapi.ImplResponse{Code: http.StatusOK, Body: pair}, nil
+}
+
+// userForIdentity returns the user linked to the ID token's subject. An
+// unknown subject is linked to the user with the same verified email, or to a
+// new user without a password.
+func (s APIService) userForIdentity(ctx context.Context, claims *oidc.Claims) (*models.User, *refuelapi.ImplResponse) {
+	identity, err := s.Users.GetIdentity(ctx, claims.Issuer, claims.Subject)
+	if err == nil {
+		user, err := s.Users.Get(ctx, identity.UserID)
+		if err != nil {
+			return nil, &refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(ctx, http.StatusInternalServerError, i18n.FetchFailed, i18n.NounUser, err)}
+		}
+		return user, nil
+	}
+	if err != repository.ErrNotFound {
+		return nil, &refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(ctx, http.StatusInternalServerError, i18n.FetchFailed, i18n.NounIdentity, err)}
+	}
+
+	email := normalizeEmail(claims.Email)
+	if email == "" {
+		return nil, &refuelapi.ImplResponse{Code: http.StatusBadRequest, Body: NewErrorResponse(ctx, http.StatusBadRequest, i18n.ProviderEmailMissing)}
+	}
+
+	// Linking on an unverified email would let anyone take over the account.
+	newUser := models.User{ID: uuid.NewString(), Email: email, DisplayName: claims.Name}
+	link := &models.UserIdentity{Issuer: claims.Issuer, Subject: claims.Subject, Email: email}
+	user, err := s.Users.LinkIdentity(ctx, link, newUser, claims.EmailVerified)
+	if err == repository.ErrConflict {
+		return nil, &refuelapi.ImplResponse{Code: http.StatusConflict, Body: NewErrorResponse(ctx, http.StatusConflict, i18n.EmailTakenUnverified)}
+	}
+	if err != nil {
+		return nil, &refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(ctx, http.StatusInternalServerError, i18n.IdentityLinkFailed, err)}
+	}
+	log.Printf("🔗 Linked OIDC subject %q to user %s", claims.Subject, user.ID)
+	return user, nil
+}
@@ -0,0 +1,160 @@
+package app
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+
+	refuelapi "refuel/backend/generated/go"
+	"refuel/backend/i18n"
+	"refuel/backend/models"
+	"refuel/backend/repository"
+)
+
+// CreateMyExport - アカウントのデータのエクスポートを依頼
+func (s APIService) CreateMyExport(ctx context.Context) (refuelapi.ImplResponse, error) {
+	userID, resp := GetUserIDFromContext(ctx)
+	if resp != nil {
+		return *resp, nil
+	}
+
+	// An export still to run will include everything a new one would.
+	jobs, err := s.Exports.ListJobs(ctx, userID)
+	if err != nil {
+		return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(ctx, http.StatusInternalServerError, i18n.FetchFailed, i18n.NounExports, err)}, nil
+	}
+	for _, job := range jobs {
+		if job.Status == models.ExportPending || job.Status == models.ExportRunning {
+			return exportAccepted(job), nil
+		}
+	}
+
+	job := models.ExportJob{UserID: userID, Status: models.ExportPending}
+	if err := s.Exports.CreateJob(ctx, &job); err != nil {
+		return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(ctx, http.StatusInternalServerError, i18n.CreateFailed, i18n.NounExport, err)}, nil
+	}
+	s.Exporter.Notify()
+
+	return exportAccepted(job), nil
+}
+
+// GetMyExports - アカウントのデータのエクスポートの一覧を取得
+func (s APIService) GetMyExports(ctx context.Context) (refuelapi.ImplResponse, error) {
+	userID, resp := GetUserIDFromContext(ctx)
+	if resp != nil {
+		return *resp, nil
+	}
+
+	jobs, err := s.Exports.ListJobs(ctx, userID)
+	if err != nil {
+		return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(ctx, http.StatusInternalServerError, i18n.FetchFailed, i18n.NounExports, err)}, nil
+	}
+
+	resJobs := make([]refuelapi.ExportJob, len(jobs))
+	for i, job := range jobs {
+		resJobs[i] = toAPIExportJob(job)
+	}
+
+	return refuelapi.ImplResponse{Code: http.StatusOK, Body: resJobs}, nil
+}
+
+// GetMyExport - アカウントのデータのエクスポートの状態を取得
+func (s APIService) GetMyExport(ctx context.Context, exportId int64) (refuelapi.ImplResponse, error) {
+	userID, resp := GetUserIDFromContext(ctx)
+	if resp != nil {
+		return *resp, nil
+	}
+
+	job, err := s.Exports.GetJob(ctx, userID, uint(exportId))
+	if err != nil {
+		if err == repository.ErrNotFound {
+			return refuelapi.ImplResponse{Code: http.StatusNotFound, Body: NewErrorResponse(ctx, http.StatusNotFound, i18n.ExportNotFound)}, nil
+		}
+		return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(ctx, http.StatusInternalServerError, i18n.FetchFailed, i18n.NounExport, err)}, nil
+	}
+
+	return refuelapi.ImplResponse{Code: http.StatusOK, Body: toAPIExportJob(*job)}, nil
+}
+
+// GetMyExportArchive - アカウントのデータのアーカイブをダウンロード
+func (s APIService) GetMyExportArchive(ctx context.Context, exportId int64) (refuelapi.ImplResponse, error) {
+	userID, resp := GetUserIDFromContext(ctx)
+	if resp != nil {
+		return *resp, nil
+	}
+
+	job, err := s.Exports.GetArchive(ctx, userID, uint(exportId))
+	if err != nil {
+		if err == repository.ErrNotFound {
+			return refuelapi.ImplResponse{Code: http.StatusNotFound, Body: NewErrorResponse(ctx, http.StatusNotFound, i18n.ExportNotFound)}, nil
+		}
+		return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(ctx, http.StatusInternalServerError, i18n.FetchFailed, i18n.NounExport, err)}, nil
+	}
+	if job.Status != models.ExportCompleted {
+		return refuelapi.ImplResponse{Code: http.StatusConflict, Body: NewErrorResponse(ctx, http.StatusConflict, i18n.ExportNotReady, job.Status)}, nil
+	}
+
+	archive, err := archiveFile(job)
+	if err != nil {
+		return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(ctx, http.StatusInternalServerError, i18n.FetchFailed, i18n.NounExport, err)}, nil
+	}
+
+	return refuelapi.ImplResponse{Code: http.StatusOK, Body: archive}, nil
+}
+
+// archiveFile writes the archive of a completed job to a temporary file
+// and returns it open at its start, the form the generated controllers
+// send as a download. The file is removed right away and vanishes once
+// closed.
+func archiveFile(job *models.ExportJob) (*os.File, error) {
+	archive, err := base64.StdEncoding.DecodeString(job.Archive)
+	if err != nil {
+		return nil, fmt.Errorf("stored archive of export %d is malformed: %v", job.ID, err)
+	}
+	f, err := os.CreateTemp("", fmt.Sprintf("refuel-export-%d-*.zip", job.ID))
+	if err != nil {
+		return nil, err
+	}
+	os.Remove(f.Name())
+	if _, err := f.Write(archive); err != nil {
+		f.Close()
+		return nil, err
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return f, nil
+}
+
+// exportAccepted is the response to a requested export, pointing at the
+// job to poll.
+func exportAccepted(job models.ExportJob) refuelapi.ImplResponse {
+	return refuelapi.ImplResponse{
+		Code:    http.StatusAccepted,
+		Headers: map[string][]string{"Location": {exportURL(job.ID)}},
+		Body:    toAPIExportJob(job),
+	}
+}
+
+func exportURL(id uint) string {
+	return fmt.Sprintf("/api/v1/me/exports/%d", id)
+}
+
+func toAPIExportJob(job models.ExportJob) refuelapi.ExportJob {
+	res := refuelapi.ExportJob{
+		Id:          int64(job.ID),
+		Status:      job.Status,
+		CreatedAt:   job.CreatedAt,
+		StartedAt:   job.StartedAt,
+		CompletedAt: job.CompletedAt,
+		ExpiresAt:   job.ExpiresAt,
+	}
+	if job.Status == models.ExportCompleted {
+		res.ArchiveUrl = exportURL(job.ID) + "/archive"
+	}
+	return res
+}
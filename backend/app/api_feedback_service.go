@@ -0,0 +1,93 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"refuel/backend/feedback"
+	refuelapi "refuel/backend/generated/go"
+	"refuel/backend/models"
+	"refuel/backend/streak"
+)
+
+// feedbackFor picks the feedback message for completing action, records it
+// so it is not repeated within the window and returns it in the request's
+// language. reached are the milestone badges the completion earned; streaks
+// are counted in days in loc. Failures are only logged and return nil
+// because the completion has already been saved.
+func (s APIService) feedbackFor(ctx context.Context, userID string, action models.Action, reached []models.UserBadge, loc *time.Location) *refuelapi.ActionFeedback {
+	c, err := s.feedbackContext(ctx, userID, action, reached, loc)
+	if err != nil {
+		log.Printf("⚠️ Failed to prepare feedback on action %d for user %s: %v", action.ID, userID, err)
+		return nil
+	}
+
+	shown, err := s.Feedback.ListSince(ctx, userID, time.Now().Add(-feedback.DefaultRepeatWindow))
+	if err != nil {
+		log.Printf("⚠️ Failed to fetch recent feedback for user %s: %v", userID, err)
+		return nil
+	}
+	recent := make(map[string]time.Time, len(shown))
+	for _, m := range shown {
+		// Newest first: keep when each template was last shown.
+		if _, ok := recent[m.TemplateCode]; !ok {
+			recent[m.TemplateCode] = m.CreatedAt
+		}
+	}
+
+	msg, ok := feedback.Pick(feedback.Catalog, c, recent, requestLang(ctx))
+	if !ok {
+		return nil
+	}
+	record := models.FeedbackMessage{UserID: userID, ActionID: action.ID, TemplateCode: msg.Code, Message: msg.Text}
+	if err := s.Feedback.Record(ctx, &record); err != nil {
+		log.Printf("⚠️ Failed to record feedback on action %d for user %s: %v", action.ID, userID, err)
+	}
+	return &refuelapi.ActionFeedback{Code: msg.Code, Message: msg.Text}
+}
+
+// feedbackContext gathers what the feedback templates are chosen by: the
+// complex behind the action's goal, the goal's streak and its milestones.
+func (s APIService) feedbackContext(ctx context.Context, userID string, action models.Action, reached []models.UserBadge, loc *time.Location) (feedback.Context, error) {
+	c := feedback.Context{Action: action.Content}
+
+	goal, err := s.Goals.Get(ctx, userID, action.GoalID)
+	if err != nil {
+		return c, fmt.Errorf("failed to fetch goal: %w", err)
+	}
+	c.Goal = goal.Content
+	complex, err := s.Complexes.Get(ctx, userID, goal.ComplexID)
+	if err != nil {
+		return c, fmt.Errorf("failed to fetch complex: %w", err)
+	}
+	c.Category, c.Complex, c.TriggerEpisode = complex.Category, complex.Content, complex.TriggerEpisode
+
+	actions, err := s.Actions.ListByGoal(ctx, userID, goal.ID)
+	if err != nil {
+		return c, fmt.Errorf("failed to fetch actions: %w", err)
+	}
+	summary, err := s.streakSummary(ctx, actions, loc, streak.DefaultWindowDays)
+	if err != nil {
+		return c, err
+	}
+	if summary != nil {
+		c.Streak = summary.Current
+	}
+
+	// Milestones are awarded in order, so the last one is the furthest.
+	for _, ub := range reached {
+		if ub.Milestone != nil {
+			c.ReachedMilestone = ub.Milestone.Title
+		}
+	}
+	next, share, err := s.Evaluator.NextMilestone(ctx, userID, goal.ID)
+	if err != nil {
+		return c, err
+	}
+	if next != nil {
+		c.NextMilestone, c.NextShare = next.Title, share
+	}
+	return c, nil
+}
@@ -0,0 +1,466 @@
+package app
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"refuel/backend/audit"
+	"refuel/backend/checkin"
+	refuelapi "refuel/backend/generated/go"
+	"refuel/backend/i18n"
+	"refuel/backend/models"
+	"refuel/backend/quantity"
+	"refuel/backend/repository"
+)
+
+// How ActionUpdateInput.Gains and Losses are applied to the stored lists.
+const (
+	gainsLossesReplace = "replace"
+	gainsLossesMerge   = "merge"
+)
+
+// outcome is the validated content of a gain or loss input.
+type outcome struct {
+	Type        string
+	Description string
+	Value       *float64
+	Unit        string
+}
+
+// checkOutcome validates the fields shared by gain and loss inputs. A
+// quantitative entry needs a positive value and a known unit, which is
+// stored in its canonical spelling; a qualitative one takes neither.
+func checkOutcome(kind i18n.Code, outcomeType, description string, value float64, unit string) (outcome, error) {
+	o := outcome{Type: outcomeType, Description: description}
+	if description == "" {
+		return o, i18n.NewError(i18n.DetailDescriptionRequired, kind)
+	}
+	switch outcomeType {
+	case models.Quantitative:
+		if value <= 0 {
+			return o, i18n.NewError(i18n.DetailOutcomeValueRequired, kind)
+		}
+		if unit == "" {
+			return o, i18n.NewError(i18n.DetailOutcomeUnitRequired, kind)
+		}
+		canonical, err := quantity.NormalizeUnit(unit)
+		if err != nil {
+			return o, err
+		}
+		o.Value, o.Unit = &value, canonical
+	case models.Qualitative:
+		if value != 0 || unit != "" {
+			return o, i18n.NewError(i18n.DetailQualitativeOutcome, kind)
+		}
+	default:
+		return o, i18n.NewError(i18n.DetailOutcomeType, kind, models.Quantitative, models.Qualitative)
+	}
+	return o, nil
+}
+
+func (o outcome) gain(id, actionID uint) models.Gain {
+	return models.Gain{ID: id, ActionID: actionID, Type: o.Type, Description: o.Description, Value: o.Value, Unit: o.Unit}
+}
+
+func (o outcome) loss(id, actionID uint) models.Loss {
+	return models.Loss{ID: id, ActionID: actionID, Type: o.Type, Description: o.Description, Value: o.Value, Unit: o.Unit}
+}
+
+// findAction loads the user's action, or returns the response to send when it cannot.
+func (s APIService) findAction(ctx context.Context, userID string, actionId int64) (*models.Action, *refuelapi.ImplResponse) {
+	action, err := s.Actions.Get(ctx, userID, uint(actionId))
+	if err != nil {
+		if err == repository.ErrNotFound {
+			return nil, &refuelapi.ImplResponse{Code: http.StatusNotFound, Body: NewErrorResponse(ctx, http.StatusNotFound, i18n.ActionNotFound)}
+		}
+		return nil, &refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(ctx, http.StatusInternalServerError, i18n.FetchFailed, i18n.NounAction, err)}
+	}
+	return action, nil
+}
+
+// GetActionGains - 行動に紐づくGainの一覧を取得
+func (s APIService) GetActionGains(ctx context.Context, actionId int64) (refuelapi.ImplResponse, error) {
+	userID, resp := GetUserIDFromContext(ctx)
+	if resp != nil {
+		return *resp, nil
+	}
+	action, resp := s.findAction(ctx, userID, actionId)
+	if resp != nil {
+		return *resp, nil
+	}
+	return refuelapi.ImplResponse{Code: http.StatusOK, Body: mapGains(action.Gains)}, nil
+}
+
+// CreateActionGain - 行動にGainを追加
+func (s APIService) CreateActionGain(ctx context.Context, actionId int64, gainInput refuelapi.GainInput) (refuelapi.ImplResponse, error) {
+	userID, resp := GetUserIDFromContext(ctx)
+	if resp != nil {
+		return *resp, nil
+	}
+	checked, err := checkOutcome(i18n.NounGain, gainInput.Type, gainInput.Description, gainInput.Value, gainInput.Unit)
+	if err != nil {
+		return refuelapi.ImplResponse{Code: http.StatusBadRequest, Body: NewErrorResponse(ctx, http.StatusBadRequest, i18n.ValidationFailed, err)}, nil
+	}
+	action, resp := s.findAction(ctx, userID, actionId)
+	if resp != nil {
+		return *resp, nil
+	}
+
+	gain := checked.gain(0, action.ID)
+	if err := s.Actions.SaveGain(ctx, &gain); err != nil {
+		return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(ctx, http.StatusInternalServerError, i18n.CreateFailed, i18n.NounGain, err)}, nil
+	}
+	s.recordRevision(ctx, userID, audit.Gain, gain.ID, audit.Create, nil, &gain)
+	return refuelapi.ImplResponse{Code: http.StatusCreated, Body: mapGain(gain)}, nil
+}
+
+// UpdateActionGain - 行動に紐づくGainを更新
+func (s APIService) UpdateActionGain(ctx context.Context, actionId int64, gainId int64, gainInput refuelapi.GainInput) (refuelapi.ImplResponse, error) {
+	userID, resp := GetUserIDFromContext(ctx)
+	if resp != nil {
+		return *resp, nil
+	}
+	checked, err := checkOutcome(i18n.NounGain, gainInput.Type, gainInput.Description, gainInput.Value, gainInput.Unit)
+	if err != nil {
+		return refuelapi.ImplResponse{Code: http.StatusBadRequest, Body: NewErrorResponse(ctx, http.StatusBadRequest, i18n.ValidationFailed, err)}, nil
+	}
+	action, resp := s.findAction(ctx, userID, actionId)
+	if resp != nil {
+		return *resp, nil
+	}
+
+	gain, err := s.Actions.GetGain(ctx, action.ID, uint(gainId))
+	if err != nil {
+		if err == repository.ErrNotFound {
+			return refuelapi.ImplResponse{Code: http.StatusNotFound, Body: NewErrorResponse(ctx, http.StatusNotFound, i18n.GainNotFound)}, nil
+		}
+		return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(ctx, http.StatusInternalServerError, i18n.FetchFailed, i18n.NounGain, err)}, nil
+	}
+	updated := checked.gain(gain.ID, gain.ActionID)
+	if err := s.Actions.SaveGain(ctx, &updated); err != nil {
+		return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(ctx, http.StatusInternalServerError, i18n.UpdateFailed, i18n.NounGain, err)}, nil
+	}
+	s.recordRevision(ctx, userID, audit.Gain, updated.ID, audit.Update, gain, &updated)
+	return refuelapi.ImplResponse{Code: http.StatusOK, Body: mapGain(updated)}, nil
+}
+
+// DeleteActionGain - 行動に紐づくGainを削除
+func (s APIService) DeleteActionGain(ctx context.Context, actionId int64, gainId int64) (refuelapi.ImplResponse, error) {
+	userID, resp := GetUserIDFromContext(ctx)
+	if resp != nil {
+		return *resp, nil
+	}
+	action, resp := s.findAction(ctx, userID, actionId)
+	if resp != nil {
+		return *resp, nil
+	}
+
+	gain, err := s.Actions.GetGain(ctx, action.ID, uint(gainId))
+	if err == nil {
+		err = s.Actions.DeleteGain(ctx, action.ID, gain.ID)
+	}
+	if err != nil {
+		if err == repository.ErrNotFound {
+			return refuelapi.ImplResponse{Code: http.StatusNotFound, Body: NewErrorResponse(ctx, http.StatusNotFound, i18n.GainNotFound)}, nil
+		}
+		return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(ctx, http.StatusInternalServerError, i18n.DeleteFailed, i18n.NounGain, err)}, nil
+	}
+	s.recordRevision(ctx, userID, audit.Gain, gain.ID, audit.Delete, gain, nil)
+	return refuelapi.ImplResponse{Code: http.StatusNoContent}, nil
+}
+
+// GetActionLosses - 行動に紐づくLossの一覧を取得
+func (s APIService) GetActionLosses(ctx context.Context, actionId int64) (refuelapi.ImplResponse, error) {
+	userID, resp := GetUserIDFromContext(ctx)
+	if resp != nil {
+		return *resp, nil
+	}
+	action, resp := s.findAction(ctx, userID, actionId)
+	if resp != nil {
+		return *resp, nil
+	}
+	return refuelapi.ImplResponse{Code: http.StatusOK, Body: mapLosses(action.Losses)}, nil
+}
+
+// CreateActionLoss - 行動にLossを追加
+func (s APIService) CreateActionLoss(ctx context.Context, actionId int64, lossInput refuelapi.LossInput) (refuelapi.ImplResponse, error) {
+	userID, resp := GetUserIDFromContext(ctx)
+	if resp != nil {
+		return *resp, nil
+	}
+	checked, err := checkOutcome(i18n.NounLoss, lossInput.Type, lossInput.Description, lossInput.Value, lossInput.Unit)
+	if err != nil {
+		return refuelapi.ImplResponse{Code: http.StatusBadRequest, Body: NewErrorResponse(ctx, http.StatusBadRequest, i18n.ValidationFailed, err)}, nil
+	}
+	action, resp := s.findAction(ctx, userID, actionId)
+	if resp != nil {
+		return *resp, nil
+	}
+
+	loss := checked.loss(0, action.ID)
+	if err := s.Actions.SaveLoss(ctx, &loss); err != nil {
+		return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(ctx, http.StatusInternalServerError, i18n.CreateFailed, i18n.NounLoss, err)}, nil
+	}
+	s.recordRevision(ctx, userID, audit.Loss, loss.ID, audit.Create, nil, &loss)
+	return refuelapi.ImplResponse{Code: http.StatusCreated, Body: mapLoss(loss)}, nil
+}
+
+// UpdateActionLoss - 行動に紐づくLossを更新
+func (s APIService) UpdateActionLoss(ctx context.Context, actionId int64, lossId int64, lossInput refuelapi.LossInput) (refuelapi.ImplResponse, error) {
+	userID, resp := GetUserIDFromContext(ctx)
+	if resp != nil {
+		return *resp, nil
+	}
+	checked, err := checkOutcome(i18n.NounLoss, lossInput.Type, lossInput.Description, lossInput.Value, lossInput.Unit)
+	if err != nil {
+		return refuelapi.ImplResponse{Code: http.StatusBadRequest, Body: NewErrorResponse(ctx, http.StatusBadRequest, i18n.ValidationFailed, err)}, nil
+	}
+	action, resp := s.findAction(ctx, userID, actionId)
+	if resp != nil {
+		return *resp, nil
+	}
+
+	loss, err := s.Actions.GetLoss(ctx, action.ID, uint(lossId))
+	if err != nil {
+		if err == repository.ErrNotFound {
+			return refuelapi.ImplResponse{Code: http.StatusNotFound, Body: NewErrorResponse(ctx, http.StatusNotFound, i18n.LossNotFound)}, nil
+		}
+		return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(ctx, http.StatusInternalServerError, i18n.FetchFailed, i18n.NounLoss, err)}, nil
+	}
+	updated := checked.loss(loss.ID, loss.ActionID)
+	if err := s.Actions.SaveLoss(ctx, &updated); err != nil {
+		return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(ctx, http.StatusInternalServerError, i18n.UpdateFailed, i18n.NounLoss, err)}, nil
+	}
+	s.recordRevision(ctx, userID, audit.Loss, updated.ID, audit.Update, loss, &updated)
+	return refuelapi.ImplResponse{Code: http.StatusOK, Body: mapLoss(updated)}, nil
+}
+
+// DeleteActionLoss - 行動に紐づくLossを削除
+func (s APIService) DeleteActionLoss(ctx context.Context, actionId int64, lossId int64) (refuelapi.ImplResponse, error) {
+	userID, resp := GetUserIDFromContext(ctx)
+	if resp != nil {
+		return *resp, nil
+	}
+	action, resp := s.findAction(ctx, userID, actionId)
+	if resp != nil {
+		return *resp, nil
+	}
+
+	loss, err := s.Actions.GetLoss(ctx, action.ID, uint(lossId))
+	if err == nil {
+		err = s.Actions.DeleteLoss(ctx, action.ID, loss.ID)
+	}
+	if err != nil {
+		if err == repository.ErrNotFound {
+			return refuelapi.ImplResponse{Code: http.StatusNotFound, Body: NewErrorResponse(ctx, http.StatusNotFound, i18n.LossNotFound)}, nil
+		}
+		return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(ctx, http.StatusInternalServerError, i18n.DeleteFailed, i18n.NounLoss, err)}, nil
+	}
+	s.recordRevision(ctx, userID, audit.Loss, loss.ID, audit.Delete, loss, nil)
+	return refuelapi.ImplResponse{Code: http.StatusNoContent}, nil
+}
+
+// GetGainLossSummary - 定量的なGain/Lossを単位・目標・期間ごとに集計
+func (s APIService) GetGainLossSummary(ctx context.Context, goalId int64, kind string, unit string, bucket string, from string, to string, tz string) (refuelapi.ImplResponse, error) {
+	userID, resp := GetUserIDFromContext(ctx)
+	if resp != nil {
+		return *resp, nil
+	}
+
+	loc, err := loadLocation(tz)
+	if err != nil {
+		return refuelapi.ImplResponse{Code: http.StatusBadRequest, Body: NewErrorResponse(ctx, http.StatusBadRequest, i18n.InvalidTimeZone, err)}, nil
+	}
+	b, err := quantity.ParseBucket(bucket)
+	if err != nil {
+		return refuelapi.ImplResponse{Code: http.StatusBadRequest, Body: NewErrorResponse(ctx, http.StatusBadRequest, i18n.InvalidBucket, err)}, nil
+	}
+	if kind != "" && kind != quantity.KindGain && kind != quantity.KindLoss {
+		return refuelapi.ImplResponse{Code: http.StatusBadRequest, Body: NewErrorResponse(ctx, http.StatusBadRequest, i18n.InvalidChoice, "kind", quantity.KindGain+", "+quantity.KindLoss)}, nil
+	}
+	if unit != "" {
+		if unit, err = quantity.NormalizeUnit(unit); err != nil {
+			return refuelapi.ImplResponse{Code: http.StatusBadRequest, Body: NewErrorResponse(ctx, http.StatusBadRequest, i18n.InvalidUnit, err)}, nil
+		}
+	}
+	var fromDate, toDate time.Time
+	if from != "" {
+		if fromDate, err = checkin.ParseDate(from); err != nil {
+			return refuelapi.ImplResponse{Code: http.StatusBadRequest, Body: NewErrorResponse(ctx, http.StatusBadRequest, i18n.InvalidDate, "from")}, nil
+		}
+	}
+	if to != "" {
+		if toDate, err = checkin.ParseDate(to); err != nil {
+			return refuelapi.ImplResponse{Code: http.StatusBadRequest, Body: NewErrorResponse(ctx, http.StatusBadRequest, i18n.InvalidDate, "to")}, nil
+		}
+	}
+	if !fromDate.IsZero() && !toDate.IsZero() && fromDate.After(toDate) {
+		return refuelapi.ImplResponse{Code: http.StatusBadRequest, Body: NewErrorResponse(ctx, http.StatusBadRequest, i18n.DateRangeOrder)}, nil
+	}
+
+	if goalId != 0 {
+		if _, err := s.Goals.Get(ctx, userID, uint(goalId)); err != nil {
+			if err == repository.ErrNotFound {
+				return refuelapi.ImplResponse{Code: http.StatusNotFound, Body: NewErrorResponse(ctx, http.StatusNotFound, i18n.GoalNotFound)}, nil
+			}
+			return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(ctx, http.StatusInternalServerError, i18n.FetchFailed, i18n.NounGoal, err)}, nil
+		}
+	}
+	actions, err := s.Actions.List(ctx, userID)
+	if err != nil {
+		return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(ctx, http.StatusInternalServerError, i18n.FetchFailed, i18n.NounActions, err)}, nil
+	}
+
+	var entries []quantity.Entry
+	add := func(entryKind string, action models.Action, value *float64, entryUnit string, at time.Time) {
+		if value == nil || (kind != "" && kind != entryKind) || (unit != "" && unit != entryUnit) {
+			return
+		}
+		entries = append(entries, quantity.Entry{Kind: entryKind, GoalID: action.GoalID, Unit: entryUnit, Value: *value, At: at})
+	}
+	for _, action := range actions {
+		if goalId != 0 && action.GoalID != uint(goalId) {
+			continue
+		}
+		// Gains and losses have no date of their own; they count when the action was done.
+		at := action.CreatedAt
+		if action.CompletedAt != nil {
+			at = *action.CompletedAt
+		}
+		day := checkin.Civil(at, loc)
+		if (!fromDate.IsZero() && day.Before(fromDate)) || (!toDate.IsZero() && day.After(toDate)) {
+			continue
+		}
+		for _, g := range action.Gains {
+			add(quantity.KindGain, action, g.Value, g.Unit, at)
+		}
+		for _, l := range action.Losses {
+			add(quantity.KindLoss, action, l.Value, l.Unit, at)
+		}
+	}
+
+	rows := quantity.Aggregate(entries, b, loc)
+	resRows := make([]refuelapi.GainLossSummary, len(rows))
+	for i, row := range rows {
+		resRows[i] = refuelapi.GainLossSummary{
+			Kind:        row.Kind,
+			GoalId:      int64(row.GoalID),
+			Unit:        row.Unit,
+			BucketStart: checkin.Key(row.BucketStart),
+			Count:       int32(row.Count),
+			Total:       row.Total,
+			Average:     row.Average,
+		}
+	}
+	return refuelapi.ImplResponse{Code: http.StatusOK, Body: resRows}, nil
+}
+
+// gainsLossesUpdate is the validated gains and losses part of an ActionUpdateInput.
+// A nil list leaves that side untouched.
+type gainsLossesUpdate struct {
+	gains   []models.Gain
+	losses  []models.Loss
+	replace bool
+}
+
+// parseGainsLosses validates the gains and losses of an ActionUpdateInput
+// before anything is written.
+func parseGainsLosses(ctx context.Context, actionID uint, input refuelapi.ActionUpdateInput) (gainsLossesUpdate, *refuelapi.ImplResponse) {
+	var update gainsLossesUpdate
+	switch input.GainsLossesMode {
+	case "", gainsLossesReplace:
+		update.replace = true
+	case gainsLossesMerge:
+	default:
+		return update, &refuelapi.ImplResponse{Code: http.StatusBadRequest, Body: NewErrorResponse(ctx, http.StatusBadRequest, i18n.InvalidChoice, "gains_losses_mode", gainsLossesReplace+", "+gainsLossesMerge)}
+	}
+
+	if input.Gains != nil {
+		update.gains = make([]models.Gain, len(input.Gains))
+		for i, in := range input.Gains {
+			checked, err := checkOutcome(i18n.NounGain, in.Type, in.Description, in.Value, in.Unit)
+			if err != nil {
+				return update, &refuelapi.ImplResponse{Code: http.StatusBadRequest, Body: NewErrorResponse(ctx, http.StatusBadRequest, i18n.ValidationFailed, err)}
+			}
+			update.gains[i] = checked.gain(uint(in.Id), actionID)
+		}
+	}
+	if input.Losses != nil {
+		update.losses = make([]models.Loss, len(input.Losses))
+		for i, in := range input.Losses {
+			checked, err := checkOutcome(i18n.NounLoss, in.Type, in.Description, in.Value, in.Unit)
+			if err != nil {
+				return update, &refuelapi.ImplResponse{Code: http.StatusBadRequest, Body: NewErrorResponse(ctx, http.StatusBadRequest, i18n.ValidationFailed, err)}
+			}
+			update.losses[i] = checked.loss(uint(in.Id), actionID)
+		}
+	}
+	return update, nil
+}
+
+// saveGainsLosses writes the lists of update. With replace, the stored list
+// becomes exactly the given one; with merge, gains and losses not listed stay.
+func (s APIService) saveGainsLosses(ctx context.Context, actionID uint, update gainsLossesUpdate) *refuelapi.ImplResponse {
+	if update.gains != nil {
+		if err := s.Actions.SaveGains(ctx, actionID, update.gains, update.replace); err != nil {
+			if err == repository.ErrNotFound {
+				return &refuelapi.ImplResponse{Code: http.StatusBadRequest, Body: NewErrorResponse(ctx, http.StatusBadRequest, i18n.GainNotInAction)}
+			}
+			return &refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(ctx, http.StatusInternalServerError, i18n.UpdateFailed, i18n.NounGains, err)}
+		}
+	}
+	if update.losses != nil {
+		if err := s.Actions.SaveLosses(ctx, actionID, update.losses, update.replace); err != nil {
+			if err == repository.ErrNotFound {
+				return &refuelapi.ImplResponse{Code: http.StatusBadRequest, Body: NewErrorResponse(ctx, http.StatusBadRequest, i18n.LossNotInAction)}
+			}
+			return &refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(ctx, http.StatusInternalServerError, i18n.UpdateFailed, i18n.NounLosses, err)}
+		}
+	}
+	return nil
+}
+
+func mapGain(g models.Gain) refuelapi.Gain {
+	res := refuelapi.Gain{
+		Id:          int64(g.ID),
+		ActionId:    int64(g.ActionID),
+		Type:        g.Type,
+		Description: g.Description,
+		Unit:        g.Unit,
+	}
+	if g.Value != nil {
+		res.Value = *g.Value
+	}
+	return res
+}
+
+func mapGains(gains []models.Gain) []refuelapi.Gain {
+	res := make([]refuelapi.Gain, len(gains))
+	for i, g := range gains {
+		res[i] = mapGain(g)
+	}
+	return res
+}
+
+func mapLoss(l models.Loss) refuelapi.Loss {
+	res := refuelapi.Loss{
+		Id:          int64(l.ID),
+		ActionId:    int64(l.ActionID),
+		Type:        l.Type,
+		Description: l.Description,
+		Unit:        l.Unit,
+	}
+	if l.Value != nil {
+		res.Value = *l.Value
+	}
+	return res
+}
+
+func mapLosses(losses []models.Loss) []refuelapi.Loss {
+	res := make([]refuelapi.Loss, len(losses))
+	for i, l := range losses {
+		res[i] = mapLoss(l)
+	}
+	return res
+}
@@ -0,0 +1,214 @@
+package app
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"refuel/backend/audit"
+	"refuel/backend/checkin"
+	refuelapi "refuel/backend/generated/go"
+	"refuel/backend/i18n"
+	"refuel/backend/models"
+	"refuel/backend/progress"
+	"refuel/backend/quantity"
+	"refuel/backend/repository"
+)
+
+// applyGoalMetric validates input and stores it on the goal, or clears the
+// goal's metric when input is nil. The goal is left alone on error.
+func applyGoalMetric(goal *models.Goal, input *refuelapi.GoalMetric) error {
+	if input == nil {
+		goal.MetricUnit = ""
+		goal.MetricBaseline = nil
+		goal.MetricTarget = nil
+		goal.MetricDirection = ""
+		goal.MetricDeadline = nil
+		return nil
+	}
+	unit, err := quantity.NormalizeUnit(input.Unit)
+	if err != nil {
+		return err
+	}
+	metric := progress.Metric{Baseline: input.Baseline, Target: input.Target, Direction: progress.Direction(input.Direction)}
+	if err := metric.Validate(); err != nil {
+		return err
+	}
+	var deadline *time.Time
+	if input.Deadline != "" {
+		d, err := checkin.ParseDate(input.Deadline)
+		if err != nil {
+			return i18n.NewError(i18n.DetailDeadline, input.Deadline)
+		}
+		deadline = &d
+	}
+	goal.MetricUnit = unit
+	goal.MetricBaseline = &metric.Baseline
+	goal.MetricTarget = &metric.Target
+	goal.MetricDirection = input.Direction
+	goal.MetricDeadline = deadline
+	return nil
+}
+
+// goalMetric returns the metric a measurable goal declares.
+func goalMetric(goal models.Goal) progress.Metric {
+	m := progress.Metric{Direction: progress.Direction(goal.MetricDirection)}
+	if goal.MetricBaseline != nil {
+		m.Baseline = *goal.MetricBaseline
+	}
+	if goal.MetricTarget != nil {
+		m.Target = *goal.MetricTarget
+	}
+	if goal.MetricDeadline != nil {
+		m.Deadline = *goal.MetricDeadline
+	}
+	return m
+}
+
+func mapGoalMetric(goal models.Goal) *refuelapi.GoalMetric {
+	if !goal.HasMetric() {
+		return nil
+	}
+	m := goalMetric(goal)
+	res := &refuelapi.GoalMetric{
+		Unit:      goal.MetricUnit,
+		Baseline:  m.Baseline,
+		Target:    m.Target,
+		Direction: goal.MetricDirection,
+	}
+	if goal.MetricDeadline != nil {
+		res.Deadline = checkin.Key(*goal.MetricDeadline)
+	}
+	return res
+}
+
+// goalProgress computes the progress of a measurable goal from its
+// measurements, or returns nil for a goal without a metric.
+func (s APIService) goalProgress(ctx context.Context, goal models.Goal, loc *time.Location) (*refuelapi.GoalProgress, error) {
+	if !goal.HasMetric() {
+		return nil, nil
+	}
+	measurements, err := s.Goals.ListMeasurements(ctx, goal.UserID, goal.ID)
+	if err != nil {
+		return nil, err
+	}
+	points := make([]progress.Point, len(measurements))
+	for i, m := range measurements {
+		points[i] = progress.Point{Value: m.Value, At: m.MeasuredAt}
+	}
+	summary := progress.Compute(goalMetric(goal), points, time.Now(), loc)
+
+	res := &refuelapi.GoalProgress{
+		CurrentValue:     summary.Current,
+		ProgressPercent:  summary.Percent,
+		Achieved:         summary.Achieved,
+		OnTrack:          summary.OnTrack,
+		MeasurementCount: int32(summary.Count),
+	}
+	if summary.Count > 0 {
+		res.LastMeasuredAt = &summary.LastAt
+	}
+	if !summary.Projected.IsZero() {
+		res.ProjectedCompletionDate = checkin.Key(summary.Projected)
+	}
+	return res, nil
+}
+
+// findGoal loads the user's goal, or returns the response to send when it cannot.
+func (s APIService) findGoal(ctx context.Context, userID string, goalId int64) (*models.Goal, *refuelapi.ImplResponse) {
+	goal, err := s.Goals.Get(ctx, userID, uint(goalId))
+	if err != nil {
+		if err == repository.ErrNotFound {
+			return nil, &refuelapi.ImplResponse{Code: http.StatusNotFound, Body: NewErrorResponse(ctx, http.StatusNotFound, i18n.GoalNotFound)}
+		}
+		return nil, &refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(ctx, http.StatusInternalServerError, i18n.FetchFailed, i18n.NounGoal, err)}
+	}
+	return goal, nil
+}
+
+// GetGoalMeasurements - 目標の計測記録の一覧を取得
+func (s APIService) GetGoalMeasurements(ctx context.Context, goalId int64) (refuelapi.ImplResponse, error) {
+	userID, resp := GetUserIDFromContext(ctx)
+	if resp != nil {
+		return *resp, nil
+	}
+	goal, resp := s.findGoal(ctx, userID, goalId)
+	if resp != nil {
+		return *resp, nil
+	}
+
+	measurements, err := s.Goals.ListMeasurements(ctx, userID, goal.ID)
+	if err != nil {
+		return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(ctx, http.StatusInternalServerError, i18n.FetchFailed, i18n.NounMeasurements, err)}, nil
+	}
+	res := make([]refuelapi.GoalMeasurement, len(measurements))
+	for i, m := range measurements {
+		res[i] = mapGoalMeasurement(m)
+	}
+	return refuelapi.ImplResponse{Code: http.StatusOK, Body: res}, nil
+}
+
+// CreateGoalMeasurement - 目標に計測記録を追加
+func (s APIService) CreateGoalMeasurement(ctx context.Context, goalId int64, goalMeasurementInput refuelapi.GoalMeasurementInput) (refuelapi.ImplResponse, error) {
+	userID, resp := GetUserIDFromContext(ctx)
+	if resp != nil {
+		return *resp, nil
+	}
+	goal, resp := s.findGoal(ctx, userID, goalId)
+	if resp != nil {
+		return *resp, nil
+	}
+	if !goal.HasMetric() {
+		return refuelapi.ImplResponse{Code: http.StatusBadRequest, Body: NewErrorResponse(ctx, http.StatusBadRequest, i18n.GoalHasNoMetric)}, nil
+	}
+
+	measuredAt := goalMeasurementInput.MeasuredAt
+	if measuredAt.IsZero() {
+		measuredAt = time.Now()
+	}
+	measurement := models.GoalMeasurement{
+		GoalID:     goal.ID,
+		UserID:     userID,
+		Value:      goalMeasurementInput.Value,
+		MeasuredAt: measuredAt,
+		Note:       goalMeasurementInput.Note,
+	}
+	if err := s.Goals.CreateMeasurement(ctx, &measurement); err != nil {
+		return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(ctx, http.StatusInternalServerError, i18n.CreateFailed, i18n.NounMeasurement, err)}, nil
+	}
+	s.recordRevision(ctx, userID, audit.Measurement, measurement.ID, audit.Create, nil, &measurement)
+	s.checkMilestones(ctx, userID, goal.ID)
+	return refuelapi.ImplResponse{Code: http.StatusCreated, Body: mapGoalMeasurement(measurement)}, nil
+}
+
+// DeleteGoalMeasurement - 目標の計測記録を削除
+func (s APIService) DeleteGoalMeasurement(ctx context.Context, goalId int64, measurementId int64) (refuelapi.ImplResponse, error) {
+	userID, resp := GetUserIDFromContext(ctx)
+	if resp != nil {
+		return *resp, nil
+	}
+
+	measurement, err := s.Goals.GetMeasurement(ctx, userID, uint(goalId), uint(measurementId))
+	if err == nil {
+		err = s.Goals.DeleteMeasurement(ctx, userID, measurement.GoalID, measurement.ID)
+	}
+	if err != nil {
+		if err == repository.ErrNotFound {
+			return refuelapi.ImplResponse{Code: http.StatusNotFound, Body: NewErrorResponse(ctx, http.StatusNotFound, i18n.MeasurementNotFound)}, nil
+		}
+		return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(ctx, http.StatusInternalServerError, i18n.DeleteFailed, i18n.NounMeasurement, err)}, nil
+	}
+	s.recordRevision(ctx, userID, audit.Measurement, measurement.ID, audit.Delete, measurement, nil)
+	return refuelapi.ImplResponse{Code: http.StatusNoContent}, nil
+}
+
+func mapGoalMeasurement(m models.GoalMeasurement) refuelapi.GoalMeasurement {
+	return refuelapi.GoalMeasurement{
+		Id:         int64(m.ID),
+		GoalId:     int64(m.GoalID),
+		Value:      m.Value,
+		MeasuredAt: m.MeasuredAt,
+		Note:       m.Note,
+		CreatedAt:  m.CreatedAt,
+	}
+}
@@ -0,0 +1,170 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"refuel/backend/audit"
+	refuelapi "refuel/backend/generated/go"
+	"refuel/backend/i18n"
+	"refuel/backend/importer"
+	"refuel/backend/models"
+	"refuel/backend/repository"
+)
+
+// importMaxSize is the largest file an import reads.
+const importMaxSize = 20 << 20
+
+// maxReportedRejections caps the rejections listed in an import report;
+// the counts still cover every record.
+const maxReportedRejections = 100
+
+// ImportMyData - アカウントのデータをインポート
+func (s APIService) ImportMyData(ctx context.Context, dryRun bool, file *os.File, complexId int64) (refuelapi.ImplResponse, error) {
+	userID, resp := GetUserIDFromContext(ctx)
+	if resp != nil {
+		return *resp, nil
+	}
+
+	// The controller leaves the upload in a temporary file for us to remove.
+	defer os.Remove(file.Name())
+	info, err := os.Stat(file.Name())
+	if err != nil {
+		return refuelapi.ImplResponse{Code: http.StatusBadRequest, Body: NewErrorResponse(ctx, http.StatusBadRequest, i18n.InvalidImportFile, err)}, nil
+	}
+	if info.Size() > importMaxSize {
+		return refuelapi.ImplResponse{Code: http.StatusBadRequest, Body: NewErrorResponse(ctx, http.StatusBadRequest, i18n.ImportFileTooLarge, importMaxSize>>20)}, nil
+	}
+	content, err := os.ReadFile(file.Name())
+	if err != nil {
+		return refuelapi.ImplResponse{Code: http.StatusBadRequest, Body: NewErrorResponse(ctx, http.StatusBadRequest, i18n.InvalidImportFile, err)}, nil
+	}
+
+	existing, err := s.Exports.UserData(ctx, userID)
+	if err != nil {
+		return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(ctx, http.StatusInternalServerError, i18n.FetchFailed, i18n.NounComplexes, err)}, nil
+	}
+	plan, err := importer.NewPlan(userID, content, existing, importer.Options{ComplexID: uint(complexId), Now: time.Now()})
+	if err != nil {
+		var importErr *importer.Error
+		if errors.As(err, &importErr) {
+			return refuelapi.ImplResponse{Code: http.StatusBadRequest, Body: NewErrorResponse(ctx, http.StatusBadRequest, importErr.Code, importErr.Args...)}, nil
+		}
+		return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(ctx, http.StatusInternalServerError, i18n.InvalidImportFile, err)}, nil
+	}
+
+	if !dryRun && plan.Creates() {
+		created := newImportRecords(&plan.Batch)
+		if err := s.Imports.Import(ctx, &plan.Batch); err != nil {
+			return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(ctx, http.StatusInternalServerError, i18n.SaveFailed, i18n.NounImport, err)}, nil
+		}
+		log.Printf("📥 Imported %s for user %s", plan.Format, userID)
+		s.recordImport(ctx, userID, &plan.Batch, created)
+		// Imported check-ins and measurements may earn badges and reach
+		// the milestones of the goals they were added to. An import has no
+		// time zone, so days are counted in UTC as without tz elsewhere.
+		if s.evaluateBadges(ctx, userID, time.UTC) {
+			for _, goalID := range created.progressedGoals {
+				s.checkMilestones(ctx, userID, goalID)
+			}
+		}
+	}
+
+	return refuelapi.ImplResponse{Code: http.StatusOK, Body: toAPIImportReport(ctx, plan, dryRun)}, nil
+}
+
+// importRecords are the complexes, goals and actions of a batch that an
+// import creates, told apart before it runs from those already stored,
+// and the stored goals it adds check-ins or measurements to.
+type importRecords struct {
+	complexes       map[*models.Complex]bool
+	goals           map[*models.Goal]bool
+	actions         map[*models.Action]bool
+	progressedGoals []uint
+}
+
+func newImportRecords(batch *repository.ImportBatch) importRecords {
+	r := importRecords{complexes: map[*models.Complex]bool{}, goals: map[*models.Goal]bool{}, actions: map[*models.Action]bool{}}
+	for _, c := range batch.Complexes {
+		r.complexes[&c.Complex] = c.Complex.ID == 0
+		for _, g := range c.Goals {
+			r.goals[&g.Goal] = g.Goal.ID == 0
+			added := len(g.Measurements) > 0
+			for _, a := range g.Actions {
+				r.actions[&a.Action] = a.Action.ID == 0
+				added = added || len(a.Completions) > 0
+			}
+			if g.Goal.ID != 0 && added {
+				r.progressedGoals = append(r.progressedGoals, g.Goal.ID)
+			}
+		}
+	}
+	return r
+}
+
+// recordImport records the creation of every imported record, as if the
+// user had created each of them.
+func (s APIService) recordImport(ctx context.Context, userID string, batch *repository.ImportBatch, created importRecords) {
+	for _, c := range batch.Complexes {
+		if created.complexes[&c.Complex] {
+			s.recordRevision(ctx, userID, audit.Complex, c.Complex.ID, audit.Create, nil, &c.Complex)
+		}
+		for _, g := range c.Goals {
+			if created.goals[&g.Goal] {
+				s.recordRevision(ctx, userID, audit.Goal, g.Goal.ID, audit.Create, nil, &g.Goal)
+			}
+			for i := range g.Measurements {
+				s.recordRevision(ctx, userID, audit.Measurement, g.Measurements[i].ID, audit.Create, nil, &g.Measurements[i])
+			}
+			for _, a := range g.Actions {
+				if created.actions[&a.Action] {
+					s.recordRevision(ctx, userID, audit.Action, a.Action.ID, audit.Create, nil, &a.Action)
+				}
+				for i := range a.Gains {
+					s.recordRevision(ctx, userID, audit.Gain, a.Gains[i].ID, audit.Create, nil, &a.Gains[i])
+				}
+				for i := range a.Losses {
+					s.recordRevision(ctx, userID, audit.Loss, a.Losses[i].ID, audit.Create, nil, &a.Losses[i])
+				}
+				for i := range a.Completions {
+					s.recordRevision(ctx, userID, audit.Checkin, a.Completions[i].ID, audit.Create, nil, &a.Completions[i])
+				}
+			}
+		}
+	}
+}
+
+func toAPIImportReport(ctx context.Context, plan *importer.Plan, dryRun bool) refuelapi.ImportReport {
+	report := refuelapi.ImportReport{
+		DryRun:     dryRun,
+		Format:     plan.Format,
+		Entities:   make([]refuelapi.ImportEntityReport, len(importer.Entities)),
+		Rejections: []refuelapi.ImportRejection{},
+	}
+	for i, entity := range importer.Entities {
+		counts := plan.Counts[entity]
+		report.Entities[i] = refuelapi.ImportEntityReport{
+			Entity:       entity,
+			Created:      int32(counts.Created),
+			Deduplicated: int32(counts.Deduplicated),
+			Rejected:     int32(counts.Rejected),
+		}
+	}
+	lang := requestLang(ctx)
+	for i, r := range plan.Rejections {
+		if i == maxReportedRejections {
+			break
+		}
+		report.Rejections = append(report.Rejections, refuelapi.ImportRejection{
+			Entity:  r.Entity,
+			Ref:     r.Ref,
+			Code:    string(r.Code),
+			Message: i18n.T(lang, r.Code, r.Args...),
+		})
+	}
+	return report
+}
@@ -0,0 +1,196 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+
+	refuelapi "refuel/backend/generated/go"
+
+	"refuel/backend/audit"
+	"refuel/backend/i18n"
+	"refuel/backend/models"
+	"refuel/backend/progress"
+)
+
+// defaultMilestoneCount is how many evenly spaced milestones a plan without
+// count or fractions gets.
+const defaultMilestoneCount = 4
+
+// planMilestones works the plan's milestones backwards from the goal's final
+// target. The error is meant for the client.
+func planMilestones(goal models.Goal, plan refuelapi.MilestonePlanInput) ([]models.Milestone, error) {
+	count := int(plan.Count)
+	if count == 0 && len(plan.Fractions) == 0 {
+		count = defaultMilestoneCount
+	}
+
+	var thresholds []float64
+	var title func(threshold float64) string
+	var err error
+	switch plan.Basis {
+	case models.MilestoneMetric:
+		if !goal.HasMetric() {
+			return nil, i18n.NewError(i18n.DetailPlanWithoutMetric)
+		}
+		metric := goalMetric(goal)
+		if thresholds, err = progress.Thresholds(metric.Baseline, metric.Target, count, plan.Fractions); err != nil {
+			return nil, err
+		}
+		title = func(t float64) string { return strconv.FormatFloat(t, 'f', -1, 64) + " " + goal.MetricUnit }
+	case models.MilestoneCompletedActions:
+		if plan.Target <= 0 {
+			return nil, i18n.NewError(i18n.DetailCompletedTarget)
+		}
+		if thresholds, err = progress.Thresholds(0, float64(plan.Target), count, plan.Fractions); err != nil {
+			return nil, err
+		}
+		thresholds = progress.WholeThresholds(thresholds)
+		title = func(t float64) string { return fmt.Sprintf("行動%d回", int(t)) }
+	default:
+		return nil, i18n.NewError(i18n.DetailMilestoneBasis, models.MilestoneMetric, models.MilestoneCompletedActions)
+	}
+
+	milestones := make([]models.Milestone, len(thresholds))
+	for i, t := range thresholds {
+		milestones[i] = models.Milestone{
+			GoalID:    goal.ID,
+			UserID:    goal.UserID,
+			Position:  i + 1,
+			Title:     title(t),
+			Basis:     plan.Basis,
+			Threshold: t,
+		}
+	}
+	return milestones, nil
+}
+
+// checkMilestones marks the goal's crossed milestones achieved, awards
+// their badges and returns the badges awarded. Failures are only logged
+// because the change that crossed them has already been saved.
+func (s APIService) checkMilestones(ctx context.Context, userID string, goalID uint) []models.UserBadge {
+	awarded, err := s.Evaluator.CheckMilestones(ctx, userID, goalID)
+	if err != nil {
+		log.Printf("⚠️ Failed to check milestones of goal %d for user %s: %v", goalID, userID, err)
+	}
+	for _, ub := range awarded {
+		log.Printf("🏆 User %s reached milestone %q of goal %d", userID, ub.Milestone.Title, goalID)
+	}
+	return awarded
+}
+
+// GetGoalMilestones - 目標のマイルストーンの一覧を取得
+func (s APIService) GetGoalMilestones(ctx context.Context, goalId int64) (refuelapi.ImplResponse, error) {
+	userID, resp := GetUserIDFromContext(ctx)
+	if resp != nil {
+		return *resp, nil
+	}
+	goal, resp := s.findGoal(ctx, userID, goalId)
+	if resp != nil {
+		return *resp, nil
+	}
+
+	milestones, err := s.Goals.ListMilestones(ctx, userID, goal.ID)
+	if err != nil {
+		return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(ctx, http.StatusInternalServerError, i18n.FetchFailed, i18n.NounMilestones, err)}, nil
+	}
+	return refuelapi.ImplResponse{Code: http.StatusOK, Body: mapMilestones(milestones)}, nil
+}
+
+// GenerateGoalMilestones - 最終目標から逆算してマイルストーンを作成
+func (s APIService) GenerateGoalMilestones(ctx context.Context, goalId int64, milestonePlanInput refuelapi.MilestonePlanInput) (refuelapi.ImplResponse, error) {
+	userID, resp := GetUserIDFromContext(ctx)
+	if resp != nil {
+		return *resp, nil
+	}
+	goal, resp := s.findGoal(ctx, userID, goalId)
+	if resp != nil {
+		return *resp, nil
+	}
+
+	milestones, err := planMilestones(*goal, milestonePlanInput)
+	if err != nil {
+		return refuelapi.ImplResponse{Code: http.StatusBadRequest, Body: NewErrorResponse(ctx, http.StatusBadRequest, i18n.InvalidMilestonePlan, err)}, nil
+	}
+	existing, err := s.Goals.ListMilestones(ctx, userID, goal.ID)
+	if err != nil {
+		return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(ctx, http.StatusInternalServerError, i18n.FetchFailed, i18n.NounMilestones, err)}, nil
+	}
+	milestones, replaced := afterAchieved(existing, milestones)
+	if err := s.Goals.ReplaceMilestones(ctx, userID, goal.ID, milestones); err != nil {
+		return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(ctx, http.StatusInternalServerError, i18n.SaveFailed, i18n.NounMilestones, err)}, nil
+	}
+	created := make(map[uint]bool, len(milestones))
+	for _, m := range milestones {
+		created[m.ID] = true
+	}
+
+	// Milestones the goal has already passed are achieved right away.
+	s.checkMilestones(ctx, userID, goal.ID)
+	if milestones, err = s.Goals.ListMilestones(ctx, userID, goal.ID); err != nil {
+		return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(ctx, http.StatusInternalServerError, i18n.FetchFailed, i18n.NounMilestones, err)}, nil
+	}
+	for i := range replaced {
+		s.recordRevision(ctx, userID, audit.Milestone, replaced[i].ID, audit.Delete, &replaced[i], nil)
+	}
+	for i := range milestones {
+		if created[milestones[i].ID] {
+			s.recordRevision(ctx, userID, audit.Milestone, milestones[i].ID, audit.Create, nil, &milestones[i])
+		}
+	}
+	return refuelapi.ImplResponse{Code: http.StatusCreated, Body: mapMilestones(milestones)}, nil
+}
+
+// afterAchieved fits planned milestones to a goal's existing ones. The
+// achieved milestones are kept with their badges, so the planned ones they
+// already stand for are dropped and the rest are numbered after them. It
+// returns the planned milestones left and the existing ones they replace.
+func afterAchieved(existing, planned []models.Milestone) (milestones, replaced []models.Milestone) {
+	type step struct {
+		basis     string
+		threshold float64
+	}
+	achieved := map[step]bool{}
+	position := 0
+	for _, m := range existing {
+		if m.AchievedAt == nil {
+			replaced = append(replaced, m)
+			continue
+		}
+		achieved[step{m.Basis, m.Threshold}] = true
+		position = max(position, m.Position)
+	}
+	for _, m := range planned {
+		if achieved[step{m.Basis, m.Threshold}] {
+			continue
+		}
+		position++
+		m.Position = position
+		milestones = append(milestones, m)
+	}
+	return milestones, replaced
+}
+
+func mapMilestone(m models.Milestone) refuelapi.Milestone {
+	return refuelapi.Milestone{
+		Id:         int64(m.ID),
+		GoalId:     int64(m.GoalID),
+		Position:   int32(m.Position),
+		Title:      m.Title,
+		Basis:      m.Basis,
+		Threshold:  m.Threshold,
+		Achieved:   m.AchievedAt != nil,
+		AchievedAt: m.AchievedAt,
+		CreatedAt:  m.CreatedAt,
+	}
+}
+
+func mapMilestones(milestones []models.Milestone) []refuelapi.Milestone {
+	res := make([]refuelapi.Milestone, len(milestones))
+	for i, m := range milestones {
+		res[i] = mapMilestone(m)
+	}
+	return res
+}
@@ -0,0 +1,63 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	refuelapi "refuel/backend/generated/go"
+	"refuel/backend/i18n"
+	"refuel/backend/repository"
+)
+
+// pageRequest checks the pagination parameters shared by the list
+// endpoints. sort must be one of fields, optionally prefixed with "-", and
+// defaults to defaultSort; a cursor is only valid for the sort order it was
+// issued for.
+func pageRequest(ctx context.Context, limit int32, cursor, sort string, fields []string, defaultSort string) (repository.Page, *refuelapi.ImplResponse) {
+	page := repository.Page{Limit: repository.DefaultPageSize}
+	if limit != 0 {
+		if limit < 1 || limit > repository.MaxPageSize {
+			return page, &refuelapi.ImplResponse{Code: http.StatusBadRequest, Body: NewErrorResponse(ctx, http.StatusBadRequest, i18n.OutOfRange, "limit", 1, repository.MaxPageSize)}
+		}
+		page.Limit = int(limit)
+	}
+
+	if sort == "" {
+		sort = defaultSort
+	}
+	var ok bool
+	if page.Sort, ok = repository.ParseSort(sort, fields); !ok {
+		choices := make([]string, 0, 2*len(fields))
+		for _, f := range fields {
+			choices = append(choices, f, "-"+f)
+		}
+		return page, &refuelapi.ImplResponse{Code: http.StatusBadRequest, Body: NewErrorResponse(ctx, http.StatusBadRequest, i18n.InvalidChoice, "sort", strings.Join(choices, ", "))}
+	}
+
+	if cursor != "" {
+		after, err := repository.DecodeCursor(cursor)
+		if err != nil || after.Sort != page.Sort {
+			return page, &refuelapi.ImplResponse{Code: http.StatusBadRequest, Body: NewErrorResponse(ctx, http.StatusBadRequest, i18n.InvalidCursor)}
+		}
+		page.After = after
+	}
+	return page, nil
+}
+
+// pageResponse returns one page of a list. When another page follows, its
+// URL is sent in a Link header: path with query, the request's filters,
+// and the page's limit and sort plus the next cursor.
+func pageResponse(path string, query url.Values, page repository.Page, next *repository.Cursor, body interface{}) refuelapi.ImplResponse {
+	if next == nil {
+		return refuelapi.ImplResponse{Code: http.StatusOK, Body: body}
+	}
+	query.Set("cursor", next.Encode())
+	query.Set("limit", strconv.Itoa(page.Limit))
+	query.Set("sort", page.Sort.String())
+	link := fmt.Sprintf(`<%s?%s>; rel="next"`, path, query.Encode())
+	return refuelapi.ImplResponse{Code: http.StatusOK, Headers: map[string][]string{"Link": {link}}, Body: body}
+}
@@ -0,0 +1,72 @@
+package app
+
+import (
+	"context"
+	"log"
+	"net/http"
+
+	"refuel/backend/audit"
+	refuelapi "refuel/backend/generated/go"
+	"refuel/backend/i18n"
+	"refuel/backend/models"
+	"refuel/backend/repository"
+)
+
+// recordRevision records a change the user made to one of their records.
+// before is nil for a created record and after for a deleted one. Failures
+// are only logged because the change itself has already been saved.
+func (s APIService) recordRevision(ctx context.Context, userID, entityType string, entityID uint, action string, before, after interface{}) {
+	revision := models.Revision{UserID: userID, EntityType: entityType, EntityID: entityID, Action: action, Actor: userID}
+	var err error
+	if revision.Before, err = audit.Snapshot(before); err == nil {
+		if revision.After, err = audit.Snapshot(after); err == nil {
+			err = s.Revisions.Create(ctx, &revision)
+		}
+	}
+	if err != nil {
+		log.Printf("⚠️ Failed to record the %s of %s %d for user %s: %v", action, entityType, entityID, userID, err)
+	}
+}
+
+// GetComplexHistory - コンプレックスの変更履歴を取得
+func (s APIService) GetComplexHistory(ctx context.Context, complexId int64) (refuelapi.ImplResponse, error) {
+	userID, resp := GetUserIDFromContext(ctx)
+	if resp != nil {
+		return *resp, nil
+	}
+
+	revisions, err := s.Revisions.List(ctx, userID, audit.Complex, uint(complexId))
+	if err != nil {
+		return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(ctx, http.StatusInternalServerError, i18n.FetchFailed, i18n.NounRevisions, err)}, nil
+	}
+	if len(revisions) == 0 {
+		// Complexes created before revisions were recorded have no history yet.
+		if _, err := s.Complexes.Get(ctx, userID, uint(complexId)); err != nil {
+			if err == repository.ErrNotFound {
+				return refuelapi.ImplResponse{Code: http.StatusNotFound, Body: NewErrorResponse(ctx, http.StatusNotFound, i18n.ComplexNotFound)}, nil
+			}
+			return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(ctx, http.StatusInternalServerError, i18n.FetchFailed, i18n.NounComplex, err)}, nil
+		}
+	}
+
+	resRevisions := make([]refuelapi.Revision, len(revisions))
+	for i, rev := range revisions {
+		changes, err := audit.Diff(rev.Before, rev.After)
+		if err != nil {
+			return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(ctx, http.StatusInternalServerError, i18n.FetchFailed, i18n.NounRevisions, err)}, nil
+		}
+		resChanges := make([]refuelapi.RevisionChange, len(changes))
+		for j, change := range changes {
+			resChanges[j] = refuelapi.RevisionChange{Field: change.Field, Before: change.Before, After: change.After}
+		}
+		resRevisions[i] = refuelapi.Revision{
+			Id:        int64(rev.ID),
+			Action:    rev.Action,
+			Actor:     rev.Actor,
+			CreatedAt: rev.CreatedAt,
+			Changes:   resChanges,
+		}
+	}
+
+	return refuelapi.ImplResponse{Code: http.StatusOK, Body: resRevisions}, nil
+}
@@ -0,0 +1,138 @@
+package app
+
+import (
+	"context"
+	"net/http"
+	"unicode/utf8"
+
+	refuelapi "refuel/backend/generated/go"
+	"refuel/backend/i18n"
+	"refuel/backend/repository"
+	"refuel/backend/search"
+)
+
+// Kinds of records a search hit refers to.
+const (
+	searchComplex = "complex"
+	searchGoal    = "goal"
+	searchAction  = "action"
+	searchGain    = "gain"
+	searchLoss    = "loss"
+)
+
+// searchMaxRecords is how many of the user's most recently updated records
+// of each kind a search looks through.
+const searchMaxRecords = 1000
+
+// searchPage selects the records a search looks through.
+var searchPage = repository.Page{Sort: repository.Sort{Field: "updated_at", Desc: true}, Limit: searchMaxRecords}
+
+// searchRef tells which record a searched document was built from.
+type searchRef struct {
+	kind     string
+	id       uint
+	actionID uint
+}
+
+// Search - コンプレックス・目標・行動・Gain/Lossを全文検索
+func (s APIService) Search(ctx context.Context, q string, type_ string, limit int32) (refuelapi.ImplResponse, error) {
+	userID, resp := GetUserIDFromContext(ctx)
+	if resp != nil {
+		return *resp, nil
+	}
+
+	if limit == 0 {
+		limit = repository.DefaultPageSize
+	}
+	if limit < 1 || limit > repository.MaxPageSize {
+		return refuelapi.ImplResponse{Code: http.StatusBadRequest, Body: NewErrorResponse(ctx, http.StatusBadRequest, i18n.OutOfRange, "limit", 1, repository.MaxPageSize)}, nil
+	}
+	switch type_ {
+	case "", searchComplex, searchGoal, searchAction, searchGain, searchLoss:
+	default:
+		return refuelapi.ImplResponse{Code: http.StatusBadRequest, Body: NewErrorResponse(ctx, http.StatusBadRequest, i18n.InvalidChoice, "type", "complex, goal, action, gain, loss")}, nil
+	}
+	if utf8.RuneCountInString(q) > search.MaxQueryLength {
+		return refuelapi.ImplResponse{Code: http.StatusBadRequest, Body: NewErrorResponse(ctx, http.StatusBadRequest, i18n.InvalidQuery, search.MaxQueryLength)}, nil
+	}
+	query := search.ParseQuery(q)
+	if query.Empty() {
+		return refuelapi.ImplResponse{Code: http.StatusBadRequest, Body: NewErrorResponse(ctx, http.StatusBadRequest, i18n.InvalidQuery, search.MaxQueryLength)}, nil
+	}
+	wants := func(kind string) bool { return type_ == "" || type_ == kind }
+
+	// Searching the records in memory keeps the search independent of the
+	// database's full-text features; searchPage bounds how many are read.
+	var (
+		docs []search.Document
+		refs []searchRef
+	)
+	if wants(searchComplex) {
+		complexes, _, err := s.Complexes.ListPage(ctx, userID, repository.ComplexFilter{}, searchPage)
+		if err != nil {
+			return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(ctx, http.StatusInternalServerError, i18n.FetchFailed, i18n.NounComplexes, err)}, nil
+		}
+		for _, c := range complexes {
+			docs = append(docs, search.Document{Fields: []search.Field{
+				{Name: "content", Text: c.Content},
+				{Name: "trigger_episode", Text: c.TriggerEpisode},
+			}})
+			refs = append(refs, searchRef{kind: searchComplex, id: c.ID})
+		}
+	}
+	if wants(searchGoal) {
+		goals, _, err := s.Goals.ListPage(ctx, userID, repository.GoalFilter{}, searchPage)
+		if err != nil {
+			return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(ctx, http.StatusInternalServerError, i18n.FetchFailed, i18n.NounGoals, err)}, nil
+		}
+		for _, g := range goals {
+			docs = append(docs, search.Document{Fields: []search.Field{{Name: "content", Text: g.Content}}})
+			refs = append(refs, searchRef{kind: searchGoal, id: g.ID})
+		}
+	}
+	if wants(searchAction) || wants(searchGain) || wants(searchLoss) {
+		actions, _, err := s.Actions.ListPage(ctx, userID, repository.ActionFilter{}, searchPage)
+		if err != nil {
+			return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(ctx, http.StatusInternalServerError, i18n.FetchFailed, i18n.NounActions, err)}, nil
+		}
+		for _, a := range actions {
+			if wants(searchAction) {
+				docs = append(docs, search.Document{Fields: []search.Field{{Name: "content", Text: a.Content}}})
+				refs = append(refs, searchRef{kind: searchAction, id: a.ID})
+			}
+			if wants(searchGain) {
+				for _, g := range a.Gains {
+					docs = append(docs, search.Document{Fields: []search.Field{{Name: "description", Text: g.Description}}})
+					refs = append(refs, searchRef{kind: searchGain, id: g.ID, actionID: a.ID})
+				}
+			}
+			if wants(searchLoss) {
+				for _, l := range a.Losses {
+					docs = append(docs, search.Document{Fields: []search.Field{{Name: "description", Text: l.Description}}})
+					refs = append(refs, searchRef{kind: searchLoss, id: l.ID, actionID: a.ID})
+				}
+			}
+		}
+	}
+
+	hits := search.Search(docs, query, int(limit))
+	resHits := make([]refuelapi.SearchHit, len(hits))
+	for i, hit := range hits {
+		ref := refs[hit.Index]
+		highlights := make([]refuelapi.SearchHighlight, len(hit.Highlights))
+		for j, span := range hit.Highlights {
+			highlights[j] = refuelapi.SearchHighlight{Start: int32(span.Start), End: int32(span.End)}
+		}
+		resHits[i] = refuelapi.SearchHit{
+			Type:       ref.kind,
+			Id:         int64(ref.id),
+			ActionId:   int64(ref.actionID),
+			Field:      hit.Field,
+			Score:      hit.Score,
+			Snippet:    hit.Snippet,
+			Highlights: highlights,
+		}
+	}
+
+	return refuelapi.ImplResponse{Code: http.StatusOK, Body: resHits}, nil
+}
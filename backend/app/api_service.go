@@ -2,579 +2,1325 @@ package app
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"log"
 	"net/http"
+	"net/url"
+	"strconv"
 	"time"
 
-	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
-	"gorm.io/gorm"
 
+	"refuel/backend/audit"
+	"refuel/backend/auth"
+	"refuel/backend/auth/oidc"
+	"refuel/backend/badge"
+	"refuel/backend/badge/rule"
+	"refuel/backend/checkin"
+	"refuel/backend/export"
 	refuelapi "refuel/backend/generated/go"
+	"refuel/backend/i18n"
 	"refuel/backend/models"
+	"refuel/backend/recurrence"
+	"refuel/backend/repository"
+	"refuel/backend/streak"
+	"refuel/backend/trash"
 )
 
 // Servicer is an interface that defines the methods required to implement the
 // various API endpoints.
 type Servicer interface {
 	refuelapi.ActionsAPIServicer
+	refuelapi.AuthAPIServicer
 	refuelapi.BadgesAPIServicer
 	refuelapi.ComplexesAPIServicer
+	refuelapi.ExportAPIServicer
 	refuelapi.GoalsAPIServicer
 	refuelapi.HealthAPIServicer
+	refuelapi.ImportAPIServicer
+	refuelapi.SearchAPIServicer
+	refuelapi.TrashAPIServicer
 	refuelapi.UserBadgesAPIServicer
 }
 
-// APIService implements the Servicer interface.
+// APIService implements the Servicer interface. It reaches storage only
+// through the repository interfaces.
 type APIService struct {
-	DB       *gorm.DB
-	Validate *validator.Validate
+	Complexes    repository.ComplexRepository
+	Goals        repository.GoalRepository
+	Actions      repository.ActionRepository
+	Trash        repository.TrashRepository
+	Badges       repository.BadgeRepository
+	Feedback     repository.FeedbackRepository
+	Revisions    repository.RevisionRepository
+	Exports      repository.ExportRepository
+	Imports      repository.ImportRepository
+	Users        repository.UserRepository
+	Evaluator    *badge.Evaluator
+	Validate     *validator.Validate
+	AdminUserIDs map[string]struct{}
+	Tokens       *auth.Tokens
+	RefreshTTL   time.Duration
+	TrashConfig  trash.Config
+	Exporter     *export.Worker
+	// OIDC is nil when no external identity provider is configured.
+	OIDC *oidc.Provider
 }
 
 // NewAPIService creates a new instance of APIService.
-func NewAPIService(db *gorm.DB, validate *validator.Validate) Servicer {
+func NewAPIService(appCtx *AppContext) Servicer {
+	admins := make(map[string]struct{}, len(appCtx.AdminUserIDs))
+	for _, id := range appCtx.AdminUserIDs {
+		admins[id] = struct{}{}
+	}
+	repos := appCtx.Repos
 	return &APIService{
-		DB:       db,
-		Validate: validate,
+		Complexes:    repos.Complexes,
+		Goals:        repos.Goals,
+		Actions:      repos.Actions,
+		Trash:        repos.Trash,
+		Badges:       repos.Badges,
+		Feedback:     repos.Feedback,
+		Revisions:    repos.Revisions,
+		Exports:      repos.Exports,
+		Imports:      repos.Imports,
+		Users:        repos.Users,
+		Evaluator:    badge.NewEvaluator(repos),
+		Validate:     appCtx.Validate,
+		AdminUserIDs: admins,
+		Tokens:       appCtx.Tokens,
+		RefreshTTL:   appCtx.Auth.RefreshTTL,
+		TrashConfig:  appCtx.Trash,
+		Exporter:     appCtx.Exporter,
+		OIDC:         appCtx.OIDC,
 	}
 }
 
 // GetUserIDFromContext extracts the user ID from the Gin context.
 func GetUserIDFromContext(ctx context.Context) (string, *refuelapi.ImplResponse) {
-	ginCtx, ok := ctx.Value(refuelapi.ContextGinContext).(*gin.Context)
+	ginCtx, ok := ginContext(ctx)
 	if !ok {
 		return "", &refuelapi.ImplResponse{
 			Code: http.StatusInternalServerError,
-			Body: NewErrorResponse(http.StatusInternalServerError, "Internal server error: Gin context not found"),
+			Body: NewErrorResponse(ctx, http.StatusInternalServerError, i18n.InternalError, errors.New("gin context not found")),
 		}
 	}
 	userID, exists := ginCtx.Get("userID")
 	if !exists {
 		return "", &refuelapi.ImplResponse{
 			Code: http.StatusUnauthorized,
-			Body: NewErrorResponse(http.StatusUnauthorized, "User ID not found in context"),
+			Body: NewErrorResponse(ctx, http.StatusUnauthorized, i18n.NotAuthenticated),
 		}
 	}
 	return userID.(string), nil
 }
 
+// requireAdmin returns an error response unless the current user is listed in ADMIN_USER_IDS.
+func (s APIService) requireAdmin(ctx context.Context) *refuelapi.ImplResponse {
+	userID, resp := GetUserIDFromContext(ctx)
+	if resp != nil {
+		return resp
+	}
+	if _, ok := s.AdminUserIDs[userID]; !ok {
+		return &refuelapi.ImplResponse{
+			Code: http.StatusForbidden,
+			Body: NewErrorResponse(ctx, http.StatusForbidden, i18n.AdminRequired),
+		}
+	}
+	return nil
+}
+
 // --- API Service Implementations ---
 
 // CreateAction implements the ActionsAPIServicer interface.
-func (s APIService) CreateAction(ctx context.Context, actionInput refuelapi.ActionInput) (refuelapi.ImplResponse) {
-    userID, resp := GetUserIDFromContext(ctx)
-    if resp != nil {
-        return *resp
-    }
-
-    // Validate input
-    if err := s.Validate.Struct(actionInput); err != nil {
-        return refuelapi.ImplResponse{Code: http.StatusBadRequest, Body: NewErrorResponse(http.StatusBadRequest, "Validation failed: "+err.Error())}
-    }
-
-    // Check if the referenced goal exists and belongs to the user
-    var goal models.Goal
-    if err := s.DB.Where("id = ? AND user_id = ?", actionInput.GoalId, userID).First(&goal).Error; err != nil {
-        if err == gorm.ErrRecordNotFound {
-            return refuelapi.ImplResponse{Code: http.StatusBadRequest, Body: NewErrorResponse(http.StatusBadRequest, "Referenced goal not found or does not belong to user")}
-        }
-        return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(http.StatusInternalServerError, "Error checking goal: "+err.Error())}
-    }
-
-    var completedAtParsed *time.Time
-    if actionInput.CompletedAt != nil {
-        t, err := time.Parse(time.RFC3339, *actionInput.CompletedAt)
-        if err != nil {
-            return refuelapi.ImplResponse{Code: http.StatusBadRequest, Body: NewErrorResponse(http.StatusBadRequest, "Invalid completed_at format. Use ISO8601 (RFC3339).")}
-        }
-        completedAtParsed = &t
-    }
-
-    // ... (RecurrencePatternの処理) ...
-
-    action := models.Action{
-        UserID:      userID,
-        GoalID:      uint(actionInput.GoalId),
-        Content:     actionInput.Content,
-        CompletedAt: completedAtParsed,
-    }
-
-    // Handle Gains
-    for _, gainInput := range actionInput.Gains {
-        action.Gains = append(action.Gains, models.Gain{
-            Type:        string(gainInput.Type),
-            Description: gainInput.Description,
-        })
-    }
-
-    // Handle Losses
-    for _, lossInput := range actionInput.Losses {
-        action.Losses = append(action.Losses, models.Loss{
-            Type:        string(lossInput.Type),
-            Description: lossInput.Description,
-        })
-    }
-
-    if result := s.DB.Create(&action); result.Error != nil {
-        return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(http.StatusInternalServerError, "Failed to create action: "+result.Error.Error())}
-    }
-
-    // DBモデルからAPIモデルへのマッピング
-    resAction := refuelapi.Action{
-        Id:          int64(action.ID),
-        UserId:      action.UserID,
-        GoalId:      int64(action.GoalID),
-        Content:     action.Content,
-        CompletedAt: action.CompletedAt,
-        CreatedAt:   &action.CreatedAt,
-        UpdatedAt:   &action.UpdatedAt,
-        // ... Gains, Losses, RecurrencePatternのマッピングも必要
-    }
-
-    return refuelapi.ImplResponse{Code: http.StatusCreated, Body: resAction}
+func (s APIService) CreateAction(ctx context.Context, actionInput refuelapi.ActionInput) (refuelapi.ImplResponse, error) {
+	userID, resp := GetUserIDFromContext(ctx)
+	if resp != nil {
+		return *resp, nil
+	}
+
+	// Validate input
+	if err := s.Validate.Struct(actionInput); err != nil {
+		return refuelapi.ImplResponse{Code: http.StatusBadRequest, Body: NewErrorResponse(ctx, http.StatusBadRequest, i18n.ValidationFailed, err)}, nil
+	}
+	loc, err := loadLocation(actionInput.Tz)
+	if err != nil {
+		return refuelapi.ImplResponse{Code: http.StatusBadRequest, Body: NewErrorResponse(ctx, http.StatusBadRequest, i18n.InvalidTimeZone, err)}, nil
+	}
+
+	// Check if the referenced goal exists and belongs to the user
+	if _, err := s.Goals.Get(ctx, userID, uint(actionInput.GoalId)); err != nil {
+		if err == repository.ErrNotFound {
+			return refuelapi.ImplResponse{Code: http.StatusBadRequest, Body: NewErrorResponse(ctx, http.StatusBadRequest, i18n.ReferencedGoalNotFound)}, nil
+		}
+		return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(ctx, http.StatusInternalServerError, i18n.FetchFailed, i18n.NounGoal, err)}, nil
+	}
+
+	pattern, err := toRecurrencePattern(actionInput.RecurrencePattern)
+	if err != nil {
+		return refuelapi.ImplResponse{Code: http.StatusBadRequest, Body: NewErrorResponse(ctx, http.StatusBadRequest, i18n.InvalidRecurrencePattern, err)}, nil
+	}
+
+	action := models.Action{
+		UserID:            userID,
+		GoalID:            uint(actionInput.GoalId),
+		Content:           actionInput.Content,
+		CompletedAt:       actionInput.CompletedAt,
+		RecurrencePattern: pattern,
+	}
+
+	// Handle Gains
+	for _, gainInput := range actionInput.Gains {
+		checked, err := checkOutcome(i18n.NounGain, string(gainInput.Type), gainInput.Description, gainInput.Value, gainInput.Unit)
+		if err != nil {
+			return refuelapi.ImplResponse{Code: http.StatusBadRequest, Body: NewErrorResponse(ctx, http.StatusBadRequest, i18n.ValidationFailed, err)}, nil
+		}
+		action.Gains = append(action.Gains, checked.gain(0, 0))
+	}
+
+	// Handle Losses
+	for _, lossInput := range actionInput.Losses {
+		checked, err := checkOutcome(i18n.NounLoss, string(lossInput.Type), lossInput.Description, lossInput.Value, lossInput.Unit)
+		if err != nil {
+			return refuelapi.ImplResponse{Code: http.StatusBadRequest, Body: NewErrorResponse(ctx, http.StatusBadRequest, i18n.ValidationFailed, err)}, nil
+		}
+		action.Losses = append(action.Losses, checked.loss(0, 0))
+	}
+
+	if err := s.Actions.Create(ctx, &action); err != nil {
+		return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(ctx, http.StatusInternalServerError, i18n.CreateFailed, i18n.NounAction, err)}, nil
+	}
+	s.recordRevision(ctx, userID, audit.Action, action.ID, audit.Create, nil, &action)
+
+	var reached []models.UserBadge
+	if action.CompletedAt != nil {
+		reached = s.awardBadges(ctx, userID, action.GoalID, loc)
+	}
+
+	// DBモデルからAPIモデルへのマッピング
+	resAction := refuelapi.Action{
+		Id:                int64(action.ID),
+		UserId:            action.UserID,
+		GoalId:            int64(action.GoalID),
+		Content:           action.Content,
+		CompletedAt:       action.CompletedAt,
+		RecurrencePattern: mapRecurrencePattern(action.RecurrencePattern),
+		Gains:             mapGains(action.Gains),
+		Losses:            mapLosses(action.Losses),
+		CreatedAt:         action.CreatedAt,
+		UpdatedAt:         action.UpdatedAt,
+	}
+	if action.CompletedAt != nil {
+		resAction.Feedback = s.feedbackFor(ctx, userID, action, reached, loc)
+	}
+
+	return refuelapi.ImplResponse{Code: http.StatusCreated, Body: resAction}, nil
 }
 
 // DeleteAction - 既存の行動を削除
-func (s APIService) DeleteAction(ctx context.Context, actionId int64) (refuelapi.ImplResponse) {
-    userID, resp := GetUserIDFromContext(ctx)
-    if resp != nil {
-        return *resp
-    }
+func (s APIService) DeleteAction(ctx context.Context, actionId int64) (refuelapi.ImplResponse, error) {
+	userID, resp := GetUserIDFromContext(ctx)
+	if resp != nil {
+		return *resp, nil
+	}
 
-    if result := s.DB.Where("id = ? AND user_id = ?", actionId, userID).Delete(&models.Action{}); result.Error != nil || result.RowsAffected == 0 {
-        return refuelapi.ImplResponse{Code: http.StatusNotFound, Body: NewErrorResponse(http.StatusNotFound, "Action not found or already deleted")}
-    }
-    return refuelapi.ImplResponse{Code: http.StatusNoContent}
+	action, resp := s.findAction(ctx, userID, actionId)
+	if resp != nil {
+		return *resp, nil
+	}
+	if err := s.Actions.Delete(ctx, userID, action.ID); err != nil {
+		return refuelapi.ImplResponse{Code: http.StatusNotFound, Body: NewErrorResponse(ctx, http.StatusNotFound, i18n.ActionNotFound)}, nil
+	}
+	s.recordRevision(ctx, userID, audit.Action, action.ID, audit.Delete, action, nil)
+	return refuelapi.ImplResponse{Code: http.StatusNoContent}, nil
 }
 
 // GetActions - 指定された目標IDに紐づく行動の一覧を取得
-func (s APIService) GetActions(ctx context.Context, goalId int64) (refuelapi.ImplResponse) {
-    userID, resp := GetUserIDFromContext(ctx)
-    if resp != nil {
-        return *resp
-    }
-
-    // Ensure the goal belongs to the user to prevent fetching actions for other users' goals
-    var goal models.Goal
-    if err := s.DB.Where("id = ? AND user_id = ?", goalId, userID).First(&goal).Error; err != nil {
-        if err == gorm.ErrRecordNotFound {
-            return refuelapi.ImplResponse{Code: http.StatusNotFound, Body: NewErrorResponse(http.StatusNotFound, "Goal not found or does not belong to user")}
-        }
-        return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(http.StatusInternalServerError, "Error verifying goal: "+err.Error())}
-    }
-
-    var actions []models.Action
-    if result := s.DB.Where("goal_id = ? AND user_id = ?", goalId, userID).Order("created_at DESC").Find(&actions); result.Error != nil {
-        return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(http.StatusInternalServerError, "Failed to fetch actions: "+result.Error.Error())}
-    }
-
-    if actions == nil {
-        actions = []models.Action{}
-    }
-
-    // Map internal Action models to generated refuelapi.Action models
-    resActions := make([]refuelapi.Action, len(actions))
-    for i, action := range actions {
-        resActions[i] = refuelapi.Action{
-            Id:          int64(action.ID),
-            UserId:      action.UserID,
-            GoalId:      int64(action.GoalID),
-            Content:     action.Content,
-            CompletedAt: action.CompletedAt,
-            CreatedAt:   &action.CreatedAt,
-            UpdatedAt:   &action.UpdatedAt,
-            // RecurrencePattern: mapRecurrencePattern(action.RecurrencePattern),
-            // Gains: mapGains(action.Gains),
-            // Losses: mapLosses(action.Losses),
-        }
-    }
-
-    return refuelapi.ImplResponse{Code: http.StatusOK, Body: resActions}
+func (s APIService) GetActions(ctx context.Context, goalId int64, limit int32, cursor string, sort string, status string, from string, to string, tz string) (refuelapi.ImplResponse, error) {
+	userID, resp := GetUserIDFromContext(ctx)
+	if resp != nil {
+		return *resp, nil
+	}
+
+	page, resp := pageRequest(ctx, limit, cursor, sort, repository.ActionSortFields, "-created_at")
+	if resp != nil {
+		return *resp, nil
+	}
+	// Without goal_id, the actions of all the user's goals are listed.
+	filter := repository.ActionFilter{GoalID: uint(goalId)}
+	query := url.Values{}
+	if goalId != 0 {
+		query.Set("goal_id", strconv.FormatInt(goalId, 10))
+	}
+	switch status {
+	case "":
+	case "completed", "uncompleted":
+		completed := status == "completed"
+		filter.Completed = &completed
+		query.Set("status", status)
+	default:
+		return refuelapi.ImplResponse{Code: http.StatusBadRequest, Body: NewErrorResponse(ctx, http.StatusBadRequest, i18n.InvalidChoice, "status", "completed, uncompleted")}, nil
+	}
+	if from != "" || to != "" {
+		loc, err := loadLocation(tz)
+		if err != nil {
+			return refuelapi.ImplResponse{Code: http.StatusBadRequest, Body: NewErrorResponse(ctx, http.StatusBadRequest, i18n.InvalidTimeZone, err)}, nil
+		}
+		if tz != "" {
+			query.Set("tz", tz)
+		}
+		if from != "" {
+			fromDate, err := checkin.ParseDate(from)
+			if err != nil {
+				return refuelapi.ImplResponse{Code: http.StatusBadRequest, Body: NewErrorResponse(ctx, http.StatusBadRequest, i18n.InvalidDate, "from")}, nil
+			}
+			filter.CompletedFrom = checkin.InLocation(fromDate, loc)
+			query.Set("from", from)
+		}
+		if to != "" {
+			toDate, err := checkin.ParseDate(to)
+			if err != nil {
+				return refuelapi.ImplResponse{Code: http.StatusBadRequest, Body: NewErrorResponse(ctx, http.StatusBadRequest, i18n.InvalidDate, "to")}, nil
+			}
+			// to is inclusive: keep everything before the next midnight.
+			filter.CompletedTo = checkin.InLocation(toDate.AddDate(0, 0, 1), loc)
+			query.Set("to", to)
+		}
+		if !filter.CompletedFrom.IsZero() && !filter.CompletedTo.IsZero() && !filter.CompletedFrom.Before(filter.CompletedTo) {
+			return refuelapi.ImplResponse{Code: http.StatusBadRequest, Body: NewErrorResponse(ctx, http.StatusBadRequest, i18n.DateRangeOrder)}, nil
+		}
+	}
+
+	// Ensure the goal belongs to the user to prevent fetching actions for other users' goals
+	if goalId != 0 {
+		if _, err := s.Goals.Get(ctx, userID, uint(goalId)); err != nil {
+			if err == repository.ErrNotFound {
+				return refuelapi.ImplResponse{Code: http.StatusNotFound, Body: NewErrorResponse(ctx, http.StatusNotFound, i18n.GoalNotFound)}, nil
+			}
+			return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(ctx, http.StatusInternalServerError, i18n.FetchFailed, i18n.NounGoal, err)}, nil
+		}
+	}
+
+	actions, next, err := s.Actions.ListPage(ctx, userID, filter, page)
+	if err != nil {
+		return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(ctx, http.StatusInternalServerError, i18n.FetchFailed, i18n.NounActions, err)}, nil
+	}
+
+	// Map internal Action models to generated refuelapi.Action models
+	resActions := make([]refuelapi.Action, len(actions))
+	for i, action := range actions {
+		resActions[i] = refuelapi.Action{
+			Id:                int64(action.ID),
+			UserId:            action.UserID,
+			GoalId:            int64(action.GoalID),
+			Content:           action.Content,
+			CompletedAt:       action.CompletedAt,
+			CreatedAt:         action.CreatedAt,
+			UpdatedAt:         action.UpdatedAt,
+			RecurrencePattern: mapRecurrencePattern(action.RecurrencePattern),
+			Gains:             mapGains(action.Gains),
+			Losses:            mapLosses(action.Losses),
+		}
+	}
+
+	return pageResponse("/api/v1/actions", query, page, next, resActions), nil
 }
 
 // UpdateAction - 既存の行動情報を更新
-func (s APIService) UpdateAction(ctx context.Context, actionId int64, actionUpdateInput refuelapi.ActionUpdateInput) (refuelapi.ImplResponse) {
-    userID, resp := GetUserIDFromContext(ctx)
-    if resp != nil {
-        return *resp
-    }
-
-    var action models.Action
-    if err := s.DB.Where("id = ? AND user_id = ?", actionId, userID).First(&action).Error; err != nil {
-        if err == gorm.ErrRecordNotFound {
-            return refuelapi.ImplResponse{Code: http.StatusNotFound, Body: NewErrorResponse(http.StatusNotFound, "Action not found")}
-        }
-        return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(http.StatusInternalServerError, "Failed to fetch action: "+err.Error())}
-    }
-
-    // Update fields if provided
-    if actionUpdateInput.Content != nil {
-        action.Content = *actionUpdateInput.Content
-    }
-    if actionUpdateInput.CompletedAt != nil {
-        t, err := time.Parse(time.RFC3339, *actionUpdateInput.CompletedAt)
-        if err != nil {
-            return refuelapi.ImplResponse{Code: http.StatusBadRequest, Body: NewErrorResponse(http.StatusBadRequest, "Invalid completed_at format. Use ISO8601 (RFC3339).")}
-        }
-        action.CompletedAt = &t
-    } else {
-        // If completed_at is explicitly null in input, set to null
-        // This depends on how ActionUpdateInput is generated.
-        // If nullable is true, it might be a pointer.
-        // For now, assuming if it's not provided, we don't change it.
-        // If client sends "completed_at": null, you need to handle it.
-    }
-
-    if result := s.DB.Save(&action); result.Error != nil {
-        return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(http.StatusInternalServerError, "Failed to update action: "+result.Error.Error())}
-    }
-
-    // Map internal Action model to generated refuelapi.Action model for response
-    resAction := refuelapi.Action{
-        Id:          int64(action.ID),
-        UserId:      action.UserID,
-        GoalId:      int64(action.GoalID),
-        Content:     action.Content,
-        CompletedAt: action.CompletedAt,
-        CreatedAt:   &action.CreatedAt,
-        UpdatedAt:   &action.UpdatedAt,
-        // RecurrencePattern: mapRecurrencePattern(action.RecurrencePattern),
-        // Gains: mapGains(action.Gains),
-        // Losses: mapLosses(action.Losses),
-    }
-
-    return refuelapi.ImplResponse{Code: http.StatusOK, Body: resAction}
+func (s APIService) UpdateAction(ctx context.Context, actionId int64, actionUpdateInput refuelapi.ActionUpdateInput) (refuelapi.ImplResponse, error) {
+	userID, resp := GetUserIDFromContext(ctx)
+	if resp != nil {
+		return *resp, nil
+	}
+	loc, err := loadLocation(actionUpdateInput.Tz)
+	if err != nil {
+		return refuelapi.ImplResponse{Code: http.StatusBadRequest, Body: NewErrorResponse(ctx, http.StatusBadRequest, i18n.InvalidTimeZone, err)}, nil
+	}
+
+	action, err := s.Actions.Get(ctx, userID, uint(actionId))
+	if err != nil {
+		if err == repository.ErrNotFound {
+			return refuelapi.ImplResponse{Code: http.StatusNotFound, Body: NewErrorResponse(ctx, http.StatusNotFound, i18n.ActionNotFound)}, nil
+		}
+		return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(ctx, http.StatusInternalServerError, i18n.FetchFailed, i18n.NounAction, err)}, nil
+	}
+
+	// Badges and feedback are given only when this update completes the action.
+	wasCompleted := action.CompletedAt != nil
+	before := *action
+
+	// Update fields if provided
+	if actionUpdateInput.Content != "" {
+		action.Content = actionUpdateInput.Content
+	}
+	// The decoded input cannot tell completed_at: null from a missing
+	// field, so a completion is never cleared here.
+	if actionUpdateInput.CompletedAt != nil {
+		action.CompletedAt = actionUpdateInput.CompletedAt
+	}
+	if actionUpdateInput.RecurrencePattern != nil {
+		pattern, err := toRecurrencePattern(actionUpdateInput.RecurrencePattern)
+		if err != nil {
+			return refuelapi.ImplResponse{Code: http.StatusBadRequest, Body: NewErrorResponse(ctx, http.StatusBadRequest, i18n.InvalidRecurrencePattern, err)}, nil
+		}
+		action.RecurrencePattern = pattern
+	}
+	gainsLosses, resp := parseGainsLosses(ctx, action.ID, actionUpdateInput)
+	if resp != nil {
+		return *resp, nil
+	}
+
+	if err := s.Actions.Update(ctx, action); err != nil {
+		return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(ctx, http.StatusInternalServerError, i18n.UpdateFailed, i18n.NounAction, err)}, nil
+	}
+	if resp := s.saveGainsLosses(ctx, action.ID, gainsLosses); resp != nil {
+		return *resp, nil
+	}
+	if gainsLosses.gains != nil || gainsLosses.losses != nil {
+		// Reload so the response carries the IDs of newly created gains and losses.
+		if action, resp = s.findAction(ctx, userID, actionId); resp != nil {
+			return *resp, nil
+		}
+	}
+	s.recordRevision(ctx, userID, audit.Action, action.ID, audit.Update, &before, action)
+
+	var reached []models.UserBadge
+	if action.CompletedAt != nil && !wasCompleted {
+		reached = s.awardBadges(ctx, userID, action.GoalID, loc)
+	}
+
+	// Map internal Action model to generated refuelapi.Action model for response
+	resAction := refuelapi.Action{
+		Id:                int64(action.ID),
+		UserId:            action.UserID,
+		GoalId:            int64(action.GoalID),
+		Content:           action.Content,
+		CompletedAt:       action.CompletedAt,
+		CreatedAt:         action.CreatedAt,
+		UpdatedAt:         action.UpdatedAt,
+		RecurrencePattern: mapRecurrencePattern(action.RecurrencePattern),
+		Gains:             mapGains(action.Gains),
+		Losses:            mapLosses(action.Losses),
+	}
+	if action.CompletedAt != nil && !wasCompleted {
+		resAction.Feedback = s.feedbackFor(ctx, userID, *action, reached, loc)
+	}
+
+	return refuelapi.ImplResponse{Code: http.StatusOK, Body: resAction}, nil
+}
+
+// GetActionOccurrences - 繰り返しパターンから行動の次回以降の実施予定を取得
+func (s APIService) GetActionOccurrences(ctx context.Context, actionId int64, count int32, from time.Time, tz string) (refuelapi.ImplResponse, error) {
+	userID, resp := GetUserIDFromContext(ctx)
+	if resp != nil {
+		return *resp, nil
+	}
+
+	if count == 0 {
+		count = 5
+	}
+	if count < 1 || count > 100 {
+		return refuelapi.ImplResponse{Code: http.StatusBadRequest, Body: NewErrorResponse(ctx, http.StatusBadRequest, i18n.OutOfRange, "count", 1, 100)}, nil
+	}
+	loc, err := loadLocation(tz)
+	if err != nil {
+		return refuelapi.ImplResponse{Code: http.StatusBadRequest, Body: NewErrorResponse(ctx, http.StatusBadRequest, i18n.InvalidTimeZone, err)}, nil
+	}
+	if from.IsZero() {
+		from = time.Now()
+	}
+
+	action, err := s.Actions.Get(ctx, userID, uint(actionId))
+	if err != nil {
+		if err == repository.ErrNotFound {
+			return refuelapi.ImplResponse{Code: http.StatusNotFound, Body: NewErrorResponse(ctx, http.StatusNotFound, i18n.ActionNotFound)}, nil
+		}
+		return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(ctx, http.StatusInternalServerError, i18n.FetchFailed, i18n.NounAction, err)}, nil
+	}
+	if action.RecurrencePattern == nil {
+		return refuelapi.ImplResponse{Code: http.StatusBadRequest, Body: NewErrorResponse(ctx, http.StatusBadRequest, i18n.NotRecurring)}, nil
+	}
+
+	schedule, err := recurrence.NewSchedule(*action.RecurrencePattern, action.CreatedAt, loc)
+	if err != nil {
+		return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(ctx, http.StatusInternalServerError, i18n.StoredRecurrenceInvalid, err)}, nil
+	}
+
+	occurrences := schedule.Next(from, int(count))
+	resOccurrences := make([]refuelapi.ActionOccurrence, len(occurrences))
+	for i, at := range occurrences {
+		resOccurrences[i] = refuelapi.ActionOccurrence{ActionId: int64(action.ID), ScheduledAt: at}
+	}
+
+	return refuelapi.ImplResponse{Code: http.StatusOK, Body: resOccurrences}, nil
+}
+
+// GetActionCheckins - 期間内の実施予定ごとの実施状況を取得
+func (s APIService) GetActionCheckins(ctx context.Context, actionId int64, from string, to string, tz string) (refuelapi.ImplResponse, error) {
+	userID, resp := GetUserIDFromContext(ctx)
+	if resp != nil {
+		return *resp, nil
+	}
+
+	loc, err := loadLocation(tz)
+	if err != nil {
+		return refuelapi.ImplResponse{Code: http.StatusBadRequest, Body: NewErrorResponse(ctx, http.StatusBadRequest, i18n.InvalidTimeZone, err)}, nil
+	}
+
+	now := time.Now()
+	toDate := checkin.Civil(now, loc)
+	if to != "" {
+		if toDate, err = checkin.ParseDate(to); err != nil {
+			return refuelapi.ImplResponse{Code: http.StatusBadRequest, Body: NewErrorResponse(ctx, http.StatusBadRequest, i18n.InvalidDate, "to")}, nil
+		}
+	}
+	fromDate := toDate.AddDate(0, 0, -30)
+	if from != "" {
+		if fromDate, err = checkin.ParseDate(from); err != nil {
+			return refuelapi.ImplResponse{Code: http.StatusBadRequest, Body: NewErrorResponse(ctx, http.StatusBadRequest, i18n.InvalidDate, "from")}, nil
+		}
+	}
+	if fromDate.After(toDate) {
+		return refuelapi.ImplResponse{Code: http.StatusBadRequest, Body: NewErrorResponse(ctx, http.StatusBadRequest, i18n.DateRangeOrder)}, nil
+	}
+	if toDate.Sub(fromDate) > maxCheckinRangeDays*24*time.Hour {
+		return refuelapi.ImplResponse{Code: http.StatusBadRequest, Body: NewErrorResponse(ctx, http.StatusBadRequest, i18n.DateRangeTooLong, maxCheckinRangeDays)}, nil
+	}
+
+	action, err := s.Actions.Get(ctx, userID, uint(actionId))
+	if err != nil {
+		if err == repository.ErrNotFound {
+			return refuelapi.ImplResponse{Code: http.StatusNotFound, Body: NewErrorResponse(ctx, http.StatusNotFound, i18n.ActionNotFound)}, nil
+		}
+		return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(ctx, http.StatusInternalServerError, i18n.FetchFailed, i18n.NounAction, err)}, nil
+	}
+
+	schedule, err := actionSchedule(*action, loc)
+	if err != nil {
+		return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(ctx, http.StatusInternalServerError, i18n.StoredRecurrenceInvalid, err)}, nil
+	}
+
+	completions, err := s.Actions.ListCompletions(ctx, []uint{action.ID}, fromDate, toDate)
+	if err != nil {
+		return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(ctx, http.StatusInternalServerError, i18n.FetchFailed, i18n.NounCheckins, err)}, nil
+	}
+
+	timeline := checkin.Timeline(schedule, completions, fromDate, toDate, now)
+	resCheckins := make([]refuelapi.ActionCheckin, len(timeline))
+	for i, occ := range timeline {
+		resCheckins[i] = mapCheckin(action.ID, occ)
+	}
+
+	return refuelapi.ImplResponse{Code: http.StatusOK, Body: resCheckins}, nil
+}
+
+// CheckinAction - 行動の実施予定に実施状況を記録 (チェックイン)
+func (s APIService) CheckinAction(ctx context.Context, actionId int64, checkinInput refuelapi.ActionCheckinInput) (refuelapi.ImplResponse, error) {
+	userID, resp := GetUserIDFromContext(ctx)
+	if resp != nil {
+		return *resp, nil
+	}
+
+	status := checkinInput.Status
+	if status == "" {
+		status = models.CompletionDone
+	}
+	switch status {
+	case models.CompletionDone, models.CompletionSkipped, models.CompletionMissed:
+	default:
+		return refuelapi.ImplResponse{Code: http.StatusBadRequest, Body: NewErrorResponse(ctx, http.StatusBadRequest, i18n.InvalidChoice, "status", "done, skipped, missed")}, nil
+	}
+	date, err := checkin.ParseDate(checkinInput.Date)
+	if err != nil {
+		return refuelapi.ImplResponse{Code: http.StatusBadRequest, Body: NewErrorResponse(ctx, http.StatusBadRequest, i18n.InvalidDate, "date")}, nil
+	}
+	loc, err := loadLocation(checkinInput.Tz)
+	if err != nil {
+		return refuelapi.ImplResponse{Code: http.StatusBadRequest, Body: NewErrorResponse(ctx, http.StatusBadRequest, i18n.InvalidTimeZone, err)}, nil
+	}
+
+	action, err := s.Actions.Get(ctx, userID, uint(actionId))
+	if err != nil {
+		if err == repository.ErrNotFound {
+			return refuelapi.ImplResponse{Code: http.StatusNotFound, Body: NewErrorResponse(ctx, http.StatusNotFound, i18n.ActionNotFound)}, nil
+		}
+		return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(ctx, http.StatusInternalServerError, i18n.FetchFailed, i18n.NounAction, err)}, nil
+	}
+
+	schedule, err := actionSchedule(*action, loc)
+	if err != nil {
+		return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(ctx, http.StatusInternalServerError, i18n.StoredRecurrenceInvalid, err)}, nil
+	}
+	// Recurring actions can only be checked in on days the schedule covers.
+	if schedule != nil && !schedule.OccursOn(checkin.InLocation(date, loc)) {
+		return refuelapi.ImplResponse{Code: http.StatusBadRequest, Body: NewErrorResponse(ctx, http.StatusBadRequest, i18n.NotScheduled)}, nil
+	}
+	now := time.Now()
+	if status == models.CompletionDone && date.After(checkin.Civil(now, loc)) {
+		return refuelapi.ImplResponse{Code: http.StatusBadRequest, Body: NewErrorResponse(ctx, http.StatusBadRequest, i18n.FutureCompletion)}, nil
+	}
+
+	var completedAt *time.Time
+	if status == models.CompletionDone {
+		t := now
+		if !checkinInput.CompletedAt.IsZero() {
+			t = checkinInput.CompletedAt
+		}
+		completedAt = &t
+	}
+
+	code := http.StatusOK
+	var before *models.ActionCompletion
+	completion, err := s.Actions.GetCompletion(ctx, action.ID, date)
+	switch {
+	case err == repository.ErrNotFound:
+		code = http.StatusCreated
+		completion = &models.ActionCompletion{
+			ActionID:       action.ID,
+			UserID:         userID,
+			OccurrenceDate: date,
+		}
+	case err != nil:
+		return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(ctx, http.StatusInternalServerError, i18n.FetchFailed, i18n.NounCheckin, err)}, nil
+	default:
+		previous := *completion
+		before = &previous
+	}
+	wasDone := completion.Status == models.CompletionDone
+	completion.Status = status
+	completion.CompletedAt = completedAt
+	completion.Note = checkinInput.Note
+
+	if err := s.Actions.SaveCompletion(ctx, completion); err != nil {
+		return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(ctx, http.StatusInternalServerError, i18n.SaveFailed, i18n.NounCheckin, err)}, nil
+	}
+	if before == nil {
+		s.recordRevision(ctx, userID, audit.Checkin, completion.ID, audit.Create, nil, completion)
+	} else {
+		s.recordRevision(ctx, userID, audit.Checkin, completion.ID, audit.Update, before, completion)
+	}
+
+	var reached []models.UserBadge
+	if status == models.CompletionDone {
+		reached = s.awardBadges(ctx, userID, action.GoalID, loc)
+	}
+
+	occ := checkin.Occurrence{Date: date, Status: checkin.Status(status), Completion: completion}
+	if schedule != nil {
+		at := schedule.At(checkin.InLocation(date, loc))
+		occ.ScheduledAt = &at
+	}
+	res := mapCheckin(action.ID, occ)
+	if status == models.CompletionDone && !wasDone {
+		res.Feedback = s.feedbackFor(ctx, userID, *action, reached, loc)
+	}
+	return refuelapi.ImplResponse{Code: code, Body: res}, nil
+}
+
+// DeleteActionCheckin - 実施状況の記録を取り消し
+func (s APIService) DeleteActionCheckin(ctx context.Context, actionId int64, date string) (refuelapi.ImplResponse, error) {
+	userID, resp := GetUserIDFromContext(ctx)
+	if resp != nil {
+		return *resp, nil
+	}
+
+	day, err := checkin.ParseDate(date)
+	if err != nil {
+		return refuelapi.ImplResponse{Code: http.StatusBadRequest, Body: NewErrorResponse(ctx, http.StatusBadRequest, i18n.InvalidDate, "date")}, nil
+	}
+
+	completion, err := s.Actions.GetCompletion(ctx, uint(actionId), day)
+	if err == nil && completion.UserID != userID {
+		err = repository.ErrNotFound
+	}
+	if err != nil {
+		if err == repository.ErrNotFound {
+			return refuelapi.ImplResponse{Code: http.StatusNotFound, Body: NewErrorResponse(ctx, http.StatusNotFound, i18n.CheckinNotFound)}, nil
+		}
+		return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(ctx, http.StatusInternalServerError, i18n.FetchFailed, i18n.NounCheckin, err)}, nil
+	}
+
+	if err := s.Actions.DeleteCompletion(ctx, userID, uint(actionId), day); err != nil {
+		return refuelapi.ImplResponse{Code: http.StatusNotFound, Body: NewErrorResponse(ctx, http.StatusNotFound, i18n.CheckinNotFound)}, nil
+	}
+	s.recordRevision(ctx, userID, audit.Checkin, completion.ID, audit.Delete, completion, nil)
+	return refuelapi.ImplResponse{Code: http.StatusNoContent}, nil
+}
+
+// GetActionStreak - 繰り返し行動の連続記録と実施率を取得
+func (s APIService) GetActionStreak(ctx context.Context, actionId int64, window int32, tz string) (refuelapi.ImplResponse, error) {
+	userID, resp := GetUserIDFromContext(ctx)
+	if resp != nil {
+		return *resp, nil
+	}
+
+	if window == 0 {
+		window = streak.DefaultWindowDays
+	}
+	if window < 1 || window > maxCheckinRangeDays {
+		return refuelapi.ImplResponse{Code: http.StatusBadRequest, Body: NewErrorResponse(ctx, http.StatusBadRequest, i18n.OutOfRange, "window", 1, maxCheckinRangeDays)}, nil
+	}
+	loc, err := loadLocation(tz)
+	if err != nil {
+		return refuelapi.ImplResponse{Code: http.StatusBadRequest, Body: NewErrorResponse(ctx, http.StatusBadRequest, i18n.InvalidTimeZone, err)}, nil
+	}
+
+	action, err := s.Actions.Get(ctx, userID, uint(actionId))
+	if err != nil {
+		if err == repository.ErrNotFound {
+			return refuelapi.ImplResponse{Code: http.StatusNotFound, Body: NewErrorResponse(ctx, http.StatusNotFound, i18n.ActionNotFound)}, nil
+		}
+		return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(ctx, http.StatusInternalServerError, i18n.FetchFailed, i18n.NounAction, err)}, nil
+	}
+	if action.RecurrencePattern == nil {
+		return refuelapi.ImplResponse{Code: http.StatusBadRequest, Body: NewErrorResponse(ctx, http.StatusBadRequest, i18n.NotRecurring)}, nil
+	}
+
+	summary, err := s.streakSummary(ctx, []models.Action{*action}, loc, int(window))
+	if err != nil {
+		return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(ctx, http.StatusInternalServerError, i18n.ComputeFailed, i18n.NounStreak, err)}, nil
+	}
+
+	return refuelapi.ImplResponse{Code: http.StatusOK, Body: mapStreakSummary(summary)}, nil
+}
+
+// streakSummary computes the combined streak of the recurring actions among actions.
+// It returns nil if none of them recur.
+func (s APIService) streakSummary(ctx context.Context, actions []models.Action, loc *time.Location, window int) (*streak.Summary, error) {
+	var tracks []streak.Track
+	var actionIDs []uint
+	trackOf := map[uint]int{}
+	for _, action := range actions {
+		schedule, err := actionSchedule(action, loc)
+		if err != nil {
+			return nil, fmt.Errorf("action %d has an invalid recurrence pattern: %w", action.ID, err)
+		}
+		if schedule == nil {
+			continue
+		}
+		trackOf[action.ID] = len(tracks)
+		tracks = append(tracks, streak.Track{Schedule: *schedule})
+		actionIDs = append(actionIDs, action.ID)
+	}
+	if len(tracks) == 0 {
+		return nil, nil
+	}
+
+	completions, err := s.Actions.ListCompletions(ctx, actionIDs, time.Time{}, time.Time{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch check-ins: %w", err)
+	}
+	for _, c := range completions {
+		i := trackOf[c.ActionID]
+		tracks[i].Completions = append(tracks[i].Completions, c)
+	}
+
+	summary := streak.Compute(tracks, time.Now(), loc, window)
+	return &summary, nil
+}
+
+// mapStreakSummary maps a streak.Summary to the generated refuelapi.StreakSummary model.
+func mapStreakSummary(summary *streak.Summary) *refuelapi.StreakSummary {
+	if summary == nil {
+		return nil
+	}
+	res := &refuelapi.StreakSummary{
+		CurrentStreak:  int32(summary.Current),
+		LongestStreak:  int32(summary.Longest),
+		CompletionRate: summary.Rate,
+		WindowDays:     int32(summary.WindowDays),
+		ScheduledCount: int32(summary.Scheduled),
+		CompletedCount: int32(summary.Completed),
+	}
+	if summary.LastCompleted != nil {
+		res.LastCompletedDate = checkin.Key(*summary.LastCompleted)
+	}
+	return res
+}
+
+// maxCheckinRangeDays bounds the date range GetActionCheckins expands.
+const maxCheckinRangeDays = 366
+
+// actionSchedule returns the action's schedule in loc, or nil if it does not recur.
+func actionSchedule(action models.Action, loc *time.Location) (*recurrence.Schedule, error) {
+	if action.RecurrencePattern == nil {
+		return nil, nil
+	}
+	schedule, err := recurrence.NewSchedule(*action.RecurrencePattern, action.CreatedAt, loc)
+	if err != nil {
+		return nil, err
+	}
+	return &schedule, nil
+}
+
+// mapCheckin maps a timeline occurrence to the generated refuelapi.ActionCheckin model.
+func mapCheckin(actionID uint, occ checkin.Occurrence) refuelapi.ActionCheckin {
+	res := refuelapi.ActionCheckin{
+		ActionId:    int64(actionID),
+		Date:        checkin.Key(occ.Date),
+		ScheduledAt: occ.ScheduledAt,
+		Status:      string(occ.Status),
+	}
+	if occ.Completion != nil {
+		res.CompletedAt = occ.Completion.CompletedAt
+		res.Note = occ.Completion.Note
+	}
+	return res
+}
+
+// toRecurrencePattern validates an API recurrence pattern and converts it for storage.
+func toRecurrencePattern(in *refuelapi.RecurrencePattern) (*recurrence.Pattern, error) {
+	if in == nil {
+		return nil, nil
+	}
+	p := recurrence.Pattern{
+		Frequency:  recurrence.Frequency(in.Frequency),
+		Interval:   int(in.Interval),
+		TimeOfDay:  in.TimeOfDay,
+		DaysOfWeek: in.DaysOfWeek,
+		DayOfMonth: int(in.DayOfMonth),
+	}.Normalize()
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// mapRecurrencePattern maps a stored recurrence pattern to the generated refuelapi.RecurrencePattern model.
+func mapRecurrencePattern(p *recurrence.Pattern) *refuelapi.RecurrencePattern {
+	if p == nil {
+		return nil
+	}
+	return &refuelapi.RecurrencePattern{
+		Frequency:  string(p.Frequency),
+		Interval:   int32(p.Interval),
+		TimeOfDay:  p.TimeOfDay,
+		DaysOfWeek: p.DaysOfWeek,
+		DayOfMonth: int32(p.DayOfMonth),
+	}
+}
+
+// loadLocation resolves an IANA time zone name, defaulting to UTC when empty.
+func loadLocation(tz string) (*time.Location, error) {
+	if tz == "" {
+		return time.UTC, nil
+	}
+	return time.LoadLocation(tz)
 }
 
 // GetBadges - 利用可能なバッジの一覧を取得
-func (s APIService) GetBadges(ctx context.Context) (refuelapi.ImplResponse) {
-	// TODO - implement GetBadges
-	return refuelapi.ImplResponse{Code: http.StatusNotImplemented}
+func (s APIService) GetBadges(ctx context.Context) (refuelapi.ImplResponse, error) {
+	badges, err := s.Badges.List(ctx)
+	if err != nil {
+		return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(ctx, http.StatusInternalServerError, i18n.FetchFailed, i18n.NounBadges, err)}, nil
+	}
+
+	resBadges := make([]refuelapi.Badge, len(badges))
+	for i, b := range badges {
+		resBadges[i] = mapBadge(b)
+	}
+
+	return refuelapi.ImplResponse{Code: http.StatusOK, Body: resBadges}, nil
+}
+
+// CreateBadge - 新しいバッジを登録 (管理者のみ)
+func (s APIService) CreateBadge(ctx context.Context, badgeInput refuelapi.BadgeInput) (refuelapi.ImplResponse, error) {
+	if resp := s.requireAdmin(ctx); resp != nil {
+		return *resp, nil
+	}
+
+	if _, err := rule.Compile(badgeInput.Rule); err != nil {
+		return refuelapi.ImplResponse{Code: http.StatusBadRequest, Body: NewErrorResponse(ctx, http.StatusBadRequest, i18n.InvalidBadgeRule, err)}, nil
+	}
+
+	b := models.Badge{
+		Code:        badgeInput.Code,
+		Name:        badgeInput.Name,
+		Description: badgeInput.Description,
+		IconURL:     badgeInput.IconUrl,
+		Rule:        badgeInput.Rule,
+	}
+	if err := s.Badges.Create(ctx, &b); err != nil {
+		if err == repository.ErrConflict {
+			return refuelapi.ImplResponse{Code: http.StatusConflict, Body: NewErrorResponse(ctx, http.StatusConflict, i18n.BadgeCodeTaken)}, nil
+		}
+		return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(ctx, http.StatusInternalServerError, i18n.CreateFailed, i18n.NounBadge, err)}, nil
+	}
+	// Badges belong to no user: the revision goes to the admin who made it.
+	adminID, _ := GetUserIDFromContext(ctx)
+	s.recordRevision(ctx, adminID, audit.Badge, b.ID, audit.Create, nil, &b)
+
+	return refuelapi.ImplResponse{Code: http.StatusCreated, Body: mapBadge(b)}, nil
+}
+
+// DryRunBadgeRule - バッジのルール式をユーザーの行動履歴に対して試験評価 (管理者のみ)
+func (s APIService) DryRunBadgeRule(ctx context.Context, dryRunInput refuelapi.BadgeRuleDryRunInput) (refuelapi.ImplResponse, error) {
+	if resp := s.requireAdmin(ctx); resp != nil {
+		return *resp, nil
+	}
+
+	r, err := rule.Compile(dryRunInput.Rule)
+	if err != nil {
+		return refuelapi.ImplResponse{Code: http.StatusBadRequest, Body: NewErrorResponse(ctx, http.StatusBadRequest, i18n.InvalidBadgeRule, err)}, nil
+	}
+	loc, err := loadLocation(dryRunInput.Tz)
+	if err != nil {
+		return refuelapi.ImplResponse{Code: http.StatusBadRequest, Body: NewErrorResponse(ctx, http.StatusBadRequest, i18n.InvalidTimeZone, err)}, nil
+	}
+	history, err := s.Evaluator.LoadHistory(ctx, dryRunInput.UserId, loc)
+	if err != nil {
+		return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(ctx, http.StatusInternalServerError, i18n.FetchFailed, i18n.NounHistory, err)}, nil
+	}
+	result := r.Explain(history)
+
+	conditions := make([]refuelapi.BadgeRuleConditionResult, len(result.Conditions))
+	for i, c := range result.Conditions {
+		conditions[i] = refuelapi.BadgeRuleConditionResult{
+			Expression: c.Expression,
+			Value:      int64(c.Value),
+			Matched:    c.Matched,
+		}
+	}
+
+	return refuelapi.ImplResponse{Code: http.StatusOK, Body: refuelapi.BadgeRuleDryRunResult{Matched: result.Matched, Conditions: conditions}}, nil
 }
 
 // GetUserBadges - 認証ユーザーが獲得したバッジの一覧を取得
-func (s APIService) GetUserBadges(ctx context.Context) (refuelapi.ImplResponse) {
-	// TODO - implement GetUserBadges
-	return refuelapi.ImplResponse{Code: http.StatusNotImplemented}
+func (s APIService) GetUserBadges(ctx context.Context) (refuelapi.ImplResponse, error) {
+	userID, resp := GetUserIDFromContext(ctx)
+	if resp != nil {
+		return *resp, nil
+	}
+
+	userBadges, err := s.Badges.ListUserBadges(ctx, userID)
+	if err != nil {
+		return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(ctx, http.StatusInternalServerError, i18n.FetchFailed, i18n.NounUserBadges, err)}, nil
+	}
+
+	resUserBadges := make([]refuelapi.UserBadge, len(userBadges))
+	for i, ub := range userBadges {
+		resUserBadges[i] = refuelapi.UserBadge{
+			UserId:     ub.UserID,
+			Badge:      mapBadge(ub.Badge),
+			AchievedAt: ub.AchievedAt,
+		}
+		if ub.Milestone != nil {
+			milestone := mapMilestone(*ub.Milestone)
+			resUserBadges[i].Milestone = &milestone
+		}
+	}
+
+	return refuelapi.ImplResponse{Code: http.StatusOK, Body: resUserBadges}, nil
 }
 
-// GetComplexes - 登録されているコンプレックスの一覧を取得
-func (s APIService) GetComplexes(ctx context.Context) (refuelapi.ImplResponse) {
-    userID, resp := GetUserIDFromContext(ctx)
-    if resp != nil {
-        return *resp
-    }
-
-    var complexes []models.Complex
-    if result := s.DB.Where("user_id = ?", userID).Find(&complexes); result.Error != nil {
-        return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(http.StatusInternalServerError, "Failed to fetch complexes: "+result.Error.Error())}
-    }
-
-    if complexes == nil {
-        complexes = []models.Complex{}
-    }
-
-    // Map internal Complex models to generated refuelapi.Complex models
-    resComplexes := make([]refuelapi.Complex, len(complexes))
-    for i, c := range complexes {
-        resComplexes[i] = refuelapi.Complex{
-            Id:        int64(c.ID),
-            UserId:    c.UserID,
-            Content:   c.Content,
-            Category:  c.Category,
-            CreatedAt: &c.CreatedAt,
-            UpdatedAt: &c.UpdatedAt,
-            // Goals: mapGoals(c.Goals), // If goals are preloaded
-        }
-    }
-
-    return refuelapi.ImplResponse{Code: http.StatusOK, Body: resComplexes}
+// mapBadge maps an internal Badge model to the generated refuelapi.Badge model.
+func mapBadge(b models.Badge) refuelapi.Badge {
+	return refuelapi.Badge{
+		Id:          int64(b.ID),
+		Code:        b.Code,
+		Name:        b.Name,
+		Description: b.Description,
+		IconUrl:     b.IconURL,
+		Rule:        b.Rule,
+	}
 }
 
-// CreateComplex - 新しいコンプレックスを登録
-func (s APIService) CreateComplex(ctx context.Context, complexInput refuelapi.ComplexInput) (refuelapi.ImplResponse) {
-    userID, resp := GetUserIDFromContext(ctx)
-    if resp != nil {
-        return *resp
-    }
-
-    if err := s.Validate.Struct(complexInput); err != nil {
-        return refuelapi.ImplResponse{Code: http.StatusBadRequest, Body: NewErrorResponse(http.StatusBadRequest, "Validation failed: "+err.Error())}
-    }
-
-    // Start a database transaction
-    tx := s.DB.Begin()
-    if tx.Error != nil {
-        return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(http.StatusInternalServerError, "Failed to start transaction: "+tx.Error.Error())}
-    }
+// awardBadges evaluates badge criteria, counting days in loc, and the
+// milestones of the action's goal after an action is completed, and returns
+// the milestone badges it awarded.
+// Failures are only logged because the action itself has already been saved.
+func (s APIService) awardBadges(ctx context.Context, userID string, goalID uint, loc *time.Location) []models.UserBadge {
+	if !s.evaluateBadges(ctx, userID, loc) {
+		return nil
+	}
+	return s.checkMilestones(ctx, userID, goalID)
+}
 
-	complex := models.Complex{
-		UserID:   userID,
-        Content:  complexInput.Content,
-        Category: complexInput.Category,
-        // TriggerEpisode: complexInput.TriggerEpisode, // New field
-    }
+// evaluateBadges awards the badges whose criteria the user now meets, with
+// days counted in loc, and reports whether they could be evaluated.
+// Failures are only logged.
+func (s APIService) evaluateBadges(ctx context.Context, userID string, loc *time.Location) bool {
+	awarded, err := s.Evaluator.Evaluate(ctx, userID, loc)
+	if err != nil {
+		log.Printf("⚠️ Failed to evaluate badges for user %s: %v", userID, err)
+		return false
+	}
+	for _, ub := range awarded {
+		log.Printf("🏆 User %s earned badge %q", userID, ub.Badge.Code)
+	}
+	return true
+}
 
-    if result := tx.Create(&complex); result.Error != nil {
-        tx.Rollback()
-        return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(http.StatusInternalServerError, "Failed to create complex: "+result.Error.Error())}
-    }
+// GetComplexes - 登録されているコンプレックスの一覧を取得
+func (s APIService) GetComplexes(ctx context.Context, limit int32, cursor string, sort string, category string) (refuelapi.ImplResponse, error) {
+	userID, resp := GetUserIDFromContext(ctx)
+	if resp != nil {
+		return *resp, nil
+	}
 
-    // Commit the transaction
-    if err := tx.Commit().Error; err != nil {
-        return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(http.StatusInternalServerError, "Failed to commit transaction: "+err.Error())}
-    }
+	page, resp := pageRequest(ctx, limit, cursor, sort, repository.ComplexSortFields, "created_at")
+	if resp != nil {
+		return *resp, nil
+	}
+	query := url.Values{}
+	if category != "" {
+		query.Set("category", category)
+	}
 
-    // Reload the complex with its goals to return the complete entity if needed
-    // s.DB.Preload("Goals").First(&complex, complex.ID)
+	complexes, next, err := s.Complexes.ListPage(ctx, userID, repository.ComplexFilter{Category: category}, page)
+	if err != nil {
+		return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(ctx, http.StatusInternalServerError, i18n.FetchFailed, i18n.NounComplexes, err)}, nil
+	}
 
-    resComplex := refuelapi.Complex{
-        Id:        int64(complex.ID),
-        UserId:    complex.UserID,
-        Content:   complex.Content,
-        Category:  complex.Category,
-        CreatedAt: &complex.CreatedAt,
-        UpdatedAt: &complex.UpdatedAt,
-    }
+	// Map internal Complex models to generated refuelapi.Complex models
+	resComplexes := make([]refuelapi.Complex, len(complexes))
+	for i, c := range complexes {
+		resComplexes[i] = refuelapi.Complex{
+			Id:             int64(c.ID),
+			UserId:         c.UserID,
+			Content:        c.Content,
+			TriggerEpisode: c.TriggerEpisode,
+			Category:       c.Category,
+			CreatedAt:      c.CreatedAt,
+			UpdatedAt:      c.UpdatedAt,
+			// Goals: mapGoals(c.Goals), // If goals are preloaded
+		}
+	}
 
-    return refuelapi.ImplResponse{Code: http.StatusCreated, Body: resComplex}
+	return pageResponse("/api/v1/complexes", query, page, next, resComplexes), nil
+}
+
+// CreateComplex - 新しいコンプレックスを登録
+func (s APIService) CreateComplex(ctx context.Context, complexInput refuelapi.ComplexInput) (refuelapi.ImplResponse, error) {
+	userID, resp := GetUserIDFromContext(ctx)
+	if resp != nil {
+		return *resp, nil
+	}
+
+	if err := s.Validate.Struct(complexInput); err != nil {
+		return refuelapi.ImplResponse{Code: http.StatusBadRequest, Body: NewErrorResponse(ctx, http.StatusBadRequest, i18n.ValidationFailed, err)}, nil
+	}
+
+	complex := models.Complex{
+		UserID:         userID,
+		Content:        complexInput.Content,
+		Category:       complexInput.Category,
+		TriggerEpisode: complexInput.TriggerEpisode,
+	}
+
+	if err := s.Complexes.Create(ctx, &complex); err != nil {
+		return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(ctx, http.StatusInternalServerError, i18n.CreateFailed, i18n.NounComplex, err)}, nil
+	}
+	s.recordRevision(ctx, userID, audit.Complex, complex.ID, audit.Create, nil, &complex)
+
+	resComplex := refuelapi.Complex{
+		Id:             int64(complex.ID),
+		UserId:         complex.UserID,
+		Content:        complex.Content,
+		TriggerEpisode: complex.TriggerEpisode,
+		Category:       complex.Category,
+		CreatedAt:      complex.CreatedAt,
+		UpdatedAt:      complex.UpdatedAt,
+	}
+
+	return refuelapi.ImplResponse{Code: http.StatusCreated, Body: resComplex}, nil
 }
 
 // DeleteComplex - 既存のコンプレックスを削除
-func (s APIService) DeleteComplex(ctx context.Context, complexId int64) (refuelapi.ImplResponse) {
-    userID, resp := GetUserIDFromContext(ctx)
-    if resp != nil {
-        return *resp
-    }
+func (s APIService) DeleteComplex(ctx context.Context, complexId int64) (refuelapi.ImplResponse, error) {
+	userID, resp := GetUserIDFromContext(ctx)
+	if resp != nil {
+		return *resp, nil
+	}
+
+	complex, err := s.Complexes.Get(ctx, userID, uint(complexId))
+	if err != nil {
+		if err == repository.ErrNotFound {
+			return refuelapi.ImplResponse{Code: http.StatusNotFound, Body: NewErrorResponse(ctx, http.StatusNotFound, i18n.ComplexNotFound)}, nil
+		}
+		return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(ctx, http.StatusInternalServerError, i18n.FetchFailed, i18n.NounComplex, err)}, nil
+	}
 
-    if result := s.DB.Where("id = ? AND user_id = ?", complexId, userID).Delete(&models.Complex{}); result.Error != nil || result.RowsAffected == 0 {
-        return refuelapi.ImplResponse{Code: http.StatusNotFound, Body: NewErrorResponse(http.StatusNotFound, "Complex not found or already deleted")}
-    }
-    return refuelapi.ImplResponse{Code: http.StatusNoContent}
+	if err := s.Complexes.Delete(ctx, userID, complex.ID); err != nil {
+		return refuelapi.ImplResponse{Code: http.StatusNotFound, Body: NewErrorResponse(ctx, http.StatusNotFound, i18n.ComplexNotFound)}, nil
+	}
+	// The goals and actions moved to the trash with the complex are part of
+	// this revision rather than revisions of their own.
+	s.recordRevision(ctx, userID, audit.Complex, complex.ID, audit.Delete, complex, nil)
+	return refuelapi.ImplResponse{Code: http.StatusNoContent}, nil
 }
 
 // GetComplex - 指定されたIDのコンプレックス情報を取得します。
-func (s APIService) GetComplex(ctx context.Context, complexId int64) (refuelapi.ImplResponse) {
-    userID, resp := GetUserIDFromContext(ctx)
-    if resp != nil {
-        return *resp
-    }
-
-    var complex models.Complex
-    if result := s.DB.Where("id = ? AND user_id = ?", complexId, userID).First(&complex); result.Error != nil {
-        if result.Error == gorm.ErrRecordNotFound {
-            return refuelapi.ImplResponse{Code: http.StatusNotFound, Body: NewErrorResponse(http.StatusNotFound, "Complex not found")}
-        }
-        return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(http.StatusInternalServerError, "Failed to fetch complex: "+result.Error.Error())}
-    }
-
-    // Preload Goals if needed for the response
-    s.DB.Preload("Goals").First(&complex, complex.ID)
-
-    resComplex := refuelapi.Complex{
-        Id:        int64(complex.ID),
-        UserId:    complex.UserID,
-        Content:   complex.Content,
-        Category:  complex.Category,
-        CreatedAt: &complex.CreatedAt,
-        UpdatedAt: &complex.UpdatedAt,
-        // Goals: mapGoals(complex.Goals), // Map internal Goal to generated refuelapi.Goal
-    }
-
-    return refuelapi.ImplResponse{Code: http.StatusOK, Body: resComplex}
+func (s APIService) GetComplex(ctx context.Context, complexId int64) (refuelapi.ImplResponse, error) {
+	userID, resp := GetUserIDFromContext(ctx)
+	if resp != nil {
+		return *resp, nil
+	}
+
+	complex, err := s.Complexes.Get(ctx, userID, uint(complexId))
+	if err != nil {
+		if err == repository.ErrNotFound {
+			return refuelapi.ImplResponse{Code: http.StatusNotFound, Body: NewErrorResponse(ctx, http.StatusNotFound, i18n.ComplexNotFound)}, nil
+		}
+		return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(ctx, http.StatusInternalServerError, i18n.FetchFailed, i18n.NounComplex, err)}, nil
+	}
+
+	resComplex := refuelapi.Complex{
+		Id:             int64(complex.ID),
+		UserId:         complex.UserID,
+		Content:        complex.Content,
+		TriggerEpisode: complex.TriggerEpisode,
+		Category:       complex.Category,
+		CreatedAt:      complex.CreatedAt,
+		UpdatedAt:      complex.UpdatedAt,
+		// Goals: mapGoals(complex.Goals), // Map internal Goal to generated refuelapi.Goal
+	}
+
+	return refuelapi.ImplResponse{Code: http.StatusOK, Body: resComplex}, nil
 }
 
 // UpdateComplex - 既存のコンプレックス情報を更新します。
-func (s APIService) UpdateComplex(ctx context.Context, complexId int64, complexInput refuelapi.ComplexInput) (refuelapi.ImplResponse) {
-    userID, resp := GetUserIDFromContext(ctx)
-    if resp != nil {
-        return *resp
-    }
-
-    if err := s.Validate.Struct(complexInput); err != nil {
-        return refuelapi.ImplResponse{Code: http.StatusBadRequest, Body: NewErrorResponse(http.StatusBadRequest, "Validation failed: "+err.Error())}
-    }
-
-    var existingComplex models.Complex
-    if err := s.DB.Where("id = ? AND user_id = ?", complexId, userID).First(&existingComplex).Error; err != nil {
-        if err == gorm.ErrRecordNotFound {
-            return refuelapi.ImplResponse{Code: http.StatusNotFound, Body: NewErrorResponse(http.StatusNotFound, "Complex not found to update")}
-        }
-        return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(http.StatusInternalServerError, "Failed to find complex to update: "+err.Error())}
-    }
-
-    // Update Complex fields
-    existingComplex.Content = complexInput.Content
-    existingComplex.Category = complexInput.Category
-    // existingComplex.TriggerEpisode = complexInput.TriggerEpisode // New field
-
-    if err := s.DB.Save(&existingComplex).Error; err != nil {
-        return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(http.StatusInternalServerError, "Failed to update complex: "+err.Error())}
-    }
-
-    // Reload the complex with its goals to return the complete entity if needed
-    s.DB.Preload("Goals").First(&existingComplex, existingComplex.ID)
-
-    resComplex := refuelapi.Complex{
-        Id:        int64(existingComplex.ID),
-        UserId:    existingComplex.UserID,
-        Content:   existingComplex.Content,
-        Category:  existingComplex.Category,
-        CreatedAt: &existingComplex.CreatedAt,
-        UpdatedAt: &existingComplex.UpdatedAt,
-        // Goals: mapGoals(existingComplex.Goals),
-    }
-
-    return refuelapi.ImplResponse{Code: http.StatusOK, Body: resComplex}
+func (s APIService) UpdateComplex(ctx context.Context, complexId int64, complexInput refuelapi.ComplexInput) (refuelapi.ImplResponse, error) {
+	userID, resp := GetUserIDFromContext(ctx)
+	if resp != nil {
+		return *resp, nil
+	}
+
+	if err := s.Validate.Struct(complexInput); err != nil {
+		return refuelapi.ImplResponse{Code: http.StatusBadRequest, Body: NewErrorResponse(ctx, http.StatusBadRequest, i18n.ValidationFailed, err)}, nil
+	}
+
+	existingComplex, err := s.Complexes.Get(ctx, userID, uint(complexId))
+	if err != nil {
+		if err == repository.ErrNotFound {
+			return refuelapi.ImplResponse{Code: http.StatusNotFound, Body: NewErrorResponse(ctx, http.StatusNotFound, i18n.ComplexNotFound)}, nil
+		}
+		return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(ctx, http.StatusInternalServerError, i18n.FetchFailed, i18n.NounComplex, err)}, nil
+	}
+
+	before := *existingComplex
+
+	// Update Complex fields
+	existingComplex.Content = complexInput.Content
+	existingComplex.Category = complexInput.Category
+	existingComplex.TriggerEpisode = complexInput.TriggerEpisode
+
+	if err := s.Complexes.Update(ctx, existingComplex); err != nil {
+		return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(ctx, http.StatusInternalServerError, i18n.UpdateFailed, i18n.NounComplex, err)}, nil
+	}
+	s.recordRevision(ctx, userID, audit.Complex, existingComplex.ID, audit.Update, &before, existingComplex)
+
+	resComplex := refuelapi.Complex{
+		Id:             int64(existingComplex.ID),
+		UserId:         existingComplex.UserID,
+		Content:        existingComplex.Content,
+		TriggerEpisode: existingComplex.TriggerEpisode,
+		Category:       existingComplex.Category,
+		CreatedAt:      existingComplex.CreatedAt,
+		UpdatedAt:      existingComplex.UpdatedAt,
+		// Goals: mapGoals(existingComplex.Goals),
+	}
+
+	return refuelapi.ImplResponse{Code: http.StatusOK, Body: resComplex}, nil
 }
 
 // CreateGoal - 新しい目標を登録
-func (s APIService) CreateGoal(ctx context.Context, goalInput refuelapi.GoalInput) (refuelapi.ImplResponse) {
-    userID, resp := GetUserIDFromContext(ctx)
-    if resp != nil {
-        return *resp
-    }
-
-    if err := s.Validate.Struct(goalInput); err != nil {
-        return refuelapi.ImplResponse{Code: http.StatusBadRequest, Body: NewErrorResponse(http.StatusBadRequest, "Validation failed: "+err.Error())}
-    }
-
-    // Check if the referenced complex exists and belongs to the user
-    var complex models.Complex
-    if err := s.DB.Where("id = ? AND user_id = ?", goalInput.ComplexId, userID).First(&complex).Error; err != nil {
-        if err == gorm.ErrRecordNotFound {
-            return refuelapi.ImplResponse{Code: http.StatusBadRequest, Body: NewErrorResponse(http.StatusBadRequest, "Referenced complex not found or does not belong to user")}
-        }
-        return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(http.StatusInternalServerError, "Error checking complex: "+err.Error())}
-    }
-
-    goal := models.Goal{
-		UserID:    userID,
-        ComplexID: uint(goalInput.ComplexId),
-        Content:   goalInput.Content,
-    }
+func (s APIService) CreateGoal(ctx context.Context, goalInput refuelapi.GoalInput) (refuelapi.ImplResponse, error) {
+	userID, resp := GetUserIDFromContext(ctx)
+	if resp != nil {
+		return *resp, nil
+	}
+
+	if err := s.Validate.Struct(goalInput); err != nil {
+		return refuelapi.ImplResponse{Code: http.StatusBadRequest, Body: NewErrorResponse(ctx, http.StatusBadRequest, i18n.ValidationFailed, err)}, nil
+	}
+
+	// Check if the referenced complex exists and belongs to the user
+	if _, err := s.Complexes.Get(ctx, userID, uint(goalInput.ComplexId)); err != nil {
+		if err == repository.ErrNotFound {
+			return refuelapi.ImplResponse{Code: http.StatusBadRequest, Body: NewErrorResponse(ctx, http.StatusBadRequest, i18n.ReferencedComplexNotFound)}, nil
+		}
+		return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(ctx, http.StatusInternalServerError, i18n.FetchFailed, i18n.NounComplex, err)}, nil
+	}
 
-    if result := s.DB.Create(&goal); result.Error != nil {
-        return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(http.StatusInternalServerError, "Failed to create goal: "+result.Error.Error())}
-    }
+	goal := models.Goal{
+		UserID:    userID,
+		ComplexID: uint(goalInput.ComplexId),
+		Content:   goalInput.Content,
+	}
+	if err := applyGoalMetric(&goal, goalInput.Metric); err != nil {
+		return refuelapi.ImplResponse{Code: http.StatusBadRequest, Body: NewErrorResponse(ctx, http.StatusBadRequest, i18n.InvalidMetric, err)}, nil
+	}
 
-    resGoal := refuelapi.Goal{
-        Id:        int64(goal.ID),
-        UserId:    goal.UserID,
-        ComplexId: int64(goal.ComplexID),
-        Content:   goal.Content,
-        CreatedAt: &goal.CreatedAt,
-        UpdatedAt: &goal.UpdatedAt,
-    }
+	if err := s.Goals.Create(ctx, &goal); err != nil {
+		return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(ctx, http.StatusInternalServerError, i18n.CreateFailed, i18n.NounGoal, err)}, nil
+	}
+	s.recordRevision(ctx, userID, audit.Goal, goal.ID, audit.Create, nil, &goal)
+
+	resGoal := refuelapi.Goal{
+		Id:        int64(goal.ID),
+		UserId:    goal.UserID,
+		ComplexId: int64(goal.ComplexID),
+		Content:   goal.Content,
+		CreatedAt: goal.CreatedAt,
+		UpdatedAt: goal.UpdatedAt,
+		Metric:    mapGoalMetric(goal),
+	}
 
-    return refuelapi.ImplResponse{Code: http.StatusCreated, Body: resGoal}
+	return refuelapi.ImplResponse{Code: http.StatusCreated, Body: resGoal}, nil
 }
 
 // DeleteGoal - 既存の目標を削除
-func (s APIService) DeleteGoal(ctx context.Context, goalId int64) (refuelapi.ImplResponse) {
-    userID, resp := GetUserIDFromContext(ctx)
-    if resp != nil {
-        return *resp
-    }
+func (s APIService) DeleteGoal(ctx context.Context, goalId int64) (refuelapi.ImplResponse, error) {
+	userID, resp := GetUserIDFromContext(ctx)
+	if resp != nil {
+		return *resp, nil
+	}
+
+	goal, resp := s.findGoal(ctx, userID, goalId)
+	if resp != nil {
+		return *resp, nil
+	}
 
-    if result := s.DB.Where("id = ? AND user_id = ?", goalId, userID).Delete(&models.Goal{}); result.Error != nil || result.RowsAffected == 0 {
-        return refuelapi.ImplResponse{Code: http.StatusNotFound, Body: NewErrorResponse(http.StatusNotFound, "Goal not found or already deleted")}
-    }
-    return refuelapi.ImplResponse{Code: http.StatusNoContent}
+	if err := s.Goals.Delete(ctx, userID, goal.ID); err != nil {
+		return refuelapi.ImplResponse{Code: http.StatusNotFound, Body: NewErrorResponse(ctx, http.StatusNotFound, i18n.GoalNotFound)}, nil
+	}
+	s.recordRevision(ctx, userID, audit.Goal, goal.ID, audit.Delete, goal, nil)
+	return refuelapi.ImplResponse{Code: http.StatusNoContent}, nil
 }
 
 // GetGoal - 指定されたIDの目標情報を取得
-func (s APIService) GetGoal(ctx context.Context, goalId int64) (refuelapi.ImplResponse) {
-    userID, resp := GetUserIDFromContext(ctx)
-    if resp != nil {
-        return *resp
-    }
-
-    var goal models.Goal
-    if err := s.DB.Where("id = ? AND user_id = ?", goalId, userID).First(&goal).Error; err != nil {
-        if err == gorm.ErrRecordNotFound {
-            return refuelapi.ImplResponse{Code: http.StatusNotFound, Body: NewErrorResponse(http.StatusNotFound, "Goal not found")}
-        }
-        return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(http.StatusInternalServerError, "Failed to fetch goal: "+err.Error())}
-    }
-
-    resGoal := refuelapi.Goal{
-        Id:        int64(goal.ID),
-        UserId:    goal.UserID,
-        ComplexId: int64(goal.ComplexID),
-        Content:   goal.Content,
-        CreatedAt: &goal.CreatedAt,
-        UpdatedAt: &goal.UpdatedAt,
-    }
-
-    return refuelapi.ImplResponse{Code: http.StatusOK, Body: resGoal}
+func (s APIService) GetGoal(ctx context.Context, goalId int64, tz string) (refuelapi.ImplResponse, error) {
+	userID, resp := GetUserIDFromContext(ctx)
+	if resp != nil {
+		return *resp, nil
+	}
+
+	loc, err := loadLocation(tz)
+	if err != nil {
+		return refuelapi.ImplResponse{Code: http.StatusBadRequest, Body: NewErrorResponse(ctx, http.StatusBadRequest, i18n.InvalidTimeZone, err)}, nil
+	}
+
+	goal, err := s.Goals.Get(ctx, userID, uint(goalId))
+	if err != nil {
+		if err == repository.ErrNotFound {
+			return refuelapi.ImplResponse{Code: http.StatusNotFound, Body: NewErrorResponse(ctx, http.StatusNotFound, i18n.GoalNotFound)}, nil
+		}
+		return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(ctx, http.StatusInternalServerError, i18n.FetchFailed, i18n.NounGoal, err)}, nil
+	}
+
+	actions, err := s.Actions.ListByGoal(ctx, userID, goal.ID)
+	if err != nil {
+		return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(ctx, http.StatusInternalServerError, i18n.FetchFailed, i18n.NounActions, err)}, nil
+	}
+	summary, err := s.streakSummary(ctx, actions, loc, streak.DefaultWindowDays)
+	if err != nil {
+		return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(ctx, http.StatusInternalServerError, i18n.ComputeFailed, i18n.NounStreak, err)}, nil
+	}
+	goalProgress, err := s.goalProgress(ctx, *goal, loc)
+	if err != nil {
+		return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(ctx, http.StatusInternalServerError, i18n.ComputeFailed, i18n.NounProgress, err)}, nil
+	}
+	milestones, err := s.Goals.ListMilestones(ctx, userID, goal.ID)
+	if err != nil {
+		return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(ctx, http.StatusInternalServerError, i18n.FetchFailed, i18n.NounMilestones, err)}, nil
+	}
+
+	resGoal := refuelapi.Goal{
+		Id:         int64(goal.ID),
+		UserId:     goal.UserID,
+		ComplexId:  int64(goal.ComplexID),
+		Content:    goal.Content,
+		CreatedAt:  goal.CreatedAt,
+		UpdatedAt:  goal.UpdatedAt,
+		Streak:     mapStreakSummary(summary),
+		Metric:     mapGoalMetric(*goal),
+		Progress:   goalProgress,
+		Milestones: mapMilestones(milestones),
+	}
+
+	return refuelapi.ImplResponse{Code: http.StatusOK, Body: resGoal}, nil
 }
 
 // GetGoals - 登録されている目標の一覧を取得
-func (s APIService) GetGoals(ctx context.Context) (refuelapi.ImplResponse) {
-    userID, resp := GetUserIDFromContext(ctx)
-    if resp != nil {
-        return *resp
-    }
-
-    var goals []models.Goal
-    if result := s.DB.Where("user_id = ?", userID).Find(&goals); result.Error != nil {
-        return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(http.StatusInternalServerError, "Failed to fetch goals: "+result.Error.Error())}
-    }
-
-    if goals == nil {
-        goals = []models.Goal{}
-    }
-
-    resGoals := make([]refuelapi.Goal, len(goals))
-    for i, g := range goals {
-        resGoals[i] = refuelapi.Goal{
-            Id:        int64(g.ID),
-            UserId:    g.UserID,
-            ComplexId: int64(g.ComplexID),
-            Content:   g.Content,
-            CreatedAt: &g.CreatedAt,
-            UpdatedAt: &g.UpdatedAt,
-        }
-    }
-
-    return refuelapi.ImplResponse{Code: http.StatusOK, Body: resGoals}
+func (s APIService) GetGoals(ctx context.Context, limit int32, cursor string, sort string, complexId int64) (refuelapi.ImplResponse, error) {
+	userID, resp := GetUserIDFromContext(ctx)
+	if resp != nil {
+		return *resp, nil
+	}
+
+	page, resp := pageRequest(ctx, limit, cursor, sort, repository.GoalSortFields, "created_at")
+	if resp != nil {
+		return *resp, nil
+	}
+	query := url.Values{}
+	if complexId != 0 {
+		query.Set("complex_id", strconv.FormatInt(complexId, 10))
+	}
+
+	goals, next, err := s.Goals.ListPage(ctx, userID, repository.GoalFilter{ComplexID: uint(complexId)}, page)
+	if err != nil {
+		return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(ctx, http.StatusInternalServerError, i18n.FetchFailed, i18n.NounGoals, err)}, nil
+	}
+
+	resGoals := make([]refuelapi.Goal, len(goals))
+	for i, g := range goals {
+		resGoals[i] = refuelapi.Goal{
+			Id:        int64(g.ID),
+			UserId:    g.UserID,
+			ComplexId: int64(g.ComplexID),
+			Content:   g.Content,
+			CreatedAt: g.CreatedAt,
+			UpdatedAt: g.UpdatedAt,
+			Metric:    mapGoalMetric(g),
+		}
+	}
+
+	return pageResponse("/api/v1/goals", query, page, next, resGoals), nil
 }
 
 // UpdateGoal - 既存の目標情報を更新
-func (s APIService) UpdateGoal(ctx context.Context, goalId int64, goalInput refuelapi.GoalInput) (refuelapi.ImplResponse) {
-    userID, resp := GetUserIDFromContext(ctx)
-    if resp != nil {
-        return *resp
-    }
-
-    if err := s.Validate.Struct(goalInput); err != nil {
-        return refuelapi.ImplResponse{Code: http.StatusBadRequest, Body: NewErrorResponse(http.StatusBadRequest, "Validation failed: "+err.Error())}
-    }
+func (s APIService) UpdateGoal(ctx context.Context, goalId int64, goalInput refuelapi.GoalInput) (refuelapi.ImplResponse, error) {
+	userID, resp := GetUserIDFromContext(ctx)
+	if resp != nil {
+		return *resp, nil
+	}
 
-    var goal models.Goal
-    if err := s.DB.Where("id = ? AND user_id = ?", goalId, userID).First(&goal).Error; err != nil {
-        return refuelapi.ImplResponse{Code: http.StatusNotFound, Body: NewErrorResponse(http.StatusNotFound, "Goal not found to update")}
-    }
+	if err := s.Validate.Struct(goalInput); err != nil {
+		return refuelapi.ImplResponse{Code: http.StatusBadRequest, Body: NewErrorResponse(ctx, http.StatusBadRequest, i18n.ValidationFailed, err)}, nil
+	}
 
-    goal.Content = goalInput.Content
+	goal, err := s.Goals.Get(ctx, userID, uint(goalId))
+	if err != nil {
+		return refuelapi.ImplResponse{Code: http.StatusNotFound, Body: NewErrorResponse(ctx, http.StatusNotFound, i18n.GoalNotFound)}, nil
+	}
 
-    if err := s.DB.Save(&goal).Error; err != nil {
-        return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(http.StatusInternalServerError, "Failed to update goal: "+err.Error())}
-    }
+	before := *goal
+	goal.Content = goalInput.Content
+	if err := applyGoalMetric(goal, goalInput.Metric); err != nil {
+		return refuelapi.ImplResponse{Code: http.StatusBadRequest, Body: NewErrorResponse(ctx, http.StatusBadRequest, i18n.InvalidMetric, err)}, nil
+	}
 
-    resGoal := refuelapi.Goal{
-        Id:        int64(goal.ID),
-        UserId:    goal.UserID,
-        ComplexId: int64(goal.ComplexID),
-        Content:   goal.Content,
-        CreatedAt: &goal.CreatedAt,
-        UpdatedAt: &goal.UpdatedAt,
-    }
+	if err := s.Goals.Update(ctx, goal); err != nil {
+		return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(ctx, http.StatusInternalServerError, i18n.UpdateFailed, i18n.NounGoal, err)}, nil
+	}
+	s.recordRevision(ctx, userID, audit.Goal, goal.ID, audit.Update, &before, goal)
+
+	resGoal := refuelapi.Goal{
+		Id:        int64(goal.ID),
+		UserId:    goal.UserID,
+		ComplexId: int64(goal.ComplexID),
+		Content:   goal.Content,
+		CreatedAt: goal.CreatedAt,
+		UpdatedAt: goal.UpdatedAt,
+		Metric:    mapGoalMetric(*goal),
+	}
 
-    return refuelapi.ImplResponse{Code: http.StatusOK, Body: resGoal}
+	return refuelapi.ImplResponse{Code: http.StatusOK, Body: resGoal}, nil
 }
 
 // Ping - サーバーの死活監視
-func (s APIService) Ping(ctx context.Context) (refuelapi.ImplResponse) {
-    return refuelapi.ImplResponse{Code: http.StatusOK, Body: map[string]string{"message": "pong"}}
-}
\ No newline at end of file
+func (s APIService) Ping(ctx context.Context) (refuelapi.ImplResponse, error) {
+	return refuelapi.ImplResponse{Code: http.StatusOK, Body: map[string]string{"message": "pong"}}, nil
+}
@@ -0,0 +1,1188 @@
+package app
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+
+	"refuel/backend/auth"
+	"refuel/backend/auth/oidc"
+	"refuel/backend/badge"
+	"refuel/backend/checkin"
+	"refuel/backend/export"
+	refuelapi "refuel/backend/generated/go"
+	"refuel/backend/i18n"
+	"refuel/backend/models"
+	"refuel/backend/quantity"
+	"refuel/backend/recurrence"
+	"refuel/backend/repository"
+	"refuel/backend/trash"
+)
+
+// newTestService returns a service backed by in-memory repositories, with
+// "admin" as its only admin.
+func newTestService() (APIService, *repository.Repositories) {
+	repos := repository.NewMemory()
+	return APIService{
+		Complexes:    repos.Complexes,
+		Goals:        repos.Goals,
+		Actions:      repos.Actions,
+		Trash:        repos.Trash,
+		Badges:       repos.Badges,
+		Feedback:     repos.Feedback,
+		Revisions:    repos.Revisions,
+		Users:        repos.Users,
+		Evaluator:    badge.NewEvaluator(repos),
+		Validate:     validator.New(),
+		AdminUserIDs: map[string]struct{}{"admin": {}},
+		Tokens:       auth.NewTokens([]byte("test-secret"), time.Minute),
+		RefreshTTL:   time.Hour,
+		TrashConfig:  trash.Config{Retention: 30 * 24 * time.Hour, PurgeInterval: time.Hour},
+		Exports:      repos.Exports,
+		Imports:      repos.Imports,
+		Exporter:     export.NewWorker(repos.Exports, export.Config{Retention: time.Hour, PollInterval: time.Hour}),
+	}, repos
+}
+
+// requestAs returns the Gin context of a request by the user, or of an
+// anonymous request if userID is empty.
+func requestAs(userID string) *gin.Context {
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+	if userID != "" {
+		c.Set("userID", userID)
+	}
+	return c
+}
+
+// checkResponse fails the test unless resp has the status and, for an
+// error, the problem code.
+func checkResponse(t *testing.T, resp refuelapi.ImplResponse, err error, status int, code i18n.Code) {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("got error %v, want a response", err)
+	}
+	if resp.Code != status {
+		t.Fatalf("got status %d (%+v), want %d", resp.Code, resp.Body, status)
+	}
+	if code == "" {
+		return
+	}
+	problem, ok := resp.Body.(Problem)
+	if !ok {
+		t.Fatalf("got body %T, want a Problem", resp.Body)
+	}
+	if problem.Code != string(code) {
+		t.Errorf("got code %q, want %q", problem.Code, code)
+	}
+}
+
+func TestCreateBadgeRejected(t *testing.T) {
+	s, _ := newTestService()
+	tests := []struct {
+		name   string
+		userID string
+		rule   string
+		status int
+		code   i18n.Code
+	}{
+		{"not signed in", "", "actions >= 1", http.StatusUnauthorized, ""},
+		{"not an admin", "u1", "actions >= 1", http.StatusForbidden, i18n.AdminRequired},
+		{"syntax error", "admin", "actions >=", http.StatusBadRequest, i18n.InvalidBadgeRule},
+		{"unknown metric", "admin", "badges >= 1", http.StatusBadRequest, i18n.InvalidBadgeRule},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := refuelapi.BadgeInput{Code: "first_action", Name: "はじめの一歩", Description: "最初の行動", Rule: tt.rule}
+			resp, err := s.CreateBadge(requestAs(tt.userID), input)
+			checkResponse(t, resp, err, tt.status, tt.code)
+		})
+	}
+}
+
+func TestGetActionOccurrencesRejected(t *testing.T) {
+	s, _ := newTestService()
+	tests := []struct {
+		name   string
+		userID string
+		count  int32
+		tz     string
+		status int
+		code   i18n.Code
+	}{
+		{"not signed in", "", 5, "", http.StatusUnauthorized, ""},
+		{"count too small", "u1", -1, "", http.StatusBadRequest, i18n.OutOfRange},
+		{"count too large", "u1", 101, "", http.StatusBadRequest, i18n.OutOfRange},
+		{"unknown time zone", "u1", 5, "Mars/Olympus", http.StatusBadRequest, i18n.InvalidTimeZone},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := s.GetActionOccurrences(requestAs(tt.userID), 1, tt.count, time.Time{}, tt.tz)
+			checkResponse(t, resp, err, tt.status, tt.code)
+		})
+	}
+}
+
+func TestCheckinRejected(t *testing.T) {
+	s, _ := newTestService()
+	tests := []struct {
+		name   string
+		userID string
+		input  refuelapi.ActionCheckinInput
+		status int
+		code   i18n.Code
+	}{
+		{"not signed in", "", refuelapi.ActionCheckinInput{Date: "2025-04-01"}, http.StatusUnauthorized, ""},
+		{"unknown status", "u1", refuelapi.ActionCheckinInput{Date: "2025-04-01", Status: "late"}, http.StatusBadRequest, i18n.InvalidChoice},
+		{"invalid date", "u1", refuelapi.ActionCheckinInput{Date: "2025/04/01"}, http.StatusBadRequest, i18n.InvalidDate},
+		{"unknown time zone", "u1", refuelapi.ActionCheckinInput{Date: "2025-04-01", Tz: "Mars/Olympus"}, http.StatusBadRequest, i18n.InvalidTimeZone},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := s.CheckinAction(requestAs(tt.userID), 1, tt.input)
+			checkResponse(t, resp, err, tt.status, tt.code)
+		})
+	}
+}
+
+func TestGetActionCheckinsRejected(t *testing.T) {
+	s, _ := newTestService()
+	tests := []struct {
+		name     string
+		from, to string
+		status   int
+		code     i18n.Code
+	}{
+		{"invalid from", "April", "2025-04-30", http.StatusBadRequest, i18n.InvalidDate},
+		{"invalid to", "2025-04-01", "30/04/2025", http.StatusBadRequest, i18n.InvalidDate},
+		{"reversed", "2025-04-30", "2025-04-01", http.StatusBadRequest, i18n.DateRangeOrder},
+		{"too long", "2024-01-01", "2025-04-01", http.StatusBadRequest, i18n.DateRangeTooLong},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := s.GetActionCheckins(requestAs("u1"), 1, tt.from, tt.to, "")
+			checkResponse(t, resp, err, tt.status, tt.code)
+		})
+	}
+}
+
+func TestRegister(t *testing.T) {
+	s, _ := newTestService()
+	ctx := requestAs("")
+	resp, err := s.Register(ctx, refuelapi.RegisterInput{Email: "taken@example.com", Password: "password123"})
+	checkResponse(t, resp, err, http.StatusCreated, "")
+
+	tests := []struct {
+		name     string
+		email    string
+		password string
+		status   int
+		code     i18n.Code
+	}{
+		{"registered", "new@example.com", "password123", http.StatusCreated, ""},
+		{"email taken", " Taken@Example.com ", "password123", http.StatusConflict, i18n.EmailTaken},
+		{"invalid email", "not-an-email", "password123", http.StatusBadRequest, i18n.InvalidEmail},
+		{"password too short", "short@example.com", strings.Repeat("a", auth.MinPasswordLength-1), http.StatusBadRequest, i18n.PasswordTooShort},
+		{"password at the limit", "limit@example.com", strings.Repeat("あ", auth.MaxPasswordLength/3), http.StatusCreated, ""},
+		{"password too long", "long@example.com", strings.Repeat("a", auth.MaxPasswordLength+1), http.StatusBadRequest, i18n.PasswordTooLong},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := s.Register(ctx, refuelapi.RegisterInput{Email: tt.email, Password: tt.password})
+			checkResponse(t, resp, err, tt.status, tt.code)
+		})
+	}
+}
+
+func TestRefreshTokenRotation(t *testing.T) {
+	s, _ := newTestService()
+	ctx := requestAs("")
+	login := func() refuelapi.TokenPair {
+		t.Helper()
+		resp, err := s.Login(ctx, refuelapi.LoginInput{Email: "user@example.com", Password: "password123"})
+		checkResponse(t, resp, err, http.StatusOK, "")
+		return resp.Body.(refuelapi.TokenPair)
+	}
+	refresh := func(token string, status int) refuelapi.TokenPair {
+		t.Helper()
+		resp, err := s.RefreshToken(ctx, refuelapi.RefreshTokenInput{RefreshToken: token})
+		checkResponse(t, resp, err, status, "")
+		pair, _ := resp.Body.(refuelapi.TokenPair)
+		return pair
+	}
+	resp, err := s.Register(ctx, refuelapi.RegisterInput{Email: "user@example.com", Password: "password123"})
+	checkResponse(t, resp, err, http.StatusCreated, "")
+
+	// Each refresh token is consumed by its first use.
+	first := login()
+	second := refresh(first.RefreshToken, http.StatusOK)
+	if second.RefreshToken == first.RefreshToken {
+		t.Fatal("got the same refresh token back, want a rotated one")
+	}
+	if userID, err := s.Tokens.Verify(second.AccessToken); err != nil || userID != second.User.Id {
+		t.Errorf("got access token for %q, %v, want %q", userID, err, second.User.Id)
+	}
+
+	// Replaying a rotated token ends every session of the user.
+	other := login()
+	refresh(first.RefreshToken, http.StatusUnauthorized)
+	refresh(second.RefreshToken, http.StatusUnauthorized)
+	refresh(other.RefreshToken, http.StatusUnauthorized)
+
+	// Logout revokes the token; unknown tokens are not reported.
+	session := login()
+	resp, err = s.Logout(ctx, refuelapi.RefreshTokenInput{RefreshToken: session.RefreshToken})
+	checkResponse(t, resp, err, http.StatusNoContent, "")
+	refresh(session.RefreshToken, http.StatusUnauthorized)
+	resp, err = s.Logout(ctx, refuelapi.RefreshTokenInput{RefreshToken: "unknown"})
+	checkResponse(t, resp, err, http.StatusNoContent, "")
+	refresh("unknown", http.StatusUnauthorized)
+}
+
+func TestLogin(t *testing.T) {
+	s, _ := newTestService()
+	ctx := requestAs("")
+	resp, err := s.Register(ctx, refuelapi.RegisterInput{Email: "user@example.com", Password: "password123"})
+	checkResponse(t, resp, err, http.StatusCreated, "")
+
+	tests := []struct {
+		name     string
+		email    string
+		password string
+		status   int
+	}{
+		{"signed in", "User@Example.com", "password123", http.StatusOK},
+		{"wrong password", "user@example.com", "password124", http.StatusUnauthorized},
+		{"unknown email", "other@example.com", "password123", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := s.Login(ctx, refuelapi.LoginInput{Email: tt.email, Password: tt.password})
+			checkResponse(t, resp, err, tt.status, "")
+		})
+	}
+}
+
+func TestCompleteOidcLoginState(t *testing.T) {
+	s, repos := newTestService()
+	// Nothing listens on the issuer, so a known state gets as far as the code exchange.
+	s.OIDC = oidc.NewProvider(oidc.Config{Issuer: "http://127.0.0.1:1", ClientID: "client-1", RedirectURL: "https://app.example.com/callback"}, nil)
+	ctx := context.Background()
+	now := time.Now()
+	for _, state := range []models.OIDCLoginState{
+		{State: "valid", CodeVerifier: "verifier", Nonce: "nonce", ExpiresAt: now.Add(time.Minute)},
+		{State: "expired", CodeVerifier: "verifier", Nonce: "nonce", ExpiresAt: now.Add(-time.Minute)},
+	} {
+		if err := repos.Users.CreateLoginState(ctx, &state); err != nil {
+			t.Fatalf("creating login state: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		state  string
+		status int
+		code   i18n.Code
+	}{
+		{"known state", "valid", http.StatusBadGateway, i18n.IdentityProviderFailed},
+		{"state already used", "valid", http.StatusBadRequest, i18n.UnknownLoginState},
+		{"expired state", "expired", http.StatusBadRequest, i18n.UnknownLoginState},
+		{"unknown state", "forged", http.StatusBadRequest, i18n.UnknownLoginState},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := s.CompleteOidcLogin(ctx, refuelapi.OidcCallbackInput{Code: "code", State: tt.state})
+			checkResponse(t, resp, err, tt.status, tt.code)
+		})
+	}
+}
+
+func TestCreateBadgeConflict(t *testing.T) {
+	s, _ := newTestService()
+	input := refuelapi.BadgeInput{Code: "first_action", Name: "はじめの一歩", Description: "最初の行動", Rule: "actions >= 1"}
+	resp, err := s.CreateBadge(requestAs("admin"), input)
+	checkResponse(t, resp, err, http.StatusCreated, "")
+	resp, err = s.CreateBadge(requestAs("admin"), input)
+	checkResponse(t, resp, err, http.StatusConflict, i18n.BadgeCodeTaken)
+}
+
+func TestUpdateActionAwardsBadgesOnCompletion(t *testing.T) {
+	s, repos := newTestService()
+	ctx := context.Background()
+	complex := models.Complex{UserID: "u1", Content: "人前で話すのが怖い", Category: "仕事"}
+	if err := repos.Complexes.Create(ctx, &complex); err != nil {
+		t.Fatalf("creating complex: %v", err)
+	}
+	goal := models.Goal{UserID: "u1", ComplexID: complex.ID, Content: "発表する"}
+	if err := repos.Goals.Create(ctx, &goal); err != nil {
+		t.Fatalf("creating goal: %v", err)
+	}
+	action := models.Action{UserID: "u1", GoalID: goal.ID, Content: "練習する"}
+	if err := repos.Actions.Create(ctx, &action); err != nil {
+		t.Fatalf("creating action: %v", err)
+	}
+	// The rule is met already; only completing an action awards it.
+	if err := repos.Badges.Create(ctx, &models.Badge{Code: "first_action", Name: "はじめの一歩", Description: "最初の行動", Rule: "actions >= 1"}); err != nil {
+		t.Fatalf("creating badge: %v", err)
+	}
+
+	completedAt := time.Now()
+	tests := []struct {
+		name   string
+		input  refuelapi.ActionUpdateInput
+		status int
+		code   i18n.Code
+		badges int
+	}{
+		{"invalid time zone", refuelapi.ActionUpdateInput{CompletedAt: &completedAt, Tz: "Mars/Olympus"}, http.StatusBadRequest, i18n.InvalidTimeZone, 0},
+		{"edited", refuelapi.ActionUpdateInput{Content: "鏡の前で練習する"}, http.StatusOK, "", 0},
+		{"completed", refuelapi.ActionUpdateInput{CompletedAt: &completedAt, Tz: "Asia/Tokyo"}, http.StatusOK, "", 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := s.UpdateAction(requestAs("u1"), int64(action.ID), tt.input)
+			checkResponse(t, resp, err, tt.status, tt.code)
+			badges, err := repos.Badges.ListUserBadges(ctx, "u1")
+			if err != nil {
+				t.Fatalf("ListUserBadges: %v", err)
+			}
+			if len(badges) != tt.badges {
+				t.Errorf("got %d badges, want %d", len(badges), tt.badges)
+			}
+		})
+	}
+}
+
+func TestToRecurrencePattern(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      *refuelapi.RecurrencePattern
+		want    *refuelapi.RecurrencePattern
+		wantErr bool
+	}{
+		{"none", nil, nil, false},
+		{
+			name: "normalized",
+			in:   &refuelapi.RecurrencePattern{Frequency: "weekly", TimeOfDay: "07:30", DaysOfWeek: []string{"fri", " mon"}},
+			want: &refuelapi.RecurrencePattern{Frequency: "weekly", Interval: 1, TimeOfDay: "07:30", DaysOfWeek: []string{"FRI", "MON"}},
+		},
+		{"invalid", &refuelapi.RecurrencePattern{Frequency: "daily", TimeOfDay: "25:00"}, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := toRecurrencePattern(tt.in)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("got %+v, want an error", p)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("toRecurrencePattern: %v", err)
+			}
+			got := mapRecurrencePattern(p)
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUpdateActionKeepsCompletion(t *testing.T) {
+	s, repos := newTestService()
+	completedAt := time.Date(2025, 4, 1, 9, 0, 0, 0, time.UTC)
+	action := seedAction(t, repos, "u1", &completedAt)
+
+	for _, body := range []string{`{"content": "鏡の前で練習する"}`, `{"completed_at": null}`} {
+		t.Run(body, func(t *testing.T) {
+			var input refuelapi.ActionUpdateInput
+			if err := json.Unmarshal([]byte(body), &input); err != nil {
+				t.Fatalf("Unmarshal: %v", err)
+			}
+			resp, err := s.UpdateAction(requestAs("u1"), int64(action.ID), input)
+			checkResponse(t, resp, err, http.StatusOK, "")
+			got := resp.Body.(refuelapi.Action)
+			if got.CompletedAt == nil || !got.CompletedAt.Equal(completedAt) {
+				t.Errorf("got completed_at %v, want %v", got.CompletedAt, completedAt)
+			}
+			if got.Feedback != nil {
+				t.Errorf("got feedback %+v for an action completed before", got.Feedback)
+			}
+		})
+	}
+}
+
+func TestDryRunBadgeRuleTimeZone(t *testing.T) {
+	s, repos := newTestService()
+	// 23:00 and 01:00 the next day in Tokyo, both on April 1 in UTC.
+	late := time.Date(2025, 4, 1, 14, 0, 0, 0, time.UTC)
+	early := time.Date(2025, 4, 1, 16, 0, 0, 0, time.UTC)
+	seedAction(t, repos, "u1", &late)
+	seedAction(t, repos, "u1", &early)
+
+	tests := []struct {
+		name   string
+		tz     string
+		status int
+		code   i18n.Code
+		streak int64
+	}{
+		{"UTC by default", "", http.StatusOK, "", 1},
+		{"Tokyo", "Asia/Tokyo", http.StatusOK, "", 2},
+		{"invalid time zone", "Mars/Olympus", http.StatusBadRequest, i18n.InvalidTimeZone, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := refuelapi.BadgeRuleDryRunInput{Rule: "streak_days >= 2", UserId: "u1", Tz: tt.tz}
+			resp, err := s.DryRunBadgeRule(requestAs("admin"), input)
+			checkResponse(t, resp, err, tt.status, tt.code)
+			if tt.status != http.StatusOK {
+				return
+			}
+			result := resp.Body.(refuelapi.BadgeRuleDryRunResult)
+			if got := result.Conditions[0].Value; got != tt.streak {
+				t.Errorf("got a streak of %d days, want %d", got, tt.streak)
+			}
+			if result.Matched != (tt.streak >= 2) {
+				t.Errorf("got matched %v for a streak of %d days", result.Matched, tt.streak)
+			}
+		})
+	}
+}
+
+// seedAction stores a complex, goal and action of the user and returns the action.
+func seedAction(t *testing.T, repos *repository.Repositories, userID string, completedAt *time.Time) models.Action {
+	t.Helper()
+	ctx := context.Background()
+	complex := models.Complex{UserID: userID, Content: "人前で話すのが怖い", Category: "仕事"}
+	if err := repos.Complexes.Create(ctx, &complex); err != nil {
+		t.Fatalf("creating complex: %v", err)
+	}
+	goal := models.Goal{UserID: userID, ComplexID: complex.ID, Content: "発表する"}
+	if err := repos.Goals.Create(ctx, &goal); err != nil {
+		t.Fatalf("creating goal: %v", err)
+	}
+	action := models.Action{UserID: userID, GoalID: goal.ID, Content: "練習する", CompletedAt: completedAt}
+	if err := repos.Actions.Create(ctx, &action); err != nil {
+		t.Fatalf("creating action: %v", err)
+	}
+	return action
+}
+
+func TestGainLossQuantities(t *testing.T) {
+	s, repos := newTestService()
+	ctx := requestAs("u1")
+	april := func(day int) *time.Time {
+		at := time.Date(2025, 4, day, 12, 0, 0, 0, time.UTC)
+		return &at
+	}
+	first := seedAction(t, repos, "u1", april(1))
+	second := seedAction(t, repos, "u1", april(9))
+	goals := map[uint]string{first.GoalID: "A", second.GoalID: "B"}
+
+	gains := []struct {
+		name   string
+		action models.Action
+		input  refuelapi.GainInput
+		status int
+		code   i18n.Code
+	}{
+		{"quantitative", first, refuelapi.GainInput{Type: "quantitative", Description: "走った", Value: 3, Unit: "km"}, http.StatusCreated, ""},
+		{"unit alias", first, refuelapi.GainInput{Type: "quantitative", Description: "走った", Value: 2, Unit: "kilometers"}, http.StatusCreated, ""},
+		{"other goal", second, refuelapi.GainInput{Type: "quantitative", Description: "走った", Value: 5, Unit: "km"}, http.StatusCreated, ""},
+		{"qualitative", first, refuelapi.GainInput{Type: "qualitative", Description: "気分が良い"}, http.StatusCreated, ""},
+		{"no value", first, refuelapi.GainInput{Type: "quantitative", Description: "走った", Unit: "km"}, http.StatusBadRequest, i18n.ValidationFailed},
+		{"negative value", first, refuelapi.GainInput{Type: "quantitative", Description: "走った", Value: -1, Unit: "km"}, http.StatusBadRequest, i18n.ValidationFailed},
+		{"no unit", first, refuelapi.GainInput{Type: "quantitative", Description: "走った", Value: 3}, http.StatusBadRequest, i18n.ValidationFailed},
+		{"unknown unit", first, refuelapi.GainInput{Type: "quantitative", Description: "走った", Value: 3, Unit: "miles"}, http.StatusBadRequest, i18n.ValidationFailed},
+		{"qualitative with a value", first, refuelapi.GainInput{Type: "qualitative", Description: "気分が良い", Value: 1, Unit: "times"}, http.StatusBadRequest, i18n.ValidationFailed},
+	}
+	for _, tt := range gains {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := s.CreateActionGain(ctx, int64(tt.action.ID), tt.input)
+			checkResponse(t, resp, err, tt.status, tt.code)
+		})
+	}
+	resp, err := s.CreateActionLoss(ctx, int64(first.ID), refuelapi.LossInput{Type: "quantitative", Description: "出費", Value: 500, Unit: "円"})
+	checkResponse(t, resp, err, http.StatusCreated, "")
+
+	summaries := []struct {
+		name   string
+		goalID uint
+		kind   string
+		unit   string
+		bucket string
+		from   string
+		want   string
+	}{
+		{name: "by month", want: "2025-04-01 goalA gain km n=2 total=5\n2025-04-01 goalA loss yen n=1 total=500\n2025-04-01 goalB gain km n=1 total=5\n"},
+		{name: "by week", bucket: "week", want: "2025-03-31 goalA gain km n=2 total=5\n2025-03-31 goalA loss yen n=1 total=500\n2025-04-07 goalB gain km n=1 total=5\n"},
+		{name: "one goal", goalID: second.GoalID, want: "2025-04-01 goalB gain km n=1 total=5\n"},
+		{name: "losses", kind: "loss", want: "2025-04-01 goalA loss yen n=1 total=500\n"},
+		{name: "unit filter", unit: "kilometer", want: "2025-04-01 goalA gain km n=2 total=5\n2025-04-01 goalB gain km n=1 total=5\n"},
+		{name: "from", from: "2025-04-02", want: "2025-04-01 goalB gain km n=1 total=5\n"},
+	}
+	for _, tt := range summaries {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := s.GetGainLossSummary(ctx, int64(tt.goalID), tt.kind, tt.unit, tt.bucket, tt.from, "", "UTC")
+			checkResponse(t, resp, err, http.StatusOK, "")
+			var b strings.Builder
+			for _, row := range resp.Body.([]refuelapi.GainLossSummary) {
+				fmt.Fprintf(&b, "%s goal%s %s %s n=%d total=%g\n", row.BucketStart, goals[uint(row.GoalId)], row.Kind, row.Unit, row.Count, row.Total)
+			}
+			if got := b.String(); got != tt.want {
+				t.Errorf("got\n%swant\n%s", got, tt.want)
+			}
+		})
+	}
+
+	rejected := []struct {
+		name, kind, unit, bucket string
+		code                     i18n.Code
+	}{
+		{"unknown kind", "profit", "", "", i18n.InvalidChoice},
+		{"unknown unit", "", "miles", "", i18n.InvalidUnit},
+		{"unknown bucket", "", "", "year", i18n.InvalidBucket},
+	}
+	for _, tt := range rejected {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := s.GetGainLossSummary(ctx, 0, tt.kind, tt.unit, tt.bucket, "", "", "")
+			checkResponse(t, resp, err, http.StatusBadRequest, tt.code)
+		})
+	}
+}
+
+func TestApplyGoalMetric(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   *refuelapi.GoalMetric
+		want    string
+		wantErr bool
+	}{
+		{"cleared", nil, "<nil>", false},
+		{"unit and deadline normalized", &refuelapi.GoalMetric{Unit: "Kilograms", Baseline: 70, Target: 60, Direction: "decrease", Deadline: "2025-06-30"}, "&{kg 70 60 decrease 2025-06-30}", false},
+		{"no deadline", &refuelapi.GoalMetric{Unit: "km", Baseline: 0, Target: 42, Direction: "increase"}, "&{km 0 42 increase }", false},
+		{"unknown unit", &refuelapi.GoalMetric{Unit: "stone", Baseline: 11, Target: 10, Direction: "decrease"}, "", true},
+		{"target behind baseline", &refuelapi.GoalMetric{Unit: "kg", Baseline: 60, Target: 70, Direction: "decrease"}, "", true},
+		{"invalid deadline", &refuelapi.GoalMetric{Unit: "kg", Baseline: 70, Target: 60, Direction: "decrease", Deadline: "June"}, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			baseline := 1.0
+			goal := models.Goal{MetricUnit: "km", MetricBaseline: &baseline, MetricTarget: &baseline, MetricDirection: "increase"}
+			err := applyGoalMetric(&goal, tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("got %+v, want an error", mapGoalMetric(goal))
+				}
+				if goal.MetricUnit != "km" {
+					t.Errorf("got unit %q after an error, want the goal left alone", goal.MetricUnit)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("applyGoalMetric: %v", err)
+			}
+			if got := fmt.Sprint(mapGoalMetric(goal)); got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFeedbackStreak(t *testing.T) {
+	s, repos := newTestService()
+	ctx := context.Background()
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Fatalf("loading location: %v", err)
+	}
+	action := seedAction(t, repos, "u1", nil)
+	// A daily action started ten days ago, done on each of the last three days.
+	action.RecurrencePattern = &recurrence.Pattern{Frequency: recurrence.Daily, Interval: 1, TimeOfDay: "00:00"}
+	action.CreatedAt = time.Now().AddDate(0, 0, -10)
+	if err := repos.Actions.Update(ctx, &action); err != nil {
+		t.Fatalf("updating action: %v", err)
+	}
+	today := checkin.Civil(time.Now(), tokyo)
+	for i := 0; i < 3; i++ {
+		done := models.ActionCompletion{ActionID: action.ID, UserID: "u1", OccurrenceDate: today.AddDate(0, 0, -i), Status: models.CompletionDone}
+		if err := repos.Actions.SaveCompletion(ctx, &done); err != nil {
+			t.Fatalf("saving completion: %v", err)
+		}
+	}
+
+	c, err := s.feedbackContext(ctx, "u1", action, nil, tokyo)
+	if err != nil {
+		t.Fatalf("feedbackContext: %v", err)
+	}
+	if c.Streak != 3 {
+		t.Errorf("got streak %d, want 3", c.Streak)
+	}
+
+	// The category template outranks a short streak; each is then passed
+	// over while it was shown recently.
+	for _, want := range []string{"category_skill", "streak_3", "step_forward", "power_of_frustration"} {
+		got := s.feedbackFor(ctx, "u1", action, nil, tokyo)
+		if got == nil || got.Code != want {
+			t.Fatalf("got %+v, want %s", got, want)
+		}
+	}
+}
+
+func TestSearchValidation(t *testing.T) {
+	s, repos := newTestService()
+	if err := repos.Complexes.Create(context.Background(), &models.Complex{UserID: "u1", Content: "人前で緊張する", Category: "仕事"}); err != nil {
+		t.Fatalf("creating complex: %v", err)
+	}
+	tests := []struct {
+		name   string
+		q      string
+		type_  string
+		limit  int32
+		status int
+		code   i18n.Code
+	}{
+		{"found", "緊張", "", 0, http.StatusOK, ""},
+		{"found by type", "緊張", searchComplex, 5, http.StatusOK, ""},
+		// The limit and type are checked before the query.
+		{"limit too small", "", "", -1, http.StatusBadRequest, i18n.OutOfRange},
+		{"limit too large", "", "", repository.MaxPageSize + 1, http.StatusBadRequest, i18n.OutOfRange},
+		{"unknown type", "", "badge", 0, http.StatusBadRequest, i18n.InvalidChoice},
+		{"empty query", " 　", "", 0, http.StatusBadRequest, i18n.InvalidQuery},
+		{"query too long", strings.Repeat("緊", 1000), "", 0, http.StatusBadRequest, i18n.InvalidQuery},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := s.Search(requestAs("u1"), tt.q, tt.type_, tt.limit)
+			checkResponse(t, resp, err, tt.status, tt.code)
+		})
+	}
+
+	resp, err := s.Search(requestAs(""), "緊張", "", 0)
+	checkResponse(t, resp, err, http.StatusUnauthorized, i18n.NotAuthenticated)
+}
+
+// trashContents lists the trash of the user as "kind:id" entries, newest
+// first.
+func trashContents(t *testing.T, s APIService, userID string) []string {
+	t.Helper()
+	resp, err := s.GetTrash(requestAs(userID))
+	checkResponse(t, resp, err, http.StatusOK, "")
+	var got []string
+	for _, item := range resp.Body.([]refuelapi.TrashItem) {
+		if want := item.DeletedAt.Add(s.TrashConfig.Retention); !item.PurgeAt.Equal(want) {
+			t.Errorf("%s %d: got purge_at %v, want %v", item.Type, item.Id, item.PurgeAt, want)
+		}
+		got = append(got, fmt.Sprintf("%s:%d", item.Type, item.Id))
+	}
+	return got
+}
+
+func TestTrashRestore(t *testing.T) {
+	s, repos := newTestService()
+	ctx := requestAs("u1")
+	first := seedAction(t, repos, "u1", nil)
+	second := models.Action{UserID: "u1", GoalID: first.GoalID, Content: "録画を見返す"}
+	if err := repos.Actions.Create(context.Background(), &second); err != nil {
+		t.Fatalf("creating action: %v", err)
+	}
+	goal, err := repos.Goals.Get(context.Background(), "u1", first.GoalID)
+	if err != nil {
+		t.Fatalf("getting goal: %v", err)
+	}
+	complexID := goal.ComplexID
+	entry := func(kind string, id uint) string { return fmt.Sprintf("%s:%d", kind, id) }
+	sameList := func(got, want []string) bool { return strings.Join(got, ",") == strings.Join(want, ",") }
+
+	resp, err := s.DeleteAction(ctx, int64(second.ID))
+	checkResponse(t, resp, err, http.StatusNoContent, "")
+	resp, err = s.DeleteComplex(ctx, int64(complexID))
+	checkResponse(t, resp, err, http.StatusNoContent, "")
+
+	// The goal and the first action went with the complex and are hidden;
+	// the second action was deleted on its own and is listed.
+	want := []string{entry(repository.TrashComplex, complexID), entry(repository.TrashAction, second.ID)}
+	if got := trashContents(t, s, "u1"); !sameList(got, want) {
+		t.Fatalf("got trash %v, want %v", got, want)
+	}
+	if got := trashContents(t, s, "u2"); len(got) != 0 {
+		t.Fatalf("got trash %v for another user, want it empty", got)
+	}
+
+	rejected := []struct {
+		name   string
+		userID string
+		kind   string
+		id     uint
+		status int
+		code   i18n.Code
+	}{
+		{"unknown type", "u1", "badge", complexID, http.StatusBadRequest, i18n.InvalidChoice},
+		{"not in the trash", "u1", repository.TrashComplex, complexID + 1000, http.StatusNotFound, i18n.TrashItemNotFound},
+		{"another user's item", "u2", repository.TrashComplex, complexID, http.StatusNotFound, i18n.TrashItemNotFound},
+		{"goal in the trash", "u1", repository.TrashAction, second.ID, http.StatusConflict, i18n.ParentInTrash},
+		{"complex in the trash", "u1", repository.TrashGoal, first.GoalID, http.StatusConflict, i18n.ParentInTrash},
+	}
+	for _, tt := range rejected {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := s.RestoreTrashItem(requestAs(tt.userID), tt.kind, int64(tt.id))
+			checkResponse(t, resp, err, tt.status, tt.code)
+		})
+	}
+
+	// Restoring the complex brings back what was deleted with it, but not
+	// the action deleted before.
+	resp, err = s.RestoreTrashItem(ctx, repository.TrashComplex, int64(complexID))
+	checkResponse(t, resp, err, http.StatusNoContent, "")
+	if _, err := repos.Goals.Get(context.Background(), "u1", first.GoalID); err != nil {
+		t.Errorf("getting restored goal: %v", err)
+	}
+	if _, err := repos.Actions.Get(context.Background(), "u1", first.ID); err != nil {
+		t.Errorf("getting restored action: %v", err)
+	}
+	if _, err := repos.Actions.Get(context.Background(), "u1", second.ID); err != repository.ErrNotFound {
+		t.Errorf("getting action deleted on its own: got error %v, want %v", err, repository.ErrNotFound)
+	}
+	want = []string{entry(repository.TrashAction, second.ID)}
+	if got := trashContents(t, s, "u1"); !sameList(got, want) {
+		t.Fatalf("got trash %v, want %v", got, want)
+	}
+
+	resp, err = s.RestoreTrashItem(ctx, repository.TrashAction, int64(second.ID))
+	checkResponse(t, resp, err, http.StatusNoContent, "")
+	if got := trashContents(t, s, "u1"); len(got) != 0 {
+		t.Fatalf("got trash %v, want it empty", got)
+	}
+}
+
+func TestTrashPurge(t *testing.T) {
+	s, repos := newTestService()
+	ctx := requestAs("u1")
+	action := seedAction(t, repos, "u1", nil)
+	resp, err := s.DeleteGoal(ctx, int64(action.GoalID))
+	checkResponse(t, resp, err, http.StatusNoContent, "")
+
+	if purged, err := repos.Trash.Purge(context.Background(), time.Now().Add(-time.Hour)); err != nil || purged != 0 {
+		t.Fatalf("purging records deleted over an hour ago: got %d, %v, want 0", purged, err)
+	}
+	if got := trashContents(t, s, "u1"); len(got) != 1 {
+		t.Fatalf("got trash %v, want the goal", got)
+	}
+
+	if _, err := repos.Trash.Purge(context.Background(), time.Now().Add(time.Second)); err != nil {
+		t.Fatalf("purging: %v", err)
+	}
+	if got := trashContents(t, s, "u1"); len(got) != 0 {
+		t.Fatalf("got trash %v, want it empty", got)
+	}
+	resp, err = s.RestoreTrashItem(ctx, repository.TrashGoal, int64(action.GoalID))
+	checkResponse(t, resp, err, http.StatusNotFound, i18n.TrashItemNotFound)
+	if _, err := repos.Actions.Get(context.Background(), "u1", action.ID); err != repository.ErrNotFound {
+		t.Errorf("getting purged action: got error %v, want %v", err, repository.ErrNotFound)
+	}
+}
+
+func TestComplexHistory(t *testing.T) {
+	s, _ := newTestService()
+	ctx := requestAs("u1")
+	resp, err := s.CreateComplex(ctx, refuelapi.ComplexInput{Content: "人前で話すのが怖い", Category: "仕事"})
+	checkResponse(t, resp, err, http.StatusCreated, "")
+	id := resp.Body.(refuelapi.Complex).Id
+	resp, err = s.UpdateComplex(ctx, id, refuelapi.ComplexInput{Content: "大勢の前で話すのが怖い", Category: "仕事"})
+	checkResponse(t, resp, err, http.StatusOK, "")
+	resp, err = s.DeleteComplex(ctx, id)
+	checkResponse(t, resp, err, http.StatusNoContent, "")
+	resp, err = s.RestoreTrashItem(ctx, repository.TrashComplex, id)
+	checkResponse(t, resp, err, http.StatusNoContent, "")
+
+	resp, err = s.GetComplexHistory(ctx, id)
+	checkResponse(t, resp, err, http.StatusOK, "")
+	want := []struct {
+		action  string
+		changes []refuelapi.RevisionChange
+	}{
+		{"create", []refuelapi.RevisionChange{
+			{Field: "category", After: "仕事"},
+			{Field: "content", After: "人前で話すのが怖い"},
+			{Field: "trigger_episode", After: ""},
+		}},
+		{"update", []refuelapi.RevisionChange{
+			{Field: "content", Before: "人前で話すのが怖い", After: "大勢の前で話すのが怖い"},
+		}},
+		{"delete", []refuelapi.RevisionChange{
+			{Field: "category", Before: "仕事"},
+			{Field: "content", Before: "大勢の前で話すのが怖い"},
+			{Field: "trigger_episode", Before: ""},
+		}},
+		{"restore", []refuelapi.RevisionChange{}},
+	}
+	revisions := resp.Body.([]refuelapi.Revision)
+	if len(revisions) != len(want) {
+		t.Fatalf("got %d revisions (%+v), want %d", len(revisions), revisions, len(want))
+	}
+	for i, rev := range revisions {
+		if rev.Action != want[i].action || rev.Actor != "u1" {
+			t.Errorf("revision %d: got %s by %s, want %s by u1", i, rev.Action, rev.Actor, want[i].action)
+		}
+		if fmt.Sprint(rev.Changes) != fmt.Sprint(want[i].changes) {
+			t.Errorf("revision %d (%s): got changes %+v, want %+v", i, rev.Action, rev.Changes, want[i].changes)
+		}
+	}
+
+	rejected := []struct {
+		name   string
+		userID string
+		id     int64
+	}{
+		{"unknown complex", "u1", id + 1000},
+		{"another user's complex", "u2", id},
+	}
+	for _, tt := range rejected {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := s.GetComplexHistory(requestAs(tt.userID), tt.id)
+			checkResponse(t, resp, err, http.StatusNotFound, i18n.ComplexNotFound)
+		})
+	}
+}
+
+func TestMyExport(t *testing.T) {
+	s, repos := newTestService()
+	ctx := requestAs("u1")
+	resp, err := s.CreateMyExport(ctx)
+	checkResponse(t, resp, err, http.StatusAccepted, "")
+	job := resp.Body.(refuelapi.ExportJob)
+	if got, want := resp.Headers["Location"], []string{fmt.Sprintf("/api/v1/me/exports/%d", job.Id)}; fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("got Location %v, want %v", got, want)
+	}
+	if job.Status != models.ExportPending || job.ArchiveUrl != "" {
+		t.Errorf("got status %s and archive %q, want %s and none", job.Status, job.ArchiveUrl, models.ExportPending)
+	}
+
+	// A job still to run is reused.
+	resp, err = s.CreateMyExport(ctx)
+	checkResponse(t, resp, err, http.StatusAccepted, "")
+	if got := resp.Body.(refuelapi.ExportJob).Id; got != job.Id {
+		t.Errorf("got job %d, want the pending job %d", got, job.Id)
+	}
+
+	resp, err = s.GetMyExportArchive(ctx, job.Id)
+	checkResponse(t, resp, err, http.StatusConflict, i18n.ExportNotReady)
+	for _, userID := range []string{"u1", "u2"} {
+		id := job.Id
+		if userID == "u1" {
+			id += 1000
+		}
+		resp, err = s.GetMyExport(requestAs(userID), id)
+		checkResponse(t, resp, err, http.StatusNotFound, i18n.ExportNotFound)
+		resp, err = s.GetMyExportArchive(requestAs(userID), id)
+		checkResponse(t, resp, err, http.StatusNotFound, i18n.ExportNotFound)
+	}
+
+	claimed, err := repos.Exports.ClaimJob(context.Background(), time.Now().Add(-time.Hour), time.Now())
+	if err != nil {
+		t.Fatalf("claiming job: %v", err)
+	}
+	now := time.Now()
+	claimed.Status, claimed.CompletedAt, claimed.ExpiresAt = models.ExportCompleted, &now, &now
+	claimed.Archive = base64.StdEncoding.EncodeToString([]byte("archive"))
+	if err := repos.Exports.FinishJob(context.Background(), claimed); err != nil {
+		t.Fatalf("finishing job: %v", err)
+	}
+
+	resp, err = s.GetMyExport(ctx, job.Id)
+	checkResponse(t, resp, err, http.StatusOK, "")
+	if got, want := resp.Body.(refuelapi.ExportJob).ArchiveUrl, fmt.Sprintf("/api/v1/me/exports/%d/archive", job.Id); got != want {
+		t.Errorf("got archive URL %q, want %q", got, want)
+	}
+	resp, err = s.GetMyExportArchive(ctx, job.Id)
+	checkResponse(t, resp, err, http.StatusOK, "")
+	f := resp.Body.(*os.File)
+	defer f.Close()
+	if content, err := io.ReadAll(f); err != nil || string(content) != "archive" {
+		t.Errorf("got archive %q (%v), want %q", content, err, "archive")
+	}
+
+	// Once finished, a new export can be requested.
+	resp, err = s.CreateMyExport(ctx)
+	checkResponse(t, resp, err, http.StatusAccepted, "")
+	if got := resp.Body.(refuelapi.ExportJob).Id; got == job.Id {
+		t.Errorf("got the finished job %d, want a new one", got)
+	}
+}
+
+func TestAfterAchieved(t *testing.T) {
+	at := time.Date(2025, 4, 1, 9, 0, 0, 0, time.UTC)
+	existing := []models.Milestone{
+		{ID: 1, Position: 1, Basis: "percent", Threshold: 25, AchievedAt: &at},
+		{ID: 2, Position: 2, Basis: "percent", Threshold: 50, AchievedAt: &at},
+		{ID: 3, Position: 3, Basis: "percent", Threshold: 75},
+	}
+	planned := []models.Milestone{
+		{Position: 1, Basis: "percent", Threshold: 25},
+		{Position: 2, Basis: "percent", Threshold: 50},
+		{Position: 3, Basis: "percent", Threshold: 60},
+		{Position: 4, Basis: "percent", Threshold: 100},
+	}
+	milestones, replaced := afterAchieved(existing, planned)
+
+	var got []string
+	for _, m := range milestones {
+		got = append(got, fmt.Sprintf("%d:%g", m.Position, m.Threshold))
+	}
+	if want := []string{"3:60", "4:100"}; fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("got milestones %v, want %v", got, want)
+	}
+	if len(replaced) != 1 || replaced[0].ID != 3 {
+		t.Errorf("got replaced %+v, want only the unachieved milestone 3", replaced)
+	}
+}
+
+func TestMapCheckin(t *testing.T) {
+	scheduledAt := time.Date(2025, 4, 1, 9, 0, 0, 0, time.UTC)
+	completedAt := time.Date(2025, 4, 2, 21, 30, 0, 0, time.UTC)
+	day := func(d int) time.Time { return time.Date(2025, 4, d, 0, 0, 0, 0, time.UTC) }
+	tests := []struct {
+		name string
+		occ  checkin.Occurrence
+		want string
+	}{
+		{
+			"missed",
+			checkin.Occurrence{Date: day(1), ScheduledAt: &scheduledAt, Status: checkin.Missed},
+			`{"action_id":7,"date":"2025-04-01","scheduled_at":"2025-04-01T09:00:00Z","status":"missed"}`,
+		},
+		{
+			"skipped",
+			checkin.Occurrence{Date: day(1), ScheduledAt: &scheduledAt, Status: checkin.Skipped,
+				Completion: &models.ActionCompletion{Status: models.CompletionSkipped, Note: "雨"}},
+			`{"action_id":7,"date":"2025-04-01","scheduled_at":"2025-04-01T09:00:00Z","status":"skipped","note":"雨"}`,
+		},
+		{
+			"done off schedule",
+			checkin.Occurrence{Date: day(2), Status: checkin.Done,
+				Completion: &models.ActionCompletion{Status: models.CompletionDone, CompletedAt: &completedAt}},
+			`{"action_id":7,"date":"2025-04-02","status":"done","completed_at":"2025-04-02T21:30:00Z"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := json.Marshal(mapCheckin(7, tt.occ))
+			if err != nil {
+				t.Fatalf("encoding: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGoalProgressLastMeasuredAt(t *testing.T) {
+	s, repos := newTestService()
+	ctx := context.Background()
+	action := seedAction(t, repos, "u1", nil)
+	goal, err := repos.Goals.Get(ctx, "u1", action.GoalID)
+	if err != nil {
+		t.Fatalf("getting goal: %v", err)
+	}
+	baseline, target := 70.0, 60.0
+	goal.MetricUnit, goal.MetricBaseline, goal.MetricTarget, goal.MetricDirection = "kg", &baseline, &target, "decrease"
+
+	encoded := func() map[string]interface{} {
+		t.Helper()
+		res, err := s.goalProgress(ctx, *goal, time.UTC)
+		if err != nil {
+			t.Fatalf("goalProgress: %v", err)
+		}
+		data, err := json.Marshal(res)
+		if err != nil {
+			t.Fatalf("encoding: %v", err)
+		}
+		fields := map[string]interface{}{}
+		if err := json.Unmarshal(data, &fields); err != nil {
+			t.Fatalf("decoding: %v", err)
+		}
+		return fields
+	}
+
+	if got, ok := encoded()["last_measured_at"]; ok {
+		t.Errorf("got last_measured_at %v without measurements, want it omitted", got)
+	}
+	measuredAt := time.Date(2025, 4, 1, 7, 0, 0, 0, time.UTC)
+	if err := repos.Goals.CreateMeasurement(ctx, &models.GoalMeasurement{UserID: "u1", GoalID: goal.ID, Value: 68, MeasuredAt: measuredAt}); err != nil {
+		t.Fatalf("creating measurement: %v", err)
+	}
+	if got := encoded()["last_measured_at"]; got != "2025-04-01T07:00:00Z" {
+		t.Errorf("got last_measured_at %v, want 2025-04-01T07:00:00Z", got)
+	}
+}
+
+func TestMapMilestone(t *testing.T) {
+	createdAt := time.Date(2025, 4, 1, 9, 0, 0, 0, time.UTC)
+	achievedAt := time.Date(2025, 4, 20, 7, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name       string
+		achievedAt *time.Time
+		want       string
+	}{
+		{"unachieved", nil, `{"id":3,"goal_id":2,"position":1,"title":"68kg","basis":"metric","threshold":68,"achieved":false,"created_at":"2025-04-01T09:00:00Z"}`},
+		{"achieved", &achievedAt, `{"id":3,"goal_id":2,"position":1,"title":"68kg","basis":"metric","threshold":68,"achieved":true,"achieved_at":"2025-04-20T07:00:00Z","created_at":"2025-04-01T09:00:00Z"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := models.Milestone{ID: 3, GoalID: 2, Position: 1, Title: "68kg", Basis: "metric", Threshold: 68, AchievedAt: tt.achievedAt, CreatedAt: createdAt}
+			got, err := json.Marshal(mapMilestone(m))
+			if err != nil {
+				t.Fatalf("encoding: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAgendaListsScheduledOccurrencesOnly(t *testing.T) {
+	s, repos := newTestService()
+	ctx := context.Background()
+	day := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+	// A daily action at 21:00 and one at 07:00, both done that day, and a
+	// non-recurring action of the same goal also checked in that day.
+	evening := seedAction(t, repos, "u1", nil)
+	morning := models.Action{UserID: "u1", GoalID: evening.GoalID, Content: "朝に練習する"}
+	once := models.Action{UserID: "u1", GoalID: evening.GoalID, Content: "発表会に申し込む"}
+	for _, a := range []*models.Action{&morning, &once} {
+		if err := repos.Actions.Create(ctx, a); err != nil {
+			t.Fatalf("creating action: %v", err)
+		}
+	}
+	for _, a := range []struct {
+		action *models.Action
+		at     string
+	}{{&evening, "21:00"}, {&morning, "07:00"}, {&once, ""}} {
+		if a.at != "" {
+			a.action.RecurrencePattern = &recurrence.Pattern{Frequency: recurrence.Daily, Interval: 1, TimeOfDay: a.at}
+		}
+		a.action.CreatedAt = day.AddDate(0, 0, -7)
+		if err := repos.Actions.Update(ctx, a.action); err != nil {
+			t.Fatalf("updating action: %v", err)
+		}
+		done := models.ActionCompletion{ActionID: a.action.ID, UserID: "u1", OccurrenceDate: day, Status: models.CompletionDone}
+		if err := repos.Actions.SaveCompletion(ctx, &done); err != nil {
+			t.Fatalf("saving completion: %v", err)
+		}
+	}
+
+	resp, err := s.GetAgenda(requestAs("u1"), "2025-04-01", "UTC")
+	checkResponse(t, resp, err, http.StatusOK, "")
+	agenda := resp.Body.(refuelapi.Agenda)
+	if len(agenda.Complexes) != 1 || len(agenda.Complexes[0].Goals) != 1 {
+		t.Fatalf("got %+v, want the one goal", agenda)
+	}
+	var got []string
+	for _, item := range agenda.Complexes[0].Goals[0].Items {
+		if item.Occurrence.ScheduledAt == nil {
+			t.Errorf("action %d: got an unscheduled occurrence on the agenda", item.ActionId)
+			continue
+		}
+		got = append(got, fmt.Sprintf("%d %s %s", item.ActionId, item.Occurrence.ScheduledAt.Format("15:04"), item.Occurrence.Status))
+	}
+	want := []string{fmt.Sprintf("%d 07:00 done", morning.ID), fmt.Sprintf("%d 21:00 done", evening.ID)}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("got items %v, want %v", got, want)
+	}
+
+	// The day's check-in of the non-recurring action is still in its own list.
+	resp, err = s.GetActionCheckins(requestAs("u1"), int64(once.ID), "2025-04-01", "2025-04-01", "UTC")
+	checkResponse(t, resp, err, http.StatusOK, "")
+	if checkins := resp.Body.([]refuelapi.ActionCheckin); len(checkins) != 1 || checkins[0].ScheduledAt != nil {
+		t.Errorf("got check-ins %+v, want the one unscheduled check-in", checkins)
+	}
+}
+
+func TestValidationDetailLanguage(t *testing.T) {
+	s, repos := newTestService()
+	action := seedAction(t, repos, "u1", nil)
+	tests := []struct {
+		name string
+		lang string
+		call func(ctx context.Context) Problem
+		want string
+	}{
+		{
+			name: "gain type in Japanese",
+			lang: "ja",
+			call: func(ctx context.Context) Problem {
+				resp, _ := s.CreateActionGain(ctx, int64(action.ID), refuelapi.GainInput{Type: "mixed", Description: "走った"})
+				return resp.Body.(Problem)
+			},
+			want: `入力内容に誤りがあります: Gainの type は "quantitative" か "qualitative" のいずれかです`,
+		},
+		{
+			name: "gain type in English",
+			lang: "en",
+			call: func(ctx context.Context) Problem {
+				resp, _ := s.CreateActionGain(ctx, int64(action.ID), refuelapi.GainInput{Type: "mixed", Description: "走った"})
+				return resp.Body.(Problem)
+			},
+			want: `Validation failed: gain type must be "quantitative" or "qualitative"`,
+		},
+		{
+			name: "unknown unit in Japanese",
+			lang: "ja",
+			call: func(ctx context.Context) Problem {
+				resp, _ := s.CreateActionLoss(ctx, int64(action.ID), refuelapi.LossInput{Type: "quantitative", Description: "出費", Value: 5, Unit: "ドル"})
+				return resp.Body.(Problem)
+			},
+			want: `入力内容に誤りがあります: "ドル" は使えない単位です。` + strings.Join(quantity.Units, ", ") + " のいずれかを指定してください",
+		},
+		{
+			name: "milestone basis in Japanese",
+			lang: "ja",
+			call: func(ctx context.Context) Problem {
+				resp, _ := s.GenerateGoalMilestones(ctx, int64(action.GoalID), refuelapi.MilestonePlanInput{Basis: "weeks"})
+				return resp.Body.(Problem)
+			},
+			want: `マイルストーンの設定が正しくありません: basis は "metric" か "completed_actions" のいずれかです`,
+		},
+		{
+			name: "milestone basis in English",
+			lang: "en",
+			call: func(ctx context.Context) Problem {
+				resp, _ := s.GenerateGoalMilestones(ctx, int64(action.GoalID), refuelapi.MilestonePlanInput{Basis: "weeks"})
+				return resp.Body.(Problem)
+			},
+			want: `Invalid milestone plan: basis must be "metric" or "completed_actions"`,
+		},
+		{
+			name: "recurrence pattern in Japanese",
+			lang: "ja",
+			call: func(ctx context.Context) Problem {
+				err := recurrence.Pattern{Frequency: "yearly", TimeOfDay: "09:00"}.Validate()
+				return NewErrorResponse(ctx, http.StatusBadRequest, i18n.InvalidRecurrencePattern, err)
+			},
+			want: "繰り返しパターンが正しくありません: recurrence_pattern.frequency は daily、weekly、monthly のいずれかです",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := requestAs("u1")
+			ctx.Request.Header.Set("Accept-Language", tt.lang)
+			if got := tt.call(ctx).Detail; got != tt.want {
+				t.Errorf("got detail %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package app
+
+import (
+	"context"
+	"log"
+	"net/http"
+
+	"refuel/backend/audit"
+	refuelapi "refuel/backend/generated/go"
+	"refuel/backend/i18n"
+	"refuel/backend/repository"
+)
+
+// trashNouns names the records of each kind the trash holds.
+var trashNouns = map[string]i18n.Code{
+	repository.TrashComplex: i18n.NounComplex,
+	repository.TrashGoal:    i18n.NounGoal,
+	repository.TrashAction:  i18n.NounAction,
+}
+
+// GetTrash - ゴミ箱の中身を取得
+func (s APIService) GetTrash(ctx context.Context) (refuelapi.ImplResponse, error) {
+	userID, resp := GetUserIDFromContext(ctx)
+	if resp != nil {
+		return *resp, nil
+	}
+
+	items, err := s.Trash.List(ctx, userID)
+	if err != nil {
+		return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(ctx, http.StatusInternalServerError, i18n.FetchFailed, i18n.NounTrash, err)}, nil
+	}
+
+	resItems := make([]refuelapi.TrashItem, len(items))
+	for i, item := range items {
+		resItems[i] = refuelapi.TrashItem{
+			Type:      item.Kind,
+			Id:        int64(item.ID),
+			Content:   item.Content,
+			DeletedAt: item.DeletedAt,
+			PurgeAt:   s.TrashConfig.PurgeAt(item.DeletedAt),
+		}
+	}
+
+	return refuelapi.ImplResponse{Code: http.StatusOK, Body: resItems}, nil
+}
+
+// RestoreTrashItem - ゴミ箱の記録を元に戻す
+func (s APIService) RestoreTrashItem(ctx context.Context, type_ string, id int64) (refuelapi.ImplResponse, error) {
+	userID, resp := GetUserIDFromContext(ctx)
+	if resp != nil {
+		return *resp, nil
+	}
+
+	noun, ok := trashNouns[type_]
+	if !ok {
+		return refuelapi.ImplResponse{Code: http.StatusBadRequest, Body: NewErrorResponse(ctx, http.StatusBadRequest, i18n.InvalidChoice, "type", "complex, goal, action")}, nil
+	}
+
+	switch err := s.Trash.Restore(ctx, userID, type_, uint(id)); err {
+	case nil:
+	case repository.ErrNotFound:
+		return refuelapi.ImplResponse{Code: http.StatusNotFound, Body: NewErrorResponse(ctx, http.StatusNotFound, i18n.TrashItemNotFound)}, nil
+	case repository.ErrParentDeleted:
+		return refuelapi.ImplResponse{Code: http.StatusConflict, Body: NewErrorResponse(ctx, http.StatusConflict, i18n.ParentInTrash)}, nil
+	default:
+		return refuelapi.ImplResponse{Code: http.StatusInternalServerError, Body: NewErrorResponse(ctx, http.StatusInternalServerError, i18n.RestoreFailed, noun, err)}, nil
+	}
+
+	s.recordRestore(ctx, userID, type_, uint(id))
+
+	return refuelapi.ImplResponse{Code: http.StatusNoContent}, nil
+}
+
+// recordRestore records the restore of a record from the trash. Restoring
+// changes none of the record's fields, so the revision has the same
+// snapshot before and after. Failures are only logged because the record
+// has already been restored.
+func (s APIService) recordRestore(ctx context.Context, userID, kind string, id uint) {
+	var (
+		entity string
+		record interface{}
+		err    error
+	)
+	switch kind {
+	case repository.TrashComplex:
+		entity = audit.Complex
+		record, err = s.Complexes.Get(ctx, userID, id)
+	case repository.TrashGoal:
+		entity = audit.Goal
+		record, err = s.Goals.Get(ctx, userID, id)
+	case repository.TrashAction:
+		entity = audit.Action
+		record, err = s.Actions.Get(ctx, userID, id)
+	}
+	if err != nil {
+		log.Printf("⚠️ Failed to fetch restored %s %d for user %s: %v", kind, id, userID, err)
+		return
+	}
+	s.recordRevision(ctx, userID, entity, id, audit.Restore, record, record)
+}
@@ -1,20 +1,31 @@
 package app
 
 import (
+	"context"
 	"fmt"
 	"log"
+	"net/http"
 	"os"
+	"strings"
 	"time"
+	_ "time/tzdata" // Embed the zone database; the alpine image ships without one.
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
-	"github.com/golang-migrate/migrate/v4"
-	_ "github.com/golang-migrate/migrate/v4/database/mysql"
-	_ "github.com/golang-migrate/migrate/v4/source/file"
-	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
+
+	"refuel/backend/auth"
+	"refuel/backend/auth/oidc"
+	"refuel/backend/badge"
+	"refuel/backend/database"
+	"refuel/backend/envelope"
+	"refuel/backend/export"
+	"refuel/backend/i18n"
+	"refuel/backend/models"
+	"refuel/backend/repository"
+	"refuel/backend/trash"
 	// Generated models will be used here, but we need to ensure they have GORM tags
 	// For now, we'll use a placeholder for modelsToMigrate.
 	// In a real scenario, you'd either add gorm tags to generated models
@@ -26,63 +37,138 @@ import (
 // AppContext holds shared application resources like DB connection and validator.
 type AppContext struct {
 	DB       *gorm.DB
+	Repos    *repository.Repositories
 	Validate *validator.Validate
+	// AdminUserIDs lists the users allowed to call admin-only endpoints.
+	AdminUserIDs []string
+	Auth         auth.Config
+	Tokens       *auth.Tokens
+	// OIDC is nil unless OIDC_ISSUER is set.
+	OIDC  *oidc.Provider
+	Trash trash.Config
+	// Exporter runs the account data export jobs.
+	Exporter *export.Worker
 }
 
-// ErrorResponse represents a structured error message.
-type ErrorResponse struct {
-	Code    int    `json:"code"`
-	Message string `json:"message"`
-}
-
-// NewErrorResponse Helper
-func NewErrorResponse(code int, message string) ErrorResponse {
-	return ErrorResponse{Code: code, Message: message}
+// publicPaths can be called without an access token.
+var publicPaths = map[string]bool{
+	"/api/v1/ping":                true,
+	"/api/v1/auth/register":       true,
+	"/api/v1/auth/login":          true,
+	"/api/v1/auth/refresh":        true,
+	"/api/v1/auth/logout":         true,
+	"/api/v1/auth/oidc/authorize": true,
+	"/api/v1/auth/oidc/callback":  true,
 }
 
-// AuthMiddleware provides a simple authentication mechanism for the MVP.
-func AuthMiddleware() gin.HandlerFunc {
+// AuthMiddleware authenticates requests with a bearer access token and stores
+// the user ID in the Gin context. Without a token the request is rejected,
+// unless dev mode is on, in which case the X-User-ID header (or a fixed test
+// user) is trusted instead.
+func AuthMiddleware(appCtx *AppContext) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		userID := c.GetHeader("X-User-ID")
-		if userID == "" {
-			userID = "user-test-123" // Dummy user ID for testing
-			log.Printf("Warning: X-User-ID header not found, using default test user ID: %s", userID)
+		if c.Request.Method == http.MethodOptions || publicPaths[c.Request.URL.Path] {
+			c.Next()
+			return
 		}
-		c.Set("userID", userID)
-		c.Next()
+
+		if token, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer "); ok {
+			userID, err := appCtx.Tokens.Verify(token)
+			if err != nil && appCtx.OIDC != nil {
+				// Not one of ours; accept an ID token from the identity provider instead.
+				userID, err = identityUserID(c.Request.Context(), appCtx, token)
+			}
+			if err != nil {
+				abortWithProblem(c, NewErrorResponse(c, http.StatusUnauthorized, i18n.InvalidAccessToken))
+				return
+			}
+			c.Set("userID", userID)
+			c.Next()
+			return
+		}
+
+		if appCtx.Auth.DevMode {
+			userID := c.GetHeader("X-User-ID")
+			if userID == "" {
+				userID = "user-test-123" // Dummy user ID for testing
+				log.Printf("Warning: X-User-ID header not found, using default test user ID: %s", userID)
+			}
+			c.Set("userID", userID)
+			c.Next()
+			return
+		}
+
+		abortWithProblem(c, NewErrorResponse(c, http.StatusUnauthorized, i18n.MissingBearerToken))
+	}
+}
+
+// identityUserID verifies an identity provider ID token and returns the user
+// its subject is linked to. Subjects are linked on first login through
+// /auth/oidc/callback; unlinked subjects are rejected.
+func identityUserID(ctx context.Context, appCtx *AppContext, token string) (string, error) {
+	claims, err := appCtx.OIDC.Verify(ctx, token)
+	if err != nil {
+		return "", err
 	}
+	identity, err := appCtx.Repos.Users.GetIdentity(ctx, claims.Issuer, claims.Subject)
+	if err != nil {
+		return "", err
+	}
+	return identity.UserID, nil
 }
 
 // SetupApp initializes the database, validator, and runs migrations.
 func SetupApp() (*AppContext, error) {
 	// --- Environment variable loading ---
-	dbUser := os.Getenv("DB_USER")
-	dbPassword := os.Getenv("DB_PASSWORD")
-	dbHost := os.Getenv("DB_HOST")
-	dbPort := os.Getenv("DB_PORT")
-	dbName := os.Getenv("DB_NAME")
-	migrationPath := os.Getenv("MIGRATION_PATH") // e.g., "file://./db/migrations"
-
-	requiredEnvVars := map[string]string{
-		"DB_USER":    dbUser,
-		"DB_PASSWORD": dbPassword,
-		"DB_HOST":    dbHost,
-		"DB_PORT":    dbPort,
-		"DB_NAME":    dbName,
-	}
-	for key, value := range requiredEnvVars {
-		if value == "" {
-			return nil, fmt.Errorf("🚨 Missing required environment variable: %s", key)
-		}
+	dbConfig, err := database.ConfigFromEnv()
+	if err != nil {
+		return nil, fmt.Errorf("🚨 Invalid database configuration: %v", err)
+	}
+	migrationPath := os.Getenv("MIGRATION_PATH") // e.g., "file://./db/migrations"; the driver's subdirectory is used
+
+	adminUserIDs := parseList(os.Getenv("ADMIN_USER_IDS"))
+
+	authConfig, err := auth.ConfigFromEnv()
+	if err != nil {
+		return nil, fmt.Errorf("🚨 Invalid auth configuration: %v", err)
+	}
+	oidcConfig, oidcEnabled, err := oidc.ConfigFromEnv()
+	if err != nil {
+		return nil, fmt.Errorf("🚨 Invalid OIDC configuration: %v", err)
+	}
+	var oidcProvider *oidc.Provider
+	if oidcEnabled {
+		oidcProvider = oidc.NewProvider(oidcConfig, nil)
+		log.Printf("🔑 OIDC login enabled with issuer %s", oidcConfig.Issuer)
+	}
+	trashConfig, err := trash.ConfigFromEnv()
+	if err != nil {
+		return nil, fmt.Errorf("🚨 Invalid trash configuration: %v", err)
+	}
+	exportConfig, err := export.ConfigFromEnv()
+	if err != nil {
+		return nil, fmt.Errorf("🚨 Invalid export configuration: %v", err)
+	}
+	keyring, err := envelope.ConfigFromEnv()
+	if err != nil {
+		return nil, fmt.Errorf("🚨 Invalid encryption configuration: %v", err)
+	}
+	if keyring != nil {
+		models.SetFieldCipher(envelope.NewCipher(keyring))
+		log.Printf("🔒 Sensitive fields are encrypted with master key %q", keyring.ActiveID())
+	} else {
+		log.Println("⚠️ ENCRYPTION_MASTER_KEYS is not set: complex contents, trigger episodes and gain/loss descriptions are stored in plaintext.")
+	}
+	if authConfig.DevMode {
+		log.Println("⚠️ AUTH_DEV_MODE is enabled: requests without a token are trusted via X-User-ID. Do not use in production.")
 	}
 
 	// --- Validator initialization ---
 	validate := validator.New()
+	// Name invalid fields as clients send them.
+	validate.RegisterTagNameFunc(jsonFieldName)
 
 	// --- Database connection (GORM) ---
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=True&loc=Local",
-		dbUser, dbPassword, dbHost, dbPort, dbName,
-	)
 	gormLogger := logger.New(
 		log.New(os.Stdout, "\r\n", log.LstdFlags),
 		logger.Config{
@@ -92,62 +178,104 @@ func SetupApp() (*AppContext, error) {
 			Colorful:                  true,
 		},
 	)
-	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{
+	db, err := database.Open(dbConfig, &gorm.Config{
 		Logger: gormLogger,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("🚨 Failed to connect to database: %v", err)
 	}
-	log.Println("🎉 Database connected successfully!")
+	log.Printf("🎉 Database connected successfully! (driver: %s)", dbConfig.Driver)
 
 	// --- Database Migrations ---
 	if migrationPath != "" {
-		if err := runMigrations(dbUser, dbPassword, dbHost, dbPort, dbName, migrationPath); err != nil {
+		if err := database.Migrate(dbConfig, migrationPath); err != nil {
 			return nil, fmt.Errorf("🚨 Failed to run migrations: %v", err)
 		}
 	} else {
 		log.Println("ℹ️ MIGRATION_PATH not set, skipping automated migrations. Ensure DB schema is up to date.")
-		// For initial setup, you might still want AutoMigrate here if no migrations are used.
-		// db.AutoMigrate(&refuelapi.ModelComplex{}, &refuelapi.ModelGoal{}, &refuelapi.ModelAction{}, ...)
+	}
+	// Refuse to serve against a schema the models were not written for.
+	if err := database.CheckSchema(db); err != nil {
+		return nil, fmt.Errorf("🚨 Database schema check failed: %v", err)
 	}
 
-	return &AppContext{DB: db, Validate: validate}, nil
-}
+	repos := repository.NewGorm(db)
 
-// runMigrations handles database migrations.
-func runMigrations(dbUser, dbPassword, dbHost, dbPort, dbName, migrationPath string) error {
-	dbURL := fmt.Sprintf("mysql://%s:%s@tcp(%s:%s)/%s",
-		dbUser, dbPassword, dbHost, dbPort, dbName,
-	)
-	m, err := migrate.New(migrationPath, dbURL)
-	if err != nil {
-		return fmt.Errorf("failed to initialize migrations: %w", err)
+	// --- Badge catalog ---
+	if err := badge.SyncCatalog(context.Background(), repos.Badges); err != nil {
+		return nil, fmt.Errorf("🚨 Failed to sync badge catalog: %v", err)
 	}
-	if err := m.Up(); err != nil && err != migrate.ErrNoChange {
-		return fmt.Errorf("failed to run migrations: %w", err)
+
+	// --- Trash purge ---
+	go trash.RunPurger(context.Background(), repos.Trash, trashConfig)
+	log.Printf("🗑️ Deleted records stay in the trash for %s", trashConfig.Retention)
+
+	// --- Data export ---
+	exporter := export.NewWorker(repos.Exports, exportConfig)
+	go exporter.Run(context.Background())
+	log.Printf("📦 Data exports are kept for %s", exportConfig.Retention)
+
+	return &AppContext{
+		DB:           db,
+		Repos:        repos,
+		Validate:     validate,
+		AdminUserIDs: adminUserIDs,
+		Auth:         authConfig,
+		Tokens:       auth.NewTokens(authConfig.Secret, authConfig.AccessTTL),
+		OIDC:         oidcProvider,
+		Trash:        trashConfig,
+		Exporter:     exporter,
+	}, nil
+}
+
+// parseList splits a comma-separated environment value, dropping empty entries.
+func parseList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
 	}
-	log.Println("🎉 Migrations applied successfully or no changes.")
-	srcErr, dbErr := m.Close()
-	if srcErr != nil {
-		log.Printf("⚠️ Error closing migration source: %v", srcErr)
+	return items
+}
+
+// ginContextKey keys the request's Gin context in its request context.
+type ginContextKey struct{}
+
+// GinContextMiddleware keeps the Gin context in the request context. The
+// generated controllers pass only the request's context to the services,
+// which read what the middlewares stored in the Gin context through it.
+func GinContextMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), ginContextKey{}, c))
+		c.Next()
 	}
-	if dbErr != nil {
-		log.Printf("⚠️ Error closing migration database connection: %v", dbErr)
+}
+
+// ginContext returns the Gin context of the request ctx belongs to.
+func ginContext(ctx context.Context) (*gin.Context, bool) {
+	if c, ok := ctx.(*gin.Context); ok {
+		return c, true
 	}
-	return nil
+	c, ok := ctx.Value(ginContextKey{}).(*gin.Context)
+	return c, ok
 }
 
 // SetupGinMiddlewares configures common Gin middlewares.
-func SetupGinMiddlewares(r *gin.Engine) {
+func SetupGinMiddlewares(r *gin.Engine, appCtx *AppContext) {
 	r.Use(gin.Logger())
 	r.Use(gin.Recovery())
+	r.Use(GinContextMiddleware())
 	r.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{"http://localhost:5173", "http://localhost:3000"},
 		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization", "X-User-ID"},
-		ExposeHeaders:    []string{"Content-Length"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Accept-Language", "Authorization", "X-User-ID", requestIDHeader},
+		ExposeHeaders:    []string{"Content-Length", "Link", requestIDHeader},
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
 	}))
-	r.Use(AuthMiddleware())
-}
\ No newline at end of file
+	r.Use(RequestIDMiddleware())
+	r.Use(ProblemMiddleware())
+	r.Use(LocaleMiddleware(appCtx.Repos.Users))
+	r.Use(AuthMiddleware(appCtx))
+}
@@ -0,0 +1,68 @@
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"refuel/backend/auth"
+)
+
+func TestAuthMiddleware(t *testing.T) {
+	tokens := auth.NewTokens([]byte("test-secret"), time.Minute)
+	token, _, err := tokens.Issue("u1", time.Now())
+	if err != nil {
+		t.Fatalf("Issue: %v", err)
+	}
+	expired, _, err := tokens.Issue("u1", time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("Issue: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		devMode bool
+		path    string
+		header  map[string]string
+		status  int
+		userID  string
+	}{
+		{"bearer token", false, "/api/v1/complexes", map[string]string{"Authorization": "Bearer " + token}, http.StatusOK, "u1"},
+		{"expired token", false, "/api/v1/complexes", map[string]string{"Authorization": "Bearer " + expired}, http.StatusUnauthorized, ""},
+		{"no token", false, "/api/v1/complexes", nil, http.StatusUnauthorized, ""},
+		{"X-User-ID outside dev mode", false, "/api/v1/complexes", map[string]string{"X-User-ID": "u2"}, http.StatusUnauthorized, ""},
+		{"public path", false, "/api/v1/auth/login", nil, http.StatusOK, ""},
+		{"dev mode X-User-ID", true, "/api/v1/complexes", map[string]string{"X-User-ID": "u2"}, http.StatusOK, "u2"},
+		{"dev mode default user", true, "/api/v1/complexes", nil, http.StatusOK, "user-test-123"},
+		// A bad token is rejected even in dev mode rather than falling back to X-User-ID.
+		{"dev mode expired token", true, "/api/v1/complexes", map[string]string{"Authorization": "Bearer " + expired, "X-User-ID": "u2"}, http.StatusUnauthorized, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			appCtx := &AppContext{Auth: auth.Config{DevMode: tt.devMode}, Tokens: tokens}
+			r := gin.New()
+			r.Use(AuthMiddleware(appCtx))
+			var userID string
+			r.GET(tt.path, func(c *gin.Context) {
+				userID = c.GetString("userID")
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			for k, v := range tt.header {
+				req.Header.Set(k, v)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			if w.Code != tt.status {
+				t.Fatalf("got status %d, want %d", w.Code, tt.status)
+			}
+			if userID != tt.userID {
+				t.Errorf("got user %q, want %q", userID, tt.userID)
+			}
+		})
+	}
+}
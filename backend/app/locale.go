@@ -0,0 +1,114 @@
+package app
+
+import (
+	"context"
+	"log"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"refuel/backend/i18n"
+	"refuel/backend/repository"
+)
+
+// localeKey is the Gin context key LocaleMiddleware stores the request's
+// *locale under.
+const localeKey = "locale"
+
+// localeContextKey is the request context key of the same *locale, for the
+// generated controllers, which only see the request.
+type localeContextKey struct{}
+
+// locale works out the language of one request's messages the first time
+// one is needed: the signed-in user's preference, then Accept-Language,
+// then i18n.Default. Resolving lazily keeps requests that never produce a
+// message from looking the user up.
+type locale struct {
+	c              *gin.Context
+	acceptLanguage string
+	users          repository.UserRepository
+	resolved       bool
+	lang           i18n.Lang
+}
+
+// LocaleMiddleware prepares the negotiation of the language the request's
+// messages are translated into.
+func LocaleMiddleware(users repository.UserRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		l := &locale{c: c, acceptLanguage: c.GetHeader("Accept-Language"), users: users}
+		c.Set(localeKey, l)
+		c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), localeContextKey{}, l))
+		c.Header("Vary", "Accept-Language")
+		c.Next()
+	}
+}
+
+func (l *locale) resolve() i18n.Lang {
+	if l.resolved {
+		return l.lang
+	}
+	l.resolved = true
+	l.lang = i18n.Default
+	if lang, ok := i18n.Negotiate(l.acceptLanguage); ok {
+		l.lang = lang
+	}
+	if userID := l.c.GetString("userID"); userID != "" && l.users != nil {
+		user, err := l.users.Get(l.c.Request.Context(), userID)
+		switch {
+		case err == nil:
+			if lang, ok := i18n.Parse(user.Language); ok {
+				l.lang = lang
+			}
+		case err != repository.ErrNotFound:
+			log.Printf("⚠️ Failed to fetch the language of user %s: %v", userID, err)
+		}
+	}
+	return l.lang
+}
+
+// requestLang returns the language to answer the request with. ctx is
+// either the request's Gin context or a context carrying it or its locale.
+func requestLang(ctx context.Context) i18n.Lang {
+	if l, ok := ctx.Value(localeContextKey{}).(*locale); ok {
+		return l.resolve()
+	}
+	ginCtx, ok := ginContext(ctx)
+	if !ok {
+		return i18n.Default
+	}
+	if v, ok := ginCtx.Get(localeKey); ok {
+		return v.(*locale).resolve()
+	}
+	if lang, ok := i18n.Negotiate(ginCtx.GetHeader("Accept-Language")); ok {
+		return lang
+	}
+	return i18n.Default
+}
+
+// fieldMessages maps validation tags to the message describing a field
+// that fails them, and whether the message quotes the tag's parameter.
+var fieldMessages = map[string]struct {
+	code      i18n.Code
+	withParam bool
+}{
+	"required": {i18n.FieldRequired, false},
+	"email":    {i18n.FieldEmail, false},
+	"min":      {i18n.FieldMin, true},
+	"gte":      {i18n.FieldMin, true},
+	"max":      {i18n.FieldMax, true},
+	"lte":      {i18n.FieldMax, true},
+	"oneof":    {i18n.FieldOneOf, true},
+}
+
+// jsonFieldName names struct fields in validation errors by their JSON key.
+func jsonFieldName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	switch name {
+	case "-":
+		return ""
+	case "":
+		return field.Name
+	}
+	return name
+}
@@ -0,0 +1,224 @@
+package app
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+
+	refuelapi "refuel/backend/generated/go"
+	"refuel/backend/i18n"
+)
+
+// problemContentType is the media type error responses are sent as
+// (RFC 7807).
+const problemContentType = "application/problem+json"
+
+// problemTypeBase prefixes the error code to form a problem's type URI.
+const problemTypeBase = "urn:refuel:problem:"
+
+// requestIDHeader carries the ID of a request, both ways.
+const requestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength bounds the request IDs accepted from clients.
+const maxRequestIDLength = 128
+
+// Problem is an error response, a problem details document (RFC 7807).
+// Detail is in the language negotiated for the request while Code, the
+// stable code Type is derived from, is never translated. Clients should
+// match on Code. Causes of server errors are logged under RequestID instead
+// of being returned.
+type Problem struct {
+	Type          string         `json:"type"`
+	Title         string         `json:"title"`
+	Status        int            `json:"status"`
+	Detail        string         `json:"detail"`
+	Code          string         `json:"code"`
+	InvalidParams []InvalidParam `json:"invalid_params,omitempty"`
+	RequestID     string         `json:"request_id,omitempty"`
+}
+
+// InvalidParam names an input that failed validation and why.
+type InvalidParam struct {
+	Name   string `json:"name"`
+	Reason string `json:"reason"`
+}
+
+// NewErrorResponse builds the problem with the given HTTP status and the
+// catalog message for code, translated for the request in ctx. Error args
+// are treated by kind: validator errors fill InvalidParams and are
+// described field by field, the causes of 5xx problems are logged and
+// left out of the message, and any other error is quoted, in the request's
+// language if it is worded by an i18n.Error.
+func NewErrorResponse(ctx context.Context, status int, code i18n.Code, args ...interface{}) Problem {
+	lang := requestLang(ctx)
+	p := Problem{
+		Type:      problemTypeBase + string(code),
+		Title:     i18n.StatusTitle(lang, status),
+		Status:    status,
+		Code:      string(code),
+		RequestID: requestID(ctx),
+	}
+
+	var causes []error
+	formatArgs := make([]interface{}, 0, len(args))
+	for _, arg := range args {
+		err, ok := arg.(error)
+		if !ok {
+			formatArgs = append(formatArgs, arg)
+			continue
+		}
+		var invalid validator.ValidationErrors
+		switch {
+		case errors.As(err, &invalid):
+			p.InvalidParams = invalidParams(lang, invalid)
+			reasons := make([]string, len(p.InvalidParams))
+			for i, param := range p.InvalidParams {
+				reasons[i] = param.Reason
+			}
+			formatArgs = append(formatArgs, strings.Join(reasons, "; "))
+		case status >= http.StatusInternalServerError:
+			causes = append(causes, err)
+		default:
+			formatArgs = append(formatArgs, err)
+		}
+	}
+	if len(causes) > 0 {
+		log.Printf("⚠️ Request %s failed with %s: %v", p.RequestID, code, errors.Join(causes...))
+	}
+	p.Detail = i18n.T(lang, code, formatArgs...)
+	return p
+}
+
+// abortWithProblem ends a request in a middleware with p.
+func abortWithProblem(c *gin.Context, p Problem) {
+	// Gin keeps a Content-Type that is already set when rendering JSON.
+	c.Header("Content-Type", problemContentType)
+	c.AbortWithStatusJSON(p.Status, p)
+}
+
+// ErrorHandler answers the errors the generated controllers run into
+// around the service, such as unparsable parameters or bodies and missing
+// required fields, with problems like the ones the service returns.
+func ErrorHandler(w http.ResponseWriter, r *http.Request, err error, result *refuelapi.ImplResponse) {
+	ctx := r.Context()
+	var (
+		parsingErr  *refuelapi.ParsingError
+		requiredErr *refuelapi.RequiredError
+		p           Problem
+	)
+	switch {
+	case errors.As(err, &parsingErr) && parsingErr.Param == "":
+		p = NewErrorResponse(ctx, http.StatusBadRequest, i18n.MalformedRequest)
+	case errors.As(err, &parsingErr):
+		p = NewErrorResponse(ctx, http.StatusBadRequest, i18n.InvalidParameter, parsingErr.Param)
+		p.InvalidParams = []InvalidParam{{Name: parsingErr.Param, Reason: p.Detail}}
+	case errors.As(err, &requiredErr):
+		reason := i18n.T(requestLang(ctx), i18n.FieldRequired, requiredErr.Field)
+		p = NewErrorResponse(ctx, http.StatusBadRequest, i18n.ValidationFailed, reason)
+		p.InvalidParams = []InvalidParam{{Name: requiredErr.Field, Reason: reason}}
+	case result == nil:
+		// The body was decoded but broke the schema's constraints.
+		p = NewErrorResponse(ctx, http.StatusBadRequest, i18n.ValidationFailed, err)
+	default:
+		p = NewErrorResponse(ctx, http.StatusInternalServerError, i18n.InternalError, err)
+	}
+	_ = refuelapi.EncodeJSONResponse(p, &p.Status, nil, w)
+}
+
+// invalidParams describes each field of invalid in lang.
+func invalidParams(lang i18n.Lang, invalid validator.ValidationErrors) []InvalidParam {
+	params := make([]InvalidParam, len(invalid))
+	for i, fe := range invalid {
+		params[i].Name = fe.Field()
+		m, ok := fieldMessages[fe.Tag()]
+		switch {
+		case !ok:
+			params[i].Reason = i18n.T(lang, i18n.FieldInvalid, fe.Field(), fe.Tag())
+		case m.withParam:
+			params[i].Reason = i18n.T(lang, m.code, fe.Field(), fe.Param())
+		default:
+			params[i].Reason = i18n.T(lang, m.code, fe.Field())
+		}
+	}
+	return params
+}
+
+// requestIDKey is the request context key RequestIDMiddleware stores the
+// request's ID under.
+type requestIDKey struct{}
+
+// RequestIDMiddleware gives every request an ID, echoed in the
+// X-Request-ID response header, that ties its problem to the server's log.
+// A well-formed ID sent by the client or a proxy is kept.
+func RequestIDMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(requestIDHeader)
+		if !validRequestID(id) {
+			id = newRequestID()
+		}
+		c.Header(requestIDHeader, id)
+		// The generated controllers only see the request, so keep the ID there.
+		c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), requestIDKey{}, id))
+		c.Next()
+	}
+}
+
+// requestID returns the ID of the request in ctx, which is either its Gin
+// context or its request context.
+func requestID(ctx context.Context) string {
+	if c, ok := ctx.(*gin.Context); ok && c.Request != nil {
+		ctx = c.Request.Context()
+	}
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', strings.ContainsRune("-_.:", r):
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestID() string {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		log.Printf("⚠️ Failed to generate a request ID: %v", err)
+		return ""
+	}
+	return hex.EncodeToString(buf)
+}
+
+// ProblemMiddleware labels the JSON bodies of error responses, which are
+// all problems, as application/problem+json.
+func ProblemMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Writer = &problemWriter{ResponseWriter: c.Writer}
+		c.Next()
+	}
+}
+
+type problemWriter struct {
+	gin.ResponseWriter
+}
+
+func (w *problemWriter) WriteHeader(status int) {
+	if status >= http.StatusBadRequest && strings.HasPrefix(w.Header().Get("Content-Type"), "application/json") {
+		w.Header().Set("Content-Type", problemContentType)
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
@@ -0,0 +1,116 @@
+// Package audit builds the revision history of users' records: the JSON
+// snapshots stored with every change and the field-level differences
+// between them.
+package audit
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+)
+
+// Actions a revision records.
+const (
+	Create  = "create"
+	Update  = "update"
+	Delete  = "delete"
+	Restore = "restore"
+)
+
+// Kinds of records with a revision history.
+const (
+	Complex     = "complex"
+	Goal        = "goal"
+	Action      = "action"
+	Gain        = "gain"
+	Loss        = "loss"
+	Measurement = "measurement"
+	Milestone   = "milestone"
+	Checkin     = "checkin"
+	Badge       = "badge"
+)
+
+// omitted lists the keys left out of snapshots: bookkeeping columns, which
+// the revision itself records or which change on every write, and the
+// records preloaded along with complexes, goals and actions, which have
+// revisions of their own. An action's gains and losses are kept, as they
+// can be edited together with the action.
+var omitted = map[string]bool{
+	"id":          true,
+	"user_id":     true,
+	"created_at":  true,
+	"updated_at":  true,
+	"deleted_at":  true,
+	"Complex":     true,
+	"Goal":        true,
+	"goals":       true,
+	"milestones":  true,
+	"completions": true,
+}
+
+// Snapshot encodes a record, a pointer to one of the structs in package
+// models, as the JSON object stored with a revision. A nil record encodes
+// as "".
+func Snapshot(record interface{}) (string, error) {
+	if v := reflect.ValueOf(record); !v.IsValid() || v.Kind() == reflect.Ptr && v.IsNil() {
+		return "", nil
+	}
+	data, err := json.Marshal(record)
+	if err != nil {
+		return "", err
+	}
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return "", err
+	}
+	for key := range omitted {
+		delete(fields, key)
+	}
+	// Maps encode with sorted keys, so equal records give equal snapshots.
+	data, err = json.Marshal(fields)
+	return string(data), err
+}
+
+// Change is the change of one field between two snapshots. Before or After
+// is nil where the field was absent or null.
+type Change struct {
+	Field  string
+	Before interface{}
+	After  interface{}
+}
+
+// Diff returns the fields whose values differ between two snapshots, in
+// order of field name. An empty snapshot has no fields, so every field of
+// the other one differs.
+func Diff(before, after string) ([]Change, error) {
+	from, err := decode(before)
+	if err != nil {
+		return nil, err
+	}
+	to, err := decode(after)
+	if err != nil {
+		return nil, err
+	}
+	var changes []Change
+	for field, value := range from {
+		if !reflect.DeepEqual(value, to[field]) {
+			changes = append(changes, Change{Field: field, Before: value, After: to[field]})
+		}
+	}
+	for field, value := range to {
+		if _, ok := from[field]; !ok && value != nil {
+			changes = append(changes, Change{Field: field, After: value})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Field < changes[j].Field })
+	return changes, nil
+}
+
+func decode(snapshot string) (map[string]interface{}, error) {
+	fields := map[string]interface{}{}
+	if snapshot == "" {
+		return fields, nil
+	}
+	err := json.Unmarshal([]byte(snapshot), &fields)
+	return fields, err
+}
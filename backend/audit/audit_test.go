@@ -0,0 +1,100 @@
+package audit
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+
+	"refuel/backend/models"
+)
+
+func TestSnapshot(t *testing.T) {
+	at := time.Date(2025, 4, 1, 9, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		record interface{}
+		want   string
+	}{
+		{"nil", nil, ""},
+		{"nil pointer", (*models.Complex)(nil), ""},
+		{
+			"bookkeeping and preloads omitted",
+			&models.Complex{
+				ID: 3, UserID: "u1", Content: "人前で話すのが怖い", Category: "仕事",
+				CreatedAt: at, UpdatedAt: at, DeletedAt: gorm.DeletedAt{Time: at, Valid: true},
+				Goals: []models.Goal{{ID: 4, Content: "発表する"}},
+			},
+			`{"category":"仕事","content":"人前で話すのが怖い","trigger_episode":""}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Snapshot(tt.record)
+			if err != nil {
+				t.Fatalf("got error %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name    string
+		before  string
+		after   string
+		want    []Change
+		wantErr bool
+	}{
+		{"unchanged", `{"a":1,"b":"x"}`, `{"b":"x","a":1}`, nil, false},
+		{
+			"created",
+			"",
+			`{"content":"発表する","done":false,"note":null}`,
+			[]Change{{Field: "content", After: "発表する"}, {Field: "done", After: false}},
+			false,
+		},
+		{
+			"deleted",
+			`{"content":"発表する","count":2}`,
+			"",
+			[]Change{{Field: "content", Before: "発表する"}, {Field: "count", Before: float64(2)}},
+			false,
+		},
+		{
+			"updated",
+			`{"content":"発表する","count":2,"tags":["a"],"note":"x"}`,
+			`{"content":"発表する","count":3,"tags":["a","b"],"extra":true}`,
+			[]Change{
+				{Field: "count", Before: float64(2), After: float64(3)},
+				{Field: "extra", After: true},
+				{Field: "note", Before: "x"},
+				{Field: "tags", Before: []interface{}{"a"}, After: []interface{}{"a", "b"}},
+			},
+			false,
+		},
+		{"invalid before", `{`, "", nil, true},
+		{"invalid after", "", `[1]`, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Diff(tt.before, tt.after)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("got %+v, want an error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("got error %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package auth
+
+import (
+	"crypto/rand"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Default token lifetimes, overridable with ACCESS_TOKEN_TTL and REFRESH_TOKEN_TTL.
+const (
+	DefaultAccessTTL  = 15 * time.Minute
+	DefaultRefreshTTL = 30 * 24 * time.Hour
+)
+
+// Config holds the authentication settings read from the environment.
+type Config struct {
+	Secret     []byte
+	AccessTTL  time.Duration
+	RefreshTTL time.Duration
+	// DevMode lets requests without a bearer token act as the user named in
+	// X-User-ID, or a fixed test user. Never enable it in production.
+	DevMode bool
+}
+
+// ConfigFromEnv reads JWT_SECRET, ACCESS_TOKEN_TTL, REFRESH_TOKEN_TTL and
+// AUTH_DEV_MODE. JWT_SECRET is required unless dev mode is on, in which case
+// a random secret is generated for the lifetime of the process.
+func ConfigFromEnv() (Config, error) {
+	cfg := Config{AccessTTL: DefaultAccessTTL, RefreshTTL: DefaultRefreshTTL}
+
+	if v := os.Getenv("AUTH_DEV_MODE"); v != "" {
+		devMode, err := strconv.ParseBool(v)
+		if err != nil {
+			return cfg, fmt.Errorf("invalid AUTH_DEV_MODE: %w", err)
+		}
+		cfg.DevMode = devMode
+	}
+	if v := os.Getenv("ACCESS_TOKEN_TTL"); v != "" {
+		ttl, err := time.ParseDuration(v)
+		if err != nil || ttl <= 0 {
+			return cfg, fmt.Errorf("invalid ACCESS_TOKEN_TTL %q", v)
+		}
+		cfg.AccessTTL = ttl
+	}
+	if v := os.Getenv("REFRESH_TOKEN_TTL"); v != "" {
+		ttl, err := time.ParseDuration(v)
+		if err != nil || ttl <= 0 {
+			return cfg, fmt.Errorf("invalid REFRESH_TOKEN_TTL %q", v)
+		}
+		cfg.RefreshTTL = ttl
+	}
+
+	secret := os.Getenv("JWT_SECRET")
+	switch {
+	case secret != "":
+		if len(secret) < 32 {
+			return cfg, fmt.Errorf("JWT_SECRET must be at least 32 bytes")
+		}
+		cfg.Secret = []byte(secret)
+	case cfg.DevMode:
+		cfg.Secret = make([]byte, 32)
+		if _, err := rand.Read(cfg.Secret); err != nil {
+			return cfg, fmt.Errorf("failed to generate JWT secret: %w", err)
+		}
+		log.Println("⚠️ JWT_SECRET not set, using a random secret. Tokens will not survive a restart.")
+	default:
+		return cfg, fmt.Errorf("JWT_SECRET is required unless AUTH_DEV_MODE is enabled")
+	}
+	return cfg, nil
+}
@@ -0,0 +1,48 @@
+package auth
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestConfigFromEnv(t *testing.T) {
+	secret := strings.Repeat("s", 32)
+	tests := []struct {
+		name    string
+		env     map[string]string
+		want    Config
+		wantErr bool
+	}{
+		{"defaults", map[string]string{"JWT_SECRET": secret}, Config{AccessTTL: DefaultAccessTTL, RefreshTTL: DefaultRefreshTTL}, false},
+		{"custom lifetimes", map[string]string{"JWT_SECRET": secret, "ACCESS_TOKEN_TTL": "5m", "REFRESH_TOKEN_TTL": "48h"}, Config{AccessTTL: 5 * time.Minute, RefreshTTL: 48 * time.Hour}, false},
+		{"dev mode without a secret", map[string]string{"AUTH_DEV_MODE": "true"}, Config{AccessTTL: DefaultAccessTTL, RefreshTTL: DefaultRefreshTTL, DevMode: true}, false},
+		{"no secret", nil, Config{}, true},
+		{"short secret", map[string]string{"JWT_SECRET": "short"}, Config{}, true},
+		{"invalid dev mode", map[string]string{"JWT_SECRET": secret, "AUTH_DEV_MODE": "maybe"}, Config{}, true},
+		{"negative lifetime", map[string]string{"JWT_SECRET": secret, "ACCESS_TOKEN_TTL": "-1m"}, Config{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, key := range []string{"JWT_SECRET", "AUTH_DEV_MODE", "ACCESS_TOKEN_TTL", "REFRESH_TOKEN_TTL"} {
+				t.Setenv(key, tt.env[key])
+			}
+			got, err := ConfigFromEnv()
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("got %+v, want an error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ConfigFromEnv: %v", err)
+			}
+			if len(got.Secret) < 32 {
+				t.Errorf("got a %d-byte secret, want at least 32", len(got.Secret))
+			}
+			if got.AccessTTL != tt.want.AccessTTL || got.RefreshTTL != tt.want.RefreshTTL || got.DevMode != tt.want.DevMode {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
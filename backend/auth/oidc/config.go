@@ -0,0 +1,46 @@
+// Package oidc signs users in through an external OpenID Connect provider
+// using the authorization code flow with PKCE.
+package oidc
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Config describes the relying party registration at the provider.
+type Config struct {
+	// Issuer is the provider's issuer URL, compared exactly with the iss of
+	// the discovery document and of ID tokens; discovery is fetched from
+	// Issuer + "/.well-known/openid-configuration", without a doubled slash.
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	// RedirectURL is the registered callback, usually a frontend route that
+	// posts the code and state back to /auth/oidc/callback.
+	RedirectURL string
+	Scopes      []string
+}
+
+// ConfigFromEnv reads OIDC_ISSUER, OIDC_CLIENT_ID, OIDC_CLIENT_SECRET,
+// OIDC_REDIRECT_URL and OIDC_SCOPES. ok is false when OIDC_ISSUER is unset,
+// which disables OIDC login.
+func ConfigFromEnv() (cfg Config, ok bool, err error) {
+	cfg = Config{
+		Issuer:       os.Getenv("OIDC_ISSUER"),
+		ClientID:     os.Getenv("OIDC_CLIENT_ID"),
+		ClientSecret: os.Getenv("OIDC_CLIENT_SECRET"),
+		RedirectURL:  os.Getenv("OIDC_REDIRECT_URL"),
+		Scopes:       strings.Fields(os.Getenv("OIDC_SCOPES")),
+	}
+	if cfg.Issuer == "" {
+		return cfg, false, nil
+	}
+	if cfg.ClientID == "" || cfg.RedirectURL == "" {
+		return cfg, false, fmt.Errorf("OIDC_CLIENT_ID and OIDC_REDIRECT_URL are required when OIDC_ISSUER is set")
+	}
+	if len(cfg.Scopes) == 0 {
+		cfg.Scopes = []string{"openid", "email", "profile"}
+	}
+	return cfg, true, nil
+}
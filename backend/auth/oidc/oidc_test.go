@@ -0,0 +1,441 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// fakeProvider is an identity provider serving discovery, a JWKS and a
+// token endpoint that checks the PKCE verifier.
+type fakeProvider struct {
+	*httptest.Server
+	key *rsa.PrivateKey
+
+	mu        sync.Mutex
+	issuer    string // overrides the issuer in the discovery document
+	discovery int    // discovery requests served
+	// held, when set, receives each discovery request, which is answered
+	// once it is closed; requests nobody receives wait until cancelled.
+	held      chan chan struct{}
+	kids      []string
+	jwksCalls int
+	jwksDown  bool
+	challenge string // code challenge of the pending authorization
+	idToken   string // ID token returned by the token endpoint
+}
+
+func newFakeProvider(t *testing.T) *fakeProvider {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+	p := &fakeProvider{key: key, kids: []string{"k1"}}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		p.mu.Lock()
+		issuer, held := p.issuer, p.held
+		p.discovery++
+		p.mu.Unlock()
+		if held != nil {
+			release := make(chan struct{})
+			select {
+			case held <- release:
+				<-release
+			case <-r.Context().Done():
+				return
+			}
+		}
+		if issuer == "" {
+			issuer = p.URL
+		}
+		json.NewEncoder(w).Encode(Metadata{
+			Issuer:                        issuer,
+			AuthorizationEndpoint:         p.URL + "/authorize",
+			TokenEndpoint:                 p.URL + "/token",
+			JWKSURI:                       p.URL + "/jwks",
+			CodeChallengeMethodsSupported: []string{"S256"},
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		p.jwksCalls++
+		if p.jwksDown {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		var keys []jwk
+		for _, kid := range p.kids {
+			keys = append(keys, jwk{
+				Kty: "RSA",
+				Kid: kid,
+				Use: "sig",
+				N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			})
+		}
+		w.Header().Set("Cache-Control", "public, max-age=60")
+		json.NewEncoder(w).Encode(map[string][]jwk{"keys": keys})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		if CodeChallenge(r.PostFormValue("code_verifier")) != p.challenge {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant", "error_description": "PKCE verification failed"})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]string{"id_token": p.idToken})
+	})
+	p.Server = httptest.NewServer(mux)
+	t.Cleanup(p.Close)
+	return p
+}
+
+// sign returns an RS256 ID token signed with the provider's key.
+func (p *fakeProvider) sign(t *testing.T, kid string, claims Claims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = kid
+	signed, err := token.SignedString(p.key)
+	if err != nil {
+		t.Fatalf("signing ID token: %v", err)
+	}
+	return signed
+}
+
+// claims returns valid ID token claims for the provider and client.
+func (p *fakeProvider) claims(nonce string) Claims {
+	now := time.Now()
+	return Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    p.URL,
+			Subject:   "subject-1",
+			Audience:  jwt.ClaimStrings{"client-1"},
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(time.Hour)),
+		},
+		Nonce:         nonce,
+		Email:         "user@example.com",
+		EmailVerified: true,
+	}
+}
+
+func (p *fakeProvider) provider() *Provider {
+	return NewProvider(Config{Issuer: p.URL, ClientID: "client-1", RedirectURL: "https://app.example.com/callback", Scopes: []string{"openid", "email"}}, p.Client())
+}
+
+func TestDiscover(t *testing.T) {
+	p := newFakeProvider(t)
+	tests := []struct {
+		name    string
+		served  string // issuer in the discovery document, or "" for the server URL
+		issuer  string // configured issuer
+		wantErr bool
+	}{
+		{"matching issuer", "", p.URL, false},
+		{"mismatched issuer", "https://other.example.com", p.URL, true},
+		{"other tenant on the same host", p.URL + "/tenant-2", p.URL, true},
+		{"trailing slash in both", p.URL + "/", p.URL + "/", false},
+		{"trailing slash configured only", "", p.URL + "/", true},
+		{"trailing slash served only", p.URL + "/", p.URL, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p.mu.Lock()
+			p.issuer = tt.served
+			p.mu.Unlock()
+			meta, err := Discover(context.Background(), p.Client(), tt.issuer)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("got %+v, want an error", meta)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Discover: %v", err)
+			}
+			if meta.JWKSURI != p.URL+"/jwks" {
+				t.Errorf("got JWKS URI %q, want %q", meta.JWKSURI, p.URL+"/jwks")
+			}
+		})
+	}
+}
+
+func TestKeySetCaching(t *testing.T) {
+	p := newFakeProvider(t)
+	ks := NewKeySet(p.URL+"/jwks", p.Client())
+	now := time.Date(2025, 4, 1, 9, 0, 0, 0, time.UTC)
+	ks.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	steps := []struct {
+		name      string
+		advance   time.Duration
+		kids      []string // keys served from this step on
+		down      bool
+		kid       string
+		wantErr   error
+		wantCalls int
+	}{
+		{name: "first use fetches", kids: []string{"k1"}, kid: "k1", wantCalls: 1},
+		{name: "cached", advance: 5 * time.Second, kid: "k1", wantCalls: 1},
+		{name: "unknown kid right after a fetch", kids: []string{"k1", "k2"}, kid: "k2", wantErr: ErrUnknownKey, wantCalls: 1},
+		{name: "unknown kid refetches", advance: minRefreshInterval, kid: "k2", wantCalls: 2},
+		{name: "still cached", advance: 30 * time.Second, kid: "k1", wantCalls: 2},
+		{name: "expired by max-age", advance: time.Minute, kid: "k1", wantCalls: 3},
+		{name: "provider down after expiry serves cached keys", advance: 2 * time.Minute, down: true, kid: "k2", wantCalls: 4},
+		{name: "provider down and unknown kid", advance: minRefreshInterval, down: true, kid: "k3", wantErr: errors.New("JWKS returned"), wantCalls: 5},
+	}
+	for _, step := range steps {
+		now = now.Add(step.advance)
+		p.mu.Lock()
+		if step.kids != nil {
+			p.kids = step.kids
+		}
+		p.jwksDown = step.down
+		p.mu.Unlock()
+
+		key, err := ks.Key(ctx, step.kid)
+		switch {
+		case step.wantErr == nil && (err != nil || key == nil):
+			t.Errorf("%s: got %v, %v, want a key", step.name, key, err)
+		case step.wantErr == ErrUnknownKey && !errors.Is(err, ErrUnknownKey):
+			t.Errorf("%s: got %v, want ErrUnknownKey", step.name, err)
+		case step.wantErr != nil && (err == nil || !strings.Contains(err.Error(), step.wantErr.Error())):
+			t.Errorf("%s: got %v, want %v", step.name, err, step.wantErr)
+		}
+		p.mu.Lock()
+		calls := p.jwksCalls
+		p.mu.Unlock()
+		if calls != step.wantCalls {
+			t.Errorf("%s: got %d JWKS fetches, want %d", step.name, calls, step.wantCalls)
+		}
+	}
+}
+
+func TestCodeChallenge(t *testing.T) {
+	// The S256 example from RFC 7636, appendix B.
+	if got, want := CodeChallenge("dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"), "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	verifier, err := NewCodeVerifier()
+	if err != nil {
+		t.Fatalf("NewCodeVerifier: %v", err)
+	}
+	if len(verifier) != 43 {
+		t.Errorf("got a %d-character verifier, want 43", len(verifier))
+	}
+}
+
+func TestAuthCodeURL(t *testing.T) {
+	p := newFakeProvider(t)
+	raw, err := p.provider().AuthCodeURL(context.Background(), "state-1", "nonce-1", "verifier-1")
+	if err != nil {
+		t.Fatalf("AuthCodeURL: %v", err)
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		t.Fatalf("parsing %q: %v", raw, err)
+	}
+	want := map[string]string{
+		"response_type":         "code",
+		"client_id":             "client-1",
+		"redirect_uri":          "https://app.example.com/callback",
+		"scope":                 "openid email",
+		"state":                 "state-1",
+		"nonce":                 "nonce-1",
+		"code_challenge":        CodeChallenge("verifier-1"),
+		"code_challenge_method": "S256",
+	}
+	for name, value := range want {
+		if got := u.Query().Get(name); got != value {
+			t.Errorf("got %s %q, want %q", name, got, value)
+		}
+	}
+}
+
+func TestExchange(t *testing.T) {
+	p := newFakeProvider(t)
+	tests := []struct {
+		name        string
+		verifier    string // verifier sent with the code
+		nonce       string // nonce in the ID token
+		wantIDError bool
+		wantErr     bool
+	}{
+		{name: "valid", verifier: "verifier-1", nonce: "nonce-1"},
+		{name: "wrong PKCE verifier", verifier: "verifier-2", nonce: "nonce-1", wantErr: true},
+		{name: "nonce mismatch", verifier: "verifier-1", nonce: "nonce-2", wantErr: true, wantIDError: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p.mu.Lock()
+			p.challenge = CodeChallenge("verifier-1")
+			p.idToken = p.sign(t, "k1", p.claims(tt.nonce))
+			p.mu.Unlock()
+
+			claims, err := p.provider().Exchange(context.Background(), "code-1", tt.verifier, "nonce-1")
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("got %+v, want an error", claims)
+				}
+				if got := errors.Is(err, ErrInvalidIDToken); got != tt.wantIDError {
+					t.Errorf("got error %v (ErrInvalidIDToken %v), want ErrInvalidIDToken %v", err, got, tt.wantIDError)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Exchange: %v", err)
+			}
+			if claims.Subject != "subject-1" || claims.Email != "user@example.com" {
+				t.Errorf("got %+v, want subject-1 and user@example.com", claims)
+			}
+		})
+	}
+}
+
+func TestVerify(t *testing.T) {
+	p := newFakeProvider(t)
+	provider := p.provider()
+	with := func(change func(*Claims)) Claims {
+		c := p.claims("")
+		change(&c)
+		return c
+	}
+	hs256, err := jwt.NewWithClaims(jwt.SigningMethodHS256, p.claims("")).SignedString([]byte("secret"))
+	if err != nil {
+		t.Fatalf("signing token: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr bool
+	}{
+		{"valid", p.sign(t, "k1", p.claims("")), false},
+		{"expired within leeway", p.sign(t, "k1", with(func(c *Claims) { c.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-30 * time.Second)) })), false},
+		{"expired", p.sign(t, "k1", with(func(c *Claims) { c.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Hour)) })), true},
+		{"no expiry", p.sign(t, "k1", with(func(c *Claims) { c.ExpiresAt = nil })), true},
+		{"wrong audience", p.sign(t, "k1", with(func(c *Claims) { c.Audience = jwt.ClaimStrings{"client-2"} })), true},
+		{"wrong issuer", p.sign(t, "k1", with(func(c *Claims) { c.Issuer = "https://other.example.com" })), true},
+		{"no subject", p.sign(t, "k1", with(func(c *Claims) { c.Subject = "" })), true},
+		{"unknown key", p.sign(t, "k9", p.claims("")), true},
+		{"symmetric algorithm", hs256, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := provider.Verify(context.Background(), tt.token)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidIDToken) {
+					t.Errorf("got %+v, %v, want ErrInvalidIDToken", claims, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Verify: %v", err)
+			}
+		})
+	}
+}
+
+func TestVerifyIssuerWithTrailingSlash(t *testing.T) {
+	p := newFakeProvider(t)
+	p.issuer = p.URL + "/"
+	provider := NewProvider(Config{Issuer: p.URL + "/", ClientID: "client-1"}, p.Client())
+	claims := p.claims("")
+
+	claims.Issuer = p.URL + "/"
+	if _, err := provider.Verify(context.Background(), p.sign(t, "k1", claims)); err != nil {
+		t.Errorf("Verify with the configured issuer: %v", err)
+	}
+	claims.Issuer = p.URL
+	if _, err := provider.Verify(context.Background(), p.sign(t, "k1", claims)); !errors.Is(err, ErrInvalidIDToken) {
+		t.Errorf("got %v for the issuer without its trailing slash, want ErrInvalidIDToken", err)
+	}
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	t.Setenv("OIDC_ISSUER", "https://idp.example.com/")
+	t.Setenv("OIDC_CLIENT_ID", "client-1")
+	t.Setenv("OIDC_CLIENT_SECRET", "")
+	t.Setenv("OIDC_REDIRECT_URL", "https://app.example.com/callback")
+	t.Setenv("OIDC_SCOPES", "")
+	cfg, ok, err := ConfigFromEnv()
+	if err != nil || !ok {
+		t.Fatalf("got %v, %v, want the configuration", ok, err)
+	}
+	// The issuer is kept as configured to compare it exactly.
+	if cfg.Issuer != "https://idp.example.com/" {
+		t.Errorf("got issuer %q, want it as configured", cfg.Issuer)
+	}
+	if want := []string{"openid", "email", "profile"}; strings.Join(cfg.Scopes, " ") != strings.Join(want, " ") {
+		t.Errorf("got scopes %v, want %v", cfg.Scopes, want)
+	}
+}
+
+func TestMetadataDoesNotWaitForAnotherDiscovery(t *testing.T) {
+	p := newFakeProvider(t)
+	provider := p.provider()
+	held := make(chan chan struct{})
+	p.mu.Lock()
+	p.held = held
+	p.mu.Unlock()
+
+	first := make(chan error, 1)
+	go func() {
+		_, err := provider.Metadata(context.Background())
+		first <- err
+	}()
+	release := <-held
+
+	// A second login gives up at its own deadline instead of queueing
+	// behind the discovery in progress.
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	second := make(chan error, 1)
+	go func() {
+		_, err := provider.Metadata(ctx)
+		second <- err
+	}()
+	select {
+	case err := <-second:
+		if err == nil {
+			t.Errorf("got metadata from a discovery that timed out, want an error")
+		}
+	case <-time.After(5 * time.Second):
+		t.Errorf("second call still waiting after its deadline")
+	}
+
+	close(release)
+	if err := <-first; err != nil {
+		t.Fatalf("first Metadata: %v", err)
+	}
+	p.mu.Lock()
+	p.held = nil
+	calls := p.discovery
+	p.mu.Unlock()
+	if _, err := provider.Metadata(context.Background()); err != nil {
+		t.Fatalf("Metadata once discovered: %v", err)
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.discovery != calls {
+		t.Errorf("got %d discovery requests, want the document cached after %d", p.discovery, calls)
+	}
+}
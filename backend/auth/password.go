@@ -0,0 +1,36 @@
+// Package auth implements local account authentication: password hashing,
+// signed access tokens and rotating refresh tokens.
+package auth
+
+import (
+	"errors"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// MinPasswordLength is the shortest password accepted at registration.
+const MinPasswordLength = 8
+
+// MaxPasswordLength is the longest password accepted at registration, in
+// bytes. bcrypt ignores anything past it.
+const MaxPasswordLength = 72
+
+// ErrInvalidCredentials is returned when an email or password does not match.
+var ErrInvalidCredentials = errors.New("invalid email or password")
+
+// HashPassword hashes a password with bcrypt.
+func HashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// CheckPassword compares a password with its bcrypt hash.
+func CheckPassword(hash, password string) error {
+	if err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)); err != nil {
+		return ErrInvalidCredentials
+	}
+	return nil
+}
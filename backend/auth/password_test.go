@@ -0,0 +1,42 @@
+package auth
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestPassword(t *testing.T) {
+	tests := []struct {
+		name     string
+		password string
+		wantErr  bool
+	}{
+		{"ascii", "password123", false},
+		{"multi-byte", "パスワード123", false},
+		// bcrypt only reads the first 72 bytes, so longer passwords are refused.
+		{"72 bytes", strings.Repeat("a", 72), false},
+		{"73 bytes", strings.Repeat("a", 73), true},
+		{"over 72 bytes in fewer characters", strings.Repeat("あ", 25), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hash, err := HashPassword(tt.password)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("got hash %q, want an error", hash)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("HashPassword: %v", err)
+			}
+			if err := CheckPassword(hash, tt.password); err != nil {
+				t.Errorf("CheckPassword with the password: %v", err)
+			}
+			if err := CheckPassword(hash, "wrong-password"); !errors.Is(err, ErrInvalidCredentials) {
+				t.Errorf("got %v for a wrong password, want ErrInvalidCredentials", err)
+			}
+		})
+	}
+}
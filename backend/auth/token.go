@@ -0,0 +1,81 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// ErrInvalidToken is returned for malformed, expired or wrongly signed tokens.
+var ErrInvalidToken = errors.New("invalid or expired token")
+
+// Issuer is the iss claim of access tokens signed by this server.
+const Issuer = "refuel"
+
+// Tokens signs and verifies HS256 access tokens.
+type Tokens struct {
+	secret    []byte
+	AccessTTL time.Duration
+}
+
+// NewTokens creates a token signer with the given secret.
+func NewTokens(secret []byte, accessTTL time.Duration) *Tokens {
+	return &Tokens{secret: secret, AccessTTL: accessTTL}
+}
+
+// Issue signs an access token for the user. It returns the token and its expiry.
+func (t *Tokens) Issue(userID string, now time.Time) (string, time.Time, error) {
+	expiresAt := now.Add(t.AccessTTL)
+	claims := jwt.RegisteredClaims{
+		Issuer:    Issuer,
+		Subject:   userID,
+		IssuedAt:  jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(expiresAt),
+	}
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(t.secret)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to sign access token: %w", err)
+	}
+	return signed, expiresAt, nil
+}
+
+// Verify checks an access token and returns the user ID it was issued to.
+func (t *Tokens) Verify(token string) (string, error) {
+	var claims jwt.RegisteredClaims
+	_, err := jwt.ParseWithClaims(token, &claims, func(*jwt.Token) (interface{}, error) {
+		return t.secret, nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithIssuer(Issuer),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil || claims.Subject == "" {
+		return "", ErrInvalidToken
+	}
+	return claims.Subject, nil
+}
+
+// NewRefreshToken returns a random opaque refresh token and the hash that is
+// stored in place of it.
+func NewRefreshToken() (token, hash string, err error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", fmt.Errorf("failed to generate refresh token: %w", err)
+	}
+	token = base64.RawURLEncoding.EncodeToString(buf)
+	return token, HashRefreshToken(token), nil
+}
+
+// HashRefreshToken returns the stored form of a refresh token. Refresh tokens
+// are long random strings, so a plain SHA-256 is enough to keep a database
+// leak from handing out live sessions.
+func HashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
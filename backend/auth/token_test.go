@@ -0,0 +1,95 @@
+package auth
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func TestTokensVerify(t *testing.T) {
+	tokens := NewTokens([]byte("test-secret"), time.Minute)
+	now := time.Now()
+	sign := func(method jwt.SigningMethod, key interface{}, claims jwt.RegisteredClaims) string {
+		t.Helper()
+		signed, err := jwt.NewWithClaims(method, claims).SignedString(key)
+		if err != nil {
+			t.Fatalf("signing token: %v", err)
+		}
+		return signed
+	}
+	claims := func(issuer, subject string, expiresAt time.Time) jwt.RegisteredClaims {
+		return jwt.RegisteredClaims{Issuer: issuer, Subject: subject, ExpiresAt: jwt.NewNumericDate(expiresAt)}
+	}
+	issued, _, err := tokens.Issue("u1", now)
+	if err != nil {
+		t.Fatalf("Issue: %v", err)
+	}
+	expired, _, err := tokens.Issue("u1", now.Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("Issue: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		token string
+		want  string
+	}{
+		{"issued", issued, "u1"},
+		{"expired", expired, ""},
+		{"wrong secret", sign(jwt.SigningMethodHS256, []byte("other-secret"), claims(Issuer, "u1", now.Add(time.Minute))), ""},
+		{"tampered", issued[:len(issued)-2] + "xx", ""},
+		{"other algorithm", sign(jwt.SigningMethodHS512, []byte("test-secret"), claims(Issuer, "u1", now.Add(time.Minute))), ""},
+		{"unsigned", sign(jwt.SigningMethodNone, jwt.UnsafeAllowNoneSignatureType, claims(Issuer, "u1", now.Add(time.Minute))), ""},
+		{"other issuer", sign(jwt.SigningMethodHS256, []byte("test-secret"), claims("someone-else", "u1", now.Add(time.Minute))), ""},
+		{"no subject", sign(jwt.SigningMethodHS256, []byte("test-secret"), claims(Issuer, "", now.Add(time.Minute))), ""},
+		{"no expiry", sign(jwt.SigningMethodHS256, []byte("test-secret"), jwt.RegisteredClaims{Issuer: Issuer, Subject: "u1"}), ""},
+		{"malformed", "not-a-token", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tokens.Verify(tt.token)
+			if tt.want == "" {
+				if !errors.Is(err, ErrInvalidToken) {
+					t.Errorf("got %q, %v, want ErrInvalidToken", got, err)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("got %q, %v, want %q", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestTokensIssueExpiry(t *testing.T) {
+	now := time.Date(2025, 4, 1, 9, 0, 0, 0, time.UTC)
+	_, expiresAt, err := NewTokens([]byte("test-secret"), 15*time.Minute).Issue("u1", now)
+	if err != nil {
+		t.Fatalf("Issue: %v", err)
+	}
+	if want := now.Add(15 * time.Minute); !expiresAt.Equal(want) {
+		t.Errorf("got expiry %v, want %v", expiresAt, want)
+	}
+}
+
+func TestNewRefreshToken(t *testing.T) {
+	token, hash, err := NewRefreshToken()
+	if err != nil {
+		t.Fatalf("NewRefreshToken: %v", err)
+	}
+	if hash != HashRefreshToken(token) {
+		t.Errorf("got hash %q, want HashRefreshToken(token) %q", hash, HashRefreshToken(token))
+	}
+	if hash == token {
+		t.Error("got the token stored in plain text")
+	}
+	other, _, err := NewRefreshToken()
+	if err != nil {
+		t.Fatalf("NewRefreshToken: %v", err)
+	}
+	if other == token {
+		t.Errorf("got the same token %q twice", token)
+	}
+}
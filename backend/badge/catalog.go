@@ -0,0 +1,69 @@
+package badge
+
+// Definition describes a badge that can be awarded to a user.
+type Definition struct {
+	// Code is the stable identifier stored in badges.code.
+	Code        string
+	Name        string
+	Description string
+	IconURL     string
+	// Rule is the badge rule expression; see package rule for the syntax.
+	// It is empty for a badge that is awarded by other means than a rule.
+	Rule string
+}
+
+// MilestoneCode is the code of the badge awarded for every goal milestone
+// reached. It has no rule: CheckMilestones awards it once per milestone.
+const MilestoneCode = "goal_milestone"
+
+// Catalog is the list of badges known to the application.
+// SyncCatalog keeps the badges table in line with it.
+var Catalog = []Definition{
+	{
+		Code:        "first_action",
+		Name:        "はじめの一歩",
+		Description: "初めて行動を完了しました。",
+		Rule:        `completed_actions >= 1`,
+	},
+	{
+		Code:        "actions_10",
+		Name:        "燃料補給",
+		Description: "行動を10回完了しました。",
+		Rule:        `completed_actions >= 10`,
+	},
+	{
+		Code:        "actions_100",
+		Name:        "フルタンク",
+		Description: "行動を100回完了しました。",
+		Rule:        `completed_actions >= 100`,
+	},
+	{
+		Code:        "goal_actions_30",
+		Name:        "継続は力なり",
+		Description: "ひとつの目標で行動を30回完了しました。",
+		Rule:        `completed_actions >= 30 for any goal`,
+	},
+	{
+		Code:        "active_days_7",
+		Name:        "一週間の火種",
+		Description: "7日間、行動を完了しました。",
+		Rule:        `active_days >= 7`,
+	},
+	{
+		Code:        "streak_7",
+		Name:        "七日間の炎",
+		Description: "7日連続で行動を完了しました。",
+		Rule:        `streak_days >= 7`,
+	},
+	{
+		Code:        "goals_3",
+		Name:        "多方面作戦",
+		Description: "目標を3つ登録しました。",
+		Rule:        `goals >= 3`,
+	},
+	{
+		Code:        MilestoneCode,
+		Name:        "道しるべ",
+		Description: "目標から逆算したマイルストーンに到達しました。",
+	},
+}
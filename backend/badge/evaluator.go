@@ -0,0 +1,119 @@
+package badge
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"refuel/backend/badge/rule"
+	"refuel/backend/models"
+	"refuel/backend/repository"
+)
+
+// Evaluator awards badges to users by evaluating each badge's rule against
+// their history.
+type Evaluator struct {
+	Repos *repository.Repositories
+}
+
+// NewEvaluator creates a new Evaluator.
+func NewEvaluator(repos *repository.Repositories) *Evaluator {
+	return &Evaluator{Repos: repos}
+}
+
+// SyncCatalog upserts every catalog definition into the badge repository, keyed by code.
+func SyncCatalog(ctx context.Context, badges repository.BadgeRepository) error {
+	for _, def := range Catalog {
+		if def.Rule != "" {
+			if _, err := rule.Compile(def.Rule); err != nil {
+				return fmt.Errorf("catalog badge %q has an invalid rule: %w", def.Code, err)
+			}
+		}
+		row := models.Badge{
+			Code:        def.Code,
+			Name:        def.Name,
+			Description: def.Description,
+			IconURL:     def.IconURL,
+			Rule:        def.Rule,
+		}
+		if err := badges.Upsert(ctx, &row); err != nil {
+			return fmt.Errorf("failed to sync badge %q: %w", def.Code, err)
+		}
+	}
+	return nil
+}
+
+// LoadHistory gathers the complexes, goals, actions and check-ins a rule is
+// evaluated against, with completions falling on calendar days in loc.
+func (e *Evaluator) LoadHistory(ctx context.Context, userID string, loc *time.Location) (rule.History, error) {
+	h := rule.History{Location: loc}
+	var err error
+	if h.Complexes, err = e.Repos.Complexes.List(ctx, userID); err != nil {
+		return h, fmt.Errorf("failed to load complexes: %w", err)
+	}
+	if h.Goals, err = e.Repos.Goals.List(ctx, userID); err != nil {
+		return h, fmt.Errorf("failed to load goals: %w", err)
+	}
+	if h.Actions, err = e.Repos.Actions.List(ctx, userID); err != nil {
+		return h, fmt.Errorf("failed to load actions: %w", err)
+	}
+	if h.Completions, err = e.Repos.Actions.ListUserCompletions(ctx, userID); err != nil {
+		return h, fmt.Errorf("failed to load action completions: %w", err)
+	}
+	return h, nil
+}
+
+// Evaluate checks every badge with a rule that the user does not hold yet and
+// awards the ones whose rule matches. It returns only the newly awarded
+// badges, so calling it repeatedly never grants the same badge twice. Days
+// of completions are counted in loc.
+func (e *Evaluator) Evaluate(ctx context.Context, userID string, loc *time.Location) ([]models.UserBadge, error) {
+	held, err := e.Repos.Badges.ListUserBadges(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load user badges: %w", err)
+	}
+	heldIDs := make(map[uint]struct{}, len(held))
+	for _, ub := range held {
+		heldIDs[ub.BadgeID] = struct{}{}
+	}
+
+	badges, err := e.Repos.Badges.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load badges: %w", err)
+	}
+
+	h, err := e.LoadHistory(ctx, userID, loc)
+	if err != nil {
+		return nil, err
+	}
+
+	var awarded []models.UserBadge
+	now := time.Now()
+	for _, b := range badges {
+		if _, ok := heldIDs[b.ID]; ok || b.Rule == "" {
+			continue
+		}
+		r, err := rule.Compile(b.Rule)
+		if err != nil {
+			// Rules are validated on creation, so this only happens if the row was edited by hand.
+			log.Printf("⚠️ Skipping badge %q with invalid rule: %v", b.Code, err)
+			continue
+		}
+		if !r.Evaluate(h) {
+			continue
+		}
+		ub := models.UserBadge{UserID: userID, BadgeID: b.ID, AchievedAt: now}
+		// A concurrent evaluation may have awarded it first; that is not an error.
+		created, err := e.Repos.Badges.Award(ctx, &ub)
+		if err != nil {
+			return awarded, fmt.Errorf("failed to award badge %q: %w", b.Code, err)
+		}
+		if !created {
+			continue
+		}
+		ub.Badge = b
+		awarded = append(awarded, ub)
+	}
+	return awarded, nil
+}
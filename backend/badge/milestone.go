@@ -0,0 +1,204 @@
+package badge
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"refuel/backend/badge/rule"
+	"refuel/backend/models"
+	"refuel/backend/progress"
+)
+
+// CheckMilestones marks the goal's milestones that its measurements, or the
+// completions of its actions, have crossed as achieved and awards the
+// milestone badge for each of them. It returns only the newly awarded
+// badges, so calling it repeatedly never grants a milestone twice.
+func (e *Evaluator) CheckMilestones(ctx context.Context, userID string, goalID uint) ([]models.UserBadge, error) {
+	milestones, err := e.Repos.Goals.ListMilestones(ctx, userID, goalID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load milestones: %w", err)
+	}
+	var pending []models.Milestone
+	for _, m := range milestones {
+		if m.AchievedAt == nil {
+			pending = append(pending, m)
+		}
+	}
+	if len(pending) == 0 {
+		return nil, nil
+	}
+
+	st, err := e.standing(ctx, userID, goalID, pending)
+	if err != nil {
+		return nil, err
+	}
+	milestoneBadge, err := e.milestoneBadge(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var awarded []models.UserBadge
+	now := time.Now()
+	for _, m := range pending {
+		if !st.crossed(m) {
+			continue
+		}
+		ub := models.UserBadge{UserID: userID, BadgeID: milestoneBadge.ID, MilestoneID: m.ID, AchievedAt: now}
+		// The badge is awarded before the milestone is marked, so a failure
+		// in between is repaired by the next check instead of losing the badge.
+		created, err := e.Repos.Badges.Award(ctx, &ub)
+		if err != nil {
+			return awarded, fmt.Errorf("failed to award milestone %d: %w", m.ID, err)
+		}
+		if err := e.Repos.Goals.AchieveMilestone(ctx, m.ID, now); err != nil {
+			return awarded, fmt.Errorf("failed to mark milestone %d achieved: %w", m.ID, err)
+		}
+		if !created {
+			continue
+		}
+		m.AchievedAt = &now
+		ub.Badge = *milestoneBadge
+		ub.Milestone = &m
+		awarded = append(awarded, ub)
+	}
+	return awarded, nil
+}
+
+// NextMilestone returns the goal's first milestone not yet achieved and
+// the share, between 0 and 1, of the way to it from the previous milestone
+// (or from the metric's baseline, or zero completed actions) already
+// covered. It returns nil when every milestone is achieved or there are none.
+func (e *Evaluator) NextMilestone(ctx context.Context, userID string, goalID uint) (*models.Milestone, float64, error) {
+	milestones, err := e.Repos.Goals.ListMilestones(ctx, userID, goalID)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to load milestones: %w", err)
+	}
+	for i, m := range milestones {
+		if m.AchievedAt != nil {
+			continue
+		}
+		st, err := e.standing(ctx, userID, goalID, milestones[i:i+1])
+		if err != nil {
+			return nil, 0, err
+		}
+		from := st.start(m)
+		if i > 0 && milestones[i-1].Basis == m.Basis {
+			from = milestones[i-1].Threshold
+		}
+		return &m, st.share(m, from), nil
+	}
+	return nil, 0, nil
+}
+
+// standing is what milestones are compared with: the measurements of the
+// goal's metric and the number of its completed actions.
+type standing struct {
+	metric       *progress.Metric
+	measurements []models.GoalMeasurement
+	completed    int
+}
+
+// standing loads what the given milestones need. Metric milestones are
+// crossed by any measurement reaching them; while the goal has no metric
+// they cannot be.
+func (e *Evaluator) standing(ctx context.Context, userID string, goalID uint, milestones []models.Milestone) (standing, error) {
+	var byMetric, byActions bool
+	for _, m := range milestones {
+		byMetric = byMetric || m.Basis == models.MilestoneMetric
+		byActions = byActions || m.Basis == models.MilestoneCompletedActions
+	}
+
+	var st standing
+	if byMetric {
+		goal, err := e.Repos.Goals.Get(ctx, userID, goalID)
+		if err != nil {
+			return st, fmt.Errorf("failed to load goal: %w", err)
+		}
+		if goal.HasMetric() {
+			st.metric = &progress.Metric{Direction: progress.Direction(goal.MetricDirection)}
+			if goal.MetricBaseline != nil {
+				st.metric.Baseline = *goal.MetricBaseline
+			}
+			if st.measurements, err = e.Repos.Goals.ListMeasurements(ctx, userID, goalID); err != nil {
+				return st, fmt.Errorf("failed to load measurements: %w", err)
+			}
+		}
+	}
+	if byActions {
+		// The count does not depend on the days completions fall on.
+		h, err := e.LoadHistory(ctx, userID, time.UTC)
+		if err != nil {
+			return st, err
+		}
+		st.completed = rule.CompletedActions(h, goalID)
+	}
+	return st, nil
+}
+
+// best returns the measurement furthest along the metric's direction, or
+// the baseline before the first measurement.
+func (st standing) best() float64 {
+	best := st.metric.Baseline
+	for i, v := range st.measurements {
+		if i == 0 || st.metric.Direction.Crossed(v.Value, best) {
+			best = v.Value
+		}
+	}
+	return best
+}
+
+func (st standing) crossed(m models.Milestone) bool {
+	switch m.Basis {
+	case models.MilestoneMetric:
+		return st.metric != nil && len(st.measurements) > 0 && st.metric.Direction.Crossed(st.best(), m.Threshold)
+	case models.MilestoneCompletedActions:
+		return float64(st.completed) >= m.Threshold
+	}
+	return false
+}
+
+// start is where the way to the first milestone of m's basis begins.
+func (st standing) start(m models.Milestone) float64 {
+	if m.Basis == models.MilestoneMetric && st.metric != nil {
+		return st.metric.Baseline
+	}
+	return 0
+}
+
+// share returns how much of the way from from to m has been covered.
+func (st standing) share(m models.Milestone, from float64) float64 {
+	if st.crossed(m) {
+		return 1
+	}
+	var current float64
+	switch m.Basis {
+	case models.MilestoneMetric:
+		if st.metric == nil {
+			return 0
+		}
+		current = st.best()
+	case models.MilestoneCompletedActions:
+		current = float64(st.completed)
+	}
+	span := m.Threshold - from
+	if span == 0 {
+		return 0
+	}
+	return math.Max(0, math.Min(1, (current-from)/span))
+}
+
+// milestoneBadge returns the catalog badge awarded for milestones.
+func (e *Evaluator) milestoneBadge(ctx context.Context) (*models.Badge, error) {
+	badges, err := e.Repos.Badges.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load badges: %w", err)
+	}
+	for i := range badges {
+		if badges[i].Code == MilestoneCode {
+			return &badges[i], nil
+		}
+	}
+	return nil, fmt.Errorf("badge %q is missing: the badge catalog has not been synced", MilestoneCode)
+}
@@ -0,0 +1,282 @@
+package rule
+
+import (
+	"sort"
+	"strconv"
+	"time"
+
+	"refuel/backend/models"
+)
+
+// History is the slice of a user's data a rule is evaluated against.
+type History struct {
+	Complexes []models.Complex
+	Goals     []models.Goal
+	Actions   []models.Action
+	// Completions are the user's check-ins. Done check-ins count as
+	// completions; an action without any falls back to its CompletedAt.
+	Completions []models.ActionCompletion
+	// Location decides which calendar day a completion falls on. Defaults to UTC.
+	Location *time.Location
+}
+
+// ConditionResult reports how a single condition evaluated.
+type ConditionResult struct {
+	Expression string
+	// Value is the metric value compared against the threshold. For
+	// "for any goal" it is the best goal, for "for every goal" the worst.
+	Value   int
+	Matched bool
+}
+
+// Result is the outcome of evaluating a rule with Explain.
+type Result struct {
+	Matched    bool
+	Conditions []ConditionResult
+}
+
+// Evaluate reports whether the history satisfies the rule.
+func (r *Rule) Evaluate(h History) bool {
+	return r.Explain(h).Matched
+}
+
+// Explain evaluates the rule and records the value of every condition.
+// Unlike Evaluate it does not short-circuit, so every condition is reported.
+func (r *Rule) Explain(h History) Result {
+	ev := newEvaluator(h)
+	var res Result
+	res.Matched = ev.eval(r.Root, &res.Conditions)
+	return res
+}
+
+type evaluator struct {
+	h            History
+	goalComplex  map[uint]uint
+	complexCateg map[uint]string
+	goalIDs      []uint
+	doneDays     map[uint][]time.Time
+	loc          *time.Location
+}
+
+func newEvaluator(h History) *evaluator {
+	ev := &evaluator{
+		h:            h,
+		goalComplex:  make(map[uint]uint, len(h.Goals)),
+		complexCateg: make(map[uint]string, len(h.Complexes)),
+		doneDays:     map[uint][]time.Time{},
+		loc:          h.Location,
+	}
+	if ev.loc == nil {
+		ev.loc = time.UTC
+	}
+	for _, c := range h.Complexes {
+		ev.complexCateg[c.ID] = c.Category
+	}
+	for _, g := range h.Goals {
+		ev.goalComplex[g.ID] = g.ComplexID
+		ev.goalIDs = append(ev.goalIDs, g.ID)
+	}
+	sort.Slice(ev.goalIDs, func(i, j int) bool { return ev.goalIDs[i] < ev.goalIDs[j] })
+	for _, c := range h.Completions {
+		if c.Status != models.CompletionDone {
+			continue
+		}
+		// Occurrence dates are calendar dates already, so they are not shifted into loc.
+		d := c.OccurrenceDate
+		ev.doneDays[c.ActionID] = append(ev.doneDays[c.ActionID], time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, time.UTC))
+	}
+	return ev
+}
+
+func (ev *evaluator) eval(e Expr, out *[]ConditionResult) bool {
+	switch n := e.(type) {
+	case *BinaryExpr:
+		l := ev.eval(n.Left, out)
+		r := ev.eval(n.Right, out)
+		if n.Op == "and" {
+			return l && r
+		}
+		return l || r
+	case *NotExpr:
+		return !ev.eval(n.X, out)
+	case *Condition:
+		value, matched := ev.evalCondition(n)
+		*out = append(*out, ConditionResult{Expression: n.String(), Value: value, Matched: matched})
+		return matched
+	}
+	return false
+}
+
+func (ev *evaluator) evalCondition(c *Condition) (int, bool) {
+	switch c.Scope {
+	case ScopeAnyGoal, ScopeEveryGoal:
+		if len(ev.goalIDs) == 0 {
+			return 0, false
+		}
+		best, worst := 0, 0
+		anyMatched, allMatched := false, true
+		for i, goalID := range ev.goalIDs {
+			v := ev.metric(c, func(a models.Action) bool { return a.GoalID == goalID })
+			if i == 0 || v > best {
+				best = v
+			}
+			if i == 0 || v < worst {
+				worst = v
+			}
+			ok := compare(v, c.Op, c.Value)
+			anyMatched = anyMatched || ok
+			allMatched = allMatched && ok
+		}
+		if c.Scope == ScopeAnyGoal {
+			return best, anyMatched
+		}
+		return worst, allMatched
+	default:
+		v := ev.metric(c, nil)
+		return v, compare(v, c.Op, c.Value)
+	}
+}
+
+// CompletedActions counts the completions of the goal's actions the way the
+// completed_actions metric does.
+func CompletedActions(h History, goalID uint) int {
+	ev := newEvaluator(h)
+	n := 0
+	for _, a := range h.Actions {
+		if a.GoalID == goalID {
+			n += len(ev.completionDays(a))
+		}
+	}
+	return n
+}
+
+// metric computes the condition's metric; extra further restricts the actions counted.
+func (ev *evaluator) metric(c *Condition, extra func(models.Action) bool) int {
+	switch c.Metric {
+	case "complexes":
+		n := 0
+		for _, cx := range ev.h.Complexes {
+			if ev.matchFilters(c.Filters, 0, cx.ID, cx.Category) {
+				n++
+			}
+		}
+		return n
+	case "goals":
+		n := 0
+		for _, g := range ev.h.Goals {
+			if ev.matchFilters(c.Filters, g.ID, g.ComplexID, ev.complexCateg[g.ComplexID]) {
+				n++
+			}
+		}
+		return n
+	}
+
+	var actions []models.Action
+	for _, a := range ev.h.Actions {
+		complexID := ev.goalComplex[a.GoalID]
+		if !ev.matchFilters(c.Filters, a.GoalID, complexID, ev.complexCateg[complexID]) {
+			continue
+		}
+		if extra != nil && !extra(a) {
+			continue
+		}
+		actions = append(actions, a)
+	}
+
+	switch c.Metric {
+	case "actions":
+		return len(actions)
+	case "completed_actions":
+		n := 0
+		for _, a := range actions {
+			n += len(ev.completionDays(a))
+		}
+		return n
+	case "active_days":
+		return len(ev.activeDays(actions))
+	case "streak_days":
+		return longestRun(ev.activeDays(actions))
+	}
+	return 0
+}
+
+func (ev *evaluator) matchFilters(filters []Filter, goalID, complexID uint, category string) bool {
+	for _, f := range filters {
+		var actual string
+		switch f.Field {
+		case "category":
+			actual = category
+		case "goal_id":
+			actual = strconv.FormatUint(uint64(goalID), 10)
+		case "complex_id":
+			actual = strconv.FormatUint(uint64(complexID), 10)
+		}
+		if (actual == f.Value) != (f.Op == "=") {
+			return false
+		}
+	}
+	return true
+}
+
+// completionDays returns the days an action was completed on: one per done
+// check-in, or the day of CompletedAt for an action without check-ins.
+func (ev *evaluator) completionDays(a models.Action) []time.Time {
+	if days := ev.doneDays[a.ID]; len(days) > 0 {
+		return days
+	}
+	if a.CompletedAt == nil {
+		return nil
+	}
+	t := a.CompletedAt.In(ev.loc)
+	return []time.Time{time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)}
+}
+
+// activeDays returns the sorted distinct days on which an action was completed.
+func (ev *evaluator) activeDays(actions []models.Action) []time.Time {
+	seen := map[time.Time]struct{}{}
+	for _, a := range actions {
+		for _, d := range ev.completionDays(a) {
+			seen[d] = struct{}{}
+		}
+	}
+	days := make([]time.Time, 0, len(seen))
+	for d := range seen {
+		days = append(days, d)
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i].Before(days[j]) })
+	return days
+}
+
+// longestRun returns the longest run of consecutive calendar days.
+func longestRun(days []time.Time) int {
+	longest, run := 0, 0
+	for i, d := range days {
+		if i > 0 && d.Sub(days[i-1]) == 24*time.Hour {
+			run++
+		} else {
+			run = 1
+		}
+		if run > longest {
+			longest = run
+		}
+	}
+	return longest
+}
+
+func compare(v int, op string, threshold int) bool {
+	switch op {
+	case ">=":
+		return v >= threshold
+	case ">":
+		return v > threshold
+	case "<=":
+		return v <= threshold
+	case "<":
+		return v < threshold
+	case "=":
+		return v == threshold
+	case "!=":
+		return v != threshold
+	}
+	return false
+}
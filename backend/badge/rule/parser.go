@@ -0,0 +1,303 @@
+package rule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Scope controls how an action metric is aggregated.
+type Scope int
+
+const (
+	// ScopeTotal aggregates the metric over the user's whole history.
+	ScopeTotal Scope = iota
+	// ScopeAnyGoal matches when at least one goal satisfies the comparison.
+	ScopeAnyGoal
+	// ScopeEveryGoal matches when every goal satisfies the comparison.
+	ScopeEveryGoal
+)
+
+// Expr is a node of a parsed rule.
+type Expr interface {
+	String() string
+}
+
+// BinaryExpr combines two expressions with "and" or "or".
+type BinaryExpr struct {
+	Op    string
+	Left  Expr
+	Right Expr
+}
+
+func (e *BinaryExpr) String() string {
+	return "(" + e.Left.String() + " " + e.Op + " " + e.Right.String() + ")"
+}
+
+// NotExpr negates an expression.
+type NotExpr struct {
+	X Expr
+}
+
+func (e *NotExpr) String() string {
+	return "not " + e.X.String()
+}
+
+// Filter restricts the records a metric counts, e.g. category = "健康".
+type Filter struct {
+	Field string
+	Op    string
+	Value string
+	Pos   int
+}
+
+func (f Filter) String() string {
+	return fmt.Sprintf("%s %s %q", f.Field, f.Op, f.Value)
+}
+
+// Condition compares a metric against an integer threshold.
+type Condition struct {
+	Metric  string
+	Filters []Filter
+	Op      string
+	Value   int
+	Scope   Scope
+	Pos     int
+}
+
+func (c *Condition) String() string {
+	var sb strings.Builder
+	sb.WriteString(c.Metric)
+	if len(c.Filters) > 0 {
+		parts := make([]string, len(c.Filters))
+		for i, f := range c.Filters {
+			parts[i] = f.String()
+		}
+		sb.WriteString("(" + strings.Join(parts, ", ") + ")")
+	}
+	fmt.Fprintf(&sb, " %s %d", c.Op, c.Value)
+	switch c.Scope {
+	case ScopeAnyGoal:
+		sb.WriteString(" for any goal")
+	case ScopeEveryGoal:
+		sb.WriteString(" for every goal")
+	}
+	return sb.String()
+}
+
+// Rule is a parsed badge rule expression.
+type Rule struct {
+	Source string
+	Root   Expr
+}
+
+// Parse parses a rule expression. It only checks syntax; use Validate or
+// Compile to also reject unknown metrics, filters and scopes.
+//
+// Grammar:
+//
+//	rule      = or
+//	or        = and { "or" and }
+//	and       = unary { "and" unary }
+//	unary     = "not" unary | "(" or ")" | condition
+//	condition = metric [ "(" filter { "," filter } ")" ] op integer [ "for" ( "any" | "every" ) "goal" ]
+//	filter    = field ( "=" | "!=" ) ( string | integer )
+func Parse(src string) (*Rule, error) {
+	tokens, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	if p.peek().kind == tokEOF {
+		return nil, &SyntaxError{Pos: 0, Msg: "empty rule"}
+	}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokEOF {
+		return nil, &SyntaxError{Pos: tok.pos, Msg: fmt.Sprintf("unexpected %q", tok.text)}
+	}
+	return &Rule{Source: src, Root: root}, nil
+}
+
+// Compile parses and validates a rule expression.
+func Compile(src string) (*Rule, error) {
+	r, err := Parse(src)
+	if err != nil {
+		return nil, err
+	}
+	if err := Validate(r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *parser) isKeyword(word string) bool {
+	tok := p.peek()
+	return tok.kind == tokIdent && tok.text == word
+}
+
+func (p *parser) expectKeyword(word string) error {
+	tok := p.next()
+	if tok.kind != tokIdent || tok.text != word {
+		return &SyntaxError{Pos: tok.pos, Msg: fmt.Sprintf("expected %q, got %q", word, tok.text)}
+	}
+	return nil
+}
+
+func (p *parser) parseOr() (Expr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.isKeyword("or") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &BinaryExpr{Op: "or", Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (Expr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.isKeyword("and") {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &BinaryExpr{Op: "and", Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (Expr, error) {
+	if p.isKeyword("not") {
+		p.next()
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &NotExpr{X: x}, nil
+	}
+	if p.peek().kind == tokLParen {
+		p.next()
+		x, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if tok := p.next(); tok.kind != tokRParen {
+			return nil, &SyntaxError{Pos: tok.pos, Msg: "expected ')'"}
+		}
+		return x, nil
+	}
+	return p.parseCondition()
+}
+
+func (p *parser) parseCondition() (Expr, error) {
+	tok := p.next()
+	if tok.kind != tokIdent {
+		return nil, &SyntaxError{Pos: tok.pos, Msg: fmt.Sprintf("expected metric name, got %q", tok.text)}
+	}
+	cond := &Condition{Metric: tok.text, Pos: tok.pos}
+
+	if p.peek().kind == tokLParen {
+		p.next()
+		for {
+			f, err := p.parseFilter()
+			if err != nil {
+				return nil, err
+			}
+			cond.Filters = append(cond.Filters, f)
+			sep := p.next()
+			if sep.kind == tokRParen {
+				break
+			}
+			if sep.kind != tokComma {
+				return nil, &SyntaxError{Pos: sep.pos, Msg: "expected ',' or ')' in filter list"}
+			}
+		}
+	}
+
+	opTok := p.next()
+	if opTok.kind != tokOp {
+		return nil, &SyntaxError{Pos: opTok.pos, Msg: fmt.Sprintf("expected comparison operator after %q", cond.Metric)}
+	}
+	cond.Op = normalizeOp(opTok.text)
+
+	numTok := p.next()
+	if numTok.kind != tokInt {
+		return nil, &SyntaxError{Pos: numTok.pos, Msg: "expected integer threshold"}
+	}
+	n, err := strconv.Atoi(numTok.text)
+	if err != nil {
+		return nil, &SyntaxError{Pos: numTok.pos, Msg: "threshold out of range"}
+	}
+	cond.Value = n
+
+	if p.isKeyword("for") {
+		p.next()
+		quant := p.next()
+		switch {
+		case quant.kind == tokIdent && quant.text == "any":
+			cond.Scope = ScopeAnyGoal
+		case quant.kind == tokIdent && (quant.text == "every" || quant.text == "all"):
+			cond.Scope = ScopeEveryGoal
+		default:
+			return nil, &SyntaxError{Pos: quant.pos, Msg: "expected 'any' or 'every' after 'for'"}
+		}
+		if p.isKeyword("goals") {
+			p.next()
+		} else if err := p.expectKeyword("goal"); err != nil {
+			return nil, err
+		}
+	}
+	return cond, nil
+}
+
+func (p *parser) parseFilter() (Filter, error) {
+	field := p.next()
+	if field.kind != tokIdent {
+		return Filter{}, &SyntaxError{Pos: field.pos, Msg: "expected filter field"}
+	}
+	op := p.next()
+	if op.kind != tokOp {
+		return Filter{}, &SyntaxError{Pos: op.pos, Msg: "expected operator in filter"}
+	}
+	val := p.next()
+	if val.kind != tokString && val.kind != tokInt {
+		return Filter{}, &SyntaxError{Pos: val.pos, Msg: "expected string or integer filter value"}
+	}
+	return Filter{Field: field.text, Op: normalizeOp(op.text), Value: val.text, Pos: field.pos}, nil
+}
+
+func normalizeOp(op string) string {
+	if op == "==" {
+		return "="
+	}
+	return op
+}
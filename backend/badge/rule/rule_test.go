@@ -0,0 +1,249 @@
+package rule
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"refuel/backend/models"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		src  string
+		want string
+	}{
+		{"completed_actions >= 10", "completed_actions >= 10"},
+		{"COMPLETED_ACTIONS == 3", "completed_actions = 3"},
+		{`actions(category = "健康", goal_id != 2) > 1`, `actions(category = "健康", goal_id != "2") > 1`},
+		{"streak_days >= 7 for any goal", "streak_days >= 7 for any goal"},
+		{"active_days >= 3 for all goals", "active_days >= 3 for every goal"},
+		{"goals >= 1 and complexes >= 1 or actions >= 5", "((goals >= 1 and complexes >= 1) or actions >= 5)"},
+		{"goals >= 1 and (complexes >= 1 or actions >= 5)", "(goals >= 1 and (complexes >= 1 or actions >= 5))"},
+		{"not not goals >= 1", "not not goals >= 1"},
+		{`complexes(category = "a\"b") >= 1`, `complexes(category = "a\"b") >= 1`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			r, err := Parse(tt.src)
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			if got := r.Root.String(); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSyntaxError(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		pos  int
+		msg  string
+	}{
+		{"empty", "  ", 0, ""},
+		{"missing operator", "goals 1", 6, ""},
+		{"missing threshold", "goals >=", 8, ""},
+		{"bang", "goals ! 1", 6, ""},
+		{"unterminated string", `goals(category = "a) >= 1`, 17, ""},
+		{"unclosed paren", "(goals >= 1", 11, ""},
+		{"trailing token", "goals >= 1 1", 11, ""},
+		{"bad scope", "goals >= 1 for some goal", 15, ""},
+		{"bad filter list", `actions(category = "a" goal_id = 1) >= 1`, 23, ""},
+		{"unexpected character", "goals >= 1 & actions >= 1", 11, "unexpected character"},
+		{"non-ASCII digit", "goals >= ٣", 9, "unexpected character"},
+		{"full-width digit", "goals >= １", 9, "unexpected character"},
+		{"non-ASCII digit after ASCII", "goals >= 1٣", 10, "unexpected character"},
+		{"threshold out of range", "goals >= 99999999999999999999", 9, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.src)
+			var syntaxErr *SyntaxError
+			if !errors.As(err, &syntaxErr) {
+				t.Fatalf("got %v, want a SyntaxError", err)
+			}
+			if syntaxErr.Pos != tt.pos {
+				t.Errorf("got position %d (%v), want %d", syntaxErr.Pos, err, tt.pos)
+			}
+			if !strings.Contains(syntaxErr.Msg, tt.msg) {
+				t.Errorf("got %q, want a message containing %q", syntaxErr.Msg, tt.msg)
+			}
+		})
+	}
+}
+
+func TestLexDigits(t *testing.T) {
+	tests := []struct {
+		src   string
+		kinds []tokenKind
+	}{
+		{"42", []tokenKind{tokInt, tokEOF}},
+		{"goal2", []tokenKind{tokIdent, tokEOF}},
+		{"2goal", []tokenKind{tokInt, tokIdent, tokEOF}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			tokens, err := lex(tt.src)
+			if err != nil {
+				t.Fatalf("lex: %v", err)
+			}
+			if len(tokens) != len(tt.kinds) {
+				t.Fatalf("got %d tokens, want %d", len(tokens), len(tt.kinds))
+			}
+			for i, tok := range tokens {
+				if tok.kind != tt.kinds[i] {
+					t.Errorf("token %d: got kind %d, want %d", i, tok.kind, tt.kinds[i])
+				}
+			}
+		})
+	}
+}
+
+func TestCompileValidationError(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		pos  int
+	}{
+		{"unknown metric", "badges >= 1", 0},
+		{"unsupported filter", `complexes(goal_id = 1) >= 1`, 10},
+		{"filter operator", `actions(category >= "a") >= 1`, 8},
+		{"non-integer id", `actions(goal_id = "x") >= 1`, 8},
+		{"unscoped metric", "goals >= 1 for any goal", 0},
+		{"second condition", "goals >= 1 and streak >= 2", 15},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Compile(tt.src)
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("got %v, want a ValidationError", err)
+			}
+			if validationErr.Pos != tt.pos {
+				t.Errorf("got position %d (%v), want %d", validationErr.Pos, err, tt.pos)
+			}
+		})
+	}
+}
+
+func day(d int) *time.Time {
+	t := time.Date(2025, 4, d, 12, 0, 0, 0, time.UTC)
+	return &t
+}
+
+func TestEvaluate(t *testing.T) {
+	h := History{
+		Complexes: []models.Complex{
+			{ID: 1, Category: "健康"},
+			{ID: 2, Category: "仕事"},
+		},
+		Goals: []models.Goal{
+			{ID: 10, ComplexID: 1},
+			{ID: 20, ComplexID: 2},
+		},
+		Actions: []models.Action{
+			{ID: 100, GoalID: 10, CompletedAt: day(1)},
+			{ID: 101, GoalID: 10, CompletedAt: day(2)},
+			{ID: 102, GoalID: 10, CompletedAt: day(3)},
+			{ID: 103, GoalID: 10},
+			{ID: 200, GoalID: 20, CompletedAt: day(5)},
+			// Check-ins replace CompletedAt: one completion per done day.
+			{ID: 201, GoalID: 20, CompletedAt: day(1)},
+		},
+		Completions: []models.ActionCompletion{
+			{ActionID: 201, OccurrenceDate: *day(6), Status: models.CompletionDone},
+			{ActionID: 201, OccurrenceDate: *day(7), Status: models.CompletionDone},
+			{ActionID: 201, OccurrenceDate: *day(8), Status: models.CompletionSkipped},
+		},
+	}
+	tests := []struct {
+		src   string
+		want  bool
+		value int
+	}{
+		{"complexes >= 2", true, 2},
+		{`complexes(category = "健康") = 1`, true, 1},
+		{`goals(category != "健康") = 1`, true, 1},
+		{"actions = 6", true, 6},
+		{"completed_actions = 6", true, 6},
+		{"completed_actions(goal_id = 10) >= 4", false, 3},
+		{`completed_actions(category = "仕事") = 3`, true, 3},
+		{"active_days = 6", true, 6},
+		{"streak_days = 3", true, 3},
+		{"streak_days(complex_id = 2) = 3", true, 3},
+		{"streak_days >= 3 for any goal", true, 3},
+		{"streak_days >= 3 for every goal", true, 3},
+		{"completed_actions > 3 for every goal", false, 3},
+		{"actions < 3 for any goal", true, 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			r, err := Compile(tt.src)
+			if err != nil {
+				t.Fatalf("Compile: %v", err)
+			}
+			res := r.Explain(h)
+			if res.Matched != tt.want {
+				t.Errorf("got %v, want %v", res.Matched, tt.want)
+			}
+			if len(res.Conditions) != 1 || res.Conditions[0].Value != tt.value {
+				t.Errorf("got conditions %+v, want one with value %d", res.Conditions, tt.value)
+			}
+		})
+	}
+}
+
+func TestExplainReportsEveryCondition(t *testing.T) {
+	r, err := Compile("complexes >= 1 or goals >= 1 and not actions >= 1")
+	if err != nil {
+		t.Fatalf("Compile: %v", err)
+	}
+	res := r.Explain(History{Complexes: []models.Complex{{ID: 1}}})
+	if !res.Matched {
+		t.Error("got no match, want a match")
+	}
+	want := []ConditionResult{
+		{Expression: "complexes >= 1", Value: 1, Matched: true},
+		{Expression: "goals >= 1", Value: 0, Matched: false},
+		{Expression: "actions >= 1", Value: 0, Matched: false},
+	}
+	if len(res.Conditions) != len(want) {
+		t.Fatalf("got %+v, want %+v", res.Conditions, want)
+	}
+	for i := range want {
+		if res.Conditions[i] != want[i] {
+			t.Errorf("condition %d: got %+v, want %+v", i, res.Conditions[i], want[i])
+		}
+	}
+}
+
+func TestEvaluateLocation(t *testing.T) {
+	// 23:30 UTC on April 1 is April 2 in Tokyo, which joins the run.
+	late := time.Date(2025, 4, 1, 23, 30, 0, 0, time.UTC)
+	h := History{
+		Goals: []models.Goal{{ID: 1}},
+		Actions: []models.Action{
+			{ID: 1, GoalID: 1, CompletedAt: &late},
+			{ID: 2, GoalID: 1, CompletedAt: day(3)},
+		},
+	}
+	r, err := Compile("streak_days >= 2")
+	if err != nil {
+		t.Fatalf("Compile: %v", err)
+	}
+	if r.Evaluate(h) {
+		t.Error("matched in UTC, want no match")
+	}
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Skip("no time zone data")
+	}
+	h.Location = tokyo
+	if !r.Evaluate(h) {
+		t.Error("did not match in Asia/Tokyo, want a match")
+	}
+}
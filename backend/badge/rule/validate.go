@@ -0,0 +1,98 @@
+package rule
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ValidationError reports a rule that parses but cannot be evaluated.
+type ValidationError struct {
+	Pos int
+	Msg string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("invalid rule at position %d: %s", e.Pos, e.Msg)
+}
+
+type metricSpec struct {
+	filters map[string]bool
+	// scoped metrics can be evaluated per goal with "for any/every goal".
+	scoped bool
+}
+
+var actionFilters = map[string]bool{"category": true, "goal_id": true, "complex_id": true}
+
+var metrics = map[string]metricSpec{
+	"completed_actions": {filters: actionFilters, scoped: true},
+	"actions":           {filters: actionFilters, scoped: true},
+	"active_days":       {filters: actionFilters, scoped: true},
+	"streak_days":       {filters: actionFilters, scoped: true},
+	"goals":             {filters: map[string]bool{"category": true, "complex_id": true}},
+	"complexes":         {filters: map[string]bool{"category": true}},
+}
+
+var comparisonOps = map[string]bool{">=": true, ">": true, "<=": true, "<": true, "=": true, "!=": true}
+
+var idFilters = map[string]bool{"goal_id": true, "complex_id": true}
+
+// Metrics returns the metric names a rule may use.
+func Metrics() []string {
+	names := make([]string, 0, len(metrics))
+	for name := range metrics {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Validate checks that every condition in the rule refers to a known metric
+// and uses filters, operators and scopes that the metric supports.
+func Validate(r *Rule) error {
+	return validateExpr(r.Root)
+}
+
+func validateExpr(e Expr) error {
+	switch n := e.(type) {
+	case *BinaryExpr:
+		if err := validateExpr(n.Left); err != nil {
+			return err
+		}
+		return validateExpr(n.Right)
+	case *NotExpr:
+		return validateExpr(n.X)
+	case *Condition:
+		return validateCondition(n)
+	default:
+		return &ValidationError{Msg: fmt.Sprintf("unsupported expression %T", e)}
+	}
+}
+
+func validateCondition(c *Condition) error {
+	spec, ok := metrics[c.Metric]
+	if !ok {
+		return &ValidationError{Pos: c.Pos, Msg: fmt.Sprintf("unknown metric %q (expected one of %s)", c.Metric, strings.Join(Metrics(), ", "))}
+	}
+	if !comparisonOps[c.Op] {
+		return &ValidationError{Pos: c.Pos, Msg: fmt.Sprintf("unsupported comparison operator %q", c.Op)}
+	}
+	if c.Scope != ScopeTotal && !spec.scoped {
+		return &ValidationError{Pos: c.Pos, Msg: fmt.Sprintf("metric %q cannot be scoped per goal", c.Metric)}
+	}
+	for _, f := range c.Filters {
+		if !spec.filters[f.Field] {
+			return &ValidationError{Pos: f.Pos, Msg: fmt.Sprintf("metric %q does not support filter %q", c.Metric, f.Field)}
+		}
+		if f.Op != "=" && f.Op != "!=" {
+			return &ValidationError{Pos: f.Pos, Msg: fmt.Sprintf("filter %q only supports '=' and '!='", f.Field)}
+		}
+		if idFilters[f.Field] {
+			if _, err := strconv.ParseUint(f.Value, 10, 64); err != nil {
+				return &ValidationError{Pos: f.Pos, Msg: fmt.Sprintf("filter %q requires an integer ID", f.Field)}
+			}
+		}
+	}
+	return nil
+}
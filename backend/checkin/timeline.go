@@ -0,0 +1,105 @@
+// Package checkin merges an action's recurrence schedule with its recorded
+// completions, so every scheduled occurrence gets a status.
+package checkin
+
+import (
+	"sort"
+	"time"
+
+	"refuel/backend/models"
+	"refuel/backend/recurrence"
+)
+
+// Status is the state of a single occurrence.
+type Status string
+
+const (
+	// Done, Skipped and Missed can be recorded by the user.
+	Done    Status = models.CompletionDone
+	Skipped Status = models.CompletionSkipped
+	Missed  Status = models.CompletionMissed
+	// Pending is never recorded. A past occurrence without a record is
+	// reported as missed, while today's and future ones are still pending.
+	Pending Status = "pending"
+)
+
+// Occurrence is one entry in an action's timeline.
+type Occurrence struct {
+	// Date is the calendar date, as midnight UTC.
+	Date time.Time
+	// ScheduledAt is nil for check-ins on days the schedule does not cover.
+	ScheduledAt *time.Time
+	Status      Status
+	Completion  *models.ActionCompletion
+}
+
+// ParseDate parses a YYYY-MM-DD occurrence date into midnight UTC.
+func ParseDate(s string) (time.Time, error) {
+	return time.Parse(time.DateOnly, s)
+}
+
+// Key returns the calendar date of t as YYYY-MM-DD. The time's own fields
+// are used as-is, because DATE columns carry no meaningful zone.
+func Key(t time.Time) string {
+	return t.Format(time.DateOnly)
+}
+
+// Civil returns the calendar date of t in loc as midnight UTC.
+func Civil(t time.Time, loc *time.Location) time.Time {
+	t = t.In(loc)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// InLocation returns midnight of the civil date in loc.
+func InLocation(date time.Time, loc *time.Location) time.Time {
+	return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, loc)
+}
+
+// Timeline lists every occurrence between from and to (inclusive civil
+// dates). Scheduled occurrences come from sched, which may be nil for
+// non-recurring actions; recorded completions on other days are included as
+// well. now decides which unrecorded occurrences count as missed.
+func Timeline(sched *recurrence.Schedule, completions []models.ActionCompletion, from, to, now time.Time) []Occurrence {
+	loc := time.UTC
+	if sched != nil {
+		loc = sched.Location()
+	}
+	today := Civil(now, loc)
+
+	byDate := make(map[string]*models.ActionCompletion, len(completions))
+	for i := range completions {
+		byDate[Key(completions[i].OccurrenceDate)] = &completions[i]
+	}
+
+	var out []Occurrence
+	seen := map[string]bool{}
+	if sched != nil {
+		for _, day := range sched.Dates(InLocation(from, loc), InLocation(to, loc)) {
+			date := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.UTC)
+			at := sched.At(day)
+			occ := Occurrence{Date: date, ScheduledAt: &at}
+			if c, ok := byDate[Key(date)]; ok {
+				occ.Status = Status(c.Status)
+				occ.Completion = c
+			} else if date.Before(today) {
+				occ.Status = Missed
+			} else {
+				occ.Status = Pending
+			}
+			seen[Key(date)] = true
+			out = append(out, occ)
+		}
+	}
+
+	for i := range completions {
+		c := &completions[i]
+		date := time.Date(c.OccurrenceDate.Year(), c.OccurrenceDate.Month(), c.OccurrenceDate.Day(), 0, 0, 0, 0, time.UTC)
+		if seen[Key(date)] || date.Before(from) || date.After(to) {
+			continue
+		}
+		out = append(out, Occurrence{Date: date, Status: Status(c.Status), Completion: c})
+	}
+
+	sort.Slice(out, func(i, j int) bool { return out[i].Date.Before(out[j].Date) })
+	return out
+}
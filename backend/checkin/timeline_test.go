@@ -0,0 +1,136 @@
+package checkin
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"refuel/backend/models"
+	"refuel/backend/recurrence"
+)
+
+func date(day int) time.Time {
+	return time.Date(2025, 4, day, 0, 0, 0, 0, time.UTC)
+}
+
+func schedule(t *testing.T, p recurrence.Pattern, loc *time.Location) *recurrence.Schedule {
+	t.Helper()
+	s, err := recurrence.NewSchedule(p, date(1), loc)
+	if err != nil {
+		t.Fatalf("NewSchedule: %v", err)
+	}
+	return &s
+}
+
+// format lists occurrences as "date status time", with "-" for the time
+// of an off-schedule check-in.
+func format(occs []Occurrence) string {
+	var lines []string
+	for _, occ := range occs {
+		at := "-"
+		if occ.ScheduledAt != nil {
+			at = occ.ScheduledAt.UTC().Format("15:04")
+		}
+		if (occ.Completion != nil) != (occ.Status != Missed && occ.Status != Pending) {
+			at += " (completion mismatch)"
+		}
+		lines = append(lines, Key(occ.Date)+" "+string(occ.Status)+" "+at)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func TestTimeline(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Skip("no time zone data")
+	}
+	daily := recurrence.Pattern{Frequency: recurrence.Daily, TimeOfDay: "09:00"}
+	mwf := recurrence.Pattern{Frequency: recurrence.Weekly, TimeOfDay: "09:00", DaysOfWeek: []string{"MON", "WED", "FRI"}}
+	completions := []models.ActionCompletion{
+		{OccurrenceDate: date(2), Status: models.CompletionDone},
+		{OccurrenceDate: date(4), Status: models.CompletionSkipped},
+		{OccurrenceDate: date(8), Status: models.CompletionDone},
+	}
+	tests := []struct {
+		name     string
+		sched    func(t *testing.T) *recurrence.Schedule
+		from, to time.Time
+		now      time.Time
+		want     []string
+	}{
+		{
+			name:  "past days without a check-in are missed",
+			sched: func(t *testing.T) *recurrence.Schedule { return schedule(t, daily, time.UTC) },
+			from:  date(1), to: date(6), now: date(5).Add(12 * time.Hour),
+			want: []string{
+				"2025-04-01 missed 09:00",
+				"2025-04-02 done 09:00",
+				"2025-04-03 missed 09:00",
+				"2025-04-04 skipped 09:00",
+				"2025-04-05 pending 09:00",
+				"2025-04-06 pending 09:00",
+			},
+		},
+		{
+			name:  "check-ins on unscheduled days are included",
+			sched: func(t *testing.T) *recurrence.Schedule { return schedule(t, mwf, time.UTC) },
+			from:  date(1), to: date(9), now: date(10),
+			want: []string{
+				"2025-04-02 done 09:00",
+				"2025-04-04 skipped 09:00",
+				"2025-04-07 missed 09:00",
+				"2025-04-08 done -",
+				"2025-04-09 missed 09:00",
+			},
+		},
+		{
+			name:  "an action without a schedule has its check-ins only",
+			sched: func(t *testing.T) *recurrence.Schedule { return nil },
+			from:  date(3), to: date(30), now: date(10),
+			want: []string{
+				"2025-04-04 skipped -",
+				"2025-04-08 done -",
+			},
+		},
+		{
+			name:  "today is the schedule's day",
+			sched: func(t *testing.T) *recurrence.Schedule { return schedule(t, daily, tokyo) },
+			// 16:00 UTC on April 2 is April 3 in Tokyo.
+			from: date(2), to: date(3), now: date(2).Add(16 * time.Hour),
+			want: []string{
+				"2025-04-02 done 00:00",
+				"2025-04-03 pending 00:00",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := format(Timeline(tt.sched(t), completions, tt.from, tt.to, tt.now))
+			if want := strings.Join(tt.want, "\n"); got != want {
+				t.Errorf("got\n%s\nwant\n%s", got, want)
+			}
+		})
+	}
+}
+
+func TestCivil(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Skip("no time zone data")
+	}
+	tests := []struct {
+		t    time.Time
+		loc  *time.Location
+		want string
+	}{
+		{time.Date(2025, 4, 1, 23, 30, 0, 0, time.UTC), time.UTC, "2025-04-01"},
+		{time.Date(2025, 4, 1, 23, 30, 0, 0, time.UTC), tokyo, "2025-04-02"},
+		{time.Date(2025, 4, 2, 8, 0, 0, 0, tokyo), time.UTC, "2025-04-01"},
+	}
+	for _, tt := range tests {
+		got := Civil(tt.t, tt.loc)
+		if Key(got) != tt.want || got.Location() != time.UTC || !got.Equal(InLocation(got, time.UTC)) {
+			t.Errorf("Civil(%v, %v): got %v, want %s at midnight UTC", tt.t, tt.loc, got, tt.want)
+		}
+	}
+}
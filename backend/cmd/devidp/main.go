@@ -0,0 +1,303 @@
+// Command devidp is a minimal OpenID Connect provider for local development.
+// It implements just enough of the authorization code flow with PKCE for the
+// API's OIDC login to be exercised without a real identity provider:
+// discovery, an authorization page that signs in any email address, the
+// token endpoint and a JWKS with optional key rotation.
+//
+// Point the API at it with
+//
+//	OIDC_ISSUER=http://localhost:9000
+//	OIDC_CLIENT_ID=refuel-dev
+//	OIDC_REDIRECT_URL=http://localhost:3000/auth/callback
+//
+// Never expose it outside a development machine: it authenticates anyone.
+package main
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"flag"
+	"html/template"
+	"log"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const codeTTL = time.Minute
+
+type signingKey struct {
+	kid string
+	key *rsa.PrivateKey
+}
+
+// authCode is an issued, not yet redeemed authorization code.
+type authCode struct {
+	clientID      string
+	redirectURI   string
+	codeChallenge string
+	nonce         string
+	email         string
+	expiresAt     time.Time
+}
+
+type provider struct {
+	issuer   string
+	clientID string
+	tokenTTL time.Duration
+
+	mu    sync.Mutex
+	keys  []signingKey // [next, current, previous]; keys[1] signs
+	codes map[string]authCode
+}
+
+func main() {
+	addr := flag.String("addr", ":9000", "listen address")
+	issuer := flag.String("issuer", "http://localhost:9000", "issuer URL advertised in discovery and tokens")
+	clientID := flag.String("client-id", "refuel-dev", "the only client ID accepted")
+	tokenTTL := flag.Duration("token-ttl", time.Hour, "ID token lifetime")
+	rotate := flag.Duration("rotate", 0, "rotate the signing key at this interval (0 disables rotation)")
+	flag.Parse()
+
+	p := &provider{
+		issuer:   strings.TrimSuffix(*issuer, "/"),
+		clientID: *clientID,
+		tokenTTL: *tokenTTL,
+		codes:    map[string]authCode{},
+	}
+	p.rotateKey()
+	if *rotate > 0 {
+		go func() {
+			for range time.Tick(*rotate) {
+				p.rotateKey()
+			}
+		}()
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /.well-known/openid-configuration", p.discovery)
+	mux.HandleFunc("GET /jwks", p.jwks)
+	mux.HandleFunc("GET /authorize", p.authorizeForm)
+	mux.HandleFunc("POST /authorize", p.authorize)
+	mux.HandleFunc("POST /token", p.token)
+
+	log.Printf("Dev OIDC provider %s listening on %s (client_id=%s)", p.issuer, *addr, p.clientID)
+	log.Fatal(http.ListenAndServe(*addr, mux))
+}
+
+// rotateKey promotes the pre-published next key to signing and generates a
+// new next key. The retired key stays published for one more period so
+// tokens signed just before the rotation still verify, and the next key is
+// published a period ahead so clients can fetch it before it is used.
+func (p *provider) rotateKey() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for len(p.keys) < 2 {
+		p.keys = append([]signingKey{newSigningKey()}, p.keys...)
+	}
+	p.keys = append([]signingKey{newSigningKey()}, p.keys...)
+	if len(p.keys) > 3 {
+		p.keys = p.keys[:3]
+	}
+	log.Printf("Signing with key %s", p.keys[1].kid)
+}
+
+func newSigningKey() signingKey {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		log.Fatalf("failed to generate signing key: %v", err)
+	}
+	return signingKey{kid: randomString(8), key: key}
+}
+
+func (p *provider) discovery(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"issuer":                                p.issuer,
+		"authorization_endpoint":                p.issuer + "/authorize",
+		"token_endpoint":                        p.issuer + "/token",
+		"jwks_uri":                              p.issuer + "/jwks",
+		"response_types_supported":              []string{"code"},
+		"subject_types_supported":               []string{"public"},
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+		"code_challenge_methods_supported":      []string{"S256"},
+		"scopes_supported":                      []string{"openid", "email", "profile"},
+	})
+}
+
+func (p *provider) jwks(w http.ResponseWriter, r *http.Request) {
+	p.mu.Lock()
+	keys := make([]map[string]string, len(p.keys))
+	for i, k := range p.keys {
+		keys[i] = map[string]string{
+			"kty": "RSA",
+			"use": "sig",
+			"alg": "RS256",
+			"kid": k.kid,
+			"n":   base64.RawURLEncoding.EncodeToString(k.key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(k.key.E)).Bytes()),
+		}
+	}
+	p.mu.Unlock()
+	w.Header().Set("Cache-Control", "max-age=300")
+	writeJSON(w, http.StatusOK, map[string]interface{}{"keys": keys})
+}
+
+var formTemplate = template.Must(template.New("authorize").Parse(`<!doctype html>
+<html><head><meta charset="utf-8"><title>Dev IdP</title></head>
+<body>
+<h1>Dev IdP sign-in</h1>
+<p>Any email address is accepted. The subject is derived from it, so the same email always maps to the same user.</p>
+<form method="post" action="/authorize?{{.Query}}">
+<input type="email" name="email" value="dev@example.com" required>
+<button type="submit">Sign in</button>
+</form>
+</body></html>`))
+
+// authorizeForm validates the authorization request and asks for an email.
+func (p *provider) authorizeForm(w http.ResponseWriter, r *http.Request) {
+	if msg := p.checkAuthorizeRequest(r.URL.Query()); msg != "" {
+		http.Error(w, msg, http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_ = formTemplate.Execute(w, map[string]string{"Query": r.URL.RawQuery})
+}
+
+// authorize issues a code for the submitted email and redirects back to the client.
+func (p *provider) authorize(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	if msg := p.checkAuthorizeRequest(q); msg != "" {
+		http.Error(w, msg, http.StatusBadRequest)
+		return
+	}
+	email := strings.TrimSpace(r.PostFormValue("email"))
+	if email == "" {
+		http.Error(w, "email is required", http.StatusBadRequest)
+		return
+	}
+
+	code := randomString(24)
+	p.mu.Lock()
+	p.codes[code] = authCode{
+		clientID:      q.Get("client_id"),
+		redirectURI:   q.Get("redirect_uri"),
+		codeChallenge: q.Get("code_challenge"),
+		nonce:         q.Get("nonce"),
+		email:         email,
+		expiresAt:     time.Now().Add(codeTTL),
+	}
+	p.mu.Unlock()
+
+	redirect, _ := url.Parse(q.Get("redirect_uri"))
+	rq := redirect.Query()
+	rq.Set("code", code)
+	rq.Set("state", q.Get("state"))
+	redirect.RawQuery = rq.Encode()
+	http.Redirect(w, r, redirect.String(), http.StatusFound)
+}
+
+func (p *provider) checkAuthorizeRequest(q url.Values) string {
+	switch {
+	case q.Get("response_type") != "code":
+		return "response_type must be code"
+	case q.Get("client_id") != p.clientID:
+		return "unknown client_id"
+	case q.Get("redirect_uri") == "":
+		return "redirect_uri is required"
+	case q.Get("code_challenge") == "" || q.Get("code_challenge_method") != "S256":
+		return "PKCE with code_challenge_method=S256 is required"
+	case !strings.Contains(" "+q.Get("scope")+" ", " openid "):
+		return "scope must include openid"
+	}
+	if _, err := url.ParseRequestURI(q.Get("redirect_uri")); err != nil {
+		return "invalid redirect_uri"
+	}
+	return ""
+}
+
+// token redeems an authorization code after checking the PKCE verifier.
+func (p *provider) token(w http.ResponseWriter, r *http.Request) {
+	if r.PostFormValue("grant_type") != "authorization_code" {
+		tokenError(w, "unsupported_grant_type", "only authorization_code is supported")
+		return
+	}
+	code := r.PostFormValue("code")
+	p.mu.Lock()
+	ac, ok := p.codes[code]
+	delete(p.codes, code)
+	signer := p.keys[1]
+	p.mu.Unlock()
+
+	switch {
+	case !ok || time.Now().After(ac.expiresAt):
+		tokenError(w, "invalid_grant", "unknown or expired code")
+		return
+	case r.PostFormValue("client_id") != ac.clientID:
+		tokenError(w, "invalid_client", "client_id does not match the code")
+		return
+	case r.PostFormValue("redirect_uri") != ac.redirectURI:
+		tokenError(w, "invalid_grant", "redirect_uri does not match the authorization request")
+		return
+	}
+	sum := sha256.Sum256([]byte(r.PostFormValue("code_verifier")))
+	if base64.RawURLEncoding.EncodeToString(sum[:]) != ac.codeChallenge {
+		tokenError(w, "invalid_grant", "code_verifier does not match code_challenge")
+		return
+	}
+
+	now := time.Now()
+	subject := sha256.Sum256([]byte(strings.ToLower(ac.email)))
+	claims := jwt.MapClaims{
+		"iss":            p.issuer,
+		"sub":            base64.RawURLEncoding.EncodeToString(subject[:12]),
+		"aud":            ac.clientID,
+		"iat":            now.Unix(),
+		"exp":            now.Add(p.tokenTTL).Unix(),
+		"email":          ac.email,
+		"email_verified": true,
+		"name":           strings.Split(ac.email, "@")[0],
+	}
+	if ac.nonce != "" {
+		claims["nonce"] = ac.nonce
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = signer.kid
+	idToken, err := token.SignedString(signer.key)
+	if err != nil {
+		tokenError(w, "server_error", err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"access_token": idToken,
+		"id_token":     idToken,
+		"token_type":   "Bearer",
+		"expires_in":   int(p.tokenTTL.Seconds()),
+	})
+}
+
+func tokenError(w http.ResponseWriter, code, description string) {
+	writeJSON(w, http.StatusBadRequest, map[string]string{"error": code, "error_description": description})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func randomString(n int) string {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		log.Fatalf("failed to read random bytes: %v", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(buf)
+}
@@ -0,0 +1,170 @@
+// Command rotatekeys re-wraps the users' data keys with the active master
+// key of ENCRYPTION_MASTER_KEYS, the first one listed. The encrypted
+// fields themselves are left alone, so it runs against a live database:
+//
+//  1. Put the new master key first in ENCRYPTION_MASTER_KEYS, keeping the
+//     old one after it, and redeploy. Servers now wrap new data keys with
+//     the new master key and still unwrap the old ones.
+//  2. Run rotatekeys with the same ENCRYPTION_MASTER_KEYS and database
+//     settings (DB_DRIVER and the rest) as the servers.
+//  3. Remove the old master key from ENCRYPTION_MASTER_KEYS and redeploy.
+//
+// With -encrypt-plaintext it also encrypts the values written before
+// encryption was configured, which are otherwise read as plaintext and
+// only encrypted when next saved.
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"refuel/backend/database"
+	"refuel/backend/envelope"
+	"refuel/backend/models"
+	"refuel/backend/repository"
+)
+
+// batchSize is how many rows -encrypt-plaintext reads at a time.
+const batchSize = 500
+
+// encryptedTable describes a table with encrypted columns and how to find
+// the user each row is encrypted for.
+type encryptedTable struct {
+	name    string
+	columns []string
+	// owner selects the owning user's ID; join brings in its table.
+	owner string
+	join  string
+}
+
+var encryptedTables = []encryptedTable{
+	{name: "complexes", columns: []string{"content", "trigger_episode"}, owner: "t.user_id"},
+	{name: "gains", columns: []string{"description"}, owner: "a.user_id", join: "JOIN actions a ON a.id = t.action_id"},
+	{name: "losses", columns: []string{"description"}, owner: "a.user_id", join: "JOIN actions a ON a.id = t.action_id"},
+	{name: "revisions", columns: []string{"snapshot_before", "snapshot_after"}, owner: "t.user_id"},
+	{name: "feedback_messages", columns: []string{"message"}, owner: "t.user_id"},
+}
+
+func main() {
+	encryptPlaintext := flag.Bool("encrypt-plaintext", false, "also encrypt the values stored before encryption was configured")
+	flag.Parse()
+
+	keyring, err := envelope.ConfigFromEnv()
+	if err != nil {
+		log.Fatalf("🚨 Invalid encryption configuration: %v", err)
+	}
+	if keyring == nil {
+		log.Fatal("🚨 ENCRYPTION_MASTER_KEYS is not set")
+	}
+	dbConfig, err := database.ConfigFromEnv()
+	if err != nil {
+		log.Fatalf("🚨 Invalid database configuration: %v", err)
+	}
+	gormLogger := logger.New(log.New(os.Stderr, "\r\n", log.LstdFlags), logger.Config{
+		SlowThreshold:             time.Second,
+		LogLevel:                  logger.Warn,
+		IgnoreRecordNotFoundError: true,
+	})
+	db, err := database.Open(dbConfig, &gorm.Config{Logger: gormLogger})
+	if err != nil {
+		log.Fatalf("🚨 Failed to connect to database: %v", err)
+	}
+	if err := database.CheckSchema(db); err != nil {
+		log.Fatalf("🚨 Database schema check failed: %v", err)
+	}
+
+	ctx := context.Background()
+	rewrapped, err := envelope.Rotate(ctx, repository.NewGormDataKeys(db), keyring)
+	if err != nil {
+		log.Fatalf("🚨 Failed to rotate data keys (%d re-wrapped so far, run again to resume): %v", rewrapped, err)
+	}
+	log.Printf("🔑 Re-wrapped %d data key(s) with master key %q", rewrapped, keyring.ActiveID())
+
+	if *encryptPlaintext {
+		cipher := envelope.NewCipher(keyring)
+		for _, table := range encryptedTables {
+			encrypted, err := encryptTable(db.WithContext(ctx), cipher, table)
+			if err != nil {
+				log.Fatalf("🚨 Failed to encrypt %s (%d row(s) encrypted so far, run again to resume): %v", table.name, encrypted, err)
+			}
+			log.Printf("🔒 Encrypted %d plaintext row(s) of %s", encrypted, table.name)
+		}
+	}
+}
+
+// encryptTable encrypts the plaintext values of a table's encrypted columns,
+// reading a batch of rows at a time, and returns how many rows it updated.
+// Rows are updated directly, without the models' hooks, so that their
+// updated_at stays as it is.
+func encryptTable(db *gorm.DB, cipher *envelope.Cipher, table encryptedTable) (int, error) {
+	query := fmt.Sprintf("SELECT t.id, %s, t.%s FROM %s t %s WHERE t.id > ? ORDER BY t.id LIMIT ?",
+		table.owner, strings.Join(table.columns, ", t."), table.name, table.join)
+	encrypted := 0
+	var lastID uint
+	for {
+		type row struct {
+			id     uint
+			userID string
+			values []sql.NullString
+		}
+		var batch []row
+		rows, err := db.Raw(query, lastID, batchSize).Rows()
+		if err != nil {
+			return encrypted, err
+		}
+		for rows.Next() {
+			r := row{values: make([]sql.NullString, len(table.columns))}
+			dest := []interface{}{&r.id, &r.userID}
+			for i := range r.values {
+				dest = append(dest, &r.values[i])
+			}
+			if err := rows.Scan(dest...); err != nil {
+				rows.Close()
+				return encrypted, err
+			}
+			batch = append(batch, r)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return encrypted, err
+		}
+		if len(batch) == 0 {
+			return encrypted, nil
+		}
+
+		for _, r := range batch {
+			lastID = r.id
+			updates := map[string]interface{}{}
+			update := db.Table(table.name).Where("id = ?", r.id)
+			for i, v := range r.values {
+				if !v.Valid || v.String == "" || strings.HasPrefix(v.String, models.EncryptedPrefix) {
+					continue
+				}
+				ciphertext, err := cipher.Encrypt(db, r.userID, v.String)
+				if err != nil {
+					return encrypted, err
+				}
+				updates[table.columns[i]] = ciphertext
+				// Leave a value saved by a server meanwhile, already encrypted, alone.
+				update = update.Where(table.columns[i]+" = ?", v.String)
+			}
+			if len(updates) == 0 {
+				continue
+			}
+			result := update.UpdateColumns(updates)
+			if result.Error != nil {
+				return encrypted, result.Error
+			}
+			encrypted += int(result.RowsAffected)
+		}
+	}
+}
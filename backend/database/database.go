@@ -0,0 +1,189 @@
+// Package database opens the GORM connection and applies the SQL migrations
+// for the configured driver: MySQL, PostgreSQL or SQLite.
+//
+// Every driver has its own migration directory under db/migrations, named
+// after the driver, with the same version numbers so all three schemas move
+// in lockstep.
+package database
+
+import (
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/golang-migrate/migrate/v4"
+	_ "github.com/golang-migrate/migrate/v4/database/mysql"
+	_ "github.com/golang-migrate/migrate/v4/database/pgx/v5"
+	_ "github.com/golang-migrate/migrate/v4/database/sqlite" // Also registers the pure-Go "sqlite" database/sql driver.
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// Driver names a supported database.
+type Driver string
+
+// Supported drivers. The value doubles as the migration subdirectory name.
+const (
+	MySQL    Driver = "mysql"
+	Postgres Driver = "postgres"
+	SQLite   Driver = "sqlite"
+)
+
+// DefaultSQLitePath is the database file used when DB_DRIVER=sqlite and DB_PATH is unset.
+const DefaultSQLitePath = "refuel.db"
+
+// Config describes how to reach the database.
+type Config struct {
+	Driver Driver
+	// Host, Port, User, Password and Name are used by MySQL and PostgreSQL.
+	Host     string
+	Port     string
+	User     string
+	Password string
+	Name     string
+	// SSLMode is passed to PostgreSQL. It defaults to disable.
+	SSLMode string
+	// Path is the SQLite database file.
+	Path string
+}
+
+// ConfigFromEnv reads DB_DRIVER (mysql, postgres or sqlite; mysql when
+// unset) and the settings that driver needs: DB_HOST, DB_PORT, DB_USER,
+// DB_PASSWORD and DB_NAME for the servers, DB_PATH for SQLite.
+func ConfigFromEnv() (Config, error) {
+	cfg := Config{
+		Driver:   Driver(strings.ToLower(os.Getenv("DB_DRIVER"))),
+		Host:     os.Getenv("DB_HOST"),
+		Port:     os.Getenv("DB_PORT"),
+		User:     os.Getenv("DB_USER"),
+		Password: os.Getenv("DB_PASSWORD"),
+		Name:     os.Getenv("DB_NAME"),
+		SSLMode:  os.Getenv("DB_SSLMODE"),
+		Path:     os.Getenv("DB_PATH"),
+	}
+	switch cfg.Driver {
+	case "":
+		cfg.Driver = MySQL
+	case MySQL, Postgres, SQLite:
+	default:
+		return cfg, fmt.Errorf("unsupported DB_DRIVER %q: use mysql, postgres or sqlite", cfg.Driver)
+	}
+
+	if cfg.Driver == SQLite {
+		if cfg.Path == "" {
+			cfg.Path = DefaultSQLitePath
+		}
+		return cfg, nil
+	}
+	if cfg.SSLMode == "" {
+		cfg.SSLMode = "disable"
+	}
+	required := []struct{ key, value string }{
+		{"DB_HOST", cfg.Host},
+		{"DB_PORT", cfg.Port},
+		{"DB_USER", cfg.User},
+		{"DB_PASSWORD", cfg.Password},
+		{"DB_NAME", cfg.Name},
+	}
+	for _, r := range required {
+		if r.value == "" {
+			return cfg, fmt.Errorf("missing required environment variable: %s", r.key)
+		}
+	}
+	return cfg, nil
+}
+
+// Open connects to the database.
+func Open(cfg Config, gormConfig *gorm.Config) (*gorm.DB, error) {
+	var dialector gorm.Dialector
+	switch cfg.Driver {
+	case MySQL:
+		dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=True&loc=Local",
+			cfg.User, cfg.Password, cfg.Host, cfg.Port, cfg.Name,
+		)
+		dialector = mysql.Open(dsn)
+	case Postgres:
+		dialector = postgres.Open(postgresURL("postgres", cfg))
+	case SQLite:
+		// Use the pure-Go driver registered by the migrate import, so no cgo
+		// toolchain is needed. Foreign keys are off in SQLite unless enabled
+		// per connection, and the cascading deletes depend on them.
+		dialector = sqlite.New(sqlite.Config{
+			DriverName: "sqlite",
+			DSN:        cfg.Path + "?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)",
+		})
+	default:
+		return nil, fmt.Errorf("unsupported driver %q", cfg.Driver)
+	}
+
+	db, err := gorm.Open(dialector, gormConfig)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Driver == SQLite {
+		// SQLite allows one writer at a time; serialising access avoids "database is locked".
+		sqlDB, err := db.DB()
+		if err != nil {
+			return nil, err
+		}
+		sqlDB.SetMaxOpenConns(1)
+	}
+	return db, nil
+}
+
+// MigrationSource returns the migration directory for the driver below
+// base, a golang-migrate source URL such as "file://./db/migrations".
+func MigrationSource(base string, driver Driver) string {
+	return strings.TrimSuffix(base, "/") + "/" + string(driver)
+}
+
+// Migrate applies every pending migration from the driver's directory below base.
+func Migrate(cfg Config, base string) error {
+	var dbURL string
+	switch cfg.Driver {
+	case MySQL:
+		dbURL = fmt.Sprintf("mysql://%s:%s@tcp(%s:%s)/%s",
+			cfg.User, cfg.Password, cfg.Host, cfg.Port, cfg.Name,
+		)
+	case Postgres:
+		dbURL = postgresURL("pgx5", cfg)
+	case SQLite:
+		dbURL = "sqlite://" + cfg.Path
+	default:
+		return fmt.Errorf("unsupported driver %q", cfg.Driver)
+	}
+
+	m, err := migrate.New(MigrationSource(base, cfg.Driver), dbURL)
+	if err != nil {
+		return fmt.Errorf("failed to initialize migrations: %w", err)
+	}
+	if err := m.Up(); err != nil && err != migrate.ErrNoChange {
+		return fmt.Errorf("failed to run migrations: %w", err)
+	}
+	log.Println("🎉 Migrations applied successfully or no changes.")
+	srcErr, dbErr := m.Close()
+	if srcErr != nil {
+		log.Printf("⚠️ Error closing migration source: %v", srcErr)
+	}
+	if dbErr != nil {
+		log.Printf("⚠️ Error closing migration database connection: %v", dbErr)
+	}
+	return nil
+}
+
+// postgresURL builds a PostgreSQL connection URL with the given scheme.
+func postgresURL(scheme string, cfg Config) string {
+	u := url.URL{
+		Scheme:   scheme,
+		User:     url.UserPassword(cfg.User, cfg.Password),
+		Host:     cfg.Host + ":" + cfg.Port,
+		Path:     "/" + cfg.Name,
+		RawQuery: url.Values{"sslmode": {cfg.SSLMode}}.Encode(),
+	}
+	return u.String()
+}
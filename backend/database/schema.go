@@ -0,0 +1,77 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"refuel/backend/models"
+)
+
+// SchemaVersion is the migration version the models in package models are
+// written against. Bump it together with every new migration.
+const SchemaVersion uint = 19
+
+// migrationsTable is where golang-migrate records the applied version.
+const migrationsTable = "schema_migrations"
+
+// CheckSchema refuses a database whose applied migration version differs from
+// SchemaVersion, that is left dirty by a failed migration, or that is missing
+// a table or column one of the GORM models maps to. Serving against such a
+// database would fail later, one query at a time, instead of at startup.
+func CheckSchema(db *gorm.DB) error {
+	if !db.Migrator().HasTable(migrationsTable) {
+		return fmt.Errorf("no migrations have been applied (expected version %d): set MIGRATION_PATH to apply them", SchemaVersion)
+	}
+	var state struct {
+		Version int64
+		Dirty   bool
+	}
+	if err := db.Table(migrationsTable).Select("version, dirty").Take(&state).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("no migrations have been applied (expected version %d): set MIGRATION_PATH to apply them", SchemaVersion)
+		}
+		return fmt.Errorf("failed to read the migration version: %w", err)
+	}
+	if state.Dirty {
+		return fmt.Errorf("migration %d failed halfway and left the database dirty: fix it by hand and force the version", state.Version)
+	}
+	switch {
+	case state.Version < int64(SchemaVersion):
+		return fmt.Errorf("database is at migration %d but the models expect %d: apply the pending migrations", state.Version, SchemaVersion)
+	case state.Version > int64(SchemaVersion):
+		return fmt.Errorf("database is at migration %d, newer than the %d the models expect: deploy a matching build", state.Version, SchemaVersion)
+	}
+
+	var problems []string
+	for _, model := range models.All() {
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(model); err != nil {
+			return fmt.Errorf("failed to parse model %T: %w", model, err)
+		}
+		table := stmt.Schema.Table
+		if !db.Migrator().HasTable(table) {
+			problems = append(problems, "missing table "+table)
+			continue
+		}
+		columnTypes, err := db.Migrator().ColumnTypes(model)
+		if err != nil {
+			return fmt.Errorf("failed to read the columns of %s: %w", table, err)
+		}
+		columns := make(map[string]bool, len(columnTypes))
+		for _, c := range columnTypes {
+			columns[strings.ToLower(c.Name())] = true
+		}
+		for _, field := range stmt.Schema.Fields {
+			if field.DBName != "" && !columns[strings.ToLower(field.DBName)] {
+				problems = append(problems, "missing column "+table+"."+field.DBName)
+			}
+		}
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("database schema does not match the models: %s", strings.Join(problems, ", "))
+	}
+	return nil
+}
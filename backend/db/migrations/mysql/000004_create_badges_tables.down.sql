@@ -0,0 +1,3 @@
+-- This migration will drop the badge tables if they exist
+DROP TABLE IF EXISTS user_badges;
+DROP TABLE IF EXISTS badges;
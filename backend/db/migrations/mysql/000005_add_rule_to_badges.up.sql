@@ -0,0 +1 @@
+ALTER TABLE badges ADD COLUMN rule TEXT NOT NULL AFTER icon_url;
@@ -0,0 +1,14 @@
+CREATE TABLE action_completions (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    action_id INT NOT NULL,
+    user_id VARCHAR(36) NOT NULL,
+    occurrence_date DATE NOT NULL,
+    status VARCHAR(10) NOT NULL,
+    completed_at TIMESTAMP NULL,
+    note TEXT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_action_occurrence (action_id, occurrence_date),
+    INDEX idx_user_id_action_completion (user_id),
+    FOREIGN KEY (action_id) REFERENCES actions(id) ON DELETE CASCADE
+);
@@ -0,0 +1,29 @@
+ALTER TABLE complexes ADD COLUMN trigger_episode TEXT NULL AFTER content;
+
+-- Goals used to be split into a surface goal and the underlying goal behind it.
+-- They are merged into a single content column, keeping both parts when they differ.
+ALTER TABLE goals ADD COLUMN content TEXT NULL AFTER complex_id;
+UPDATE goals SET content = CASE
+    WHEN underlying_goal = '' OR underlying_goal = surface_goal THEN surface_goal
+    ELSE CONCAT(surface_goal, ' / ', underlying_goal)
+END;
+ALTER TABLE goals MODIFY content TEXT NOT NULL;
+ALTER TABLE goals DROP COLUMN surface_goal, DROP COLUMN underlying_goal;
+
+CREATE TABLE gains (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    action_id INT NOT NULL,
+    type VARCHAR(20) NOT NULL,
+    description TEXT NOT NULL,
+    INDEX idx_action_id_gain (action_id),
+    FOREIGN KEY (action_id) REFERENCES actions(id) ON DELETE CASCADE
+);
+
+CREATE TABLE losses (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    action_id INT NOT NULL,
+    type VARCHAR(20) NOT NULL,
+    description TEXT NOT NULL,
+    INDEX idx_action_id_loss (action_id),
+    FOREIGN KEY (action_id) REFERENCES actions(id) ON DELETE CASCADE
+);
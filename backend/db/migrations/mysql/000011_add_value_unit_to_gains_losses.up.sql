@@ -0,0 +1,4 @@
+ALTER TABLE gains ADD COLUMN value DOUBLE NULL;
+ALTER TABLE gains ADD COLUMN unit VARCHAR(20) NULL;
+ALTER TABLE losses ADD COLUMN value DOUBLE NULL;
+ALTER TABLE losses ADD COLUMN unit VARCHAR(20) NULL;
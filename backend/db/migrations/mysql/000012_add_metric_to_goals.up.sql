@@ -0,0 +1,18 @@
+ALTER TABLE goals ADD COLUMN metric_unit VARCHAR(20) NULL;
+ALTER TABLE goals ADD COLUMN metric_baseline DOUBLE NULL;
+ALTER TABLE goals ADD COLUMN metric_target DOUBLE NULL;
+ALTER TABLE goals ADD COLUMN metric_direction VARCHAR(10) NULL;
+ALTER TABLE goals ADD COLUMN metric_deadline DATE NULL;
+
+CREATE TABLE goal_measurements (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    goal_id INT NOT NULL,
+    user_id VARCHAR(36) NOT NULL,
+    value DOUBLE NOT NULL,
+    measured_at TIMESTAMP NOT NULL,
+    note TEXT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_goal_id_measured_at (goal_id, measured_at),
+    INDEX idx_user_id_goal_measurement (user_id),
+    FOREIGN KEY (goal_id) REFERENCES goals(id) ON DELETE CASCADE
+);
@@ -0,0 +1,6 @@
+-- This migration will drop the milestones table and the milestone badges users hold,
+-- then restore the one-badge-per-user unique key on user_badges
+DELETE FROM user_badges WHERE milestone_id <> 0;
+ALTER TABLE user_badges DROP INDEX uq_user_badge, ADD UNIQUE KEY uq_user_badge (user_id, badge_id);
+ALTER TABLE user_badges DROP COLUMN milestone_id;
+DROP TABLE IF EXISTS milestones;
@@ -0,0 +1,20 @@
+CREATE TABLE milestones (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    goal_id INT NOT NULL,
+    user_id VARCHAR(36) NOT NULL,
+    position INT NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    basis VARCHAR(20) NOT NULL,
+    threshold DOUBLE NOT NULL,
+    achieved_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    INDEX idx_goal_id_milestone (goal_id),
+    INDEX idx_user_id_milestone (user_id),
+    FOREIGN KEY (goal_id) REFERENCES goals(id) ON DELETE CASCADE
+);
+
+-- A milestone badge is held once per milestone, so the milestone joins the
+-- unique key. Badges awarded by their rule keep milestone_id 0.
+ALTER TABLE user_badges ADD COLUMN milestone_id INT NOT NULL DEFAULT 0;
+ALTER TABLE user_badges DROP INDEX uq_user_badge, ADD UNIQUE KEY uq_user_badge (user_id, badge_id, milestone_id);
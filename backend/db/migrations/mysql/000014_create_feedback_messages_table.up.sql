@@ -0,0 +1,10 @@
+CREATE TABLE feedback_messages (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    user_id VARCHAR(36) NOT NULL,
+    action_id INT NOT NULL,
+    template_code VARCHAR(64) NOT NULL,
+    message TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_user_id_created_at_feedback (user_id, created_at),
+    FOREIGN KEY (action_id) REFERENCES actions(id) ON DELETE CASCADE
+);
@@ -0,0 +1,2 @@
+-- This migration will drop the language column from users
+ALTER TABLE users DROP COLUMN language;
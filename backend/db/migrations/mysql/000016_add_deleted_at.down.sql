@@ -0,0 +1,7 @@
+-- This migration will drop the soft delete columns from complexes, goals and actions
+DROP INDEX idx_deleted_at_action ON actions;
+DROP INDEX idx_deleted_at_goal ON goals;
+DROP INDEX idx_deleted_at_complex ON complexes;
+ALTER TABLE actions DROP COLUMN deleted_at;
+ALTER TABLE goals DROP COLUMN deleted_at;
+ALTER TABLE complexes DROP COLUMN deleted_at;
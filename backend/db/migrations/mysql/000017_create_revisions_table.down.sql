@@ -0,0 +1,2 @@
+-- This migration will drop the revisions table
+DROP TABLE IF EXISTS revisions;
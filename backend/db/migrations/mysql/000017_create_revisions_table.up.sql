@@ -0,0 +1,12 @@
+CREATE TABLE revisions (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    user_id VARCHAR(36) NOT NULL,
+    entity_type VARCHAR(20) NOT NULL,
+    entity_id INT NOT NULL,
+    action VARCHAR(10) NOT NULL,
+    actor VARCHAR(36) NOT NULL,
+    snapshot_before TEXT NULL,
+    snapshot_after TEXT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_user_id_entity_revision (user_id, entity_type, entity_id)
+);
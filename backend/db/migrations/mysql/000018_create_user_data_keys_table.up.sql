@@ -0,0 +1,10 @@
+-- Each user's data key, wrapped (encrypted) by the master key named by
+-- master_key_id. The data key encrypts the user's sensitive text fields.
+CREATE TABLE user_data_keys (
+    user_id VARCHAR(36) PRIMARY KEY,
+    master_key_id VARCHAR(64) NOT NULL,
+    wrapped_key TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    INDEX idx_master_key_id_data_key (master_key_id)
+);
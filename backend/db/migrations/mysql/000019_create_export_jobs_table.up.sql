@@ -0,0 +1,15 @@
+-- archive holds the finished zip archive, base64-encoded (and encrypted
+-- like the other sensitive text when encryption is configured).
+CREATE TABLE export_jobs (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    user_id VARCHAR(36) NOT NULL,
+    status VARCHAR(10) NOT NULL,
+    error TEXT NULL,
+    archive LONGTEXT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    started_at TIMESTAMP NULL,
+    completed_at TIMESTAMP NULL,
+    expires_at TIMESTAMP NULL,
+    INDEX idx_user_id_export_job (user_id),
+    INDEX idx_status_export_job (status)
+);
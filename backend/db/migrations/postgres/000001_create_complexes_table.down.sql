@@ -0,0 +1,2 @@
+-- This migration will drop the complexes table if it exists
+DROP TABLE IF EXISTS complexes;
@@ -0,0 +1,2 @@
+-- This migration will drop the goals table if it exists
+DROP TABLE IF EXISTS goals;
\ No newline at end of file
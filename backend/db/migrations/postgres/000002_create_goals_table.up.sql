@@ -0,0 +1,12 @@
+CREATE TABLE goals (
+    id SERIAL PRIMARY KEY,
+    user_id VARCHAR(36) NOT NULL,
+    complex_id INT NOT NULL,
+    surface_goal VARCHAR(255) NOT NULL,
+    underlying_goal VARCHAR(255) NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (complex_id) REFERENCES complexes(id) ON DELETE CASCADE
+);
+CREATE INDEX idx_user_id_goal ON goals (user_id);
+CREATE INDEX idx_complex_id_goal ON goals (complex_id);
@@ -0,0 +1,2 @@
+-- This migration will drop the actions table if it exists
+DROP TABLE IF EXISTS actions;
\ No newline at end of file
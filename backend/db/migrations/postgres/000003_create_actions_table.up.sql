@@ -0,0 +1,12 @@
+CREATE TABLE actions (
+    id SERIAL PRIMARY KEY,
+    user_id VARCHAR(36) NOT NULL,
+    goal_id INT NOT NULL,
+    content TEXT NOT NULL,
+    completed_at TIMESTAMPTZ NULL,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (goal_id) REFERENCES goals(id) ON DELETE CASCADE
+);
+CREATE INDEX idx_user_id_action ON actions (user_id);
+CREATE INDEX idx_goal_id_action ON actions (goal_id);
@@ -0,0 +1 @@
+ALTER TABLE actions ADD COLUMN recurrence_pattern JSONB NULL;
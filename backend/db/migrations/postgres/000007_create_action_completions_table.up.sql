@@ -0,0 +1,14 @@
+CREATE TABLE action_completions (
+    id SERIAL PRIMARY KEY,
+    action_id INT NOT NULL,
+    user_id VARCHAR(36) NOT NULL,
+    occurrence_date DATE NOT NULL,
+    status VARCHAR(10) NOT NULL,
+    completed_at TIMESTAMPTZ NULL,
+    note TEXT NULL,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uq_action_occurrence UNIQUE (action_id, occurrence_date),
+    FOREIGN KEY (action_id) REFERENCES actions(id) ON DELETE CASCADE
+);
+CREATE INDEX idx_user_id_action_completion ON action_completions (user_id);
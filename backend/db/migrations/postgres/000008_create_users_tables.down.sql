@@ -0,0 +1,3 @@
+-- This migration will drop the user tables if they exist
+DROP TABLE IF EXISTS refresh_tokens;
+DROP TABLE IF EXISTS users;
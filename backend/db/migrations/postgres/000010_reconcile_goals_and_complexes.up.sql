@@ -0,0 +1,29 @@
+ALTER TABLE complexes ADD COLUMN trigger_episode TEXT NULL;
+
+-- Goals used to be split into a surface goal and the underlying goal behind it.
+-- They are merged into a single content column, keeping both parts when they differ.
+ALTER TABLE goals ADD COLUMN content TEXT NULL;
+UPDATE goals SET content = CASE
+    WHEN underlying_goal = '' OR underlying_goal = surface_goal THEN surface_goal
+    ELSE surface_goal || ' / ' || underlying_goal
+END;
+ALTER TABLE goals ALTER COLUMN content SET NOT NULL;
+ALTER TABLE goals DROP COLUMN surface_goal, DROP COLUMN underlying_goal;
+
+CREATE TABLE gains (
+    id SERIAL PRIMARY KEY,
+    action_id INT NOT NULL,
+    type VARCHAR(20) NOT NULL,
+    description TEXT NOT NULL,
+    FOREIGN KEY (action_id) REFERENCES actions(id) ON DELETE CASCADE
+);
+CREATE INDEX idx_action_id_gain ON gains (action_id);
+
+CREATE TABLE losses (
+    id SERIAL PRIMARY KEY,
+    action_id INT NOT NULL,
+    type VARCHAR(20) NOT NULL,
+    description TEXT NOT NULL,
+    FOREIGN KEY (action_id) REFERENCES actions(id) ON DELETE CASCADE
+);
+CREATE INDEX idx_action_id_loss ON losses (action_id);
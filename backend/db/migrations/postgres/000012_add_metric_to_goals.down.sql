@@ -0,0 +1,7 @@
+-- This migration will drop the goal_measurements table and the metric columns from goals
+DROP TABLE IF EXISTS goal_measurements;
+ALTER TABLE goals DROP COLUMN metric_deadline;
+ALTER TABLE goals DROP COLUMN metric_direction;
+ALTER TABLE goals DROP COLUMN metric_target;
+ALTER TABLE goals DROP COLUMN metric_baseline;
+ALTER TABLE goals DROP COLUMN metric_unit;
@@ -0,0 +1,18 @@
+ALTER TABLE goals ADD COLUMN metric_unit VARCHAR(20) NULL;
+ALTER TABLE goals ADD COLUMN metric_baseline DOUBLE PRECISION NULL;
+ALTER TABLE goals ADD COLUMN metric_target DOUBLE PRECISION NULL;
+ALTER TABLE goals ADD COLUMN metric_direction VARCHAR(10) NULL;
+ALTER TABLE goals ADD COLUMN metric_deadline DATE NULL;
+
+CREATE TABLE goal_measurements (
+    id SERIAL PRIMARY KEY,
+    goal_id INT NOT NULL,
+    user_id VARCHAR(36) NOT NULL,
+    value DOUBLE PRECISION NOT NULL,
+    measured_at TIMESTAMPTZ NOT NULL,
+    note TEXT NULL,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (goal_id) REFERENCES goals(id) ON DELETE CASCADE
+);
+CREATE INDEX idx_goal_id_measured_at ON goal_measurements (goal_id, measured_at);
+CREATE INDEX idx_user_id_goal_measurement ON goal_measurements (user_id);
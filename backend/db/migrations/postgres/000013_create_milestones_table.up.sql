@@ -0,0 +1,21 @@
+CREATE TABLE milestones (
+    id SERIAL PRIMARY KEY,
+    goal_id INT NOT NULL,
+    user_id VARCHAR(36) NOT NULL,
+    position INT NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    basis VARCHAR(20) NOT NULL,
+    threshold DOUBLE PRECISION NOT NULL,
+    achieved_at TIMESTAMPTZ NULL,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (goal_id) REFERENCES goals(id) ON DELETE CASCADE
+);
+CREATE INDEX idx_goal_id_milestone ON milestones (goal_id);
+CREATE INDEX idx_user_id_milestone ON milestones (user_id);
+
+-- A milestone badge is held once per milestone, so the milestone joins the
+-- unique key. Badges awarded by their rule keep milestone_id 0.
+ALTER TABLE user_badges ADD COLUMN milestone_id INT NOT NULL DEFAULT 0;
+ALTER TABLE user_badges DROP CONSTRAINT uq_user_badge;
+ALTER TABLE user_badges ADD CONSTRAINT uq_user_badge UNIQUE (user_id, badge_id, milestone_id);
@@ -0,0 +1,2 @@
+-- This migration will drop the feedback_messages table
+DROP TABLE IF EXISTS feedback_messages;
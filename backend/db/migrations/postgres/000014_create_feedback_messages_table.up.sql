@@ -0,0 +1,10 @@
+CREATE TABLE feedback_messages (
+    id SERIAL PRIMARY KEY,
+    user_id VARCHAR(36) NOT NULL,
+    action_id INT NOT NULL,
+    template_code VARCHAR(64) NOT NULL,
+    message TEXT NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (action_id) REFERENCES actions(id) ON DELETE CASCADE
+);
+CREATE INDEX idx_user_id_created_at_feedback ON feedback_messages (user_id, created_at);
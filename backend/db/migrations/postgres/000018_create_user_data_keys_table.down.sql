@@ -0,0 +1,2 @@
+-- This migration will drop the user_data_keys table
+DROP TABLE IF EXISTS user_data_keys;
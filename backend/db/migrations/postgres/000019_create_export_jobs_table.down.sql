@@ -0,0 +1,2 @@
+-- This migration will drop the export_jobs table
+DROP TABLE IF EXISTS export_jobs;
@@ -0,0 +1,15 @@
+-- archive holds the finished zip archive, base64-encoded (and encrypted
+-- like the other sensitive text when encryption is configured).
+CREATE TABLE export_jobs (
+    id SERIAL PRIMARY KEY,
+    user_id VARCHAR(36) NOT NULL,
+    status VARCHAR(10) NOT NULL,
+    error TEXT NULL,
+    archive TEXT NULL,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    started_at TIMESTAMPTZ NULL,
+    completed_at TIMESTAMPTZ NULL,
+    expires_at TIMESTAMPTZ NULL
+);
+CREATE INDEX idx_user_id_export_job ON export_jobs (user_id);
+CREATE INDEX idx_status_export_job ON export_jobs (status);
@@ -0,0 +1 @@
+ALTER TABLE badges DROP COLUMN rule;
@@ -0,0 +1,2 @@
+-- This migration will drop the action_completions table if it exists
+DROP TABLE IF EXISTS action_completions;
@@ -0,0 +1,14 @@
+CREATE TABLE action_completions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    action_id INT NOT NULL,
+    user_id VARCHAR(36) NOT NULL,
+    occurrence_date DATE NOT NULL,
+    status VARCHAR(10) NOT NULL,
+    completed_at DATETIME NULL,
+    note TEXT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uq_action_occurrence UNIQUE (action_id, occurrence_date),
+    FOREIGN KEY (action_id) REFERENCES actions(id) ON DELETE CASCADE
+);
+CREATE INDEX idx_user_id_action_completion ON action_completions (user_id);
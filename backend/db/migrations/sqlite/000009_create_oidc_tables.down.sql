@@ -0,0 +1,3 @@
+-- This migration will drop the OIDC tables if they exist
+DROP TABLE IF EXISTS oidc_login_states;
+DROP TABLE IF EXISTS user_identities;
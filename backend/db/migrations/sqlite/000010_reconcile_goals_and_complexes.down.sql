@@ -0,0 +1,11 @@
+-- This migration will drop the gains and losses tables and split goal content back
+-- into surface_goal. The original underlying_goal cannot be recovered and is left empty.
+DROP TABLE IF EXISTS losses;
+DROP TABLE IF EXISTS gains;
+
+ALTER TABLE goals ADD COLUMN surface_goal VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE goals ADD COLUMN underlying_goal VARCHAR(255) NOT NULL DEFAULT '';
+UPDATE goals SET surface_goal = substr(content, 1, 255);
+ALTER TABLE goals DROP COLUMN content;
+
+ALTER TABLE complexes DROP COLUMN trigger_episode;
@@ -0,0 +1,30 @@
+ALTER TABLE complexes ADD COLUMN trigger_episode TEXT NULL;
+
+-- Goals used to be split into a surface goal and the underlying goal behind it.
+-- They are merged into a single content column, keeping both parts when they differ.
+-- SQLite cannot add a NOT NULL column without a default, so the empty default stays.
+ALTER TABLE goals ADD COLUMN content TEXT NOT NULL DEFAULT '';
+UPDATE goals SET content = CASE
+    WHEN underlying_goal = '' OR underlying_goal = surface_goal THEN surface_goal
+    ELSE surface_goal || ' / ' || underlying_goal
+END;
+ALTER TABLE goals DROP COLUMN surface_goal;
+ALTER TABLE goals DROP COLUMN underlying_goal;
+
+CREATE TABLE gains (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    action_id INT NOT NULL,
+    type VARCHAR(20) NOT NULL,
+    description TEXT NOT NULL,
+    FOREIGN KEY (action_id) REFERENCES actions(id) ON DELETE CASCADE
+);
+CREATE INDEX idx_action_id_gain ON gains (action_id);
+
+CREATE TABLE losses (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    action_id INT NOT NULL,
+    type VARCHAR(20) NOT NULL,
+    description TEXT NOT NULL,
+    FOREIGN KEY (action_id) REFERENCES actions(id) ON DELETE CASCADE
+);
+CREATE INDEX idx_action_id_loss ON losses (action_id);
@@ -0,0 +1,5 @@
+-- This migration will drop the value and unit columns from gains and losses
+ALTER TABLE gains DROP COLUMN unit;
+ALTER TABLE gains DROP COLUMN value;
+ALTER TABLE losses DROP COLUMN unit;
+ALTER TABLE losses DROP COLUMN value;
@@ -0,0 +1,4 @@
+ALTER TABLE gains ADD COLUMN value REAL NULL;
+ALTER TABLE gains ADD COLUMN unit VARCHAR(20) NULL;
+ALTER TABLE losses ADD COLUMN value REAL NULL;
+ALTER TABLE losses ADD COLUMN unit VARCHAR(20) NULL;
@@ -0,0 +1,18 @@
+ALTER TABLE goals ADD COLUMN metric_unit VARCHAR(20) NULL;
+ALTER TABLE goals ADD COLUMN metric_baseline REAL NULL;
+ALTER TABLE goals ADD COLUMN metric_target REAL NULL;
+ALTER TABLE goals ADD COLUMN metric_direction VARCHAR(10) NULL;
+ALTER TABLE goals ADD COLUMN metric_deadline DATE NULL;
+
+CREATE TABLE goal_measurements (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    goal_id INT NOT NULL,
+    user_id VARCHAR(36) NOT NULL,
+    value REAL NOT NULL,
+    measured_at DATETIME NOT NULL,
+    note TEXT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (goal_id) REFERENCES goals(id) ON DELETE CASCADE
+);
+CREATE INDEX idx_goal_id_measured_at ON goal_measurements (goal_id, measured_at);
+CREATE INDEX idx_user_id_goal_measurement ON goal_measurements (user_id);
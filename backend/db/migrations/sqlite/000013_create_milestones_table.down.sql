@@ -0,0 +1,16 @@
+-- This migration will drop the milestones table and the milestone badges users hold,
+-- then restore the one-badge-per-user unique key on user_badges
+CREATE TABLE user_badges_old (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id VARCHAR(36) NOT NULL,
+    badge_id INT NOT NULL,
+    achieved_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uq_user_badge UNIQUE (user_id, badge_id),
+    FOREIGN KEY (badge_id) REFERENCES badges(id) ON DELETE CASCADE
+);
+INSERT INTO user_badges_old (id, user_id, badge_id, achieved_at)
+    SELECT id, user_id, badge_id, achieved_at FROM user_badges WHERE milestone_id = 0;
+DROP TABLE user_badges;
+ALTER TABLE user_badges_old RENAME TO user_badges;
+CREATE INDEX idx_user_id_user_badge ON user_badges (user_id);
+DROP TABLE IF EXISTS milestones;
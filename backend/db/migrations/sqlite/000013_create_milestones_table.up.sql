@@ -0,0 +1,33 @@
+CREATE TABLE milestones (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    goal_id INT NOT NULL,
+    user_id VARCHAR(36) NOT NULL,
+    position INT NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    basis VARCHAR(20) NOT NULL,
+    threshold REAL NOT NULL,
+    achieved_at DATETIME NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (goal_id) REFERENCES goals(id) ON DELETE CASCADE
+);
+CREATE INDEX idx_goal_id_milestone ON milestones (goal_id);
+CREATE INDEX idx_user_id_milestone ON milestones (user_id);
+
+-- A milestone badge is held once per milestone, so the milestone joins the
+-- unique key. Badges awarded by their rule keep milestone_id 0. SQLite cannot
+-- alter a table constraint, so user_badges is rebuilt.
+CREATE TABLE user_badges_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id VARCHAR(36) NOT NULL,
+    badge_id INT NOT NULL,
+    milestone_id INT NOT NULL DEFAULT 0,
+    achieved_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uq_user_badge UNIQUE (user_id, badge_id, milestone_id),
+    FOREIGN KEY (badge_id) REFERENCES badges(id) ON DELETE CASCADE
+);
+INSERT INTO user_badges_new (id, user_id, badge_id, achieved_at)
+    SELECT id, user_id, badge_id, achieved_at FROM user_badges;
+DROP TABLE user_badges;
+ALTER TABLE user_badges_new RENAME TO user_badges;
+CREATE INDEX idx_user_id_user_badge ON user_badges (user_id);
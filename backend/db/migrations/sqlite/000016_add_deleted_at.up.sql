@@ -0,0 +1,7 @@
+ALTER TABLE complexes ADD COLUMN deleted_at DATETIME NULL;
+ALTER TABLE goals ADD COLUMN deleted_at DATETIME NULL;
+ALTER TABLE actions ADD COLUMN deleted_at DATETIME NULL;
+
+CREATE INDEX idx_deleted_at_complex ON complexes (deleted_at);
+CREATE INDEX idx_deleted_at_goal ON goals (deleted_at);
+CREATE INDEX idx_deleted_at_action ON actions (deleted_at);
@@ -0,0 +1,15 @@
+-- archive holds the finished zip archive, base64-encoded (and encrypted
+-- like the other sensitive text when encryption is configured).
+CREATE TABLE export_jobs (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id VARCHAR(36) NOT NULL,
+    status VARCHAR(10) NOT NULL,
+    error TEXT NULL,
+    archive TEXT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    started_at DATETIME NULL,
+    completed_at DATETIME NULL,
+    expires_at DATETIME NULL
+);
+CREATE INDEX idx_user_id_export_job ON export_jobs (user_id);
+CREATE INDEX idx_status_export_job ON export_jobs (status);
@@ -0,0 +1,300 @@
+// Package envelope encrypts the sensitive text of users' records at rest
+// with envelope encryption. Each user's fields are encrypted with a data
+// key of their own, stored wrapped (encrypted) by a master key from the
+// configuration. Rotating the master key re-wraps the data keys only: the
+// encrypted fields stay as they are.
+//
+// Fields and data keys are sealed with AES-256-GCM, bound to the user's ID
+// as additional data so a value copied to another user fails to decrypt.
+package envelope
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+
+	"gorm.io/gorm"
+
+	"refuel/backend/models"
+	"refuel/backend/repository"
+)
+
+// keySize is the size of master and data keys, in bytes: AES-256.
+const keySize = 32
+
+var keyIDPattern = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// Keyring holds the master keys by ID. The active one wraps new data keys;
+// the others only unwrap the data keys still wrapped by them.
+type Keyring struct {
+	active string
+	keys   map[string][]byte
+}
+
+// ConfigFromEnv reads the master keys from ENCRYPTION_MASTER_KEYS, a
+// comma-separated list of id:key pairs where each key is 32 bytes encoded
+// in base64 and the first is active. It returns nil when the variable is
+// unset, leaving encryption off.
+func ConfigFromEnv() (*Keyring, error) {
+	v := os.Getenv("ENCRYPTION_MASTER_KEYS")
+	if v == "" {
+		return nil, nil
+	}
+	keyring, err := ParseKeyring(v)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ENCRYPTION_MASTER_KEYS: %v", err)
+	}
+	return keyring, nil
+}
+
+// ParseKeyring parses a keyring in the format of ENCRYPTION_MASTER_KEYS.
+func ParseKeyring(s string) (*Keyring, error) {
+	k := &Keyring{keys: map[string][]byte{}}
+	for _, entry := range strings.Split(s, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		id, encoded, ok := strings.Cut(entry, ":")
+		if !ok || !keyIDPattern.MatchString(id) {
+			return nil, fmt.Errorf("entries must be id:key with an ID of letters, digits, '.', '_' or '-'")
+		}
+		if _, dup := k.keys[id]; dup {
+			return nil, fmt.Errorf("master key %q is listed twice", id)
+		}
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil || len(key) != keySize {
+			return nil, fmt.Errorf("master key %q must be %d bytes encoded in base64", id, keySize)
+		}
+		if k.active == "" {
+			k.active = id
+		}
+		k.keys[id] = key
+	}
+	if k.active == "" {
+		return nil, errors.New("no master keys")
+	}
+	return k, nil
+}
+
+// ActiveID returns the ID of the master key new data keys are wrapped by.
+func (k *Keyring) ActiveID() string {
+	return k.active
+}
+
+// wrap encrypts the user's data key with the active master key.
+func (k *Keyring) wrap(userID string, dataKey []byte) (models.UserDataKey, error) {
+	sealed, err := seal(k.keys[k.active], userID, dataKey)
+	if err != nil {
+		return models.UserDataKey{}, err
+	}
+	return models.UserDataKey{UserID: userID, MasterKeyID: k.active, WrappedKey: base64.StdEncoding.EncodeToString(sealed)}, nil
+}
+
+// unwrap decrypts a stored data key with the master key it names.
+func (k *Keyring) unwrap(key *models.UserDataKey) ([]byte, error) {
+	masterKey, ok := k.keys[key.MasterKeyID]
+	if !ok {
+		return nil, fmt.Errorf("data key of user %s is wrapped by master key %q, which is not configured", key.UserID, key.MasterKeyID)
+	}
+	sealed, err := base64.StdEncoding.DecodeString(key.WrappedKey)
+	if err != nil {
+		return nil, fmt.Errorf("data key of user %s is malformed: %v", key.UserID, err)
+	}
+	dataKey, err := open(masterKey, key.UserID, sealed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unwrap the data key of user %s: %v", key.UserID, err)
+	}
+	return dataKey, nil
+}
+
+// Cipher encrypts fields with the users' data keys. It implements
+// models.FieldCipher. Unwrapped data keys are cached for the life of the
+// process, which is safe because rotation re-wraps them but never changes
+// them.
+type Cipher struct {
+	keyring *Keyring
+
+	mu       sync.RWMutex
+	dataKeys map[string][]byte
+}
+
+// NewCipher returns a cipher unwrapping data keys with keyring.
+func NewCipher(keyring *Keyring) *Cipher {
+	return &Cipher{keyring: keyring, dataKeys: map[string][]byte{}}
+}
+
+// Encrypt seals plaintext with the user's data key, creating the key on
+// the user's first encrypted write. The result is
+// EncryptedPrefix + userID + ":" + base64(nonce | ciphertext).
+func (c *Cipher) Encrypt(db *gorm.DB, userID, plaintext string) (string, error) {
+	if userID == "" || strings.Contains(userID, ":") {
+		return "", fmt.Errorf("cannot encrypt for user ID %q", userID)
+	}
+	dataKey, err := c.dataKey(db, userID, true)
+	if err != nil {
+		return "", err
+	}
+	sealed, err := seal(dataKey, userID, []byte(plaintext))
+	if err != nil {
+		return "", err
+	}
+	return models.EncryptedPrefix + userID + ":" + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt opens a value returned by Encrypt.
+func (c *Cipher) Decrypt(db *gorm.DB, ciphertext string) (string, error) {
+	userID, encoded, ok := strings.Cut(strings.TrimPrefix(ciphertext, models.EncryptedPrefix), ":")
+	if !ok {
+		return "", errors.New("malformed encrypted field")
+	}
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", fmt.Errorf("malformed encrypted field: %v", err)
+	}
+	dataKey, err := c.dataKey(db, userID, false)
+	if err != nil {
+		return "", err
+	}
+	plaintext, err := open(dataKey, userID, sealed)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt a field of user %s: %v", userID, err)
+	}
+	return string(plaintext), nil
+}
+
+// dataKey returns the user's unwrapped data key, loading it in db's
+// session on a cache miss. With create, a user without a data key is given
+// one; two writers racing to create it end up sharing the one stored first.
+func (c *Cipher) dataKey(db *gorm.DB, userID string, create bool) ([]byte, error) {
+	c.mu.RLock()
+	dataKey, ok := c.dataKeys[userID]
+	c.mu.RUnlock()
+	if ok {
+		return dataKey, nil
+	}
+
+	ctx := db.Statement.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	// Start a statement of its own: db belongs to the record's statement and
+	// still names the record's table.
+	keys := repository.NewGormDataKeys(db.Session(&gorm.Session{NewDB: true}).Model(&models.UserDataKey{}))
+	stored, err := keys.Get(ctx, userID)
+	if err == repository.ErrNotFound && create {
+		stored, err = c.createDataKey(ctx, keys, userID)
+	}
+	if err == repository.ErrNotFound {
+		return nil, fmt.Errorf("user %s has no data key", userID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if dataKey, err = c.keyring.unwrap(stored); err != nil {
+		return nil, err
+	}
+
+	// A key read in a transaction may have been created in it and vanish
+	// on rollback, so only keys read outside one are cached.
+	if _, inTx := db.Statement.ConnPool.(gorm.TxCommitter); !inTx {
+		c.mu.Lock()
+		c.dataKeys[userID] = dataKey
+		c.mu.Unlock()
+	}
+	return dataKey, nil
+}
+
+func (c *Cipher) createDataKey(ctx context.Context, keys repository.DataKeyRepository, userID string) (*models.UserDataKey, error) {
+	dataKey := make([]byte, keySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return nil, err
+	}
+	wrapped, err := c.keyring.wrap(userID, dataKey)
+	if err != nil {
+		return nil, err
+	}
+	switch err := keys.Create(ctx, &wrapped); err {
+	case nil:
+		return &wrapped, nil
+	case repository.ErrConflict:
+		return keys.Get(ctx, userID)
+	default:
+		return nil, err
+	}
+}
+
+// Rotate re-wraps every data key not wrapped by the keyring's active master
+// key with it, and returns how many it re-wrapped. The keyring must still
+// hold the master keys being retired. Servers keep working throughout:
+// each data key is replaced in a single write, is unchanged by it, and
+// stays readable with either master key as long as both are configured.
+func Rotate(ctx context.Context, keys repository.DataKeyRepository, keyring *Keyring) (int, error) {
+	stale, err := keys.ListNotWrappedBy(ctx, keyring.ActiveID())
+	if err != nil {
+		return 0, err
+	}
+	rewrapped := 0
+	for i := range stale {
+		dataKey, err := keyring.unwrap(&stale[i])
+		if err != nil {
+			return rewrapped, err
+		}
+		wrapped, err := keyring.wrap(stale[i].UserID, dataKey)
+		if err != nil {
+			return rewrapped, err
+		}
+		switch err := keys.Rewrap(ctx, &wrapped, stale[i].MasterKeyID); err {
+		case nil:
+			rewrapped++
+		case repository.ErrNotFound:
+			// Re-wrapped by a concurrent rotation.
+		default:
+			return rewrapped, err
+		}
+	}
+	return rewrapped, nil
+}
+
+// seal encrypts plaintext with key, bound to userID, and returns the nonce
+// followed by the ciphertext.
+func seal(key []byte, userID string, plaintext []byte) ([]byte, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, plaintext, []byte(userID)), nil
+}
+
+// open decrypts what seal returned.
+func open(key []byte, userID string, sealed []byte) ([]byte, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < aead.NonceSize() {
+		return nil, errors.New("ciphertext too short")
+	}
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	return aead.Open(nil, nonce, ciphertext, []byte(userID))
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,250 @@
+package envelope
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"refuel/backend/database"
+	"refuel/backend/models"
+	"refuel/backend/repository"
+)
+
+// testKey returns a base64 master key made of one repeated byte.
+func testKey(b byte) string {
+	return base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{b}, keySize))
+}
+
+func TestParseKeyring(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       string
+		active  string
+		wantErr string
+	}{
+		{"one key", "k1:" + testKey(1), "k1", ""},
+		{"first is active", " new:" + testKey(2) + " , old:" + testKey(1) + ",", "new", ""},
+		{"empty", " , ", "", "no master keys"},
+		{"missing ID", testKey(1), "", "id:key"},
+		{"bad ID", "k 1:" + testKey(1), "", "id:key"},
+		{"duplicate", "k1:" + testKey(1) + ",k1:" + testKey(2), "", "listed twice"},
+		{"not base64", "k1:not-base64!", "", "32 bytes"},
+		{"short key", "k1:" + base64.StdEncoding.EncodeToString([]byte("short")), "", "32 bytes"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k, err := ParseKeyring(tt.s)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("got %v, want an error containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseKeyring: %v", err)
+			}
+			if k.ActiveID() != tt.active {
+				t.Errorf("got active key %q, want %q", k.ActiveID(), tt.active)
+			}
+		})
+	}
+}
+
+func TestSealOpen(t *testing.T) {
+	key := bytes.Repeat([]byte{7}, keySize)
+	sealed, err := seal(key, "u1", []byte("秘密"))
+	if err != nil {
+		t.Fatalf("seal: %v", err)
+	}
+	tampered := bytes.Clone(sealed)
+	tampered[len(tampered)-1] ^= 1
+
+	tests := []struct {
+		name   string
+		key    []byte
+		userID string
+		sealed []byte
+		ok     bool
+	}{
+		{"round trip", key, "u1", sealed, true},
+		{"other user", key, "u2", sealed, false},
+		{"other key", bytes.Repeat([]byte{8}, keySize), "u1", sealed, false},
+		{"tampered", key, "u1", tampered, false},
+		{"too short", key, "u1", sealed[:4], false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plaintext, err := open(tt.key, tt.userID, tt.sealed)
+			if !tt.ok {
+				if err == nil {
+					t.Fatal("got no error, want one")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("open: %v", err)
+			}
+			if string(plaintext) != "秘密" {
+				t.Errorf("got %q, want %q", plaintext, "秘密")
+			}
+		})
+	}
+}
+
+// openDB returns a migrated SQLite database in a temporary directory.
+func openDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	cfg := database.Config{Driver: database.SQLite, Path: filepath.Join(t.TempDir(), "test.db")}
+	if err := database.Migrate(cfg, "file://../db/migrations"); err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+	db, err := database.Open(cfg, &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	return db
+}
+
+func TestCipher(t *testing.T) {
+	db := openDB(t)
+	keyring, err := ParseKeyring("k1:" + testKey(1))
+	if err != nil {
+		t.Fatalf("ParseKeyring: %v", err)
+	}
+	c := NewCipher(keyring)
+
+	u1, err := c.Encrypt(db, "u1", "人前で話すのが怖い")
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+	if !strings.HasPrefix(u1, models.EncryptedPrefix+"u1:") {
+		t.Fatalf("got %q, want the prefix %q", u1, models.EncryptedPrefix+"u1:")
+	}
+	if _, err := c.Encrypt(db, "u2", "x"); err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+	again, err := c.Encrypt(db, "u1", "人前で話すのが怖い")
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+	if again == u1 {
+		t.Error("got the same ciphertext twice, want a fresh nonce each time")
+	}
+
+	tests := []struct {
+		name       string
+		cipher     *Cipher
+		ciphertext string
+		want       string
+		wantErr    bool
+	}{
+		{"same cipher", c, u1, "人前で話すのが怖い", false},
+		// A new cipher has no cached keys and unwraps the stored one.
+		{"new cipher", NewCipher(keyring), u1, "人前で話すのが怖い", false},
+		{"copied to another user", c, strings.Replace(u1, ":u1:", ":u2:", 1), "", true},
+		{"user without a data key", c, strings.Replace(u1, ":u1:", ":u3:", 1), "", true},
+		{"malformed", c, models.EncryptedPrefix + "u1", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.cipher.Decrypt(db, tt.ciphertext)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("got %q, want an error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Decrypt: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	var keys []models.UserDataKey
+	if err := db.Order("user_id").Find(&keys).Error; err != nil {
+		t.Fatalf("listing data keys: %v", err)
+	}
+	if len(keys) != 2 || keys[0].UserID != "u1" || keys[1].UserID != "u2" {
+		t.Errorf("got data keys %+v, want one for each of u1 and u2", keys)
+	}
+
+	for _, userID := range []string{"", "a:b"} {
+		if _, err := c.Encrypt(db, userID, "x"); err == nil {
+			t.Errorf("Encrypt for user ID %q: got no error, want one", userID)
+		}
+	}
+}
+
+func TestRotate(t *testing.T) {
+	ctx := context.Background()
+	keys := repository.NewMemory().DataKeys
+	old, err := ParseKeyring("old:" + testKey(1))
+	if err != nil {
+		t.Fatalf("ParseKeyring: %v", err)
+	}
+	dataKeys := map[string][]byte{}
+	for i, userID := range []string{"u1", "u2"} {
+		dataKeys[userID] = bytes.Repeat([]byte{byte(10 + i)}, keySize)
+		wrapped, err := old.wrap(userID, dataKeys[userID])
+		if err != nil {
+			t.Fatalf("wrap: %v", err)
+		}
+		if err := keys.Create(ctx, &wrapped); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+	}
+
+	both, err := ParseKeyring("new:" + testKey(2) + ",old:" + testKey(1))
+	if err != nil {
+		t.Fatalf("ParseKeyring: %v", err)
+	}
+	tests := []struct {
+		name string
+		want int
+	}{
+		{"re-wraps the old keys", 2},
+		{"leaves the new keys", 0},
+	}
+	for _, tt := range tests {
+		n, err := Rotate(ctx, keys, both)
+		if err != nil {
+			t.Fatalf("%s: Rotate: %v", tt.name, err)
+		}
+		if n != tt.want {
+			t.Errorf("%s: got %d re-wrapped, want %d", tt.name, n, tt.want)
+		}
+	}
+
+	// The old master key can now be removed.
+	newOnly, err := ParseKeyring("new:" + testKey(2))
+	if err != nil {
+		t.Fatalf("ParseKeyring: %v", err)
+	}
+	for userID, want := range dataKeys {
+		stored, err := keys.Get(ctx, userID)
+		if err != nil {
+			t.Fatalf("Get: %v", err)
+		}
+		got, err := newOnly.unwrap(stored)
+		if err != nil {
+			t.Fatalf("unwrap: %v", err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("user %s: the data key changed in rotation", userID)
+		}
+	}
+}
@@ -0,0 +1,545 @@
+// Package export builds the archive of everything a user owns: complexes,
+// goals, measurements, milestones, actions, check-ins, gains, losses,
+// badges and revision history. Each kind of record is written as JSON, the
+// form the archive is re-imported from, and as CSV for reading in a
+// spreadsheet. A manifest records the format and schema versions and the
+// checksum of every file. Archives are built in the background by a Worker.
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"refuel/backend/database"
+	"refuel/backend/recurrence"
+	"refuel/backend/repository"
+)
+
+// Format identifies an export archive in its manifest.
+const Format = "refuel-export"
+
+// FormatVersion is the version of the archive's layout and record fields.
+// Bump it on any change an older importer would misread.
+const FormatVersion = 1
+
+// ManifestName is the name of the manifest in the archive.
+const ManifestName = "manifest.json"
+
+// utf8BOM starts every CSV file so that spreadsheet applications read the
+// Japanese text in it as UTF-8.
+const utf8BOM = "\ufeff"
+
+// Manifest describes an archive and the files in it.
+type Manifest struct {
+	Format        string `json:"format"`
+	FormatVersion int    `json:"format_version"`
+	// SchemaVersion is the database migration version the data was
+	// exported from.
+	SchemaVersion uint      `json:"schema_version"`
+	UserID        string    `json:"user_id"`
+	ExportedAt    time.Time `json:"exported_at"`
+	Files         []File    `json:"files"`
+}
+
+// File is a file of the archive listed in the manifest.
+type File struct {
+	Name   string `json:"name"`
+	Entity string `json:"entity"`
+	// Format is "json" or "csv".
+	Format  string `json:"format"`
+	Records int    `json:"records"`
+	Size    int    `json:"size"`
+	SHA256  string `json:"sha256"`
+}
+
+// Complex is an exported complex.
+type Complex struct {
+	ID             uint       `json:"id"`
+	Content        string     `json:"content"`
+	TriggerEpisode string     `json:"trigger_episode,omitempty"`
+	Category       string     `json:"category"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+	DeletedAt      *time.Time `json:"deleted_at,omitempty"`
+}
+
+// Goal is an exported goal. MetricDeadline is a YYYY-MM-DD date.
+type Goal struct {
+	ID              uint       `json:"id"`
+	ComplexID       uint       `json:"complex_id"`
+	Content         string     `json:"content"`
+	MetricUnit      string     `json:"metric_unit,omitempty"`
+	MetricBaseline  *float64   `json:"metric_baseline,omitempty"`
+	MetricTarget    *float64   `json:"metric_target,omitempty"`
+	MetricDirection string     `json:"metric_direction,omitempty"`
+	MetricDeadline  string     `json:"metric_deadline,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+	DeletedAt       *time.Time `json:"deleted_at,omitempty"`
+}
+
+// Measurement is an exported goal measurement.
+type Measurement struct {
+	ID         uint      `json:"id"`
+	GoalID     uint      `json:"goal_id"`
+	Value      float64   `json:"value"`
+	MeasuredAt time.Time `json:"measured_at"`
+	Note       string    `json:"note,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// Milestone is an exported goal milestone.
+type Milestone struct {
+	ID         uint       `json:"id"`
+	GoalID     uint       `json:"goal_id"`
+	Position   int        `json:"position"`
+	Title      string     `json:"title"`
+	Basis      string     `json:"basis"`
+	Threshold  float64    `json:"threshold"`
+	AchievedAt *time.Time `json:"achieved_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// Action is an exported action.
+type Action struct {
+	ID                uint                `json:"id"`
+	GoalID            uint                `json:"goal_id"`
+	Content           string              `json:"content"`
+	CompletedAt       *time.Time          `json:"completed_at,omitempty"`
+	RecurrencePattern *recurrence.Pattern `json:"recurrence_pattern,omitempty"`
+	CreatedAt         time.Time           `json:"created_at"`
+	UpdatedAt         time.Time           `json:"updated_at"`
+	DeletedAt         *time.Time          `json:"deleted_at,omitempty"`
+}
+
+// Outcome is an exported gain or loss.
+type Outcome struct {
+	ID          uint     `json:"id"`
+	ActionID    uint     `json:"action_id"`
+	Type        string   `json:"type"`
+	Description string   `json:"description"`
+	Value       *float64 `json:"value,omitempty"`
+	Unit        string   `json:"unit,omitempty"`
+}
+
+// Completion is an exported check-in. OccurrenceDate is a YYYY-MM-DD date.
+type Completion struct {
+	ID             uint       `json:"id"`
+	ActionID       uint       `json:"action_id"`
+	OccurrenceDate string     `json:"occurrence_date"`
+	Status         string     `json:"status"`
+	CompletedAt    *time.Time `json:"completed_at,omitempty"`
+	Note           string     `json:"note,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// Badge is an exported badge award, with the badge it awards.
+type Badge struct {
+	Code        string    `json:"code"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	MilestoneID uint      `json:"milestone_id,omitempty"`
+	AchievedAt  time.Time `json:"achieved_at"`
+}
+
+// Revision is an exported revision. Before and After are the JSON
+// snapshots of the record.
+type Revision struct {
+	ID         uint            `json:"id"`
+	EntityType string          `json:"entity_type"`
+	EntityID   uint            `json:"entity_id"`
+	Action     string          `json:"action"`
+	Actor      string          `json:"actor"`
+	Before     json.RawMessage `json:"before,omitempty"`
+	After      json.RawMessage `json:"after,omitempty"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+// Data is the content of an archive. Its JSON form holds the records of
+// each entity under the entity's name.
+type Data struct {
+	Complexes    []Complex     `json:"complexes"`
+	Goals        []Goal        `json:"goals"`
+	Measurements []Measurement `json:"measurements"`
+	Milestones   []Milestone   `json:"milestones"`
+	Actions      []Action      `json:"actions"`
+	Completions  []Completion  `json:"completions"`
+	Gains        []Outcome     `json:"gains"`
+	Losses       []Outcome     `json:"losses"`
+	Badges       []Badge       `json:"badges"`
+	Revisions    []Revision    `json:"revisions"`
+}
+
+// FromUserData converts the stored records of a user to their exported form.
+func FromUserData(u *repository.UserData) *Data {
+	d := &Data{
+		Complexes:    make([]Complex, len(u.Complexes)),
+		Goals:        make([]Goal, len(u.Goals)),
+		Measurements: make([]Measurement, len(u.Measurements)),
+		Milestones:   make([]Milestone, len(u.Milestones)),
+		Actions:      make([]Action, len(u.Actions)),
+		Completions:  make([]Completion, len(u.Completions)),
+		Gains:        make([]Outcome, len(u.Gains)),
+		Losses:       make([]Outcome, len(u.Losses)),
+		Badges:       make([]Badge, len(u.Badges)),
+		Revisions:    make([]Revision, len(u.Revisions)),
+	}
+	for i, c := range u.Complexes {
+		d.Complexes[i] = Complex{
+			ID: c.ID, Content: c.Content, TriggerEpisode: c.TriggerEpisode, Category: c.Category,
+			CreatedAt: c.CreatedAt, UpdatedAt: c.UpdatedAt, DeletedAt: deletedAt(c.DeletedAt.Valid, c.DeletedAt.Time),
+		}
+	}
+	for i, g := range u.Goals {
+		d.Goals[i] = Goal{
+			ID: g.ID, ComplexID: g.ComplexID, Content: g.Content,
+			MetricUnit: g.MetricUnit, MetricBaseline: g.MetricBaseline, MetricTarget: g.MetricTarget, MetricDirection: g.MetricDirection,
+			CreatedAt: g.CreatedAt, UpdatedAt: g.UpdatedAt, DeletedAt: deletedAt(g.DeletedAt.Valid, g.DeletedAt.Time),
+		}
+		if g.MetricDeadline != nil {
+			d.Goals[i].MetricDeadline = g.MetricDeadline.Format(time.DateOnly)
+		}
+	}
+	for i, m := range u.Measurements {
+		d.Measurements[i] = Measurement{ID: m.ID, GoalID: m.GoalID, Value: m.Value, MeasuredAt: m.MeasuredAt, Note: m.Note, CreatedAt: m.CreatedAt}
+	}
+	for i, m := range u.Milestones {
+		d.Milestones[i] = Milestone{
+			ID: m.ID, GoalID: m.GoalID, Position: m.Position, Title: m.Title, Basis: m.Basis, Threshold: m.Threshold,
+			AchievedAt: m.AchievedAt, CreatedAt: m.CreatedAt, UpdatedAt: m.UpdatedAt,
+		}
+	}
+	for i, a := range u.Actions {
+		d.Actions[i] = Action{
+			ID: a.ID, GoalID: a.GoalID, Content: a.Content, CompletedAt: a.CompletedAt, RecurrencePattern: a.RecurrencePattern,
+			CreatedAt: a.CreatedAt, UpdatedAt: a.UpdatedAt, DeletedAt: deletedAt(a.DeletedAt.Valid, a.DeletedAt.Time),
+		}
+	}
+	for i, c := range u.Completions {
+		d.Completions[i] = Completion{
+			ID: c.ID, ActionID: c.ActionID, OccurrenceDate: c.OccurrenceDate.Format(time.DateOnly), Status: c.Status,
+			CompletedAt: c.CompletedAt, Note: c.Note, CreatedAt: c.CreatedAt, UpdatedAt: c.UpdatedAt,
+		}
+	}
+	for i, g := range u.Gains {
+		d.Gains[i] = Outcome{ID: g.ID, ActionID: g.ActionID, Type: g.Type, Description: g.Description, Value: g.Value, Unit: g.Unit}
+	}
+	for i, l := range u.Losses {
+		d.Losses[i] = Outcome{ID: l.ID, ActionID: l.ActionID, Type: l.Type, Description: l.Description, Value: l.Value, Unit: l.Unit}
+	}
+	for i, ub := range u.Badges {
+		d.Badges[i] = Badge{Code: ub.Badge.Code, Name: ub.Badge.Name, Description: ub.Badge.Description, MilestoneID: ub.MilestoneID, AchievedAt: ub.AchievedAt}
+	}
+	for i, r := range u.Revisions {
+		d.Revisions[i] = Revision{
+			ID: r.ID, EntityType: r.EntityType, EntityID: r.EntityID, Action: r.Action, Actor: r.Actor,
+			Before: rawJSON(r.Before), After: rawJSON(r.After), CreatedAt: r.CreatedAt,
+		}
+	}
+	return d
+}
+
+// Build writes the archive of a user's data, exported at the given time.
+func Build(userID string, d *Data, exportedAt time.Time) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	manifest := Manifest{
+		Format:        Format,
+		FormatVersion: FormatVersion,
+		SchemaVersion: database.SchemaVersion,
+		UserID:        userID,
+		ExportedAt:    exportedAt.UTC(),
+	}
+	add := func(name string, content []byte) error {
+		w, err := zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: exportedAt})
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(content)
+		return err
+	}
+
+	for _, e := range d.entities() {
+		jsonContent, err := json.MarshalIndent(e.records, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		var csvContent bytes.Buffer
+		csvContent.WriteString(utf8BOM)
+		cw := csv.NewWriter(&csvContent)
+		if err := cw.Write(e.header); err != nil {
+			return nil, err
+		}
+		if err := cw.WriteAll(e.rows); err != nil {
+			return nil, err
+		}
+		for _, f := range []struct {
+			format  string
+			content []byte
+		}{{"json", jsonContent}, {"csv", csvContent.Bytes()}} {
+			name := e.name + "." + f.format
+			if err := add(name, f.content); err != nil {
+				return nil, err
+			}
+			sum := sha256.Sum256(f.content)
+			manifest.Files = append(manifest.Files, File{
+				Name: name, Entity: e.name, Format: f.format, Records: len(e.rows),
+				Size: len(f.content), SHA256: hex.EncodeToString(sum[:]),
+			})
+		}
+	}
+
+	manifestContent, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := add(ManifestName, manifestContent); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// MaxFileSize bounds the uncompressed size of each file Read reads.
+const MaxFileSize = 64 << 20
+
+// Read reads an archive written by Build. It checks the manifest's format
+// version and the size and checksum of every file it lists, and returns
+// the manifest and the data of the JSON files.
+func Read(archive []byte) (*Manifest, *Data, error) {
+	zr, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	if err != nil {
+		return nil, nil, fmt.Errorf("not a zip archive: %v", err)
+	}
+	files := make(map[string]*zip.File, len(zr.File))
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+	read := func(name string) ([]byte, error) {
+		f, ok := files[name]
+		if !ok {
+			return nil, fmt.Errorf("%s is missing", name)
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", name, err)
+		}
+		defer rc.Close()
+		content, err := io.ReadAll(io.LimitReader(rc, MaxFileSize+1))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", name, err)
+		}
+		if len(content) > MaxFileSize {
+			return nil, fmt.Errorf("%s is larger than %d bytes", name, MaxFileSize)
+		}
+		return content, nil
+	}
+
+	manifestContent, err := read(ManifestName)
+	if err != nil {
+		return nil, nil, err
+	}
+	var manifest Manifest
+	if err := json.Unmarshal(manifestContent, &manifest); err != nil {
+		return nil, nil, fmt.Errorf("%s: %v", ManifestName, err)
+	}
+	if manifest.Format != Format {
+		return nil, nil, fmt.Errorf("%s: format is %q, not %q", ManifestName, manifest.Format, Format)
+	}
+	if manifest.FormatVersion < 1 || manifest.FormatVersion > FormatVersion {
+		return nil, nil, fmt.Errorf("%s: format version %d is not supported (up to %d)", ManifestName, manifest.FormatVersion, FormatVersion)
+	}
+
+	d := &Data{}
+	records := d.recordsByEntity()
+	for _, f := range manifest.Files {
+		content, err := read(f.Name)
+		if err != nil {
+			return nil, nil, err
+		}
+		sum := sha256.Sum256(content)
+		if len(content) != f.Size || hex.EncodeToString(sum[:]) != f.SHA256 {
+			return nil, nil, fmt.Errorf("%s does not match the checksum in the manifest", f.Name)
+		}
+		target, ok := records[f.Entity]
+		if f.Format != "json" || !ok {
+			continue
+		}
+		if err := json.Unmarshal(content, target); err != nil {
+			return nil, nil, fmt.Errorf("%s: %v", f.Name, err)
+		}
+	}
+	return &manifest, d, nil
+}
+
+// recordsByEntity returns pointers to the record slices by entity name.
+func (d *Data) recordsByEntity() map[string]interface{} {
+	return map[string]interface{}{
+		EntityComplexes:    &d.Complexes,
+		EntityGoals:        &d.Goals,
+		EntityMeasurements: &d.Measurements,
+		EntityMilestones:   &d.Milestones,
+		EntityActions:      &d.Actions,
+		EntityCompletions:  &d.Completions,
+		EntityGains:        &d.Gains,
+		EntityLosses:       &d.Losses,
+		EntityBadges:       &d.Badges,
+		EntityRevisions:    &d.Revisions,
+	}
+}
+
+// entity is one kind of record: its name, which names its files, the
+// records for the JSON file and the header and rows of the CSV file.
+type entity struct {
+	name    string
+	records interface{}
+	header  []string
+	rows    [][]string
+}
+
+// Entity names, in the order the files are written.
+const (
+	EntityComplexes    = "complexes"
+	EntityGoals        = "goals"
+	EntityMeasurements = "measurements"
+	EntityMilestones   = "milestones"
+	EntityActions      = "actions"
+	EntityCompletions  = "completions"
+	EntityGains        = "gains"
+	EntityLosses       = "losses"
+	EntityBadges       = "badges"
+	EntityRevisions    = "revisions"
+)
+
+func (d *Data) entities() []entity {
+	complexes := entity{name: EntityComplexes, records: d.Complexes,
+		header: []string{"id", "content", "trigger_episode", "category", "created_at", "updated_at", "deleted_at"}}
+	for _, c := range d.Complexes {
+		complexes.rows = append(complexes.rows, []string{
+			id(c.ID), c.Content, c.TriggerEpisode, c.Category, timestamp(c.CreatedAt), timestamp(c.UpdatedAt), optionalTime(c.DeletedAt),
+		})
+	}
+	goals := entity{name: EntityGoals, records: d.Goals,
+		header: []string{"id", "complex_id", "content", "metric_unit", "metric_baseline", "metric_target", "metric_direction", "metric_deadline", "created_at", "updated_at", "deleted_at"}}
+	for _, g := range d.Goals {
+		goals.rows = append(goals.rows, []string{
+			id(g.ID), id(g.ComplexID), g.Content, g.MetricUnit, optionalNumber(g.MetricBaseline), optionalNumber(g.MetricTarget), g.MetricDirection, g.MetricDeadline,
+			timestamp(g.CreatedAt), timestamp(g.UpdatedAt), optionalTime(g.DeletedAt),
+		})
+	}
+	measurements := entity{name: EntityMeasurements, records: d.Measurements,
+		header: []string{"id", "goal_id", "value", "measured_at", "note", "created_at"}}
+	for _, m := range d.Measurements {
+		measurements.rows = append(measurements.rows, []string{
+			id(m.ID), id(m.GoalID), number(m.Value), timestamp(m.MeasuredAt), m.Note, timestamp(m.CreatedAt),
+		})
+	}
+	milestones := entity{name: EntityMilestones, records: d.Milestones,
+		header: []string{"id", "goal_id", "position", "title", "basis", "threshold", "achieved_at", "created_at", "updated_at"}}
+	for _, m := range d.Milestones {
+		milestones.rows = append(milestones.rows, []string{
+			id(m.ID), id(m.GoalID), strconv.Itoa(m.Position), m.Title, m.Basis, number(m.Threshold), optionalTime(m.AchievedAt),
+			timestamp(m.CreatedAt), timestamp(m.UpdatedAt),
+		})
+	}
+	actions := entity{name: EntityActions, records: d.Actions,
+		header: []string{"id", "goal_id", "content", "completed_at", "recurrence_pattern", "created_at", "updated_at", "deleted_at"}}
+	for _, a := range d.Actions {
+		pattern := ""
+		if a.RecurrencePattern != nil {
+			b, _ := json.Marshal(a.RecurrencePattern)
+			pattern = string(b)
+		}
+		actions.rows = append(actions.rows, []string{
+			id(a.ID), id(a.GoalID), a.Content, optionalTime(a.CompletedAt), pattern,
+			timestamp(a.CreatedAt), timestamp(a.UpdatedAt), optionalTime(a.DeletedAt),
+		})
+	}
+	completions := entity{name: EntityCompletions, records: d.Completions,
+		header: []string{"id", "action_id", "occurrence_date", "status", "completed_at", "note", "created_at", "updated_at"}}
+	for _, c := range d.Completions {
+		completions.rows = append(completions.rows, []string{
+			id(c.ID), id(c.ActionID), c.OccurrenceDate, c.Status, optionalTime(c.CompletedAt), c.Note, timestamp(c.CreatedAt), timestamp(c.UpdatedAt),
+		})
+	}
+	outcomes := func(name string, records []Outcome) entity {
+		e := entity{name: name, records: records, header: []string{"id", "action_id", "type", "description", "value", "unit"}}
+		for _, o := range records {
+			e.rows = append(e.rows, []string{id(o.ID), id(o.ActionID), o.Type, o.Description, optionalNumber(o.Value), o.Unit})
+		}
+		return e
+	}
+	badges := entity{name: EntityBadges, records: d.Badges,
+		header: []string{"code", "name", "description", "milestone_id", "achieved_at"}}
+	for _, b := range d.Badges {
+		milestone := ""
+		if b.MilestoneID != 0 {
+			milestone = id(b.MilestoneID)
+		}
+		badges.rows = append(badges.rows, []string{b.Code, b.Name, b.Description, milestone, timestamp(b.AchievedAt)})
+	}
+	revisions := entity{name: EntityRevisions, records: d.Revisions,
+		header: []string{"id", "entity_type", "entity_id", "action", "actor", "before", "after", "created_at"}}
+	for _, r := range d.Revisions {
+		revisions.rows = append(revisions.rows, []string{
+			id(r.ID), r.EntityType, id(r.EntityID), r.Action, r.Actor, string(r.Before), string(r.After), timestamp(r.CreatedAt),
+		})
+	}
+	return []entity{
+		complexes, goals, measurements, milestones, actions, completions,
+		outcomes(EntityGains, d.Gains), outcomes(EntityLosses, d.Losses), badges, revisions,
+	}
+}
+
+func deletedAt(valid bool, t time.Time) *time.Time {
+	if !valid {
+		return nil
+	}
+	return &t
+}
+
+// rawJSON returns a stored JSON snapshot as raw JSON, nil when empty.
+func rawJSON(snapshot string) json.RawMessage {
+	if snapshot == "" {
+		return nil
+	}
+	return json.RawMessage(snapshot)
+}
+
+func id(v uint) string {
+	return strconv.FormatUint(uint64(v), 10)
+}
+
+func timestamp(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+func optionalTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return timestamp(*t)
+}
+
+func number(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+func optionalNumber(v *float64) string {
+	if v == nil {
+		return ""
+	}
+	return number(*v)
+}
@@ -0,0 +1,164 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+
+	"refuel/backend/database"
+	"refuel/backend/models"
+	"refuel/backend/repository"
+)
+
+// readArchive returns the content of every file of a zip archive by name.
+func readArchive(t *testing.T, archive []byte) map[string][]byte {
+	t.Helper()
+	zr, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	if err != nil {
+		t.Fatalf("reading archive: %v", err)
+	}
+	files := map[string][]byte{}
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatalf("opening %s: %v", f.Name, err)
+		}
+		content, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			t.Fatalf("reading %s: %v", f.Name, err)
+		}
+		files[f.Name] = content
+	}
+	return files
+}
+
+func TestBuild(t *testing.T) {
+	exportedAt := time.Date(2025, 4, 1, 18, 0, 0, 0, time.FixedZone("JST", 9*60*60))
+	completedAt := time.Date(2025, 3, 31, 12, 0, 0, 0, time.UTC)
+	value := 2.5
+	data := &Data{
+		Complexes: []Complex{{ID: 1, Content: "人前で話すのが怖い, とても", Category: "仕事", CreatedAt: completedAt, UpdatedAt: completedAt}},
+		Goals:     []Goal{{ID: 2, ComplexID: 1, Content: "発表する", MetricDeadline: "2025-06-30", CreatedAt: completedAt, UpdatedAt: completedAt}},
+		Actions:   []Action{{ID: 3, GoalID: 2, Content: "練習する", CompletedAt: &completedAt, CreatedAt: completedAt, UpdatedAt: completedAt}},
+		Gains:     []Outcome{{ID: 4, ActionID: 3, Type: "skill", Description: "声が出た", Value: &value, Unit: "分"}},
+	}
+	archive, err := Build("u1", data, exportedAt)
+	if err != nil {
+		t.Fatalf("building archive: %v", err)
+	}
+	files := readArchive(t, archive)
+
+	var manifest Manifest
+	if err := json.Unmarshal(files[ManifestName], &manifest); err != nil {
+		t.Fatalf("decoding manifest: %v", err)
+	}
+	if manifest.Format != Format || manifest.FormatVersion != FormatVersion || manifest.SchemaVersion != database.SchemaVersion {
+		t.Errorf("got format %s v%d schema %d, want %s v%d schema %d",
+			manifest.Format, manifest.FormatVersion, manifest.SchemaVersion, Format, FormatVersion, database.SchemaVersion)
+	}
+	if manifest.UserID != "u1" || !manifest.ExportedAt.Equal(exportedAt) || manifest.ExportedAt.Location() != time.UTC {
+		t.Errorf("got user %s exported at %v, want u1 at %v in UTC", manifest.UserID, manifest.ExportedAt, exportedAt)
+	}
+
+	entities := []string{
+		EntityComplexes, EntityGoals, EntityMeasurements, EntityMilestones, EntityActions,
+		EntityCompletions, EntityGains, EntityLosses, EntityBadges, EntityRevisions,
+	}
+	records := map[string]int{EntityComplexes: 1, EntityGoals: 1, EntityActions: 1, EntityGains: 1}
+	if len(manifest.Files) != 2*len(entities) {
+		t.Fatalf("got %d files in the manifest, want %d", len(manifest.Files), 2*len(entities))
+	}
+	if len(files) != len(manifest.Files)+1 {
+		t.Errorf("got %d files in the archive, want the %d listed and the manifest", len(files), len(manifest.Files))
+	}
+	for i, f := range manifest.Files {
+		entity, format := entities[i/2], []string{"json", "csv"}[i%2]
+		if f.Name != entity+"."+format || f.Entity != entity || f.Format != format {
+			t.Errorf("file %d: got %s (%s, %s), want %s.%s", i, f.Name, f.Entity, f.Format, entity, format)
+		}
+		if f.Records != records[entity] {
+			t.Errorf("%s: got %d records, want %d", f.Name, f.Records, records[entity])
+		}
+		content, ok := files[f.Name]
+		if !ok {
+			t.Errorf("%s: listed in the manifest but not in the archive", f.Name)
+			continue
+		}
+		sum := sha256.Sum256(content)
+		if f.Size != len(content) || f.SHA256 != hex.EncodeToString(sum[:]) {
+			t.Errorf("%s: got size %d and checksum %s in the manifest, want %d and %x", f.Name, f.Size, f.SHA256, len(content), sum)
+		}
+	}
+
+	var complexes []Complex
+	if err := json.Unmarshal(files["complexes.json"], &complexes); err != nil {
+		t.Fatalf("decoding complexes.json: %v", err)
+	}
+	if !reflect.DeepEqual(complexes, data.Complexes) {
+		t.Errorf("got complexes %+v, want %+v", complexes, data.Complexes)
+	}
+
+	csvContent := string(files["complexes.csv"])
+	if !strings.HasPrefix(csvContent, utf8BOM) {
+		t.Fatalf("complexes.csv does not start with a byte order mark")
+	}
+	rows, err := csv.NewReader(strings.NewReader(strings.TrimPrefix(csvContent, utf8BOM))).ReadAll()
+	if err != nil {
+		t.Fatalf("reading complexes.csv: %v", err)
+	}
+	want := [][]string{
+		{"id", "content", "trigger_episode", "category", "created_at", "updated_at", "deleted_at"},
+		{"1", "人前で話すのが怖い, とても", "", "仕事", "2025-03-31T12:00:00Z", "2025-03-31T12:00:00Z", ""},
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("got rows %q, want %q", rows, want)
+	}
+	rows, err = csv.NewReader(strings.NewReader(strings.TrimPrefix(string(files["gains.csv"]), utf8BOM))).ReadAll()
+	if err != nil {
+		t.Fatalf("reading gains.csv: %v", err)
+	}
+	if got, want := rows[1], []string{"4", "3", "skill", "声が出た", "2.5", "分"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got gain row %q, want %q", got, want)
+	}
+}
+
+func TestFromUserData(t *testing.T) {
+	at := time.Date(2025, 4, 1, 9, 0, 0, 0, time.UTC)
+	deadline := time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC)
+	data := FromUserData(&repository.UserData{
+		Complexes: []models.Complex{
+			{ID: 1, Content: "a"},
+			{ID: 2, Content: "b", DeletedAt: gorm.DeletedAt{Time: at, Valid: true}},
+		},
+		Goals:       []models.Goal{{ID: 3, ComplexID: 1, MetricDeadline: &deadline}},
+		Completions: []models.ActionCompletion{{ID: 4, ActionID: 5, OccurrenceDate: deadline, Status: "done"}},
+		Badges:      []models.UserBadge{{Badge: models.Badge{Code: "first", Name: "初めの一歩"}, AchievedAt: at}},
+		Revisions:   []models.Revision{{ID: 6, Action: "create", After: `{"content":"a"}`}},
+	})
+	if data.Complexes[0].DeletedAt != nil || data.Complexes[1].DeletedAt == nil || !data.Complexes[1].DeletedAt.Equal(at) {
+		t.Errorf("got deleted_at %v and %v, want none and %v", data.Complexes[0].DeletedAt, data.Complexes[1].DeletedAt, at)
+	}
+	if got := data.Goals[0].MetricDeadline; got != "2025-06-30" {
+		t.Errorf("got metric deadline %q, want 2025-06-30", got)
+	}
+	if got := data.Completions[0].OccurrenceDate; got != "2025-06-30" {
+		t.Errorf("got occurrence date %q, want 2025-06-30", got)
+	}
+	if got := data.Badges[0]; got.Code != "first" || got.Name != "初めの一歩" || !got.AchievedAt.Equal(at) {
+		t.Errorf("got badge %+v, want first achieved at %v", got, at)
+	}
+	if got := data.Revisions[0]; got.Before != nil || string(got.After) != `{"content":"a"}` {
+		t.Errorf("got revision before %s after %s, want none and the snapshot", got.Before, got.After)
+	}
+}
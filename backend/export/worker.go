@@ -0,0 +1,142 @@
+package export
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"refuel/backend/models"
+	"refuel/backend/repository"
+)
+
+// Defaults, overridable with EXPORT_RETENTION and EXPORT_POLL_INTERVAL.
+const (
+	DefaultRetention    = 7 * 24 * time.Hour
+	DefaultPollInterval = 10 * time.Second
+)
+
+// staleAfter is how long a job may stay running before it is taken for
+// abandoned by a worker that stopped and is run again.
+const staleAfter = 15 * time.Minute
+
+// Config holds the export settings read from the environment.
+type Config struct {
+	// Retention is how long a finished job and its archive are kept.
+	Retention time.Duration
+	// PollInterval is how often the worker looks for pending jobs created
+	// by other servers and deletes expired ones.
+	PollInterval time.Duration
+}
+
+// ConfigFromEnv reads EXPORT_RETENTION and EXPORT_POLL_INTERVAL, both Go
+// durations such as "168h".
+func ConfigFromEnv() (Config, error) {
+	cfg := Config{Retention: DefaultRetention, PollInterval: DefaultPollInterval}
+	if v := os.Getenv("EXPORT_RETENTION"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			return cfg, fmt.Errorf("invalid EXPORT_RETENTION %q", v)
+		}
+		cfg.Retention = d
+	}
+	if v := os.Getenv("EXPORT_POLL_INTERVAL"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			return cfg, fmt.Errorf("invalid EXPORT_POLL_INTERVAL %q", v)
+		}
+		cfg.PollInterval = d
+	}
+	return cfg, nil
+}
+
+// Worker runs the pending export jobs, one at a time. Several servers may
+// each run a worker: a job is claimed by exactly one of them.
+type Worker struct {
+	repo repository.ExportRepository
+	cfg  Config
+	wake chan struct{}
+}
+
+// NewWorker returns a worker running the jobs of repo.
+func NewWorker(repo repository.ExportRepository, cfg Config) *Worker {
+	return &Worker{repo: repo, cfg: cfg, wake: make(chan struct{}, 1)}
+}
+
+// Notify wakes the worker to run a newly created job without waiting for
+// the next poll.
+func (w *Worker) Notify() {
+	select {
+	case w.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run deletes the expired jobs and runs the pending ones right away, then
+// every PollInterval and whenever notified, until ctx is done.
+func (w *Worker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.cfg.PollInterval)
+	defer ticker.Stop()
+	for {
+		deleted, err := w.repo.DeleteExpired(ctx, time.Now())
+		switch {
+		case err != nil:
+			log.Printf("⚠️ Failed to delete expired exports: %v", err)
+		case deleted > 0:
+			log.Printf("🗑️ Deleted %d expired export(s)", deleted)
+		}
+		w.runPending(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-w.wake:
+		}
+	}
+}
+
+// runPending runs jobs until none is left to claim.
+func (w *Worker) runPending(ctx context.Context) {
+	for ctx.Err() == nil {
+		now := time.Now()
+		job, err := w.repo.ClaimJob(ctx, now.Add(-staleAfter), now)
+		if err == repository.ErrNotFound {
+			return
+		}
+		if err != nil {
+			log.Printf("⚠️ Failed to claim an export job: %v", err)
+			return
+		}
+		w.run(ctx, job)
+	}
+}
+
+// run builds the archive of a claimed job and stores the outcome. The
+// reason a job failed is kept for operators and logged, not shown to the
+// user.
+func (w *Worker) run(ctx context.Context, job *models.ExportJob) {
+	archive, err := w.build(ctx, job.UserID)
+	now := time.Now()
+	expiresAt := now.Add(w.cfg.Retention)
+	job.CompletedAt, job.ExpiresAt = &now, &expiresAt
+	if err != nil {
+		log.Printf("⚠️ Failed to export the data of user %s (job %d): %v", job.UserID, job.ID, err)
+		job.Status, job.Error = models.ExportFailed, err.Error()
+	} else {
+		log.Printf("📦 Exported the data of user %s (job %d, %d bytes)", job.UserID, job.ID, len(archive))
+		job.Status, job.Archive = models.ExportCompleted, base64.StdEncoding.EncodeToString(archive)
+	}
+	if err := w.repo.FinishJob(ctx, job); err != nil {
+		log.Printf("⚠️ Failed to store the outcome of export job %d for user %s: %v", job.ID, job.UserID, err)
+	}
+}
+
+func (w *Worker) build(ctx context.Context, userID string) ([]byte, error) {
+	data, err := w.repo.UserData(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return Build(userID, FromUserData(data), time.Now())
+}
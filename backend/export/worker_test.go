@@ -0,0 +1,60 @@
+package export
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"refuel/backend/models"
+	"refuel/backend/repository"
+)
+
+func TestWorkerRunsPendingJobs(t *testing.T) {
+	ctx := context.Background()
+	repos := repository.NewMemory()
+	complex := models.Complex{UserID: "u1", Content: "人前で話すのが怖い", Category: "仕事"}
+	if err := repos.Complexes.Create(ctx, &complex); err != nil {
+		t.Fatalf("creating complex: %v", err)
+	}
+	other := models.Complex{UserID: "u2", Content: "朝が弱い", Category: "生活"}
+	if err := repos.Complexes.Create(ctx, &other); err != nil {
+		t.Fatalf("creating complex: %v", err)
+	}
+	job := models.ExportJob{UserID: "u1", Status: models.ExportPending}
+	if err := repos.Exports.CreateJob(ctx, &job); err != nil {
+		t.Fatalf("creating job: %v", err)
+	}
+
+	w := NewWorker(repos.Exports, Config{Retention: time.Hour, PollInterval: time.Hour})
+	before := time.Now()
+	w.runPending(ctx)
+
+	finished, err := repos.Exports.GetArchive(ctx, "u1", job.ID)
+	if err != nil {
+		t.Fatalf("getting job: %v", err)
+	}
+	if finished.Status != models.ExportCompleted || finished.Error != "" {
+		t.Fatalf("got status %s (%s), want %s", finished.Status, finished.Error, models.ExportCompleted)
+	}
+	if finished.ExpiresAt == nil || finished.ExpiresAt.Before(before.Add(time.Hour)) {
+		t.Errorf("got expiry %v, want an hour after completion", finished.ExpiresAt)
+	}
+	archive, err := base64.StdEncoding.DecodeString(finished.Archive)
+	if err != nil {
+		t.Fatalf("decoding archive: %v", err)
+	}
+	var complexes []Complex
+	if err := json.Unmarshal(readArchive(t, archive)["complexes.json"], &complexes); err != nil {
+		t.Fatalf("decoding complexes.json: %v", err)
+	}
+	if len(complexes) != 1 || complexes[0].ID != complex.ID {
+		t.Errorf("got complexes %+v, want only complex %d of the user", complexes, complex.ID)
+	}
+
+	// Nothing is left to claim.
+	if _, err := repos.Exports.ClaimJob(ctx, time.Now().Add(-staleAfter), time.Now()); err != repository.ErrNotFound {
+		t.Errorf("claiming another job: got error %v, want %v", err, repository.ErrNotFound)
+	}
+}
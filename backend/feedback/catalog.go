@@ -0,0 +1,159 @@
+package feedback
+
+import "refuel/backend/i18n"
+
+// Catalog is the built-in set of templates. It always has a template
+// without conditions, so Pick finds a message for every completion.
+var Catalog = []Template{
+	// Fit any completion.
+	{
+		Code: "step_forward",
+		Text: map[i18n.Lang]string{
+			i18n.Japanese: "「{action}」をやり遂げました。一歩ずつ、確実に前へ進んでいます。",
+			i18n.English:  "You did it: \"{action}\". Step by step, you are moving forward.",
+		},
+	},
+	{
+		Code: "power_of_frustration",
+		Text: map[i18n.Lang]string{
+			i18n.Japanese: "あの悔しさが力に変わっているぞ。",
+			i18n.English:  "That frustration is turning into strength.",
+		},
+	},
+	{
+		Code: "beyond_yesterday",
+		Text: map[i18n.Lang]string{
+			i18n.Japanese: "今日の行動が、昨日の自分を超えた証です。",
+			i18n.English:  "What you did today is proof you have outgrown yesterday's you.",
+		},
+	},
+	{
+		Code: "toward_goal",
+		Text: map[i18n.Lang]string{
+			i18n.Japanese: "「{goal}」にまた一歩近づきました。",
+			i18n.English:  "One more step toward \"{goal}\".",
+		},
+	},
+
+	// Quote the episode that made the user aware of the complex.
+	{
+		Code: "trigger_frustration",
+		Text: map[i18n.Lang]string{
+			i18n.Japanese: "「{trigger}」――あの悔しさが力に変わっているぞ。",
+			i18n.English:  "\"{trigger}\" - that frustration is turning into strength.",
+		},
+		Trigger: true,
+	},
+	{
+		Code: "trigger_show_past_self",
+		Text: map[i18n.Lang]string{
+			i18n.Japanese: "「{trigger}」と感じたあの日の自分に、今日の行動を見せてあげましょう。",
+			i18n.English:  "Show today's action to the you who once felt \"{trigger}\".",
+		},
+		Trigger: true,
+	},
+
+	// Streaks, in days.
+	{
+		Code: "streak_3",
+		Text: map[i18n.Lang]string{
+			i18n.Japanese: "{streak}日連続で続いています。続けること自体が、もう立派な力です。",
+			i18n.English:  "{streak} days in a row. Keeping it up is already a strength.",
+		},
+		MinStreak: 3,
+	},
+	{
+		Code: "streak_7",
+		Text: map[i18n.Lang]string{
+			i18n.Japanese: "{streak}日連続達成！習慣が、あなたの新しい当たり前になりつつあります。",
+			i18n.English:  "{streak} days in a row! The habit is becoming your new normal.",
+		},
+		MinStreak: 7,
+	},
+	{
+		Code: "streak_7_trigger",
+		Text: map[i18n.Lang]string{
+			i18n.Japanese: "「{trigger}」。あの悔しさを、{streak}日連続の行動で塗り替えています。",
+			i18n.English:  "\"{trigger}\". You are rewriting that frustration with {streak} days of action in a row.",
+		},
+		MinStreak: 7,
+		Trigger:   true,
+	},
+	{
+		Code: "streak_30",
+		Text: map[i18n.Lang]string{
+			i18n.Japanese: "{streak}日連続。ここまで来たあなたを、もう誰も昔のままとは言えません。",
+			i18n.English:  "{streak} days in a row. Nobody can say you are the same as before.",
+		},
+		MinStreak: 30,
+	},
+
+	// Milestones.
+	{
+		Code: "milestone_near",
+		Text: map[i18n.Lang]string{
+			i18n.Japanese: "次のマイルストーン「{next}」まであと少しです。",
+			i18n.English:  "Your next milestone, \"{next}\", is within reach.",
+		},
+		Milestone: MilestoneNear,
+	},
+	{
+		Code: "milestone_reached",
+		Text: map[i18n.Lang]string{
+			i18n.Japanese: "マイルストーン「{milestone}」を達成！過去の自分をまた一つ乗り越えました。",
+			i18n.English:  "Milestone \"{milestone}\" reached! You have outgrown your past self once more.",
+		},
+		Milestone: MilestoneReached,
+	},
+	{
+		Code: "milestone_reached_trigger",
+		Text: map[i18n.Lang]string{
+			i18n.Japanese: "「{milestone}」に到達。「{trigger}」と悩んでいた頃から、もうこんなに遠くまで来ています。",
+			i18n.English:  "\"{milestone}\" reached. You have come a long way from the days of \"{trigger}\".",
+		},
+		Milestone: MilestoneReached,
+		Trigger:   true,
+	},
+
+	// Complex categories.
+	{
+		Code: "category_body",
+		Text: map[i18n.Lang]string{
+			i18n.Japanese: "体は行動に正直です。今日の積み重ねは、必ず形になって表れます。",
+			i18n.English:  "Your body is honest about what you do. Today's effort will show.",
+		},
+		Categories: []string{"体型", "容姿", "外見", "見た目"},
+	},
+	{
+		Code: "category_social",
+		Text: map[i18n.Lang]string{
+			i18n.Japanese: "人と向き合う一歩は、いちばん勇気のいる一歩です。今日のあなたは、それを踏み出しました。",
+			i18n.English:  "Facing people takes the most courage of all, and today you took that step.",
+		},
+		Categories: []string{"人間関係", "社会性", "コミュニケーション"},
+	},
+	{
+		Code: "category_skill",
+		Text: map[i18n.Lang]string{
+			i18n.Japanese: "できなかったことが、少しずつ「できること」に変わっています。",
+			i18n.English:  "What you could not do is turning, bit by bit, into what you can.",
+		},
+		Categories: []string{"学歴", "仕事", "能力", "スキル", "勉強"},
+	},
+	{
+		Code: "category_health",
+		Text: map[i18n.Lang]string{
+			i18n.Japanese: "自分の体をいたわる行動は、未来の自分への贈り物です。",
+			i18n.English:  "Taking care of your body is a gift to your future self.",
+		},
+		Categories: []string{"健康", "運動", "生活習慣"},
+	},
+	{
+		Code: "category_money",
+		Text: map[i18n.Lang]string{
+			i18n.Japanese: "お金との向き合い方が、今日また一つ変わりました。",
+			i18n.English:  "The way you deal with money changed a little more today.",
+		},
+		Categories: []string{"お金", "経済", "収入"},
+	},
+}
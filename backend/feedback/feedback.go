@@ -0,0 +1,191 @@
+// Package feedback picks the encouraging message shown when a user
+// completes an action. Messages come from a catalog of templates, each
+// limited to the situations it fits: the category of the complex being
+// overcome, the length of the current streak, a milestone just reached or
+// close by, and whether the user wrote down the episode that made them aware
+// of the complex. The most specific template that fits wins, and templates
+// shown recently are passed over so the same words do not keep coming back.
+package feedback
+
+import (
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"refuel/backend/i18n"
+)
+
+// DefaultRepeatWindow is how long a template is passed over after it has
+// been shown to a user.
+const DefaultRepeatWindow = 7 * 24 * time.Hour
+
+// maxTriggerRunes bounds how much of the trigger episode is quoted back.
+const maxTriggerRunes = 40
+
+// Milestone conditions a template can require.
+const (
+	MilestoneReached = "reached"
+	MilestoneNear    = "near"
+)
+
+// NearShare is the share of the way to the next milestone from which it
+// counts as near.
+const NearShare = 0.8
+
+// Context describes the completion a message is picked for.
+type Context struct {
+	// Category and Complex are the category and content of the complex the
+	// action's goal works on; TriggerEpisode is the episode that made the
+	// user aware of it, possibly empty.
+	Category       string
+	Complex        string
+	TriggerEpisode string
+	Goal           string
+	Action         string
+	// Streak is the number of consecutive days the goal's recurring
+	// actions have been done on, 0 if none recur.
+	Streak int
+	// ReachedMilestone is the title of a milestone the completion has just
+	// reached, if any.
+	ReachedMilestone string
+	// NextMilestone is the title of the goal's next milestone, if any, and
+	// NextShare the share of the way to it already covered.
+	NextMilestone string
+	NextShare     float64
+}
+
+// Template is one message of the catalog. Its texts may quote the context
+// with the placeholders {category}, {complex}, {trigger}, {goal}, {action},
+// {streak}, {milestone} and {next}.
+type Template struct {
+	// Code identifies the template in the record of shown messages. It is
+	// the same in every language.
+	Code string
+	// Text holds the message in each language; i18n.Default must be present.
+	Text map[i18n.Lang]string
+	// Categories, if set, restrict the template to complexes whose category
+	// contains one of them.
+	Categories []string
+	// MinStreak, if set, is the shortest streak the template fits.
+	MinStreak int
+	// Milestone, if set, requires a milestone just reached or a near one.
+	Milestone string
+	// Trigger requires a trigger episode to quote.
+	Trigger bool
+}
+
+// matches reports whether the template fits the context.
+func (t Template) matches(c Context) bool {
+	if len(t.Categories) > 0 {
+		found := false
+		for _, category := range t.Categories {
+			if strings.Contains(c.Category, category) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if c.Streak < t.MinStreak {
+		return false
+	}
+	switch t.Milestone {
+	case MilestoneReached:
+		if c.ReachedMilestone == "" {
+			return false
+		}
+	case MilestoneNear:
+		if c.NextMilestone == "" || c.NextShare < NearShare {
+			return false
+		}
+	}
+	return !t.Trigger || strings.TrimSpace(c.TriggerEpisode) != ""
+}
+
+// specificity weighs the conditions the template sets. A milestone just
+// reached outweighs everything else, a near one outweighs the rest, and
+// longer streaks outweigh shorter ones.
+func (t Template) specificity() int {
+	n := t.MinStreak
+	if len(t.Categories) > 0 {
+		n += 10
+	}
+	switch t.Milestone {
+	case MilestoneReached:
+		n += 1000
+	case MilestoneNear:
+		n += 100
+	}
+	if t.Trigger {
+		n += 10
+	}
+	return n
+}
+
+// Render fills the placeholders of the template's text in lang from the
+// context, falling back to i18n.Default if it has no text in lang.
+func (t Template) Render(c Context, lang i18n.Lang) string {
+	text, ok := t.Text[lang]
+	if !ok {
+		text = t.Text[i18n.Default]
+	}
+	return strings.NewReplacer(
+		"{category}", c.Category,
+		"{complex}", c.Complex,
+		"{trigger}", excerpt(strings.TrimSpace(c.TriggerEpisode), maxTriggerRunes),
+		"{goal}", c.Goal,
+		"{action}", c.Action,
+		"{streak}", strconv.Itoa(c.Streak),
+		"{milestone}", c.ReachedMilestone,
+		"{next}", c.NextMilestone,
+	).Replace(text)
+}
+
+// excerpt shortens s to at most n runes, marking the cut with an ellipsis.
+func excerpt(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return string([]rune(s)[:n-1]) + "…"
+}
+
+// Message is a picked and rendered template.
+type Message struct {
+	Code string
+	Text string
+}
+
+// Pick chooses the message for the context from catalog and renders it in
+// lang. recent maps the codes of templates shown within the repeat window
+// to when they were last shown. The most specific fitting template not
+// among them is chosen, the earlier one in the catalog on a tie; if every
+// fitting template was shown recently, the one shown longest ago is. It
+// reports false if no template fits.
+func Pick(catalog []Template, c Context, recent map[string]time.Time, lang i18n.Lang) (Message, bool) {
+	var best, stale *Template
+	for i := range catalog {
+		t := &catalog[i]
+		if !t.matches(c) {
+			continue
+		}
+		if shown, ok := recent[t.Code]; ok {
+			if stale == nil || shown.Before(recent[stale.Code]) {
+				stale = t
+			}
+			continue
+		}
+		if best == nil || t.specificity() > best.specificity() {
+			best = t
+		}
+	}
+	if best == nil {
+		best = stale
+	}
+	if best == nil {
+		return Message{}, false
+	}
+	return Message{Code: best.Code, Text: best.Render(c, lang)}, true
+}
@@ -0,0 +1,134 @@
+package feedback
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"refuel/backend/i18n"
+)
+
+func TestCatalog(t *testing.T) {
+	placeholder := regexp.MustCompile(`\{[a-z]+\}`)
+	known := map[string]bool{"{category}": true, "{complex}": true, "{trigger}": true, "{goal}": true, "{action}": true, "{streak}": true, "{milestone}": true, "{next}": true}
+	codes := map[string]bool{}
+	unconditional := false
+	for _, tmpl := range Catalog {
+		if codes[tmpl.Code] {
+			t.Errorf("template code %q is used twice", tmpl.Code)
+		}
+		codes[tmpl.Code] = true
+		if tmpl.matches(Context{}) {
+			unconditional = true
+		}
+		for _, lang := range []i18n.Lang{i18n.Japanese, i18n.English} {
+			text, ok := tmpl.Text[lang]
+			if !ok {
+				t.Errorf("%s: no %s text", tmpl.Code, lang)
+			}
+			// A placeholder must only appear where its condition guarantees a value.
+			for _, p := range placeholder.FindAllString(text, -1) {
+				switch {
+				case !known[p]:
+					t.Errorf("%s (%s): unknown placeholder %s", tmpl.Code, lang, p)
+				case p == "{trigger}" && !tmpl.Trigger,
+					p == "{streak}" && tmpl.MinStreak == 0,
+					p == "{milestone}" && tmpl.Milestone != MilestoneReached,
+					p == "{next}" && tmpl.Milestone != MilestoneNear:
+					t.Errorf("%s (%s): %s can be empty where the template fits", tmpl.Code, lang, p)
+				}
+			}
+		}
+	}
+	if !unconditional {
+		t.Error("no template fits every completion")
+	}
+}
+
+func TestPick(t *testing.T) {
+	tests := []struct {
+		name string
+		c    Context
+		want string
+	}{
+		{"no conditions", Context{Action: "走る"}, "step_forward"},
+		{"category", Context{Category: "仕事の能力"}, "category_skill"},
+		{"other category", Context{Category: "趣味"}, "step_forward"},
+		{"short streak", Context{Streak: 2}, "step_forward"},
+		{"streak of 3", Context{Streak: 3}, "streak_3"},
+		{"streak of 7", Context{Streak: 7}, "streak_7"},
+		{"streak of 7 with a trigger", Context{Streak: 7, TriggerEpisode: "会議で言葉に詰まった"}, "streak_7_trigger"},
+		{"streak of 30", Context{Streak: 30, TriggerEpisode: "会議で言葉に詰まった"}, "streak_30"},
+		{"trigger", Context{TriggerEpisode: "会議で言葉に詰まった"}, "trigger_frustration"},
+		{"blank trigger", Context{TriggerEpisode: "  "}, "step_forward"},
+		// Equally specific: the earlier template in the catalog wins.
+		{"trigger and category", Context{Category: "仕事", TriggerEpisode: "会議で言葉に詰まった"}, "trigger_frustration"},
+		{"milestone near", Context{NextMilestone: "5km", NextShare: NearShare}, "milestone_near"},
+		{"milestone not near yet", Context{NextMilestone: "5km", NextShare: NearShare - 0.01}, "step_forward"},
+		{"milestone reached", Context{ReachedMilestone: "3km", Streak: 30, NextMilestone: "5km", NextShare: 0.9}, "milestone_reached"},
+		{"milestone reached with a trigger", Context{ReachedMilestone: "3km", TriggerEpisode: "会議で言葉に詰まった"}, "milestone_reached_trigger"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg, ok := Pick(Catalog, tt.c, nil, i18n.Japanese)
+			if !ok || msg.Code != tt.want {
+				t.Errorf("got %q (%v), want %q", msg.Code, ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestPickPassesOverRecent(t *testing.T) {
+	catalog := []Template{
+		{Code: "plain", Text: map[i18n.Lang]string{i18n.Japanese: "plain"}},
+		{Code: "streak", Text: map[i18n.Lang]string{i18n.Japanese: "streak"}, MinStreak: 3},
+		{Code: "body", Text: map[i18n.Lang]string{i18n.Japanese: "body"}, Categories: []string{"体型"}},
+	}
+	now := time.Now()
+	c := Context{Streak: 3}
+	tests := []struct {
+		name   string
+		recent map[string]time.Time
+		want   string
+	}{
+		{"none shown", nil, "streak"},
+		{"most specific shown", map[string]time.Time{"streak": now}, "plain"},
+		{"all shown", map[string]time.Time{"streak": now.Add(-time.Hour), "plain": now}, "streak"},
+		{"all shown, other order", map[string]time.Time{"streak": now, "plain": now.Add(-time.Hour)}, "plain"},
+		{"a template that does not fit shown", map[string]time.Time{"body": now}, "streak"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg, ok := Pick(catalog, c, tt.recent, i18n.Japanese)
+			if !ok || msg.Code != tt.want {
+				t.Errorf("got %q (%v), want %q", msg.Code, ok, tt.want)
+			}
+		})
+	}
+
+	if msg, ok := Pick(catalog[2:], c, nil, i18n.Japanese); ok {
+		t.Errorf("got %q, want no template to fit", msg.Code)
+	}
+}
+
+func TestRender(t *testing.T) {
+	placeholders := "{action}/{goal}/{complex}/{category}/{streak}/{milestone}/{next}/{trigger}"
+	tmpl := Template{Text: map[i18n.Lang]string{i18n.Japanese: "ja:" + placeholders, i18n.English: "en:" + placeholders}}
+	c := Context{Action: "走る", Goal: "5km", Complex: "体力", Category: "健康", Streak: 4, ReachedMilestone: "3km", NextMilestone: "4km", TriggerEpisode: " 階段で息が切れた "}
+	for _, lang := range []i18n.Lang{i18n.Japanese, i18n.English} {
+		if got, want := tmpl.Render(c, lang), string(lang)+":走る/5km/体力/健康/4/3km/4km/階段で息が切れた"; got != want {
+			t.Errorf("got %q, want %q", got, want)
+		}
+	}
+	if got, want := (Template{Text: map[i18n.Lang]string{i18n.Default: "{goal}"}}).Render(c, i18n.English), "5km"; got != want {
+		t.Errorf("got %q without an English text, want the default %q", got, want)
+	}
+
+	c.TriggerEpisode = strings.Repeat("あ", maxTriggerRunes+5)
+	got := Template{Text: map[i18n.Lang]string{i18n.Default: "{trigger}"}}.Render(c, i18n.Default)
+	if utf8.RuneCountInString(got) != maxTriggerRunes || !strings.HasSuffix(got, "…") {
+		t.Errorf("got %q, want %d runes ending in an ellipsis", got, maxTriggerRunes)
+	}
+}
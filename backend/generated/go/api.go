@@ -13,6 +13,8 @@ package refuelapi
 import (
 	"context"
 	"net/http"
+	"os"
+	"time"
 )
 
 
@@ -23,14 +25,44 @@ import (
 type ActionsAPIRouter interface { 
 	GetActions(http.ResponseWriter, *http.Request)
 	CreateAction(http.ResponseWriter, *http.Request)
+	GetAgenda(http.ResponseWriter, *http.Request)
 	UpdateAction(http.ResponseWriter, *http.Request)
 	DeleteAction(http.ResponseWriter, *http.Request)
+	GetActionOccurrences(http.ResponseWriter, *http.Request)
+	GetActionStreak(http.ResponseWriter, *http.Request)
+	GetActionCheckins(http.ResponseWriter, *http.Request)
+	CheckinAction(http.ResponseWriter, *http.Request)
+	DeleteActionCheckin(http.ResponseWriter, *http.Request)
+	GetActionGains(http.ResponseWriter, *http.Request)
+	CreateActionGain(http.ResponseWriter, *http.Request)
+	UpdateActionGain(http.ResponseWriter, *http.Request)
+	DeleteActionGain(http.ResponseWriter, *http.Request)
+	GetActionLosses(http.ResponseWriter, *http.Request)
+	CreateActionLoss(http.ResponseWriter, *http.Request)
+	UpdateActionLoss(http.ResponseWriter, *http.Request)
+	DeleteActionLoss(http.ResponseWriter, *http.Request)
+	GetGainLossSummary(http.ResponseWriter, *http.Request)
+}
+// AuthAPIRouter defines the required methods for binding the api requests to a responses for the AuthAPI
+// The AuthAPIRouter implementation should parse necessary information from the http request,
+// pass the data to a AuthAPIServicer to perform the required actions, then write the service results to the http response.
+type AuthAPIRouter interface { 
+	Register(http.ResponseWriter, *http.Request)
+	Login(http.ResponseWriter, *http.Request)
+	RefreshToken(http.ResponseWriter, *http.Request)
+	Logout(http.ResponseWriter, *http.Request)
+	StartOidcLogin(http.ResponseWriter, *http.Request)
+	CompleteOidcLogin(http.ResponseWriter, *http.Request)
+	GetMe(http.ResponseWriter, *http.Request)
+	UpdateMyPreferences(http.ResponseWriter, *http.Request)
 }
 // BadgesAPIRouter defines the required methods for binding the api requests to a responses for the BadgesAPI
 // The BadgesAPIRouter implementation should parse necessary information from the http request,
 // pass the data to a BadgesAPIServicer to perform the required actions, then write the service results to the http response.
 type BadgesAPIRouter interface { 
 	GetBadges(http.ResponseWriter, *http.Request)
+	CreateBadge(http.ResponseWriter, *http.Request)
+	DryRunBadgeRule(http.ResponseWriter, *http.Request)
 }
 // ComplexesAPIRouter defines the required methods for binding the api requests to a responses for the ComplexesAPI
 // The ComplexesAPIRouter implementation should parse necessary information from the http request,
@@ -41,6 +73,16 @@ type ComplexesAPIRouter interface {
 	GetComplex(http.ResponseWriter, *http.Request)
 	UpdateComplex(http.ResponseWriter, *http.Request)
 	DeleteComplex(http.ResponseWriter, *http.Request)
+	GetComplexHistory(http.ResponseWriter, *http.Request)
+}
+// ExportAPIRouter defines the required methods for binding the api requests to a responses for the ExportAPI
+// The ExportAPIRouter implementation should parse necessary information from the http request,
+// pass the data to a ExportAPIServicer to perform the required actions, then write the service results to the http response.
+type ExportAPIRouter interface { 
+	CreateMyExport(http.ResponseWriter, *http.Request)
+	GetMyExports(http.ResponseWriter, *http.Request)
+	GetMyExport(http.ResponseWriter, *http.Request)
+	GetMyExportArchive(http.ResponseWriter, *http.Request)
 }
 // GoalsAPIRouter defines the required methods for binding the api requests to a responses for the GoalsAPI
 // The GoalsAPIRouter implementation should parse necessary information from the http request,
@@ -51,6 +93,11 @@ type GoalsAPIRouter interface {
 	GetGoal(http.ResponseWriter, *http.Request)
 	UpdateGoal(http.ResponseWriter, *http.Request)
 	DeleteGoal(http.ResponseWriter, *http.Request)
+	GetGoalMeasurements(http.ResponseWriter, *http.Request)
+	CreateGoalMeasurement(http.ResponseWriter, *http.Request)
+	DeleteGoalMeasurement(http.ResponseWriter, *http.Request)
+	GetGoalMilestones(http.ResponseWriter, *http.Request)
+	GenerateGoalMilestones(http.ResponseWriter, *http.Request)
 }
 // HealthAPIRouter defines the required methods for binding the api requests to a responses for the HealthAPI
 // The HealthAPIRouter implementation should parse necessary information from the http request,
@@ -58,6 +105,25 @@ type GoalsAPIRouter interface {
 type HealthAPIRouter interface { 
 	Ping(http.ResponseWriter, *http.Request)
 }
+// ImportAPIRouter defines the required methods for binding the api requests to a responses for the ImportAPI
+// The ImportAPIRouter implementation should parse necessary information from the http request,
+// pass the data to a ImportAPIServicer to perform the required actions, then write the service results to the http response.
+type ImportAPIRouter interface { 
+	ImportMyData(http.ResponseWriter, *http.Request)
+}
+// SearchAPIRouter defines the required methods for binding the api requests to a responses for the SearchAPI
+// The SearchAPIRouter implementation should parse necessary information from the http request,
+// pass the data to a SearchAPIServicer to perform the required actions, then write the service results to the http response.
+type SearchAPIRouter interface { 
+	Search(http.ResponseWriter, *http.Request)
+}
+// TrashAPIRouter defines the required methods for binding the api requests to a responses for the TrashAPI
+// The TrashAPIRouter implementation should parse necessary information from the http request,
+// pass the data to a TrashAPIServicer to perform the required actions, then write the service results to the http response.
+type TrashAPIRouter interface { 
+	GetTrash(http.ResponseWriter, *http.Request)
+	RestoreTrashItem(http.ResponseWriter, *http.Request)
+}
 // UserBadgesAPIRouter defines the required methods for binding the api requests to a responses for the UserBadgesAPI
 // The UserBadgesAPIRouter implementation should parse necessary information from the http request,
 // pass the data to a UserBadgesAPIServicer to perform the required actions, then write the service results to the http response.
@@ -71,10 +137,41 @@ type UserBadgesAPIRouter interface {
 // while the service implementation can be ignored with the .openapi-generator-ignore file
 // and updated with the logic required for the API.
 type ActionsAPIServicer interface { 
-	GetActions(context.Context, int64) (ImplResponse, error)
+	GetActions(context.Context, int64, int32, string, string, string, string, string, string) (ImplResponse, error)
 	CreateAction(context.Context, ActionInput) (ImplResponse, error)
+	GetAgenda(context.Context, string, string) (ImplResponse, error)
 	UpdateAction(context.Context, int64, ActionUpdateInput) (ImplResponse, error)
 	DeleteAction(context.Context, int64) (ImplResponse, error)
+	GetActionOccurrences(context.Context, int64, int32, time.Time, string) (ImplResponse, error)
+	GetActionStreak(context.Context, int64, int32, string) (ImplResponse, error)
+	GetActionCheckins(context.Context, int64, string, string, string) (ImplResponse, error)
+	CheckinAction(context.Context, int64, ActionCheckinInput) (ImplResponse, error)
+	DeleteActionCheckin(context.Context, int64, string) (ImplResponse, error)
+	GetActionGains(context.Context, int64) (ImplResponse, error)
+	CreateActionGain(context.Context, int64, GainInput) (ImplResponse, error)
+	UpdateActionGain(context.Context, int64, int64, GainInput) (ImplResponse, error)
+	DeleteActionGain(context.Context, int64, int64) (ImplResponse, error)
+	GetActionLosses(context.Context, int64) (ImplResponse, error)
+	CreateActionLoss(context.Context, int64, LossInput) (ImplResponse, error)
+	UpdateActionLoss(context.Context, int64, int64, LossInput) (ImplResponse, error)
+	DeleteActionLoss(context.Context, int64, int64) (ImplResponse, error)
+	GetGainLossSummary(context.Context, int64, string, string, string, string, string, string) (ImplResponse, error)
+}
+
+
+// AuthAPIServicer defines the api actions for the AuthAPI service
+// This interface intended to stay up to date with the openapi yaml used to generate it,
+// while the service implementation can be ignored with the .openapi-generator-ignore file
+// and updated with the logic required for the API.
+type AuthAPIServicer interface { 
+	Register(context.Context, RegisterInput) (ImplResponse, error)
+	Login(context.Context, LoginInput) (ImplResponse, error)
+	RefreshToken(context.Context, RefreshTokenInput) (ImplResponse, error)
+	Logout(context.Context, RefreshTokenInput) (ImplResponse, error)
+	StartOidcLogin(context.Context) (ImplResponse, error)
+	CompleteOidcLogin(context.Context, OidcCallbackInput) (ImplResponse, error)
+	GetMe(context.Context) (ImplResponse, error)
+	UpdateMyPreferences(context.Context, UserPreferencesInput) (ImplResponse, error)
 }
 
 
@@ -84,6 +181,8 @@ type ActionsAPIServicer interface {
 // and updated with the logic required for the API.
 type BadgesAPIServicer interface { 
 	GetBadges(context.Context) (ImplResponse, error)
+	CreateBadge(context.Context, BadgeInput) (ImplResponse, error)
+	DryRunBadgeRule(context.Context, BadgeRuleDryRunInput) (ImplResponse, error)
 }
 
 
@@ -92,11 +191,24 @@ type BadgesAPIServicer interface {
 // while the service implementation can be ignored with the .openapi-generator-ignore file
 // and updated with the logic required for the API.
 type ComplexesAPIServicer interface { 
-	GetComplexes(context.Context) (ImplResponse, error)
+	GetComplexes(context.Context, int32, string, string, string) (ImplResponse, error)
 	CreateComplex(context.Context, ComplexInput) (ImplResponse, error)
 	GetComplex(context.Context, int64) (ImplResponse, error)
 	UpdateComplex(context.Context, int64, ComplexInput) (ImplResponse, error)
 	DeleteComplex(context.Context, int64) (ImplResponse, error)
+	GetComplexHistory(context.Context, int64) (ImplResponse, error)
+}
+
+
+// ExportAPIServicer defines the api actions for the ExportAPI service
+// This interface intended to stay up to date with the openapi yaml used to generate it,
+// while the service implementation can be ignored with the .openapi-generator-ignore file
+// and updated with the logic required for the API.
+type ExportAPIServicer interface { 
+	CreateMyExport(context.Context) (ImplResponse, error)
+	GetMyExports(context.Context) (ImplResponse, error)
+	GetMyExport(context.Context, int64) (ImplResponse, error)
+	GetMyExportArchive(context.Context, int64) (ImplResponse, error)
 }
 
 
@@ -105,11 +217,16 @@ type ComplexesAPIServicer interface {
 // while the service implementation can be ignored with the .openapi-generator-ignore file
 // and updated with the logic required for the API.
 type GoalsAPIServicer interface { 
-	GetGoals(context.Context) (ImplResponse, error)
+	GetGoals(context.Context, int32, string, string, int64) (ImplResponse, error)
 	CreateGoal(context.Context, GoalInput) (ImplResponse, error)
-	GetGoal(context.Context, int64) (ImplResponse, error)
+	GetGoal(context.Context, int64, string) (ImplResponse, error)
 	UpdateGoal(context.Context, int64, GoalInput) (ImplResponse, error)
 	DeleteGoal(context.Context, int64) (ImplResponse, error)
+	GetGoalMeasurements(context.Context, int64) (ImplResponse, error)
+	CreateGoalMeasurement(context.Context, int64, GoalMeasurementInput) (ImplResponse, error)
+	DeleteGoalMeasurement(context.Context, int64, int64) (ImplResponse, error)
+	GetGoalMilestones(context.Context, int64) (ImplResponse, error)
+	GenerateGoalMilestones(context.Context, int64, MilestonePlanInput) (ImplResponse, error)
 }
 
 
@@ -122,6 +239,34 @@ type HealthAPIServicer interface {
 }
 
 
+// ImportAPIServicer defines the api actions for the ImportAPI service
+// This interface intended to stay up to date with the openapi yaml used to generate it,
+// while the service implementation can be ignored with the .openapi-generator-ignore file
+// and updated with the logic required for the API.
+type ImportAPIServicer interface { 
+	ImportMyData(context.Context, bool, *os.File, int64) (ImplResponse, error)
+}
+
+
+// SearchAPIServicer defines the api actions for the SearchAPI service
+// This interface intended to stay up to date with the openapi yaml used to generate it,
+// while the service implementation can be ignored with the .openapi-generator-ignore file
+// and updated with the logic required for the API.
+type SearchAPIServicer interface { 
+	Search(context.Context, string, string, int32) (ImplResponse, error)
+}
+
+
+// TrashAPIServicer defines the api actions for the TrashAPI service
+// This interface intended to stay up to date with the openapi yaml used to generate it,
+// while the service implementation can be ignored with the .openapi-generator-ignore file
+// and updated with the logic required for the API.
+type TrashAPIServicer interface { 
+	GetTrash(context.Context) (ImplResponse, error)
+	RestoreTrashItem(context.Context, string, int64) (ImplResponse, error)
+}
+
+
 // UserBadgesAPIServicer defines the api actions for the UserBadgesAPI service
 // This interface intended to stay up to date with the openapi yaml used to generate it,
 // while the service implementation can be ignored with the .openapi-generator-ignore file
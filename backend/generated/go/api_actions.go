@@ -14,6 +14,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/gorilla/mux"
 )
@@ -61,6 +62,11 @@ func (c *ActionsAPIController) Routes() Routes {
 			"/api/v1/actions",
 			c.CreateAction,
 		},
+		"GetAgenda": Route{
+			strings.ToUpper("Get"),
+			"/api/v1/agenda",
+			c.GetAgenda,
+		},
 		"UpdateAction": Route{
 			strings.ToUpper("Put"),
 			"/api/v1/actions/{actionId}",
@@ -71,10 +77,80 @@ func (c *ActionsAPIController) Routes() Routes {
 			"/api/v1/actions/{actionId}",
 			c.DeleteAction,
 		},
+		"GetActionOccurrences": Route{
+			strings.ToUpper("Get"),
+			"/api/v1/actions/{actionId}/occurrences",
+			c.GetActionOccurrences,
+		},
+		"GetActionStreak": Route{
+			strings.ToUpper("Get"),
+			"/api/v1/actions/{actionId}/streak",
+			c.GetActionStreak,
+		},
+		"GetActionCheckins": Route{
+			strings.ToUpper("Get"),
+			"/api/v1/actions/{actionId}/checkins",
+			c.GetActionCheckins,
+		},
+		"CheckinAction": Route{
+			strings.ToUpper("Post"),
+			"/api/v1/actions/{actionId}/checkins",
+			c.CheckinAction,
+		},
+		"DeleteActionCheckin": Route{
+			strings.ToUpper("Delete"),
+			"/api/v1/actions/{actionId}/checkins/{date}",
+			c.DeleteActionCheckin,
+		},
+		"GetActionGains": Route{
+			strings.ToUpper("Get"),
+			"/api/v1/actions/{actionId}/gains",
+			c.GetActionGains,
+		},
+		"CreateActionGain": Route{
+			strings.ToUpper("Post"),
+			"/api/v1/actions/{actionId}/gains",
+			c.CreateActionGain,
+		},
+		"UpdateActionGain": Route{
+			strings.ToUpper("Put"),
+			"/api/v1/actions/{actionId}/gains/{gainId}",
+			c.UpdateActionGain,
+		},
+		"DeleteActionGain": Route{
+			strings.ToUpper("Delete"),
+			"/api/v1/actions/{actionId}/gains/{gainId}",
+			c.DeleteActionGain,
+		},
+		"GetActionLosses": Route{
+			strings.ToUpper("Get"),
+			"/api/v1/actions/{actionId}/losses",
+			c.GetActionLosses,
+		},
+		"CreateActionLoss": Route{
+			strings.ToUpper("Post"),
+			"/api/v1/actions/{actionId}/losses",
+			c.CreateActionLoss,
+		},
+		"UpdateActionLoss": Route{
+			strings.ToUpper("Put"),
+			"/api/v1/actions/{actionId}/losses/{lossId}",
+			c.UpdateActionLoss,
+		},
+		"DeleteActionLoss": Route{
+			strings.ToUpper("Delete"),
+			"/api/v1/actions/{actionId}/losses/{lossId}",
+			c.DeleteActionLoss,
+		},
+		"GetGainLossSummary": Route{
+			strings.ToUpper("Get"),
+			"/api/v1/gains-losses/summary",
+			c.GetGainLossSummary,
+		},
 	}
 }
 
-// GetActions - 指定された目標IDに紐づく行動の一覧を取得
+// GetActions - 行動の一覧を取得
 func (c *ActionsAPIController) GetActions(w http.ResponseWriter, r *http.Request) {
 	query, err := parseQuery(r.URL.RawQuery)
 	if err != nil {
@@ -93,18 +169,58 @@ func (c *ActionsAPIController) GetActions(w http.ResponseWriter, r *http.Request
 		}
 
 		goalIdParam = param
-	} else {
-		c.errorHandler(w, r, &RequiredError{Field: "goal_id"}, nil)
-		return
 	}
-	result, err := c.service.GetActions(r.Context(), goalIdParam)
+	var limitParam int32
+	if query.Has("limit") {
+		param, err := parseNumericParameter[int32](
+			query.Get("limit"),
+			WithParse[int32](parseInt32),
+		)
+		if err != nil {
+			c.errorHandler(w, r, &ParsingError{Param: "limit", Err: err}, nil)
+			return
+		}
+
+		limitParam = param
+	}
+	var cursorParam string
+	if query.Has("cursor") {
+		param := query.Get("cursor")
+		cursorParam = param
+	}
+	var sortParam string
+	if query.Has("sort") {
+		param := query.Get("sort")
+		sortParam = param
+	}
+	var statusParam string
+	if query.Has("status") {
+		param := query.Get("status")
+		statusParam = param
+	}
+	var fromParam string
+	if query.Has("from") {
+		param := query.Get("from")
+		fromParam = param
+	}
+	var toParam string
+	if query.Has("to") {
+		param := query.Get("to")
+		toParam = param
+	}
+	var tzParam string
+	if query.Has("tz") {
+		param := query.Get("tz")
+		tzParam = param
+	}
+	result, err := c.service.GetActions(r.Context(), goalIdParam, limitParam, cursorParam, sortParam, statusParam, fromParam, toParam, tzParam)
 	// If an error occurred, encode the error with the status code
 	if err != nil {
 		c.errorHandler(w, r, err, &result)
 		return
 	}
 	// If no error, encode the body and the result code
-	_ = EncodeJSONResponse(result.Body, &result.Code, w)
+	_ = EncodeJSONResponse(result.Body, &result.Code, result.Headers, w)
 }
 
 // CreateAction - 新しい行動を記録
@@ -131,7 +247,34 @@ func (c *ActionsAPIController) CreateAction(w http.ResponseWriter, r *http.Reque
 		return
 	}
 	// If no error, encode the body and the result code
-	_ = EncodeJSONResponse(result.Body, &result.Code, w)
+	_ = EncodeJSONResponse(result.Body, &result.Code, result.Headers, w)
+}
+
+// GetAgenda - 指定した日の実施予定を目標とコンプレックスごとにまとめて取得
+func (c *ActionsAPIController) GetAgenda(w http.ResponseWriter, r *http.Request) {
+	query, err := parseQuery(r.URL.RawQuery)
+	if err != nil {
+		c.errorHandler(w, r, &ParsingError{Err: err}, nil)
+		return
+	}
+	var dateParam string
+	if query.Has("date") {
+		param := query.Get("date")
+		dateParam = param
+	}
+	var tzParam string
+	if query.Has("tz") {
+		param := query.Get("tz")
+		tzParam = param
+	}
+	result, err := c.service.GetAgenda(r.Context(), dateParam, tzParam)
+	// If an error occurred, encode the error with the status code
+	if err != nil {
+		c.errorHandler(w, r, err, &result)
+		return
+	}
+	// If no error, encode the body and the result code
+	_ = EncodeJSONResponse(result.Body, &result.Code, result.Headers, w)
 }
 
 // UpdateAction - 既存の行動情報を更新
@@ -167,7 +310,7 @@ func (c *ActionsAPIController) UpdateAction(w http.ResponseWriter, r *http.Reque
 		return
 	}
 	// If no error, encode the body and the result code
-	_ = EncodeJSONResponse(result.Body, &result.Code, w)
+	_ = EncodeJSONResponse(result.Body, &result.Code, result.Headers, w)
 }
 
 // DeleteAction - 既存の行動を削除
@@ -188,5 +331,535 @@ func (c *ActionsAPIController) DeleteAction(w http.ResponseWriter, r *http.Reque
 		return
 	}
 	// If no error, encode the body and the result code
-	_ = EncodeJSONResponse(result.Body, &result.Code, w)
+	_ = EncodeJSONResponse(result.Body, &result.Code, result.Headers, w)
+}
+
+// GetActionOccurrences - 繰り返しパターンから行動の次回以降の実施予定を取得
+func (c *ActionsAPIController) GetActionOccurrences(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	actionIdParam, err := parseNumericParameter[int64](
+		params["actionId"],
+		WithRequire[int64](parseInt64),
+	)
+	if err != nil {
+		c.errorHandler(w, r, &ParsingError{Param: "actionId", Err: err}, nil)
+		return
+	}
+	query, err := parseQuery(r.URL.RawQuery)
+	if err != nil {
+		c.errorHandler(w, r, &ParsingError{Err: err}, nil)
+		return
+	}
+	var countParam int32
+	if query.Has("count") {
+		param, err := parseNumericParameter[int32](
+			query.Get("count"),
+			WithParse[int32](parseInt32),
+		)
+		if err != nil {
+			c.errorHandler(w, r, &ParsingError{Param: "count", Err: err}, nil)
+			return
+		}
+
+		countParam = param
+	} else {
+		var param int32 = 5
+		countParam = param
+	}
+	var fromParam time.Time
+	if query.Has("from") {
+		param, err := parseTime(query.Get("from"))
+		if err != nil {
+			c.errorHandler(w, r, &ParsingError{Param: "from", Err: err}, nil)
+			return
+		}
+
+		fromParam = param
+	}
+	var tzParam string
+	if query.Has("tz") {
+		param := query.Get("tz")
+		tzParam = param
+	}
+	result, err := c.service.GetActionOccurrences(r.Context(), actionIdParam, countParam, fromParam, tzParam)
+	// If an error occurred, encode the error with the status code
+	if err != nil {
+		c.errorHandler(w, r, err, &result)
+		return
+	}
+	// If no error, encode the body and the result code
+	_ = EncodeJSONResponse(result.Body, &result.Code, result.Headers, w)
+}
+
+// GetActionStreak - 繰り返し行動の連続記録と実施率を取得
+func (c *ActionsAPIController) GetActionStreak(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	actionIdParam, err := parseNumericParameter[int64](
+		params["actionId"],
+		WithRequire[int64](parseInt64),
+	)
+	if err != nil {
+		c.errorHandler(w, r, &ParsingError{Param: "actionId", Err: err}, nil)
+		return
+	}
+	query, err := parseQuery(r.URL.RawQuery)
+	if err != nil {
+		c.errorHandler(w, r, &ParsingError{Err: err}, nil)
+		return
+	}
+	var windowParam int32
+	if query.Has("window") {
+		param, err := parseNumericParameter[int32](
+			query.Get("window"),
+			WithParse[int32](parseInt32),
+		)
+		if err != nil {
+			c.errorHandler(w, r, &ParsingError{Param: "window", Err: err}, nil)
+			return
+		}
+
+		windowParam = param
+	} else {
+		var param int32 = 30
+		windowParam = param
+	}
+	var tzParam string
+	if query.Has("tz") {
+		param := query.Get("tz")
+		tzParam = param
+	}
+	result, err := c.service.GetActionStreak(r.Context(), actionIdParam, windowParam, tzParam)
+	// If an error occurred, encode the error with the status code
+	if err != nil {
+		c.errorHandler(w, r, err, &result)
+		return
+	}
+	// If no error, encode the body and the result code
+	_ = EncodeJSONResponse(result.Body, &result.Code, result.Headers, w)
+}
+
+// GetActionCheckins - 期間内の実施予定ごとの実施状況を取得
+func (c *ActionsAPIController) GetActionCheckins(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	actionIdParam, err := parseNumericParameter[int64](
+		params["actionId"],
+		WithRequire[int64](parseInt64),
+	)
+	if err != nil {
+		c.errorHandler(w, r, &ParsingError{Param: "actionId", Err: err}, nil)
+		return
+	}
+	query, err := parseQuery(r.URL.RawQuery)
+	if err != nil {
+		c.errorHandler(w, r, &ParsingError{Err: err}, nil)
+		return
+	}
+	var fromParam string
+	if query.Has("from") {
+		param := query.Get("from")
+		fromParam = param
+	}
+	var toParam string
+	if query.Has("to") {
+		param := query.Get("to")
+		toParam = param
+	}
+	var tzParam string
+	if query.Has("tz") {
+		param := query.Get("tz")
+		tzParam = param
+	}
+	result, err := c.service.GetActionCheckins(r.Context(), actionIdParam, fromParam, toParam, tzParam)
+	// If an error occurred, encode the error with the status code
+	if err != nil {
+		c.errorHandler(w, r, err, &result)
+		return
+	}
+	// If no error, encode the body and the result code
+	_ = EncodeJSONResponse(result.Body, &result.Code, result.Headers, w)
+}
+
+// CheckinAction - 行動の実施予定に実施状況を記録 (チェックイン)
+func (c *ActionsAPIController) CheckinAction(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	actionIdParam, err := parseNumericParameter[int64](
+		params["actionId"],
+		WithRequire[int64](parseInt64),
+	)
+	if err != nil {
+		c.errorHandler(w, r, &ParsingError{Param: "actionId", Err: err}, nil)
+		return
+	}
+	var actionCheckinInputParam ActionCheckinInput
+	d := json.NewDecoder(r.Body)
+	d.DisallowUnknownFields()
+	if err := d.Decode(&actionCheckinInputParam); err != nil {
+		c.errorHandler(w, r, &ParsingError{Err: err}, nil)
+		return
+	}
+	if err := AssertActionCheckinInputRequired(actionCheckinInputParam); err != nil {
+		c.errorHandler(w, r, err, nil)
+		return
+	}
+	if err := AssertActionCheckinInputConstraints(actionCheckinInputParam); err != nil {
+		c.errorHandler(w, r, err, nil)
+		return
+	}
+	result, err := c.service.CheckinAction(r.Context(), actionIdParam, actionCheckinInputParam)
+	// If an error occurred, encode the error with the status code
+	if err != nil {
+		c.errorHandler(w, r, err, &result)
+		return
+	}
+	// If no error, encode the body and the result code
+	_ = EncodeJSONResponse(result.Body, &result.Code, result.Headers, w)
+}
+
+// DeleteActionCheckin - 実施状況の記録を取り消し
+func (c *ActionsAPIController) DeleteActionCheckin(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	actionIdParam, err := parseNumericParameter[int64](
+		params["actionId"],
+		WithRequire[int64](parseInt64),
+	)
+	if err != nil {
+		c.errorHandler(w, r, &ParsingError{Param: "actionId", Err: err}, nil)
+		return
+	}
+	dateParam := params["date"]
+	if dateParam == "" {
+		c.errorHandler(w, r, &RequiredError{"date"}, nil)
+		return
+	}
+	result, err := c.service.DeleteActionCheckin(r.Context(), actionIdParam, dateParam)
+	// If an error occurred, encode the error with the status code
+	if err != nil {
+		c.errorHandler(w, r, err, &result)
+		return
+	}
+	// If no error, encode the body and the result code
+	_ = EncodeJSONResponse(result.Body, &result.Code, result.Headers, w)
+}
+
+// GetActionGains - 行動に紐づくGainの一覧を取得
+func (c *ActionsAPIController) GetActionGains(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	actionIdParam, err := parseNumericParameter[int64](
+		params["actionId"],
+		WithRequire[int64](parseInt64),
+	)
+	if err != nil {
+		c.errorHandler(w, r, &ParsingError{Param: "actionId", Err: err}, nil)
+		return
+	}
+	result, err := c.service.GetActionGains(r.Context(), actionIdParam)
+	// If an error occurred, encode the error with the status code
+	if err != nil {
+		c.errorHandler(w, r, err, &result)
+		return
+	}
+	// If no error, encode the body and the result code
+	_ = EncodeJSONResponse(result.Body, &result.Code, result.Headers, w)
+}
+
+// CreateActionGain - 行動にGainを追加
+func (c *ActionsAPIController) CreateActionGain(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	actionIdParam, err := parseNumericParameter[int64](
+		params["actionId"],
+		WithRequire[int64](parseInt64),
+	)
+	if err != nil {
+		c.errorHandler(w, r, &ParsingError{Param: "actionId", Err: err}, nil)
+		return
+	}
+	var gainInputParam GainInput
+	d := json.NewDecoder(r.Body)
+	d.DisallowUnknownFields()
+	if err := d.Decode(&gainInputParam); err != nil {
+		c.errorHandler(w, r, &ParsingError{Err: err}, nil)
+		return
+	}
+	if err := AssertGainInputRequired(gainInputParam); err != nil {
+		c.errorHandler(w, r, err, nil)
+		return
+	}
+	if err := AssertGainInputConstraints(gainInputParam); err != nil {
+		c.errorHandler(w, r, err, nil)
+		return
+	}
+	result, err := c.service.CreateActionGain(r.Context(), actionIdParam, gainInputParam)
+	// If an error occurred, encode the error with the status code
+	if err != nil {
+		c.errorHandler(w, r, err, &result)
+		return
+	}
+	// If no error, encode the body and the result code
+	_ = EncodeJSONResponse(result.Body, &result.Code, result.Headers, w)
+}
+
+// UpdateActionGain - 行動に紐づくGainを更新
+func (c *ActionsAPIController) UpdateActionGain(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	actionIdParam, err := parseNumericParameter[int64](
+		params["actionId"],
+		WithRequire[int64](parseInt64),
+	)
+	if err != nil {
+		c.errorHandler(w, r, &ParsingError{Param: "actionId", Err: err}, nil)
+		return
+	}
+	gainIdParam, err := parseNumericParameter[int64](
+		params["gainId"],
+		WithRequire[int64](parseInt64),
+	)
+	if err != nil {
+		c.errorHandler(w, r, &ParsingError{Param: "gainId", Err: err}, nil)
+		return
+	}
+	var gainInputParam GainInput
+	d := json.NewDecoder(r.Body)
+	d.DisallowUnknownFields()
+	if err := d.Decode(&gainInputParam); err != nil {
+		c.errorHandler(w, r, &ParsingError{Err: err}, nil)
+		return
+	}
+	if err := AssertGainInputRequired(gainInputParam); err != nil {
+		c.errorHandler(w, r, err, nil)
+		return
+	}
+	if err := AssertGainInputConstraints(gainInputParam); err != nil {
+		c.errorHandler(w, r, err, nil)
+		return
+	}
+	result, err := c.service.UpdateActionGain(r.Context(), actionIdParam, gainIdParam, gainInputParam)
+	// If an error occurred, encode the error with the status code
+	if err != nil {
+		c.errorHandler(w, r, err, &result)
+		return
+	}
+	// If no error, encode the body and the result code
+	_ = EncodeJSONResponse(result.Body, &result.Code, result.Headers, w)
+}
+
+// DeleteActionGain - 行動に紐づくGainを削除
+func (c *ActionsAPIController) DeleteActionGain(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	actionIdParam, err := parseNumericParameter[int64](
+		params["actionId"],
+		WithRequire[int64](parseInt64),
+	)
+	if err != nil {
+		c.errorHandler(w, r, &ParsingError{Param: "actionId", Err: err}, nil)
+		return
+	}
+	gainIdParam, err := parseNumericParameter[int64](
+		params["gainId"],
+		WithRequire[int64](parseInt64),
+	)
+	if err != nil {
+		c.errorHandler(w, r, &ParsingError{Param: "gainId", Err: err}, nil)
+		return
+	}
+	result, err := c.service.DeleteActionGain(r.Context(), actionIdParam, gainIdParam)
+	// If an error occurred, encode the error with the status code
+	if err != nil {
+		c.errorHandler(w, r, err, &result)
+		return
+	}
+	// If no error, encode the body and the result code
+	_ = EncodeJSONResponse(result.Body, &result.Code, result.Headers, w)
+}
+
+// GetActionLosses - 行動に紐づくLossの一覧を取得
+func (c *ActionsAPIController) GetActionLosses(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	actionIdParam, err := parseNumericParameter[int64](
+		params["actionId"],
+		WithRequire[int64](parseInt64),
+	)
+	if err != nil {
+		c.errorHandler(w, r, &ParsingError{Param: "actionId", Err: err}, nil)
+		return
+	}
+	result, err := c.service.GetActionLosses(r.Context(), actionIdParam)
+	// If an error occurred, encode the error with the status code
+	if err != nil {
+		c.errorHandler(w, r, err, &result)
+		return
+	}
+	// If no error, encode the body and the result code
+	_ = EncodeJSONResponse(result.Body, &result.Code, result.Headers, w)
+}
+
+// CreateActionLoss - 行動にLossを追加
+func (c *ActionsAPIController) CreateActionLoss(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	actionIdParam, err := parseNumericParameter[int64](
+		params["actionId"],
+		WithRequire[int64](parseInt64),
+	)
+	if err != nil {
+		c.errorHandler(w, r, &ParsingError{Param: "actionId", Err: err}, nil)
+		return
+	}
+	var lossInputParam LossInput
+	d := json.NewDecoder(r.Body)
+	d.DisallowUnknownFields()
+	if err := d.Decode(&lossInputParam); err != nil {
+		c.errorHandler(w, r, &ParsingError{Err: err}, nil)
+		return
+	}
+	if err := AssertLossInputRequired(lossInputParam); err != nil {
+		c.errorHandler(w, r, err, nil)
+		return
+	}
+	if err := AssertLossInputConstraints(lossInputParam); err != nil {
+		c.errorHandler(w, r, err, nil)
+		return
+	}
+	result, err := c.service.CreateActionLoss(r.Context(), actionIdParam, lossInputParam)
+	// If an error occurred, encode the error with the status code
+	if err != nil {
+		c.errorHandler(w, r, err, &result)
+		return
+	}
+	// If no error, encode the body and the result code
+	_ = EncodeJSONResponse(result.Body, &result.Code, result.Headers, w)
+}
+
+// UpdateActionLoss - 行動に紐づくLossを更新
+func (c *ActionsAPIController) UpdateActionLoss(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	actionIdParam, err := parseNumericParameter[int64](
+		params["actionId"],
+		WithRequire[int64](parseInt64),
+	)
+	if err != nil {
+		c.errorHandler(w, r, &ParsingError{Param: "actionId", Err: err}, nil)
+		return
+	}
+	lossIdParam, err := parseNumericParameter[int64](
+		params["lossId"],
+		WithRequire[int64](parseInt64),
+	)
+	if err != nil {
+		c.errorHandler(w, r, &ParsingError{Param: "lossId", Err: err}, nil)
+		return
+	}
+	var lossInputParam LossInput
+	d := json.NewDecoder(r.Body)
+	d.DisallowUnknownFields()
+	if err := d.Decode(&lossInputParam); err != nil {
+		c.errorHandler(w, r, &ParsingError{Err: err}, nil)
+		return
+	}
+	if err := AssertLossInputRequired(lossInputParam); err != nil {
+		c.errorHandler(w, r, err, nil)
+		return
+	}
+	if err := AssertLossInputConstraints(lossInputParam); err != nil {
+		c.errorHandler(w, r, err, nil)
+		return
+	}
+	result, err := c.service.UpdateActionLoss(r.Context(), actionIdParam, lossIdParam, lossInputParam)
+	// If an error occurred, encode the error with the status code
+	if err != nil {
+		c.errorHandler(w, r, err, &result)
+		return
+	}
+	// If no error, encode the body and the result code
+	_ = EncodeJSONResponse(result.Body, &result.Code, result.Headers, w)
+}
+
+// DeleteActionLoss - 行動に紐づくLossを削除
+func (c *ActionsAPIController) DeleteActionLoss(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	actionIdParam, err := parseNumericParameter[int64](
+		params["actionId"],
+		WithRequire[int64](parseInt64),
+	)
+	if err != nil {
+		c.errorHandler(w, r, &ParsingError{Param: "actionId", Err: err}, nil)
+		return
+	}
+	lossIdParam, err := parseNumericParameter[int64](
+		params["lossId"],
+		WithRequire[int64](parseInt64),
+	)
+	if err != nil {
+		c.errorHandler(w, r, &ParsingError{Param: "lossId", Err: err}, nil)
+		return
+	}
+	result, err := c.service.DeleteActionLoss(r.Context(), actionIdParam, lossIdParam)
+	// If an error occurred, encode the error with the status code
+	if err != nil {
+		c.errorHandler(w, r, err, &result)
+		return
+	}
+	// If no error, encode the body and the result code
+	_ = EncodeJSONResponse(result.Body, &result.Code, result.Headers, w)
+}
+
+// GetGainLossSummary - 定量的なGain/Lossを単位・目標・期間ごとに集計
+func (c *ActionsAPIController) GetGainLossSummary(w http.ResponseWriter, r *http.Request) {
+	query, err := parseQuery(r.URL.RawQuery)
+	if err != nil {
+		c.errorHandler(w, r, &ParsingError{Err: err}, nil)
+		return
+	}
+	var goalIdParam int64
+	if query.Has("goal_id") {
+		param, err := parseNumericParameter[int64](
+			query.Get("goal_id"),
+			WithParse[int64](parseInt64),
+		)
+		if err != nil {
+			c.errorHandler(w, r, &ParsingError{Param: "goal_id", Err: err}, nil)
+			return
+		}
+
+		goalIdParam = param
+	}
+	var kindParam string
+	if query.Has("kind") {
+		param := query.Get("kind")
+		kindParam = param
+	}
+	var unitParam string
+	if query.Has("unit") {
+		param := query.Get("unit")
+		unitParam = param
+	}
+	var bucketParam string
+	if query.Has("bucket") {
+		param := query.Get("bucket")
+		bucketParam = param
+	} else {
+		param := "month"
+		bucketParam = param
+	}
+	var fromParam string
+	if query.Has("from") {
+		param := query.Get("from")
+		fromParam = param
+	}
+	var toParam string
+	if query.Has("to") {
+		param := query.Get("to")
+		toParam = param
+	}
+	var tzParam string
+	if query.Has("tz") {
+		param := query.Get("tz")
+		tzParam = param
+	}
+	result, err := c.service.GetGainLossSummary(r.Context(), goalIdParam, kindParam, unitParam, bucketParam, fromParam, toParam, tzParam)
+	// If an error occurred, encode the error with the status code
+	if err != nil {
+		c.errorHandler(w, r, err, &result)
+		return
+	}
+	// If no error, encode the body and the result code
+	_ = EncodeJSONResponse(result.Body, &result.Code, result.Headers, w)
 }
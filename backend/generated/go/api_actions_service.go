@@ -14,6 +14,7 @@ import (
 	"context"
 	"net/http"
 	"errors"
+	"time"
 )
 
 // ActionsAPIService is a service that implements the logic for the ActionsAPIServicer
@@ -27,8 +28,8 @@ func NewActionsAPIService() *ActionsAPIService {
 	return &ActionsAPIService{}
 }
 
-// GetActions - 指定された目標IDに紐づく行動の一覧を取得
-func (s *ActionsAPIService) GetActions(ctx context.Context, goalId int64) (ImplResponse, error) {
+// GetActions - 行動の一覧を取得
+func (s *ActionsAPIService) GetActions(ctx context.Context, goalId int64, limit int32, cursor string, sort string, status string, from string, to string, tz string) (ImplResponse, error) {
 	// TODO - update GetActions with the required logic for this service method.
 	// Add api_actions_service.go to the .openapi-generator-ignore to avoid overwriting this service implementation when updating open api generation.
 
@@ -64,6 +65,26 @@ func (s *ActionsAPIService) CreateAction(ctx context.Context, actionInput Action
 	return Response(http.StatusNotImplemented, nil), errors.New("CreateAction method not implemented")
 }
 
+// GetAgenda - 指定した日の実施予定を目標とコンプレックスごとにまとめて取得
+func (s *ActionsAPIService) GetAgenda(ctx context.Context, date string, tz string) (ImplResponse, error) {
+	// TODO - update GetAgenda with the required logic for this service method.
+	// Add api_actions_service.go to the .openapi-generator-ignore to avoid overwriting this service implementation when updating open api generation.
+
+	// TODO: Uncomment the next line to return response Response(200, Agenda{}) or use other options such as http.Ok ...
+	// return Response(200, Agenda{}), nil
+
+	// TODO: Uncomment the next line to return response Response(400, Error{}) or use other options such as http.Ok ...
+	// return Response(400, Error{}), nil
+
+	// TODO: Uncomment the next line to return response Response(401, Error{}) or use other options such as http.Ok ...
+	// return Response(401, Error{}), nil
+
+	// TODO: Uncomment the next line to return response Response(500, Error{}) or use other options such as http.Ok ...
+	// return Response(500, Error{}), nil
+
+	return Response(http.StatusNotImplemented, nil), errors.New("GetAgenda method not implemented")
+}
+
 // UpdateAction - 既存の行動情報を更新
 func (s *ActionsAPIService) UpdateAction(ctx context.Context, actionId int64, actionUpdateInput ActionUpdateInput) (ImplResponse, error) {
 	// TODO - update UpdateAction with the required logic for this service method.
@@ -106,3 +127,313 @@ func (s *ActionsAPIService) DeleteAction(ctx context.Context, actionId int64) (I
 
 	return Response(http.StatusNotImplemented, nil), errors.New("DeleteAction method not implemented")
 }
+
+// GetActionOccurrences - 繰り返しパターンから行動の次回以降の実施予定を取得
+func (s *ActionsAPIService) GetActionOccurrences(ctx context.Context, actionId int64, count int32, from time.Time, tz string) (ImplResponse, error) {
+	// TODO - update GetActionOccurrences with the required logic for this service method.
+	// Add api_actions_service.go to the .openapi-generator-ignore to avoid overwriting this service implementation when updating open api generation.
+
+	// TODO: Uncomment the next line to return response Response(200, []ActionOccurrence{}) or use other options such as http.Ok ...
+	// return Response(200, []ActionOccurrence{}), nil
+
+	// TODO: Uncomment the next line to return response Response(400, Error{}) or use other options such as http.Ok ...
+	// return Response(400, Error{}), nil
+
+	// TODO: Uncomment the next line to return response Response(401, Error{}) or use other options such as http.Ok ...
+	// return Response(401, Error{}), nil
+
+	// TODO: Uncomment the next line to return response Response(404, {}) or use other options such as http.Ok ...
+	// return Response(404, nil),nil
+
+	// TODO: Uncomment the next line to return response Response(500, {}) or use other options such as http.Ok ...
+	// return Response(500, nil),nil
+
+	return Response(http.StatusNotImplemented, nil), errors.New("GetActionOccurrences method not implemented")
+}
+
+// GetActionStreak - 繰り返し行動の連続記録と実施率を取得
+func (s *ActionsAPIService) GetActionStreak(ctx context.Context, actionId int64, window int32, tz string) (ImplResponse, error) {
+	// TODO - update GetActionStreak with the required logic for this service method.
+	// Add api_actions_service.go to the .openapi-generator-ignore to avoid overwriting this service implementation when updating open api generation.
+
+	// TODO: Uncomment the next line to return response Response(200, StreakSummary{}) or use other options such as http.Ok ...
+	// return Response(200, StreakSummary{}), nil
+
+	// TODO: Uncomment the next line to return response Response(400, Error{}) or use other options such as http.Ok ...
+	// return Response(400, Error{}), nil
+
+	// TODO: Uncomment the next line to return response Response(401, Error{}) or use other options such as http.Ok ...
+	// return Response(401, Error{}), nil
+
+	// TODO: Uncomment the next line to return response Response(404, {}) or use other options such as http.Ok ...
+	// return Response(404, nil),nil
+
+	// TODO: Uncomment the next line to return response Response(500, {}) or use other options such as http.Ok ...
+	// return Response(500, nil),nil
+
+	return Response(http.StatusNotImplemented, nil), errors.New("GetActionStreak method not implemented")
+}
+
+// GetActionCheckins - 期間内の実施予定ごとの実施状況を取得
+func (s *ActionsAPIService) GetActionCheckins(ctx context.Context, actionId int64, from string, to string, tz string) (ImplResponse, error) {
+	// TODO - update GetActionCheckins with the required logic for this service method.
+	// Add api_actions_service.go to the .openapi-generator-ignore to avoid overwriting this service implementation when updating open api generation.
+
+	// TODO: Uncomment the next line to return response Response(200, []ActionCheckin{}) or use other options such as http.Ok ...
+	// return Response(200, []ActionCheckin{}), nil
+
+	// TODO: Uncomment the next line to return response Response(400, Error{}) or use other options such as http.Ok ...
+	// return Response(400, Error{}), nil
+
+	// TODO: Uncomment the next line to return response Response(401, Error{}) or use other options such as http.Ok ...
+	// return Response(401, Error{}), nil
+
+	// TODO: Uncomment the next line to return response Response(404, {}) or use other options such as http.Ok ...
+	// return Response(404, nil),nil
+
+	// TODO: Uncomment the next line to return response Response(500, {}) or use other options such as http.Ok ...
+	// return Response(500, nil),nil
+
+	return Response(http.StatusNotImplemented, nil), errors.New("GetActionCheckins method not implemented")
+}
+
+// CheckinAction - 行動の実施予定に実施状況を記録 (チェックイン)
+func (s *ActionsAPIService) CheckinAction(ctx context.Context, actionId int64, actionCheckinInput ActionCheckinInput) (ImplResponse, error) {
+	// TODO - update CheckinAction with the required logic for this service method.
+	// Add api_actions_service.go to the .openapi-generator-ignore to avoid overwriting this service implementation when updating open api generation.
+
+	// TODO: Uncomment the next line to return response Response(200, ActionCheckin{}) or use other options such as http.Ok ...
+	// return Response(200, ActionCheckin{}), nil
+
+	// TODO: Uncomment the next line to return response Response(201, ActionCheckin{}) or use other options such as http.Ok ...
+	// return Response(201, ActionCheckin{}), nil
+
+	// TODO: Uncomment the next line to return response Response(400, Error{}) or use other options such as http.Ok ...
+	// return Response(400, Error{}), nil
+
+	// TODO: Uncomment the next line to return response Response(401, Error{}) or use other options such as http.Ok ...
+	// return Response(401, Error{}), nil
+
+	// TODO: Uncomment the next line to return response Response(404, {}) or use other options such as http.Ok ...
+	// return Response(404, nil),nil
+
+	// TODO: Uncomment the next line to return response Response(500, {}) or use other options such as http.Ok ...
+	// return Response(500, nil),nil
+
+	return Response(http.StatusNotImplemented, nil), errors.New("CheckinAction method not implemented")
+}
+
+// DeleteActionCheckin - 実施状況の記録を取り消し
+func (s *ActionsAPIService) DeleteActionCheckin(ctx context.Context, actionId int64, date string) (ImplResponse, error) {
+	// TODO - update DeleteActionCheckin with the required logic for this service method.
+	// Add api_actions_service.go to the .openapi-generator-ignore to avoid overwriting this service implementation when updating open api generation.
+
+	// TODO: Uncomment the next line to return response Response(204, {}) or use other options such as http.Ok ...
+	// return Response(204, nil),nil
+
+	// TODO: Uncomment the next line to return response Response(400, Error{}) or use other options such as http.Ok ...
+	// return Response(400, Error{}), nil
+
+	// TODO: Uncomment the next line to return response Response(404, {}) or use other options such as http.Ok ...
+	// return Response(404, nil),nil
+
+	// TODO: Uncomment the next line to return response Response(500, {}) or use other options such as http.Ok ...
+	// return Response(500, nil),nil
+
+	return Response(http.StatusNotImplemented, nil), errors.New("DeleteActionCheckin method not implemented")
+}
+
+// GetActionGains - 行動に紐づくGainの一覧を取得
+func (s *ActionsAPIService) GetActionGains(ctx context.Context, actionId int64) (ImplResponse, error) {
+	// TODO - update GetActionGains with the required logic for this service method.
+	// Add api_actions_service.go to the .openapi-generator-ignore to avoid overwriting this service implementation when updating open api generation.
+
+	// TODO: Uncomment the next line to return response Response(200, []Gain{}) or use other options such as http.Ok ...
+	// return Response(200, []Gain{}), nil
+
+	// TODO: Uncomment the next line to return response Response(401, Error{}) or use other options such as http.Ok ...
+	// return Response(401, Error{}), nil
+
+	// TODO: Uncomment the next line to return response Response(404, {}) or use other options such as http.Ok ...
+	// return Response(404, nil),nil
+
+	// TODO: Uncomment the next line to return response Response(500, {}) or use other options such as http.Ok ...
+	// return Response(500, nil),nil
+
+	return Response(http.StatusNotImplemented, nil), errors.New("GetActionGains method not implemented")
+}
+
+// CreateActionGain - 行動にGainを追加
+func (s *ActionsAPIService) CreateActionGain(ctx context.Context, actionId int64, gainInput GainInput) (ImplResponse, error) {
+	// TODO - update CreateActionGain with the required logic for this service method.
+	// Add api_actions_service.go to the .openapi-generator-ignore to avoid overwriting this service implementation when updating open api generation.
+
+	// TODO: Uncomment the next line to return response Response(201, Gain{}) or use other options such as http.Ok ...
+	// return Response(201, Gain{}), nil
+
+	// TODO: Uncomment the next line to return response Response(400, Error{}) or use other options such as http.Ok ...
+	// return Response(400, Error{}), nil
+
+	// TODO: Uncomment the next line to return response Response(401, Error{}) or use other options such as http.Ok ...
+	// return Response(401, Error{}), nil
+
+	// TODO: Uncomment the next line to return response Response(404, {}) or use other options such as http.Ok ...
+	// return Response(404, nil),nil
+
+	// TODO: Uncomment the next line to return response Response(500, {}) or use other options such as http.Ok ...
+	// return Response(500, nil),nil
+
+	return Response(http.StatusNotImplemented, nil), errors.New("CreateActionGain method not implemented")
+}
+
+// UpdateActionGain - 行動に紐づくGainを更新
+func (s *ActionsAPIService) UpdateActionGain(ctx context.Context, actionId int64, gainId int64, gainInput GainInput) (ImplResponse, error) {
+	// TODO - update UpdateActionGain with the required logic for this service method.
+	// Add api_actions_service.go to the .openapi-generator-ignore to avoid overwriting this service implementation when updating open api generation.
+
+	// TODO: Uncomment the next line to return response Response(200, Gain{}) or use other options such as http.Ok ...
+	// return Response(200, Gain{}), nil
+
+	// TODO: Uncomment the next line to return response Response(400, Error{}) or use other options such as http.Ok ...
+	// return Response(400, Error{}), nil
+
+	// TODO: Uncomment the next line to return response Response(401, Error{}) or use other options such as http.Ok ...
+	// return Response(401, Error{}), nil
+
+	// TODO: Uncomment the next line to return response Response(404, {}) or use other options such as http.Ok ...
+	// return Response(404, nil),nil
+
+	// TODO: Uncomment the next line to return response Response(500, {}) or use other options such as http.Ok ...
+	// return Response(500, nil),nil
+
+	return Response(http.StatusNotImplemented, nil), errors.New("UpdateActionGain method not implemented")
+}
+
+// DeleteActionGain - 行動に紐づくGainを削除
+func (s *ActionsAPIService) DeleteActionGain(ctx context.Context, actionId int64, gainId int64) (ImplResponse, error) {
+	// TODO - update DeleteActionGain with the required logic for this service method.
+	// Add api_actions_service.go to the .openapi-generator-ignore to avoid overwriting this service implementation when updating open api generation.
+
+	// TODO: Uncomment the next line to return response Response(204, {}) or use other options such as http.Ok ...
+	// return Response(204, nil),nil
+
+	// TODO: Uncomment the next line to return response Response(401, Error{}) or use other options such as http.Ok ...
+	// return Response(401, Error{}), nil
+
+	// TODO: Uncomment the next line to return response Response(404, {}) or use other options such as http.Ok ...
+	// return Response(404, nil),nil
+
+	// TODO: Uncomment the next line to return response Response(500, {}) or use other options such as http.Ok ...
+	// return Response(500, nil),nil
+
+	return Response(http.StatusNotImplemented, nil), errors.New("DeleteActionGain method not implemented")
+}
+
+// GetActionLosses - 行動に紐づくLossの一覧を取得
+func (s *ActionsAPIService) GetActionLosses(ctx context.Context, actionId int64) (ImplResponse, error) {
+	// TODO - update GetActionLosses with the required logic for this service method.
+	// Add api_actions_service.go to the .openapi-generator-ignore to avoid overwriting this service implementation when updating open api generation.
+
+	// TODO: Uncomment the next line to return response Response(200, []Loss{}) or use other options such as http.Ok ...
+	// return Response(200, []Loss{}), nil
+
+	// TODO: Uncomment the next line to return response Response(401, Error{}) or use other options such as http.Ok ...
+	// return Response(401, Error{}), nil
+
+	// TODO: Uncomment the next line to return response Response(404, {}) or use other options such as http.Ok ...
+	// return Response(404, nil),nil
+
+	// TODO: Uncomment the next line to return response Response(500, {}) or use other options such as http.Ok ...
+	// return Response(500, nil),nil
+
+	return Response(http.StatusNotImplemented, nil), errors.New("GetActionLosses method not implemented")
+}
+
+// CreateActionLoss - 行動にLossを追加
+func (s *ActionsAPIService) CreateActionLoss(ctx context.Context, actionId int64, lossInput LossInput) (ImplResponse, error) {
+	// TODO - update CreateActionLoss with the required logic for this service method.
+	// Add api_actions_service.go to the .openapi-generator-ignore to avoid overwriting this service implementation when updating open api generation.
+
+	// TODO: Uncomment the next line to return response Response(201, Loss{}) or use other options such as http.Ok ...
+	// return Response(201, Loss{}), nil
+
+	// TODO: Uncomment the next line to return response Response(400, Error{}) or use other options such as http.Ok ...
+	// return Response(400, Error{}), nil
+
+	// TODO: Uncomment the next line to return response Response(401, Error{}) or use other options such as http.Ok ...
+	// return Response(401, Error{}), nil
+
+	// TODO: Uncomment the next line to return response Response(404, {}) or use other options such as http.Ok ...
+	// return Response(404, nil),nil
+
+	// TODO: Uncomment the next line to return response Response(500, {}) or use other options such as http.Ok ...
+	// return Response(500, nil),nil
+
+	return Response(http.StatusNotImplemented, nil), errors.New("CreateActionLoss method not implemented")
+}
+
+// UpdateActionLoss - 行動に紐づくLossを更新
+func (s *ActionsAPIService) UpdateActionLoss(ctx context.Context, actionId int64, lossId int64, lossInput LossInput) (ImplResponse, error) {
+	// TODO - update UpdateActionLoss with the required logic for this service method.
+	// Add api_actions_service.go to the .openapi-generator-ignore to avoid overwriting this service implementation when updating open api generation.
+
+	// TODO: Uncomment the next line to return response Response(200, Loss{}) or use other options such as http.Ok ...
+	// return Response(200, Loss{}), nil
+
+	// TODO: Uncomment the next line to return response Response(400, Error{}) or use other options such as http.Ok ...
+	// return Response(400, Error{}), nil
+
+	// TODO: Uncomment the next line to return response Response(401, Error{}) or use other options such as http.Ok ...
+	// return Response(401, Error{}), nil
+
+	// TODO: Uncomment the next line to return response Response(404, {}) or use other options such as http.Ok ...
+	// return Response(404, nil),nil
+
+	// TODO: Uncomment the next line to return response Response(500, {}) or use other options such as http.Ok ...
+	// return Response(500, nil),nil
+
+	return Response(http.StatusNotImplemented, nil), errors.New("UpdateActionLoss method not implemented")
+}
+
+// DeleteActionLoss - 行動に紐づくLossを削除
+func (s *ActionsAPIService) DeleteActionLoss(ctx context.Context, actionId int64, lossId int64) (ImplResponse, error) {
+	// TODO - update DeleteActionLoss with the required logic for this service method.
+	// Add api_actions_service.go to the .openapi-generator-ignore to avoid overwriting this service implementation when updating open api generation.
+
+	// TODO: Uncomment the next line to return response Response(204, {}) or use other options such as http.Ok ...
+	// return Response(204, nil),nil
+
+	// TODO: Uncomment the next line to return response Response(401, Error{}) or use other options such as http.Ok ...
+	// return Response(401, Error{}), nil
+
+	// TODO: Uncomment the next line to return response Response(404, {}) or use other options such as http.Ok ...
+	// return Response(404, nil),nil
+
+	// TODO: Uncomment the next line to return response Response(500, {}) or use other options such as http.Ok ...
+	// return Response(500, nil),nil
+
+	return Response(http.StatusNotImplemented, nil), errors.New("DeleteActionLoss method not implemented")
+}
+
+// GetGainLossSummary - 定量的なGain/Lossを単位・目標・期間ごとに集計
+func (s *ActionsAPIService) GetGainLossSummary(ctx context.Context, goalId int64, kind string, unit string, bucket string, from string, to string, tz string) (ImplResponse, error) {
+	// TODO - update GetGainLossSummary with the required logic for this service method.
+	// Add api_actions_service.go to the .openapi-generator-ignore to avoid overwriting this service implementation when updating open api generation.
+
+	// TODO: Uncomment the next line to return response Response(200, []GainLossSummary{}) or use other options such as http.Ok ...
+	// return Response(200, []GainLossSummary{}), nil
+
+	// TODO: Uncomment the next line to return response Response(400, Error{}) or use other options such as http.Ok ...
+	// return Response(400, Error{}), nil
+
+	// TODO: Uncomment the next line to return response Response(401, Error{}) or use other options such as http.Ok ...
+	// return Response(401, Error{}), nil
+
+	// TODO: Uncomment the next line to return response Response(404, {}) or use other options such as http.Ok ...
+	// return Response(404, nil),nil
+
+	// TODO: Uncomment the next line to return response Response(500, {}) or use other options such as http.Ok ...
+	// return Response(500, nil),nil
+
+	return Response(http.StatusNotImplemented, nil), errors.New("GetGainLossSummary method not implemented")
+}
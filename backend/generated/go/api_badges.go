@@ -11,6 +11,7 @@
 package refuelapi
 
 import (
+	"encoding/json"
 	"net/http"
 	"strings"
 )
@@ -53,6 +54,16 @@ func (c *BadgesAPIController) Routes() Routes {
 			"/api/v1/badges",
 			c.GetBadges,
 		},
+		"CreateBadge": Route{
+			strings.ToUpper("Post"),
+			"/api/v1/badges",
+			c.CreateBadge,
+		},
+		"DryRunBadgeRule": Route{
+			strings.ToUpper("Post"),
+			"/api/v1/admin/badges/dry-run",
+			c.DryRunBadgeRule,
+		},
 	}
 }
 
@@ -65,5 +76,59 @@ func (c *BadgesAPIController) GetBadges(w http.ResponseWriter, r *http.Request)
 		return
 	}
 	// If no error, encode the body and the result code
-	_ = EncodeJSONResponse(result.Body, &result.Code, w)
+	_ = EncodeJSONResponse(result.Body, &result.Code, result.Headers, w)
+}
+
+// CreateBadge - 新しいバッジを登録 (管理者のみ)
+func (c *BadgesAPIController) CreateBadge(w http.ResponseWriter, r *http.Request) {
+	var badgeInputParam BadgeInput
+	d := json.NewDecoder(r.Body)
+	d.DisallowUnknownFields()
+	if err := d.Decode(&badgeInputParam); err != nil {
+		c.errorHandler(w, r, &ParsingError{Err: err}, nil)
+		return
+	}
+	if err := AssertBadgeInputRequired(badgeInputParam); err != nil {
+		c.errorHandler(w, r, err, nil)
+		return
+	}
+	if err := AssertBadgeInputConstraints(badgeInputParam); err != nil {
+		c.errorHandler(w, r, err, nil)
+		return
+	}
+	result, err := c.service.CreateBadge(r.Context(), badgeInputParam)
+	// If an error occurred, encode the error with the status code
+	if err != nil {
+		c.errorHandler(w, r, err, &result)
+		return
+	}
+	// If no error, encode the body and the result code
+	_ = EncodeJSONResponse(result.Body, &result.Code, result.Headers, w)
+}
+
+// DryRunBadgeRule - バッジのルール式をユーザーの行動履歴に対して試験評価 (管理者のみ)
+func (c *BadgesAPIController) DryRunBadgeRule(w http.ResponseWriter, r *http.Request) {
+	var badgeRuleDryRunInputParam BadgeRuleDryRunInput
+	d := json.NewDecoder(r.Body)
+	d.DisallowUnknownFields()
+	if err := d.Decode(&badgeRuleDryRunInputParam); err != nil {
+		c.errorHandler(w, r, &ParsingError{Err: err}, nil)
+		return
+	}
+	if err := AssertBadgeRuleDryRunInputRequired(badgeRuleDryRunInputParam); err != nil {
+		c.errorHandler(w, r, err, nil)
+		return
+	}
+	if err := AssertBadgeRuleDryRunInputConstraints(badgeRuleDryRunInputParam); err != nil {
+		c.errorHandler(w, r, err, nil)
+		return
+	}
+	result, err := c.service.DryRunBadgeRule(r.Context(), badgeRuleDryRunInputParam)
+	// If an error occurred, encode the error with the status code
+	if err != nil {
+		c.errorHandler(w, r, err, &result)
+		return
+	}
+	// If no error, encode the body and the result code
+	_ = EncodeJSONResponse(result.Body, &result.Code, result.Headers, w)
 }
@@ -40,3 +40,46 @@ func (s *BadgesAPIService) GetBadges(ctx context.Context) (ImplResponse, error)
 
 	return Response(http.StatusNotImplemented, nil), errors.New("GetBadges method not implemented")
 }
+
+// CreateBadge - 新しいバッジを登録 (管理者のみ)
+func (s *BadgesAPIService) CreateBadge(ctx context.Context, badgeInput BadgeInput) (ImplResponse, error) {
+	// TODO - update CreateBadge with the required logic for this service method.
+	// Add api_badges_service.go to the .openapi-generator-ignore to avoid overwriting this service implementation when updating open api generation.
+
+	// TODO: Uncomment the next line to return response Response(201, Badge{}) or use other options such as http.Ok ...
+	// return Response(201, Badge{}), nil
+
+	// TODO: Uncomment the next line to return response Response(400, Error{}) or use other options such as http.Ok ...
+	// return Response(400, Error{}), nil
+
+	// TODO: Uncomment the next line to return response Response(403, Error{}) or use other options such as http.Ok ...
+	// return Response(403, Error{}), nil
+
+	// TODO: Uncomment the next line to return response Response(409, Error{}) or use other options such as http.Ok ...
+	// return Response(409, Error{}), nil
+
+	// TODO: Uncomment the next line to return response Response(500, Error{}) or use other options such as http.Ok ...
+	// return Response(500, Error{}), nil
+
+	return Response(http.StatusNotImplemented, nil), errors.New("CreateBadge method not implemented")
+}
+
+// DryRunBadgeRule - バッジのルール式をユーザーの行動履歴に対して試験評価 (管理者のみ)
+func (s *BadgesAPIService) DryRunBadgeRule(ctx context.Context, badgeRuleDryRunInput BadgeRuleDryRunInput) (ImplResponse, error) {
+	// TODO - update DryRunBadgeRule with the required logic for this service method.
+	// Add api_badges_service.go to the .openapi-generator-ignore to avoid overwriting this service implementation when updating open api generation.
+
+	// TODO: Uncomment the next line to return response Response(200, BadgeRuleDryRunResult{}) or use other options such as http.Ok ...
+	// return Response(200, BadgeRuleDryRunResult{}), nil
+
+	// TODO: Uncomment the next line to return response Response(400, Error{}) or use other options such as http.Ok ...
+	// return Response(400, Error{}), nil
+
+	// TODO: Uncomment the next line to return response Response(403, Error{}) or use other options such as http.Ok ...
+	// return Response(403, Error{}), nil
+
+	// TODO: Uncomment the next line to return response Response(500, Error{}) or use other options such as http.Ok ...
+	// return Response(500, Error{}), nil
+
+	return Response(http.StatusNotImplemented, nil), errors.New("DryRunBadgeRule method not implemented")
+}
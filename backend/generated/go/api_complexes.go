@@ -76,19 +76,57 @@ func (c *ComplexesAPIController) Routes() Routes {
 			"/api/v1/complexes/{complexId}",
 			c.DeleteComplex,
 		},
+		"GetComplexHistory": Route{
+			strings.ToUpper("Get"),
+			"/api/v1/complexes/{complexId}/history",
+			c.GetComplexHistory,
+		},
 	}
 }
 
 // GetComplexes - 登録されているコンプレックスの一覧を取得
 func (c *ComplexesAPIController) GetComplexes(w http.ResponseWriter, r *http.Request) {
-	result, err := c.service.GetComplexes(r.Context())
+	query, err := parseQuery(r.URL.RawQuery)
+	if err != nil {
+		c.errorHandler(w, r, &ParsingError{Err: err}, nil)
+		return
+	}
+	var limitParam int32
+	if query.Has("limit") {
+		param, err := parseNumericParameter[int32](
+			query.Get("limit"),
+			WithParse[int32](parseInt32),
+		)
+		if err != nil {
+			c.errorHandler(w, r, &ParsingError{Param: "limit", Err: err}, nil)
+			return
+		}
+
+		limitParam = param
+	}
+	var cursorParam string
+	if query.Has("cursor") {
+		param := query.Get("cursor")
+		cursorParam = param
+	}
+	var sortParam string
+	if query.Has("sort") {
+		param := query.Get("sort")
+		sortParam = param
+	}
+	var categoryParam string
+	if query.Has("category") {
+		param := query.Get("category")
+		categoryParam = param
+	}
+	result, err := c.service.GetComplexes(r.Context(), limitParam, cursorParam, sortParam, categoryParam)
 	// If an error occurred, encode the error with the status code
 	if err != nil {
 		c.errorHandler(w, r, err, &result)
 		return
 	}
 	// If no error, encode the body and the result code
-	_ = EncodeJSONResponse(result.Body, &result.Code, w)
+	_ = EncodeJSONResponse(result.Body, &result.Code, result.Headers, w)
 }
 
 // CreateComplex - 新しいコンプレックスを登録
@@ -115,7 +153,7 @@ func (c *ComplexesAPIController) CreateComplex(w http.ResponseWriter, r *http.Re
 		return
 	}
 	// If no error, encode the body and the result code
-	_ = EncodeJSONResponse(result.Body, &result.Code, w)
+	_ = EncodeJSONResponse(result.Body, &result.Code, result.Headers, w)
 }
 
 // GetComplex - 指定されたIDのコンプレックス情報を取得します。
@@ -136,7 +174,7 @@ func (c *ComplexesAPIController) GetComplex(w http.ResponseWriter, r *http.Reque
 		return
 	}
 	// If no error, encode the body and the result code
-	_ = EncodeJSONResponse(result.Body, &result.Code, w)
+	_ = EncodeJSONResponse(result.Body, &result.Code, result.Headers, w)
 }
 
 // UpdateComplex - 既存のコンプレックス情報を更新します。
@@ -172,7 +210,7 @@ func (c *ComplexesAPIController) UpdateComplex(w http.ResponseWriter, r *http.Re
 		return
 	}
 	// If no error, encode the body and the result code
-	_ = EncodeJSONResponse(result.Body, &result.Code, w)
+	_ = EncodeJSONResponse(result.Body, &result.Code, result.Headers, w)
 }
 
 // DeleteComplex - 既存のコンプレックスを削除します。
@@ -193,5 +231,26 @@ func (c *ComplexesAPIController) DeleteComplex(w http.ResponseWriter, r *http.Re
 		return
 	}
 	// If no error, encode the body and the result code
-	_ = EncodeJSONResponse(result.Body, &result.Code, w)
+	_ = EncodeJSONResponse(result.Body, &result.Code, result.Headers, w)
+}
+
+// GetComplexHistory - コンプレックスの変更履歴を取得
+func (c *ComplexesAPIController) GetComplexHistory(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	complexIdParam, err := parseNumericParameter[int64](
+		params["complexId"],
+		WithRequire[int64](parseInt64),
+	)
+	if err != nil {
+		c.errorHandler(w, r, &ParsingError{Param: "complexId", Err: err}, nil)
+		return
+	}
+	result, err := c.service.GetComplexHistory(r.Context(), complexIdParam)
+	// If an error occurred, encode the error with the status code
+	if err != nil {
+		c.errorHandler(w, r, err, &result)
+		return
+	}
+	// If no error, encode the body and the result code
+	_ = EncodeJSONResponse(result.Body, &result.Code, result.Headers, w)
 }
@@ -28,13 +28,16 @@ func NewComplexesAPIService() *ComplexesAPIService {
 }
 
 // GetComplexes - 登録されているコンプレックスの一覧を取得
-func (s *ComplexesAPIService) GetComplexes(ctx context.Context) (ImplResponse, error) {
+func (s *ComplexesAPIService) GetComplexes(ctx context.Context, limit int32, cursor string, sort string, category string) (ImplResponse, error) {
 	// TODO - update GetComplexes with the required logic for this service method.
 	// Add api_complexes_service.go to the .openapi-generator-ignore to avoid overwriting this service implementation when updating open api generation.
 
 	// TODO: Uncomment the next line to return response Response(200, []Complex{}) or use other options such as http.Ok ...
 	// return Response(200, []Complex{}), nil
 
+	// TODO: Uncomment the next line to return response Response(400, Error{}) or use other options such as http.Ok ...
+	// return Response(400, Error{}), nil
+
 	// TODO: Uncomment the next line to return response Response(401, Error{}) or use other options such as http.Ok ...
 	// return Response(401, Error{}), nil
 
@@ -114,3 +117,23 @@ func (s *ComplexesAPIService) DeleteComplex(ctx context.Context, complexId int64
 
 	return Response(http.StatusNotImplemented, nil), errors.New("DeleteComplex method not implemented")
 }
+
+// GetComplexHistory - コンプレックスの変更履歴を取得
+func (s *ComplexesAPIService) GetComplexHistory(ctx context.Context, complexId int64) (ImplResponse, error) {
+	// TODO - update GetComplexHistory with the required logic for this service method.
+	// Add api_complexes_service.go to the .openapi-generator-ignore to avoid overwriting this service implementation when updating open api generation.
+
+	// TODO: Uncomment the next line to return response Response(200, []Revision{}) or use other options such as http.Ok ...
+	// return Response(200, []Revision{}), nil
+
+	// TODO: Uncomment the next line to return response Response(401, Error{}) or use other options such as http.Ok ...
+	// return Response(401, Error{}), nil
+
+	// TODO: Uncomment the next line to return response Response(404, Error{}) or use other options such as http.Ok ...
+	// return Response(404, Error{}), nil
+
+	// TODO: Uncomment the next line to return response Response(500, Error{}) or use other options such as http.Ok ...
+	// return Response(500, Error{}), nil
+
+	return Response(http.StatusNotImplemented, nil), errors.New("GetComplexHistory method not implemented")
+}
@@ -76,19 +76,85 @@ func (c *GoalsAPIController) Routes() Routes {
 			"/api/v1/goals/{goalId}",
 			c.DeleteGoal,
 		},
+		"GetGoalMeasurements": Route{
+			strings.ToUpper("Get"),
+			"/api/v1/goals/{goalId}/measurements",
+			c.GetGoalMeasurements,
+		},
+		"CreateGoalMeasurement": Route{
+			strings.ToUpper("Post"),
+			"/api/v1/goals/{goalId}/measurements",
+			c.CreateGoalMeasurement,
+		},
+		"DeleteGoalMeasurement": Route{
+			strings.ToUpper("Delete"),
+			"/api/v1/goals/{goalId}/measurements/{measurementId}",
+			c.DeleteGoalMeasurement,
+		},
+		"GetGoalMilestones": Route{
+			strings.ToUpper("Get"),
+			"/api/v1/goals/{goalId}/milestones",
+			c.GetGoalMilestones,
+		},
+		"GenerateGoalMilestones": Route{
+			strings.ToUpper("Post"),
+			"/api/v1/goals/{goalId}/milestones",
+			c.GenerateGoalMilestones,
+		},
 	}
 }
 
 // GetGoals - 登録されている目標の一覧を取得
 func (c *GoalsAPIController) GetGoals(w http.ResponseWriter, r *http.Request) {
-	result, err := c.service.GetGoals(r.Context())
+	query, err := parseQuery(r.URL.RawQuery)
+	if err != nil {
+		c.errorHandler(w, r, &ParsingError{Err: err}, nil)
+		return
+	}
+	var limitParam int32
+	if query.Has("limit") {
+		param, err := parseNumericParameter[int32](
+			query.Get("limit"),
+			WithParse[int32](parseInt32),
+		)
+		if err != nil {
+			c.errorHandler(w, r, &ParsingError{Param: "limit", Err: err}, nil)
+			return
+		}
+
+		limitParam = param
+	}
+	var cursorParam string
+	if query.Has("cursor") {
+		param := query.Get("cursor")
+		cursorParam = param
+	}
+	var sortParam string
+	if query.Has("sort") {
+		param := query.Get("sort")
+		sortParam = param
+	}
+	var complexIdParam int64
+	if query.Has("complex_id") {
+		param, err := parseNumericParameter[int64](
+			query.Get("complex_id"),
+			WithParse[int64](parseInt64),
+		)
+		if err != nil {
+			c.errorHandler(w, r, &ParsingError{Param: "complex_id", Err: err}, nil)
+			return
+		}
+
+		complexIdParam = param
+	}
+	result, err := c.service.GetGoals(r.Context(), limitParam, cursorParam, sortParam, complexIdParam)
 	// If an error occurred, encode the error with the status code
 	if err != nil {
 		c.errorHandler(w, r, err, &result)
 		return
 	}
 	// If no error, encode the body and the result code
-	_ = EncodeJSONResponse(result.Body, &result.Code, w)
+	_ = EncodeJSONResponse(result.Body, &result.Code, result.Headers, w)
 }
 
 // CreateGoal - 新しい目標を登録
@@ -115,7 +181,7 @@ func (c *GoalsAPIController) CreateGoal(w http.ResponseWriter, r *http.Request)
 		return
 	}
 	// If no error, encode the body and the result code
-	_ = EncodeJSONResponse(result.Body, &result.Code, w)
+	_ = EncodeJSONResponse(result.Body, &result.Code, result.Headers, w)
 }
 
 // GetGoal - 指定されたIDの目標情報を取得
@@ -129,14 +195,24 @@ func (c *GoalsAPIController) GetGoal(w http.ResponseWriter, r *http.Request) {
 		c.errorHandler(w, r, &ParsingError{Param: "goalId", Err: err}, nil)
 		return
 	}
-	result, err := c.service.GetGoal(r.Context(), goalIdParam)
+	query, err := parseQuery(r.URL.RawQuery)
+	if err != nil {
+		c.errorHandler(w, r, &ParsingError{Err: err}, nil)
+		return
+	}
+	var tzParam string
+	if query.Has("tz") {
+		param := query.Get("tz")
+		tzParam = param
+	}
+	result, err := c.service.GetGoal(r.Context(), goalIdParam, tzParam)
 	// If an error occurred, encode the error with the status code
 	if err != nil {
 		c.errorHandler(w, r, err, &result)
 		return
 	}
 	// If no error, encode the body and the result code
-	_ = EncodeJSONResponse(result.Body, &result.Code, w)
+	_ = EncodeJSONResponse(result.Body, &result.Code, result.Headers, w)
 }
 
 // UpdateGoal - 既存の目標情報を更新
@@ -172,7 +248,7 @@ func (c *GoalsAPIController) UpdateGoal(w http.ResponseWriter, r *http.Request)
 		return
 	}
 	// If no error, encode the body and the result code
-	_ = EncodeJSONResponse(result.Body, &result.Code, w)
+	_ = EncodeJSONResponse(result.Body, &result.Code, result.Headers, w)
 }
 
 // DeleteGoal - 既存の目標を削除
@@ -193,5 +269,148 @@ func (c *GoalsAPIController) DeleteGoal(w http.ResponseWriter, r *http.Request)
 		return
 	}
 	// If no error, encode the body and the result code
-	_ = EncodeJSONResponse(result.Body, &result.Code, w)
+	_ = EncodeJSONResponse(result.Body, &result.Code, result.Headers, w)
+}
+
+// GetGoalMeasurements - 目標の計測記録の一覧を取得
+func (c *GoalsAPIController) GetGoalMeasurements(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	goalIdParam, err := parseNumericParameter[int64](
+		params["goalId"],
+		WithRequire[int64](parseInt64),
+	)
+	if err != nil {
+		c.errorHandler(w, r, &ParsingError{Param: "goalId", Err: err}, nil)
+		return
+	}
+	result, err := c.service.GetGoalMeasurements(r.Context(), goalIdParam)
+	// If an error occurred, encode the error with the status code
+	if err != nil {
+		c.errorHandler(w, r, err, &result)
+		return
+	}
+	// If no error, encode the body and the result code
+	_ = EncodeJSONResponse(result.Body, &result.Code, result.Headers, w)
+}
+
+// CreateGoalMeasurement - 目標に計測記録を追加
+func (c *GoalsAPIController) CreateGoalMeasurement(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	goalIdParam, err := parseNumericParameter[int64](
+		params["goalId"],
+		WithRequire[int64](parseInt64),
+	)
+	if err != nil {
+		c.errorHandler(w, r, &ParsingError{Param: "goalId", Err: err}, nil)
+		return
+	}
+	var goalMeasurementInputParam GoalMeasurementInput
+	d := json.NewDecoder(r.Body)
+	d.DisallowUnknownFields()
+	if err := d.Decode(&goalMeasurementInputParam); err != nil {
+		c.errorHandler(w, r, &ParsingError{Err: err}, nil)
+		return
+	}
+	if err := AssertGoalMeasurementInputRequired(goalMeasurementInputParam); err != nil {
+		c.errorHandler(w, r, err, nil)
+		return
+	}
+	if err := AssertGoalMeasurementInputConstraints(goalMeasurementInputParam); err != nil {
+		c.errorHandler(w, r, err, nil)
+		return
+	}
+	result, err := c.service.CreateGoalMeasurement(r.Context(), goalIdParam, goalMeasurementInputParam)
+	// If an error occurred, encode the error with the status code
+	if err != nil {
+		c.errorHandler(w, r, err, &result)
+		return
+	}
+	// If no error, encode the body and the result code
+	_ = EncodeJSONResponse(result.Body, &result.Code, result.Headers, w)
+}
+
+// DeleteGoalMeasurement - 目標の計測記録を削除
+func (c *GoalsAPIController) DeleteGoalMeasurement(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	goalIdParam, err := parseNumericParameter[int64](
+		params["goalId"],
+		WithRequire[int64](parseInt64),
+	)
+	if err != nil {
+		c.errorHandler(w, r, &ParsingError{Param: "goalId", Err: err}, nil)
+		return
+	}
+	measurementIdParam, err := parseNumericParameter[int64](
+		params["measurementId"],
+		WithRequire[int64](parseInt64),
+	)
+	if err != nil {
+		c.errorHandler(w, r, &ParsingError{Param: "measurementId", Err: err}, nil)
+		return
+	}
+	result, err := c.service.DeleteGoalMeasurement(r.Context(), goalIdParam, measurementIdParam)
+	// If an error occurred, encode the error with the status code
+	if err != nil {
+		c.errorHandler(w, r, err, &result)
+		return
+	}
+	// If no error, encode the body and the result code
+	_ = EncodeJSONResponse(result.Body, &result.Code, result.Headers, w)
+}
+
+// GetGoalMilestones - 目標のマイルストーンの一覧を取得
+func (c *GoalsAPIController) GetGoalMilestones(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	goalIdParam, err := parseNumericParameter[int64](
+		params["goalId"],
+		WithRequire[int64](parseInt64),
+	)
+	if err != nil {
+		c.errorHandler(w, r, &ParsingError{Param: "goalId", Err: err}, nil)
+		return
+	}
+	result, err := c.service.GetGoalMilestones(r.Context(), goalIdParam)
+	// If an error occurred, encode the error with the status code
+	if err != nil {
+		c.errorHandler(w, r, err, &result)
+		return
+	}
+	// If no error, encode the body and the result code
+	_ = EncodeJSONResponse(result.Body, &result.Code, result.Headers, w)
+}
+
+// GenerateGoalMilestones - 最終目標から逆算してマイルストーンを作成
+func (c *GoalsAPIController) GenerateGoalMilestones(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	goalIdParam, err := parseNumericParameter[int64](
+		params["goalId"],
+		WithRequire[int64](parseInt64),
+	)
+	if err != nil {
+		c.errorHandler(w, r, &ParsingError{Param: "goalId", Err: err}, nil)
+		return
+	}
+	var milestonePlanInputParam MilestonePlanInput
+	d := json.NewDecoder(r.Body)
+	d.DisallowUnknownFields()
+	if err := d.Decode(&milestonePlanInputParam); err != nil {
+		c.errorHandler(w, r, &ParsingError{Err: err}, nil)
+		return
+	}
+	if err := AssertMilestonePlanInputRequired(milestonePlanInputParam); err != nil {
+		c.errorHandler(w, r, err, nil)
+		return
+	}
+	if err := AssertMilestonePlanInputConstraints(milestonePlanInputParam); err != nil {
+		c.errorHandler(w, r, err, nil)
+		return
+	}
+	result, err := c.service.GenerateGoalMilestones(r.Context(), goalIdParam, milestonePlanInputParam)
+	// If an error occurred, encode the error with the status code
+	if err != nil {
+		c.errorHandler(w, r, err, &result)
+		return
+	}
+	// If no error, encode the body and the result code
+	_ = EncodeJSONResponse(result.Body, &result.Code, result.Headers, w)
 }
@@ -28,13 +28,16 @@ func NewGoalsAPIService() *GoalsAPIService {
 }
 
 // GetGoals - 登録されている目標の一覧を取得
-func (s *GoalsAPIService) GetGoals(ctx context.Context) (ImplResponse, error) {
+func (s *GoalsAPIService) GetGoals(ctx context.Context, limit int32, cursor string, sort string, complexId int64) (ImplResponse, error) {
 	// TODO - update GetGoals with the required logic for this service method.
 	// Add api_goals_service.go to the .openapi-generator-ignore to avoid overwriting this service implementation when updating open api generation.
 
 	// TODO: Uncomment the next line to return response Response(200, []Goal{}) or use other options such as http.Ok ...
 	// return Response(200, []Goal{}), nil
 
+	// TODO: Uncomment the next line to return response Response(400, Error{}) or use other options such as http.Ok ...
+	// return Response(400, Error{}), nil
+
 	// TODO: Uncomment the next line to return response Response(401, Error{}) or use other options such as http.Ok ...
 	// return Response(401, Error{}), nil
 
@@ -65,7 +68,7 @@ func (s *GoalsAPIService) CreateGoal(ctx context.Context, goalInput GoalInput) (
 }
 
 // GetGoal - 指定されたIDの目標情報を取得
-func (s *GoalsAPIService) GetGoal(ctx context.Context, goalId int64) (ImplResponse, error) {
+func (s *GoalsAPIService) GetGoal(ctx context.Context, goalId int64, tz string) (ImplResponse, error) {
 	// TODO - update GetGoal with the required logic for this service method.
 	// Add api_goals_service.go to the .openapi-generator-ignore to avoid overwriting this service implementation when updating open api generation.
 
@@ -126,3 +129,109 @@ func (s *GoalsAPIService) DeleteGoal(ctx context.Context, goalId int64) (ImplRes
 
 	return Response(http.StatusNotImplemented, nil), errors.New("DeleteGoal method not implemented")
 }
+
+// GetGoalMeasurements - 目標の計測記録の一覧を取得
+func (s *GoalsAPIService) GetGoalMeasurements(ctx context.Context, goalId int64) (ImplResponse, error) {
+	// TODO - update GetGoalMeasurements with the required logic for this service method.
+	// Add api_goals_service.go to the .openapi-generator-ignore to avoid overwriting this service implementation when updating open api generation.
+
+	// TODO: Uncomment the next line to return response Response(200, []GoalMeasurement{}) or use other options such as http.Ok ...
+	// return Response(200, []GoalMeasurement{}), nil
+
+	// TODO: Uncomment the next line to return response Response(401, Error{}) or use other options such as http.Ok ...
+	// return Response(401, Error{}), nil
+
+	// TODO: Uncomment the next line to return response Response(404, {}) or use other options such as http.Ok ...
+	// return Response(404, nil),nil
+
+	// TODO: Uncomment the next line to return response Response(500, {}) or use other options such as http.Ok ...
+	// return Response(500, nil),nil
+
+	return Response(http.StatusNotImplemented, nil), errors.New("GetGoalMeasurements method not implemented")
+}
+
+// CreateGoalMeasurement - 目標に計測記録を追加
+func (s *GoalsAPIService) CreateGoalMeasurement(ctx context.Context, goalId int64, goalMeasurementInput GoalMeasurementInput) (ImplResponse, error) {
+	// TODO - update CreateGoalMeasurement with the required logic for this service method.
+	// Add api_goals_service.go to the .openapi-generator-ignore to avoid overwriting this service implementation when updating open api generation.
+
+	// TODO: Uncomment the next line to return response Response(201, GoalMeasurement{}) or use other options such as http.Ok ...
+	// return Response(201, GoalMeasurement{}), nil
+
+	// TODO: Uncomment the next line to return response Response(400, Error{}) or use other options such as http.Ok ...
+	// return Response(400, Error{}), nil
+
+	// TODO: Uncomment the next line to return response Response(401, Error{}) or use other options such as http.Ok ...
+	// return Response(401, Error{}), nil
+
+	// TODO: Uncomment the next line to return response Response(404, {}) or use other options such as http.Ok ...
+	// return Response(404, nil),nil
+
+	// TODO: Uncomment the next line to return response Response(500, {}) or use other options such as http.Ok ...
+	// return Response(500, nil),nil
+
+	return Response(http.StatusNotImplemented, nil), errors.New("CreateGoalMeasurement method not implemented")
+}
+
+// DeleteGoalMeasurement - 目標の計測記録を削除
+func (s *GoalsAPIService) DeleteGoalMeasurement(ctx context.Context, goalId int64, measurementId int64) (ImplResponse, error) {
+	// TODO - update DeleteGoalMeasurement with the required logic for this service method.
+	// Add api_goals_service.go to the .openapi-generator-ignore to avoid overwriting this service implementation when updating open api generation.
+
+	// TODO: Uncomment the next line to return response Response(204, {}) or use other options such as http.Ok ...
+	// return Response(204, nil),nil
+
+	// TODO: Uncomment the next line to return response Response(401, Error{}) or use other options such as http.Ok ...
+	// return Response(401, Error{}), nil
+
+	// TODO: Uncomment the next line to return response Response(404, {}) or use other options such as http.Ok ...
+	// return Response(404, nil),nil
+
+	// TODO: Uncomment the next line to return response Response(500, {}) or use other options such as http.Ok ...
+	// return Response(500, nil),nil
+
+	return Response(http.StatusNotImplemented, nil), errors.New("DeleteGoalMeasurement method not implemented")
+}
+
+// GetGoalMilestones - 目標のマイルストーンの一覧を取得
+func (s *GoalsAPIService) GetGoalMilestones(ctx context.Context, goalId int64) (ImplResponse, error) {
+	// TODO - update GetGoalMilestones with the required logic for this service method.
+	// Add api_goals_service.go to the .openapi-generator-ignore to avoid overwriting this service implementation when updating open api generation.
+
+	// TODO: Uncomment the next line to return response Response(200, []Milestone{}) or use other options such as http.Ok ...
+	// return Response(200, []Milestone{}), nil
+
+	// TODO: Uncomment the next line to return response Response(401, Error{}) or use other options such as http.Ok ...
+	// return Response(401, Error{}), nil
+
+	// TODO: Uncomment the next line to return response Response(404, {}) or use other options such as http.Ok ...
+	// return Response(404, nil),nil
+
+	// TODO: Uncomment the next line to return response Response(500, {}) or use other options such as http.Ok ...
+	// return Response(500, nil),nil
+
+	return Response(http.StatusNotImplemented, nil), errors.New("GetGoalMilestones method not implemented")
+}
+
+// GenerateGoalMilestones - 最終目標から逆算してマイルストーンを作成
+func (s *GoalsAPIService) GenerateGoalMilestones(ctx context.Context, goalId int64, milestonePlanInput MilestonePlanInput) (ImplResponse, error) {
+	// TODO - update GenerateGoalMilestones with the required logic for this service method.
+	// Add api_goals_service.go to the .openapi-generator-ignore to avoid overwriting this service implementation when updating open api generation.
+
+	// TODO: Uncomment the next line to return response Response(201, []Milestone{}) or use other options such as http.Ok ...
+	// return Response(201, []Milestone{}), nil
+
+	// TODO: Uncomment the next line to return response Response(400, Error{}) or use other options such as http.Ok ...
+	// return Response(400, Error{}), nil
+
+	// TODO: Uncomment the next line to return response Response(401, Error{}) or use other options such as http.Ok ...
+	// return Response(401, Error{}), nil
+
+	// TODO: Uncomment the next line to return response Response(404, {}) or use other options such as http.Ok ...
+	// return Response(404, nil),nil
+
+	// TODO: Uncomment the next line to return response Response(500, {}) or use other options such as http.Ok ...
+	// return Response(500, nil),nil
+
+	return Response(http.StatusNotImplemented, nil), errors.New("GenerateGoalMilestones method not implemented")
+}
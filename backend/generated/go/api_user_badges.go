@@ -65,5 +65,5 @@ func (c *UserBadgesAPIController) GetUserBadges(w http.ResponseWriter, r *http.R
 		return
 	}
 	// If no error, encode the body and the result code
-	_ = EncodeJSONResponse(result.Body, &result.Code, w)
+	_ = EncodeJSONResponse(result.Body, &result.Code, result.Headers, w)
 }
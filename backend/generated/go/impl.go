@@ -12,6 +12,7 @@ package refuelapi
 
 // ImplResponse defines an implementation response with error code and the associated body
 type ImplResponse struct {
-	Code int
-	Body interface{}
+	Code    int
+	Headers map[string][]string
+	Body    interface{}
 }
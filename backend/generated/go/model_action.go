@@ -32,10 +32,10 @@ type Action struct {
 	Content string `json:"content"`
 
 	// 行動完了日時 (未完了の場合はnull)
-	CompletedAt time.Time `json:"completed_at"`
+	CompletedAt *time.Time `json:"completed_at"`
 
-	// 行動の繰り返しパターン
-	RecurrencePattern RecurrencePattern `json:"recurrence_pattern"`
+	// 行動の繰り返しパターン (繰り返さない場合はnull)
+	RecurrencePattern *RecurrencePattern `json:"recurrence_pattern"`
 
 	// この行動に紐づくGainのリスト
 	Gains []Gain `json:"gains"`
@@ -43,6 +43,9 @@ type Action struct {
 	// この行動に紐づくLossのリスト
 	Losses []Loss `json:"losses"`
 
+	// 行動を完了したときのフィードバック (作成・更新で完了にした応答のみ)
+	Feedback *ActionFeedback `json:"feedback,omitempty"`
+
 	CreatedAt time.Time `json:"created_at"`
 
 	UpdatedAt time.Time `json:"updated_at"`
@@ -55,7 +58,6 @@ func AssertActionRequired(obj Action) error {
 		"user_id": obj.UserId,
 		"goal_id": obj.GoalId,
 		"content": obj.Content,
-		"completed_at": obj.CompletedAt,
 		"recurrence_pattern": obj.RecurrencePattern,
 		"gains": obj.Gains,
 		"losses": obj.Losses,
@@ -68,8 +70,10 @@ func AssertActionRequired(obj Action) error {
 		}
 	}
 
-	if err := AssertRecurrencePatternRequired(obj.RecurrencePattern); err != nil {
-		return err
+	if obj.RecurrencePattern != nil {
+		if err := AssertRecurrencePatternRequired(*obj.RecurrencePattern); err != nil {
+			return err
+		}
 	}
 	for _, el := range obj.Gains {
 		if err := AssertGainRequired(el); err != nil {
@@ -81,13 +85,20 @@ func AssertActionRequired(obj Action) error {
 			return err
 		}
 	}
+	if obj.Feedback != nil {
+		if err := AssertActionFeedbackRequired(*obj.Feedback); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
 // AssertActionConstraints checks if the values respects the defined constraints
 func AssertActionConstraints(obj Action) error {
-	if err := AssertRecurrencePatternConstraints(obj.RecurrencePattern); err != nil {
-		return err
+	if obj.RecurrencePattern != nil {
+		if err := AssertRecurrencePatternConstraints(*obj.RecurrencePattern); err != nil {
+			return err
+		}
 	}
 	for _, el := range obj.Gains {
 		if err := AssertGainConstraints(el); err != nil {
@@ -99,5 +110,10 @@ func AssertActionConstraints(obj Action) error {
 			return err
 		}
 	}
+	if obj.Feedback != nil {
+		if err := AssertActionFeedbackConstraints(*obj.Feedback); err != nil {
+			return err
+		}
+	}
 	return nil
 }
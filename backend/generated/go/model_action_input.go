@@ -27,16 +27,19 @@ type ActionInput struct {
 	Content string `json:"content"`
 
 	// 行動完了日時 (記録時に完了していれば設定)
-	CompletedAt time.Time `json:"completed_at"`
+	CompletedAt *time.Time `json:"completed_at"`
 
-	// 行動の繰り返しパターン
-	RecurrencePattern RecurrencePattern `json:"recurrence_pattern"`
+	// 行動の繰り返しパターン (繰り返さない場合は省略)
+	RecurrencePattern *RecurrencePattern `json:"recurrence_pattern,omitempty"`
 
 	// この行動に紐づくGainの入力リスト
 	Gains []GainInput `json:"gains"`
 
 	// この行動に紐づくLossの入力リスト
 	Losses []LossInput `json:"losses"`
+
+	// 完了時のフィードバックとバッジの判定で日付を数えるタイムゾーン (IANA名、省略時はUTC)
+	Tz string `json:"tz,omitempty"`
 }
 
 // AssertActionInputRequired checks if the required fields are not zero-ed
@@ -44,8 +47,6 @@ func AssertActionInputRequired(obj ActionInput) error {
 	elements := map[string]interface{}{
 		"goal_id": obj.GoalId,
 		"content": obj.Content,
-		"completed_at": obj.CompletedAt,
-		"recurrence_pattern": obj.RecurrencePattern,
 		"gains": obj.Gains,
 		"losses": obj.Losses,
 	}
@@ -55,8 +56,10 @@ func AssertActionInputRequired(obj ActionInput) error {
 		}
 	}
 
-	if err := AssertRecurrencePatternRequired(obj.RecurrencePattern); err != nil {
-		return err
+	if obj.RecurrencePattern != nil {
+		if err := AssertRecurrencePatternRequired(*obj.RecurrencePattern); err != nil {
+			return err
+		}
 	}
 	for _, el := range obj.Gains {
 		if err := AssertGainInputRequired(el); err != nil {
@@ -73,8 +76,10 @@ func AssertActionInputRequired(obj ActionInput) error {
 
 // AssertActionInputConstraints checks if the values respects the defined constraints
 func AssertActionInputConstraints(obj ActionInput) error {
-	if err := AssertRecurrencePatternConstraints(obj.RecurrencePattern); err != nil {
-		return err
+	if obj.RecurrencePattern != nil {
+		if err := AssertRecurrencePatternConstraints(*obj.RecurrencePattern); err != nil {
+			return err
+		}
 	}
 	for _, el := range obj.Gains {
 		if err := AssertGainInputConstraints(el); err != nil {
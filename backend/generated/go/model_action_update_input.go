@@ -23,16 +23,61 @@ type ActionUpdateInput struct {
 	// 行動の内容 (変更する場合に指定)
 	Content string `json:"content,omitempty"`
 
-	// 行動完了日時 (完了にする場合は日時を、未完了に戻す場合はnullを指定)
-	CompletedAt time.Time `json:"completed_at,omitempty"`
+	// 行動完了日時 (完了にする場合に指定)。省略とnullはどちらも変更しないため、完了を取り消すことはできません
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+
+	// 行動の繰り返しパターン (変更する場合に指定)
+	RecurrencePattern *RecurrencePattern `json:"recurrence_pattern,omitempty"`
+
+	// この行動のGain (変更する場合に指定)。idを指定した要素は既存のGainを更新し、省略した要素は新規作成します
+	Gains []GainUpdateInput `json:"gains,omitempty"`
+
+	// この行動のLoss (変更する場合に指定)。idを指定した要素は既存のLossを更新し、省略した要素は新規作成します
+	Losses []LossUpdateInput `json:"losses,omitempty"`
+
+	// gains/lossesの反映方法。 replace: 指定したリストで置き換え、リストにない既存の要素を削除します (空配列で全削除)。 merge: リストにない既存の要素は残します。 
+	GainsLossesMode string `json:"gains_losses_mode,omitempty"`
+
+	// 完了時のフィードバックとバッジの判定で日付を数えるタイムゾーン (IANA名、省略時はUTC)
+	Tz string `json:"tz,omitempty"`
 }
 
 // AssertActionUpdateInputRequired checks if the required fields are not zero-ed
 func AssertActionUpdateInputRequired(obj ActionUpdateInput) error {
+	if obj.RecurrencePattern != nil {
+		if err := AssertRecurrencePatternRequired(*obj.RecurrencePattern); err != nil {
+			return err
+		}
+	}
+	for _, el := range obj.Gains {
+		if err := AssertGainUpdateInputRequired(el); err != nil {
+			return err
+		}
+	}
+	for _, el := range obj.Losses {
+		if err := AssertLossUpdateInputRequired(el); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
 // AssertActionUpdateInputConstraints checks if the values respects the defined constraints
 func AssertActionUpdateInputConstraints(obj ActionUpdateInput) error {
+	if obj.RecurrencePattern != nil {
+		if err := AssertRecurrencePatternConstraints(*obj.RecurrencePattern); err != nil {
+			return err
+		}
+	}
+	for _, el := range obj.Gains {
+		if err := AssertGainUpdateInputConstraints(el); err != nil {
+			return err
+		}
+	}
+	for _, el := range obj.Losses {
+		if err := AssertLossUpdateInputConstraints(el); err != nil {
+			return err
+		}
+	}
 	return nil
 }
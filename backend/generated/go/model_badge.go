@@ -18,6 +18,9 @@ type Badge struct {
 	// バッジID
 	Id int64 `json:"id"`
 
+	// バッジを一意に識別するコード
+	Code string `json:"code"`
+
 	// バッジ名
 	Name string `json:"name"`
 
@@ -26,14 +29,19 @@ type Badge struct {
 
 	// バッジアイコンのURL
 	IconUrl string `json:"icon_url,omitempty"`
+
+	// バッジの獲得条件を表すルール式
+	Rule string `json:"rule"`
 }
 
 // AssertBadgeRequired checks if the required fields are not zero-ed
 func AssertBadgeRequired(obj Badge) error {
 	elements := map[string]interface{}{
 		"id": obj.Id,
+		"code": obj.Code,
 		"name": obj.Name,
 		"description": obj.Description,
+		"rule": obj.Rule,
 	}
 	for name, el := range elements {
 		if isZero := IsZeroValue(el); isZero {
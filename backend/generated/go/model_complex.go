@@ -28,6 +28,9 @@ type Complex struct {
 	// 言語化されたコンプレックスの内容
 	Content string `json:"content"`
 
+	// コンプレックスを意識したきっかけやエピソード
+	TriggerEpisode string `json:"trigger_episode,omitempty"`
+
 	// コンプレックスのカテゴリ
 	Category string `json:"category"`
 
@@ -13,18 +13,39 @@ package refuelapi
 
 
 
+// Error - エラー。RFC 7807のproblem details形式 (application/problem+json) で返されます。detailはAccept-Language (ログイン中はユーザーの言語設定を優先) に合わせて日本語か英語で返され、どちらにも当てはまらなければ日本語になります。サーバー内部エラーの原因はレスポンスに含めず、request_idと合わせてサーバーのログに記録します。
 type Error struct {
 
-	Code int32 `json:"code"`
+	// 問題の種類を表すURI。codeから作られます
+	Type string `json:"type"`
 
-	Message string `json:"message"`
+	// HTTPステータスの短い説明
+	Title string `json:"title"`
+
+	// HTTPステータスコード
+	Status int32 `json:"status"`
+
+	// 利用者向けのメッセージ
+	Detail string `json:"detail"`
+
+	// 翻訳されない安定したエラーコード。クライアントはdetailではなくこちらで判定してください
+	Code string `json:"code"`
+
+	// 入力チェックに失敗した項目ごとの理由
+	InvalidParams []InvalidParam `json:"invalid_params,omitempty"`
+
+	// リクエストID。X-Request-IDヘッダーと同じ値で、問い合わせの際にサーバーのログと突き合わせるのに使います
+	RequestId string `json:"request_id,omitempty"`
 }
 
 // AssertErrorRequired checks if the required fields are not zero-ed
 func AssertErrorRequired(obj Error) error {
 	elements := map[string]interface{}{
+		"type": obj.Type,
+		"title": obj.Title,
+		"status": obj.Status,
+		"detail": obj.Detail,
 		"code": obj.Code,
-		"message": obj.Message,
 	}
 	for name, el := range elements {
 		if isZero := IsZeroValue(el); isZero {
@@ -32,10 +53,20 @@ func AssertErrorRequired(obj Error) error {
 		}
 	}
 
+	for _, el := range obj.InvalidParams {
+		if err := AssertInvalidParamRequired(el); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
 // AssertErrorConstraints checks if the values respects the defined constraints
 func AssertErrorConstraints(obj Error) error {
+	for _, el := range obj.InvalidParams {
+		if err := AssertInvalidParamConstraints(el); err != nil {
+			return err
+		}
+	}
 	return nil
 }
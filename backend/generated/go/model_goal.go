@@ -34,6 +34,15 @@ type Goal struct {
 	CreatedAt time.Time `json:"created_at"`
 
 	UpdatedAt time.Time `json:"updated_at"`
+
+	Streak *StreakSummary `json:"streak,omitempty"`
+
+	Metric *GoalMetric `json:"metric,omitempty"`
+
+	Progress *GoalProgress `json:"progress,omitempty"`
+
+	// 目標のマイルストーン (目標の詳細取得時のみ)
+	Milestones []Milestone `json:"milestones,omitempty"`
 }
 
 // AssertGoalRequired checks if the required fields are not zero-ed
@@ -52,10 +61,50 @@ func AssertGoalRequired(obj Goal) error {
 		}
 	}
 
+	if obj.Streak != nil {
+		if err := AssertStreakSummaryRequired(*obj.Streak); err != nil {
+			return err
+		}
+	}
+	if obj.Metric != nil {
+		if err := AssertGoalMetricRequired(*obj.Metric); err != nil {
+			return err
+		}
+	}
+	if obj.Progress != nil {
+		if err := AssertGoalProgressRequired(*obj.Progress); err != nil {
+			return err
+		}
+	}
+	for _, el := range obj.Milestones {
+		if err := AssertMilestoneRequired(el); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
 // AssertGoalConstraints checks if the values respects the defined constraints
 func AssertGoalConstraints(obj Goal) error {
+	if obj.Streak != nil {
+		if err := AssertStreakSummaryConstraints(*obj.Streak); err != nil {
+			return err
+		}
+	}
+	if obj.Metric != nil {
+		if err := AssertGoalMetricConstraints(*obj.Metric); err != nil {
+			return err
+		}
+	}
+	if obj.Progress != nil {
+		if err := AssertGoalProgressConstraints(*obj.Progress); err != nil {
+			return err
+		}
+	}
+	for _, el := range obj.Milestones {
+		if err := AssertMilestoneConstraints(el); err != nil {
+			return err
+		}
+	}
 	return nil
 }
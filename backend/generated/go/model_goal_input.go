@@ -21,6 +21,8 @@ type GoalInput struct {
 
 	// 定量的な目標の内容
 	Content string `json:"content"`
+
+	Metric *GoalMetric `json:"metric,omitempty"`
 }
 
 // AssertGoalInputRequired checks if the required fields are not zero-ed
@@ -35,10 +37,20 @@ func AssertGoalInputRequired(obj GoalInput) error {
 		}
 	}
 
+	if obj.Metric != nil {
+		if err := AssertGoalMetricRequired(*obj.Metric); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
 // AssertGoalInputConstraints checks if the values respects the defined constraints
 func AssertGoalInputConstraints(obj GoalInput) error {
+	if obj.Metric != nil {
+		if err := AssertGoalMetricConstraints(*obj.Metric); err != nil {
+			return err
+		}
+	}
 	return nil
 }
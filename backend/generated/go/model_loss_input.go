@@ -20,6 +20,12 @@ type LossInput struct {
 
 	// Lossの内容
 	Description string `json:"description"`
+
+	// 定量的なLossの数値 (typeがquantitativeの場合は必須で正の数、qualitativeの場合は省略)
+	Value float64 `json:"value,omitempty"`
+
+	// 定量的なLossの単位 (typeがquantitativeの場合は必須。kg, g, km, m, minutes, hours, kcal, steps, reps, pages, times, yen のいずれか。min や 分 などの別表記は正規化されます)
+	Unit string `json:"unit,omitempty"`
 }
 
 // AssertLossInputRequired checks if the required fields are not zero-ed
@@ -11,12 +11,27 @@
 package refuelapi
 
 
+import (
+	"time"
+)
+
 
 
 type User struct {
 
 	// ユーザーID
 	Id string `json:"id,omitempty"`
+
+	// メールアドレス
+	Email string `json:"email,omitempty"`
+
+	// 表示名
+	DisplayName string `json:"display_name,omitempty"`
+
+	// メッセージの言語設定 (未設定ならAccept-Languageに従います)
+	Language string `json:"language,omitempty"`
+
+	CreatedAt time.Time `json:"created_at,omitempty"`
 }
 
 // AssertUserRequired checks if the required fields are not zero-ed
@@ -27,6 +27,8 @@ type UserBadge struct {
 
 	// バッジ獲得日時
 	AchievedAt time.Time `json:"achieved_at"`
+
+	Milestone *Milestone `json:"milestone,omitempty"`
 }
 
 // AssertUserBadgeRequired checks if the required fields are not zero-ed
@@ -45,6 +47,11 @@ func AssertUserBadgeRequired(obj UserBadge) error {
 	if err := AssertBadgeRequired(obj.Badge); err != nil {
 		return err
 	}
+	if obj.Milestone != nil {
+		if err := AssertMilestoneRequired(*obj.Milestone); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -53,5 +60,10 @@ func AssertUserBadgeConstraints(obj UserBadge) error {
 	if err := AssertBadgeConstraints(obj.Badge); err != nil {
 		return err
 	}
+	if obj.Milestone != nil {
+		if err := AssertMilestoneConstraints(*obj.Milestone); err != nil {
+			return err
+		}
+	}
 	return nil
 }
@@ -35,20 +35,25 @@ func main() {
 	}
 
 	// 3. Instantiate API service with business logic
-	apiService := app.NewAPIService(appCtx.DB, appCtx.Validate)
+	apiService := app.NewAPIService(appCtx)
 
 	// 4. Pass API service to generated controller and register to router
 	router := refuelapi.NewRouter(
-		refuelapi.NewActionsAPIController(apiService.(refuelapi.ActionsAPIServicer)),
-		refuelapi.NewBadgesAPIController(apiService.(refuelapi.BadgesAPIServicer)),
-		refuelapi.NewComplexesAPIController(apiService.(refuelapi.ComplexesAPIServicer)),
-		refuelapi.NewGoalsAPIController(apiService.(refuelapi.GoalsAPIServicer)),
-		refuelapi.NewHealthAPIController(apiService.(refuelapi.HealthAPIServicer)),
-		refuelapi.NewUserBadgesAPIController(apiService.(refuelapi.UserBadgesAPIServicer)),
+		refuelapi.NewActionsAPIController(apiService.(refuelapi.ActionsAPIServicer), refuelapi.WithActionsAPIErrorHandler(app.ErrorHandler)),
+		refuelapi.NewAuthAPIController(apiService.(refuelapi.AuthAPIServicer), refuelapi.WithAuthAPIErrorHandler(app.ErrorHandler)),
+		refuelapi.NewBadgesAPIController(apiService.(refuelapi.BadgesAPIServicer), refuelapi.WithBadgesAPIErrorHandler(app.ErrorHandler)),
+		refuelapi.NewComplexesAPIController(apiService.(refuelapi.ComplexesAPIServicer), refuelapi.WithComplexesAPIErrorHandler(app.ErrorHandler)),
+		refuelapi.NewExportAPIController(apiService.(refuelapi.ExportAPIServicer), refuelapi.WithExportAPIErrorHandler(app.ErrorHandler)),
+		refuelapi.NewGoalsAPIController(apiService.(refuelapi.GoalsAPIServicer), refuelapi.WithGoalsAPIErrorHandler(app.ErrorHandler)),
+		refuelapi.NewHealthAPIController(apiService.(refuelapi.HealthAPIServicer), refuelapi.WithHealthAPIErrorHandler(app.ErrorHandler)),
+		refuelapi.NewImportAPIController(apiService.(refuelapi.ImportAPIServicer), refuelapi.WithImportAPIErrorHandler(app.ErrorHandler)),
+		refuelapi.NewSearchAPIController(apiService.(refuelapi.SearchAPIServicer), refuelapi.WithSearchAPIErrorHandler(app.ErrorHandler)),
+		refuelapi.NewTrashAPIController(apiService.(refuelapi.TrashAPIServicer), refuelapi.WithTrashAPIErrorHandler(app.ErrorHandler)),
+		refuelapi.NewUserBadgesAPIController(apiService.(refuelapi.UserBadgesAPIServicer), refuelapi.WithUserBadgesAPIErrorHandler(app.ErrorHandler)),
 	)
 
 	// 5. Setup Gin middleware (CORS, Auth, etc.)
-	app.SetupGinMiddlewares(ginRouter)
+	app.SetupGinMiddlewares(ginRouter, appCtx)
 
 	// 6. Register generated router handler to Gin router
 	//    openapi-generator generated router implements http.Handler interface
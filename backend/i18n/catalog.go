@@ -0,0 +1,666 @@
+package i18n
+
+import "net/http"
+
+// Error codes, returned untranslated in the code of problems.
+const (
+	InternalError      Code = "internal_error"
+	MissingBearerToken Code = "missing_bearer_token"
+	InvalidAccessToken Code = "invalid_access_token"
+	NotAuthenticated   Code = "not_authenticated"
+	AdminRequired      Code = "admin_required"
+
+	ValidationFailed Code = "validation_failed"
+	MalformedRequest Code = "malformed_request"
+	InvalidParameter Code = "invalid_parameter"
+	InvalidDate      Code = "invalid_date"
+	InvalidDateTime  Code = "invalid_datetime"
+	InvalidTimeZone  Code = "invalid_tz"
+	InvalidChoice    Code = "invalid_choice"
+	OutOfRange       Code = "out_of_range"
+	DateRangeOrder   Code = "date_range_order"
+	DateRangeTooLong Code = "date_range_too_long"
+	InvalidCursor    Code = "invalid_cursor"
+	InvalidQuery     Code = "invalid_query"
+
+	ActionNotFound            Code = "action_not_found"
+	GoalNotFound              Code = "goal_not_found"
+	ComplexNotFound           Code = "complex_not_found"
+	UserNotFound              Code = "user_not_found"
+	MeasurementNotFound       Code = "measurement_not_found"
+	GainNotFound              Code = "gain_not_found"
+	LossNotFound              Code = "loss_not_found"
+	CheckinNotFound           Code = "checkin_not_found"
+	ReferencedGoalNotFound    Code = "referenced_goal_not_found"
+	ReferencedComplexNotFound Code = "referenced_complex_not_found"
+	GainNotInAction           Code = "gain_not_in_action"
+	LossNotInAction           Code = "loss_not_in_action"
+	TrashItemNotFound         Code = "trash_item_not_found"
+	ParentInTrash             Code = "parent_in_trash"
+	ExportNotFound            Code = "export_not_found"
+	ExportNotReady            Code = "export_not_ready"
+	InvalidImportFile         Code = "invalid_import_file"
+	ImportFileTooLarge        Code = "import_file_too_large"
+	ImportComplexRequired     Code = "import_complex_required"
+	ImportInTrash             Code = "import_in_trash"
+	ImportParentMissing       Code = "import_parent_missing"
+	ImportInvalidDate         Code = "import_invalid_date"
+	ImportUnknownDone         Code = "import_unknown_done"
+
+	FetchFailed            Code = "fetch_failed"
+	CreateFailed           Code = "create_failed"
+	UpdateFailed           Code = "update_failed"
+	DeleteFailed           Code = "delete_failed"
+	SaveFailed             Code = "save_failed"
+	RestoreFailed          Code = "restore_failed"
+	ComputeFailed          Code = "compute_failed"
+	TokenIssueFailed       Code = "token_issue_failed"
+	TokenRevokeFailed      Code = "token_revoke_failed"
+	IdentityLinkFailed     Code = "identity_link_failed"
+	IdentityProviderFailed Code = "identity_provider_failed"
+
+	InvalidRecurrencePattern Code = "invalid_recurrence_pattern"
+	StoredRecurrenceInvalid  Code = "stored_recurrence_invalid"
+	NotRecurring             Code = "not_recurring"
+	NotScheduled             Code = "not_scheduled"
+	FutureCompletion         Code = "future_completion"
+	InvalidMetric            Code = "invalid_metric"
+	GoalHasNoMetric          Code = "goal_has_no_metric"
+	InvalidMilestonePlan     Code = "invalid_milestone_plan"
+	InvalidUnit              Code = "invalid_unit"
+	InvalidBucket            Code = "invalid_bucket"
+	InvalidBadgeRule         Code = "invalid_badge_rule"
+	BadgeCodeTaken           Code = "badge_code_taken"
+
+	InvalidEmail         Code = "invalid_email"
+	PasswordTooShort     Code = "password_too_short"
+	PasswordTooLong      Code = "password_too_long"
+	EmailTaken           Code = "email_taken"
+	EmailTakenUnverified Code = "email_taken_unverified"
+	InvalidCredentials   Code = "invalid_credentials"
+	InvalidRefreshToken  Code = "invalid_refresh_token"
+	OIDCNotConfigured    Code = "oidc_not_configured"
+	UnknownLoginState    Code = "unknown_login_state"
+	InvalidIDToken       Code = "invalid_id_token"
+	ProviderEmailMissing Code = "provider_email_missing"
+)
+
+// Messages describing one invalid field of a validator.ValidationErrors,
+// by validation tag. The first argument is the field, the second the
+// tag's parameter.
+const (
+	FieldRequired Code = "field.required"
+	FieldEmail    Code = "field.email"
+	FieldMin      Code = "field.min"
+	FieldMax      Code = "field.max"
+	FieldOneOf    Code = "field.oneof"
+	FieldInvalid  Code = "field.invalid"
+)
+
+// Details of invalid input, quoted by the failure messages as errors worded
+// by an Error.
+const (
+	DetailDescriptionRequired  Code = "detail.description_required"
+	DetailOutcomeValueRequired Code = "detail.outcome_value_required"
+	DetailOutcomeUnitRequired  Code = "detail.outcome_unit_required"
+	DetailQualitativeOutcome   Code = "detail.qualitative_outcome"
+	DetailOutcomeType          Code = "detail.outcome_type"
+	DetailUnknownUnit          Code = "detail.unknown_unit"
+	DetailUnknownBucket        Code = "detail.unknown_bucket"
+	DetailDeadline             Code = "detail.deadline"
+	DetailTargetAboveBaseline  Code = "detail.target_above_baseline"
+	DetailTargetBelowBaseline  Code = "detail.target_below_baseline"
+	DetailUnknownDirection     Code = "detail.unknown_direction"
+	DetailPlanWithoutMetric    Code = "detail.plan_without_metric"
+	DetailCompletedTarget      Code = "detail.completed_target"
+	DetailMilestoneBasis       Code = "detail.milestone_basis"
+	DetailTargetIsStart        Code = "detail.target_is_start"
+	DetailMilestoneCount       Code = "detail.milestone_count"
+	DetailFractionRange        Code = "detail.fraction_range"
+	DetailFractionsIncrease    Code = "detail.fractions_increase"
+	DetailTooManyMilestones    Code = "detail.too_many_milestones"
+	DetailFrequency            Code = "detail.frequency"
+	DetailInterval             Code = "detail.interval"
+	DetailTimeOfDay            Code = "detail.time_of_day"
+	DetailWeeklyOnly           Code = "detail.weekly_only"
+	DetailUnknownDay           Code = "detail.unknown_day"
+	DetailDuplicateDay         Code = "detail.duplicate_day"
+	DetailMonthlyOnly          Code = "detail.monthly_only"
+	DetailDayOfMonth           Code = "detail.day_of_month"
+	DetailMetricIncomplete     Code = "detail.metric_incomplete"
+	DetailEmptyFile            Code = "detail.empty_file"
+	DetailNoDateColumn         Code = "detail.no_date_column"
+	DetailNoHabitColumn        Code = "detail.no_habit_column"
+)
+
+// Nouns the failure messages name resources with.
+const (
+	NounAction       Code = "noun.action"
+	NounActions      Code = "noun.actions"
+	NounGoal         Code = "noun.goal"
+	NounGoals        Code = "noun.goals"
+	NounComplex      Code = "noun.complex"
+	NounComplexes    Code = "noun.complexes"
+	NounMeasurement  Code = "noun.measurement"
+	NounMeasurements Code = "noun.measurements"
+	NounMilestones   Code = "noun.milestones"
+	NounGain         Code = "noun.gain"
+	NounGains        Code = "noun.gains"
+	NounLoss         Code = "noun.loss"
+	NounLosses       Code = "noun.losses"
+	NounCheckin      Code = "noun.checkin"
+	NounCheckins     Code = "noun.checkins"
+	NounStreak       Code = "noun.streak"
+	NounProgress     Code = "noun.progress"
+	NounBadge        Code = "noun.badge"
+	NounBadges       Code = "noun.badges"
+	NounUserBadges   Code = "noun.user_badges"
+	NounHistory      Code = "noun.history"
+	NounUser         Code = "noun.user"
+	NounRefreshToken Code = "noun.refresh_token"
+	NounIdentity     Code = "noun.identity"
+	NounLoginState   Code = "noun.login_state"
+	NounTrash        Code = "noun.trash"
+	NounRevisions    Code = "noun.revisions"
+	NounExport       Code = "noun.export"
+	NounExports      Code = "noun.exports"
+	NounImport       Code = "noun.import"
+)
+
+// catalog maps every code to its message in each language. Messages
+// taking arguments use fmt verbs.
+var catalog = map[Code]map[Lang]string{
+	InternalError: {
+		Japanese: "サーバー内部でエラーが発生しました。時間をおいてもう一度お試しください",
+		English:  "Internal server error",
+	},
+	MissingBearerToken: {
+		Japanese: "アクセストークンが指定されていません",
+		English:  "Missing bearer token",
+	},
+	InvalidAccessToken: {
+		Japanese: "アクセストークンが無効か、有効期限が切れています",
+		English:  "Invalid or expired access token",
+	},
+	NotAuthenticated: {
+		Japanese: "ログインしていません",
+		English:  "User ID not found in context",
+	},
+	AdminRequired: {
+		Japanese: "管理者権限が必要です",
+		English:  "Admin privileges required",
+	},
+
+	ValidationFailed: {
+		Japanese: "入力内容に誤りがあります: %v",
+		English:  "Validation failed: %v",
+	},
+	MalformedRequest: {
+		Japanese: "リクエストを読み取れません。本文のJSONとクエリ文字列の形式を確認してください",
+		English:  "Request could not be parsed. Check the JSON body and the query string",
+	},
+	InvalidParameter: {
+		Japanese: "%sの値の形式が正しくありません",
+		English:  "%s is malformed",
+	},
+	InvalidDate: {
+		Japanese: "%sの日付が正しくありません。YYYY-MM-DD形式で指定してください。",
+		English:  "Invalid %s. Use YYYY-MM-DD.",
+	},
+	InvalidDateTime: {
+		Japanese: "%sの形式が正しくありません。ISO8601 (RFC3339) 形式で指定してください。",
+		English:  "Invalid %s format. Use ISO8601 (RFC3339).",
+	},
+	InvalidTimeZone: {
+		Japanese: "タイムゾーンが正しくありません: %v",
+		English:  "Invalid tz: %v",
+	},
+	InvalidChoice: {
+		Japanese: "%sには次のいずれかを指定してください: %s",
+		English:  "%s must be one of %s",
+	},
+	OutOfRange: {
+		Japanese: "%sは%dから%dの範囲で指定してください",
+		English:  "%s must be between %d and %d",
+	},
+	DateRangeOrder: {
+		Japanese: "fromにはto以前の日付を指定してください",
+		English:  "from must not be after to",
+	},
+	DateRangeTooLong: {
+		Japanese: "期間は%d日以内で指定してください",
+		English:  "Date range must not exceed %d days",
+	},
+	InvalidCursor: {
+		Japanese: "cursorが正しくありません。Linkヘッダーで返されたURLをそのまま使ってください",
+		English:  "Invalid cursor. Use the URL returned in the Link header as it is",
+	},
+	InvalidQuery: {
+		Japanese: "検索語は空白以外の文字を含む%d文字以内で指定してください",
+		English:  "q must contain a non-space character and be at most %d characters",
+	},
+
+	ActionNotFound: {
+		Japanese: "行動が見つかりません",
+		English:  "Action not found",
+	},
+	GoalNotFound: {
+		Japanese: "目標が見つかりません",
+		English:  "Goal not found",
+	},
+	ComplexNotFound: {
+		Japanese: "コンプレックスが見つかりません",
+		English:  "Complex not found",
+	},
+	UserNotFound: {
+		Japanese: "ユーザーが見つかりません",
+		English:  "User not found",
+	},
+	MeasurementNotFound: {
+		Japanese: "計測記録が見つかりません",
+		English:  "Measurement not found",
+	},
+	GainNotFound: {
+		Japanese: "Gainが見つかりません",
+		English:  "Gain not found",
+	},
+	LossNotFound: {
+		Japanese: "Lossが見つかりません",
+		English:  "Loss not found",
+	},
+	CheckinNotFound: {
+		Japanese: "実施記録が見つかりません",
+		English:  "Check-in not found",
+	},
+	ReferencedGoalNotFound: {
+		Japanese: "指定された目標が見つかりません",
+		English:  "Referenced goal not found or does not belong to user",
+	},
+	ReferencedComplexNotFound: {
+		Japanese: "指定されたコンプレックスが見つかりません",
+		English:  "Referenced complex not found or does not belong to user",
+	},
+	GainNotInAction: {
+		Japanese: "gainsにこの行動のものではないGainのIDが含まれています",
+		English:  "gains refers to a gain id that does not belong to this action",
+	},
+	LossNotInAction: {
+		Japanese: "lossesにこの行動のものではないLossのIDが含まれています",
+		English:  "losses refers to a loss id that does not belong to this action",
+	},
+	TrashItemNotFound: {
+		Japanese: "ゴミ箱にその記録が見つかりません",
+		English:  "Item not found in the trash",
+	},
+	ParentInTrash: {
+		Japanese: "属するコンプレックスまたは目標がゴミ箱にあります。先にそちらを元に戻してください",
+		English:  "The complex or goal this belongs to is in the trash. Restore it first",
+	},
+	ExportNotFound: {
+		Japanese: "エクスポートが見つかりません。有効期限が切れた可能性があります",
+		English:  "Export not found. It may have expired",
+	},
+	ExportNotReady: {
+		Japanese: "エクスポートが完了していません (状態: %s)",
+		English:  "Export has not completed (status: %s)",
+	},
+	InvalidImportFile: {
+		Japanese: "ファイルを読み込めません: %v",
+		English:  "Cannot read the file: %v",
+	},
+	ImportFileTooLarge: {
+		Japanese: "ファイルが大きすぎます (上限 %d MiB)",
+		English:  "File is too large (limit %d MiB)",
+	},
+	ImportComplexRequired: {
+		Japanese: "CSV の習慣を取り込むコンプレックスを complex_id で指定してください",
+		English:  "Choose the complex to import the habits of a CSV file under with complex_id",
+	},
+	ImportInTrash: {
+		Japanese: "ゴミ箱にあるため取り込みません",
+		English:  "Not imported because it is in the trash",
+	},
+	ImportParentMissing: {
+		Japanese: "属する%s (ID %d) が取り込まれません",
+		English:  "The %s it belongs to (ID %d) is not imported",
+	},
+	ImportInvalidDate: {
+		Japanese: "日付 %q を読み取れません。YYYY-MM-DD形式で指定してください。",
+		English:  "Cannot read the date %q. Use YYYY-MM-DD.",
+	},
+	ImportUnknownDone: {
+		Japanese: "達成したかどうかを %q から読み取れません",
+		English:  "Cannot tell whether the habit was done from %q",
+	},
+
+	FetchFailed: {
+		Japanese: "%sの取得に失敗しました",
+		English:  "Failed to fetch %s",
+	},
+	CreateFailed: {
+		Japanese: "%sの作成に失敗しました",
+		English:  "Failed to create %s",
+	},
+	UpdateFailed: {
+		Japanese: "%sの更新に失敗しました",
+		English:  "Failed to update %s",
+	},
+	DeleteFailed: {
+		Japanese: "%sの削除に失敗しました",
+		English:  "Failed to delete %s",
+	},
+	SaveFailed: {
+		Japanese: "%sの保存に失敗しました",
+		English:  "Failed to save %s",
+	},
+	RestoreFailed: {
+		Japanese: "%sの復元に失敗しました",
+		English:  "Failed to restore %s",
+	},
+	ComputeFailed: {
+		Japanese: "%sの計算に失敗しました",
+		English:  "Failed to compute %s",
+	},
+	TokenIssueFailed: {
+		Japanese: "トークンの発行に失敗しました",
+		English:  "Failed to issue tokens",
+	},
+	TokenRevokeFailed: {
+		Japanese: "リフレッシュトークンの失効に失敗しました",
+		English:  "Failed to revoke refresh tokens",
+	},
+	IdentityLinkFailed: {
+		Japanese: "外部アカウントの連携に失敗しました",
+		English:  "Failed to link identity",
+	},
+	IdentityProviderFailed: {
+		Japanese: "認証プロバイダーとの通信に失敗しました",
+		English:  "Failed to contact identity provider",
+	},
+
+	InvalidRecurrencePattern: {
+		Japanese: "繰り返しパターンが正しくありません: %v",
+		English:  "Invalid recurrence_pattern: %v",
+	},
+	StoredRecurrenceInvalid: {
+		Japanese: "保存されている繰り返しパターンが壊れています",
+		English:  "Stored recurrence pattern is invalid",
+	},
+	NotRecurring: {
+		Japanese: "この行動には繰り返しパターンがありません",
+		English:  "Action has no recurrence pattern",
+	},
+	NotScheduled: {
+		Japanese: "この日は行動の実施予定日ではありません",
+		English:  "date is not a scheduled occurrence of this action",
+	},
+	FutureCompletion: {
+		Japanese: "未来の予定を実施済みにはできません",
+		English:  "Cannot mark a future occurrence as done",
+	},
+	InvalidMetric: {
+		Japanese: "指標が正しくありません: %v",
+		English:  "Invalid metric: %v",
+	},
+	GoalHasNoMetric: {
+		Japanese: "目標に指標が設定されていません。先に PUT /goals/{goalId} で設定してください",
+		English:  "Goal has no metric to measure: set one with PUT /goals/{goalId} first",
+	},
+	InvalidMilestonePlan: {
+		Japanese: "マイルストーンの設定が正しくありません: %v",
+		English:  "Invalid milestone plan: %v",
+	},
+	InvalidUnit: {
+		Japanese: "単位が正しくありません: %v",
+		English:  "Invalid unit: %v",
+	},
+	InvalidBucket: {
+		Japanese: "集計単位が正しくありません: %v",
+		English:  "Invalid bucket: %v",
+	},
+	InvalidBadgeRule: {
+		Japanese: "バッジの条件が正しくありません: %v",
+		English:  "Invalid badge rule: %v",
+	},
+	BadgeCodeTaken: {
+		Japanese: "このコードのバッジはすでに存在します",
+		English:  "Badge with this code already exists",
+	},
+
+	InvalidEmail: {
+		Japanese: "メールアドレスが正しくありません",
+		English:  "Invalid email address",
+	},
+	PasswordTooShort: {
+		Japanese: "パスワードは%d文字以上にしてください",
+		English:  "Password must be at least %d characters",
+	},
+	PasswordTooLong: {
+		Japanese: "パスワードは%dバイト以内にしてください",
+		English:  "Password must be at most %d bytes",
+	},
+	EmailTaken: {
+		Japanese: "このメールアドレスのアカウントはすでに存在します",
+		English:  "An account with this email already exists",
+	},
+	EmailTakenUnverified: {
+		Japanese: "このメールアドレスのアカウントはすでに存在し、認証プロバイダーがメールアドレスを確認していません",
+		English:  "An account with this email already exists and the provider did not verify the email",
+	},
+	InvalidCredentials: {
+		Japanese: "メールアドレスまたはパスワードが正しくありません",
+		English:  "Invalid email or password",
+	},
+	InvalidRefreshToken: {
+		Japanese: "リフレッシュトークンが無効か、有効期限が切れています",
+		English:  "Invalid or expired refresh token",
+	},
+	OIDCNotConfigured: {
+		Japanese: "外部アカウントでのログインは設定されていません",
+		English:  "OIDC login is not configured",
+	},
+	UnknownLoginState: {
+		Japanese: "ログインの状態が不明か、有効期限が切れています",
+		English:  "Unknown or expired state",
+	},
+	InvalidIDToken: {
+		Japanese: "IDトークンが正しくありません",
+		English:  "Invalid ID token",
+	},
+	ProviderEmailMissing: {
+		Japanese: "認証プロバイダーからメールアドレスが返されませんでした",
+		English:  "The identity provider did not return an email address",
+	},
+
+	FieldRequired: {
+		Japanese: "%sは必須です",
+		English:  "%s is required",
+	},
+	FieldEmail: {
+		Japanese: "%sには有効なメールアドレスを指定してください",
+		English:  "%s must be a valid email address",
+	},
+	FieldMin: {
+		Japanese: "%sは%s以上にしてください",
+		English:  "%s must be at least %s",
+	},
+	FieldMax: {
+		Japanese: "%sは%s以下にしてください",
+		English:  "%s must be at most %s",
+	},
+	FieldOneOf: {
+		Japanese: "%sには次のいずれかを指定してください: %s",
+		English:  "%s must be one of %s",
+	},
+	FieldInvalid: {
+		Japanese: "%sの値が正しくありません (%s)",
+		English:  "%s is invalid (%s)",
+	},
+
+	DetailDescriptionRequired: {
+		Japanese: "%sの説明は必須です",
+		English:  "%s description is required",
+	},
+	DetailOutcomeValueRequired: {
+		Japanese: "定量的な%sには正の値が必要です",
+		English:  "quantitative %s needs a positive value",
+	},
+	DetailOutcomeUnitRequired: {
+		Japanese: "定量的な%sには単位が必要です",
+		English:  "quantitative %s needs a unit",
+	},
+	DetailQualitativeOutcome: {
+		Japanese: "定性的な%sには値も単位も指定できません",
+		English:  "qualitative %s takes no value or unit",
+	},
+	DetailOutcomeType: {
+		Japanese: "%sの type は %q か %q のいずれかです",
+		English:  "%s type must be %q or %q",
+	},
+	DetailUnknownUnit: {
+		Japanese: "%q は使えない単位です。%s のいずれかを指定してください",
+		English:  "unknown unit %q: use one of %s",
+	},
+	DetailUnknownBucket: {
+		Japanese: "%q は使えない集計単位です。day、week、month のいずれかを指定してください",
+		English:  "unknown bucket %q: use day, week or month",
+	},
+	DetailDeadline: {
+		Japanese: "期限 %q は YYYY-MM-DD 形式の日付で指定してください",
+		English:  "deadline %q must be a date in YYYY-MM-DD format",
+	},
+	DetailTargetAboveBaseline: {
+		Japanese: "増やす目標では、目標値 %g を基準値 %g より大きくしてください",
+		English:  "target %g must be greater than baseline %g for an increase",
+	},
+	DetailTargetBelowBaseline: {
+		Japanese: "減らす目標では、目標値 %g を基準値 %g より小さくしてください",
+		English:  "target %g must be less than baseline %g for a decrease",
+	},
+	DetailUnknownDirection: {
+		Japanese: "%q は使えない方向です。increase か decrease を指定してください",
+		English:  "unknown direction %q: use increase or decrease",
+	},
+	DetailPlanWithoutMetric: {
+		Japanese: "目標に指標が設定されていません。PUT /goals/{goalId} で設定するか、completed_actions で設定してください",
+		English:  "goal has no metric: set one with PUT /goals/{goalId} or plan by completed_actions",
+	},
+	DetailCompletedTarget: {
+		Japanese: "target には完了した行動の回数を正の数で指定してください",
+		English:  "target must be a positive number of completed actions",
+	},
+	DetailMilestoneBasis: {
+		Japanese: "basis は %q か %q のいずれかです",
+		English:  "basis must be %q or %q",
+	},
+	DetailTargetIsStart: {
+		Japanese: "目標値を開始時の値と同じにはできません",
+		English:  "the target must differ from the starting value",
+	},
+	DetailMilestoneCount: {
+		Japanese: "count は 1 以上 %d 以下で指定してください",
+		English:  "count must be between 1 and %d",
+	},
+	DetailFractionRange: {
+		Japanese: "割合 %g が (0, 1] の範囲にありません",
+		English:  "fraction %g is not in (0, 1]",
+	},
+	DetailFractionsIncrease: {
+		Japanese: "割合は昇順に並べてください: %g が %g の後にあります",
+		English:  "fractions must increase: %g follows %g",
+	},
+	DetailTooManyMilestones: {
+		Japanese: "マイルストーンは最終目標を含めて %d 個までです",
+		English:  "at most %d milestones are allowed, including the final target",
+	},
+	DetailFrequency: {
+		Japanese: "%s は daily、weekly、monthly のいずれかです",
+		English:  "%s must be one of daily, weekly, monthly",
+	},
+	DetailInterval: {
+		Japanese: "%s は 1 以上で指定してください",
+		English:  "%s must be at least 1",
+	},
+	DetailTimeOfDay: {
+		Japanese: "%s は HH:MM 形式で指定してください",
+		English:  "%s must be in HH:MM format",
+	},
+	DetailWeeklyOnly: {
+		Japanese: "%s は weekly のパターンにだけ指定できます",
+		English:  "%s is only allowed for weekly patterns",
+	},
+	DetailUnknownDay: {
+		Japanese: "%s の %q は使えない曜日です",
+		English:  "%s has unknown day %q",
+	},
+	DetailDuplicateDay: {
+		Japanese: "%s で曜日 %q が重複しています",
+		English:  "%s has duplicate day %q",
+	},
+	DetailMonthlyOnly: {
+		Japanese: "%s は monthly のパターンにだけ指定できます",
+		English:  "%s is only allowed for monthly patterns",
+	},
+	DetailDayOfMonth: {
+		Japanese: "%s は 1 以上 31 以下で指定してください",
+		English:  "%s must be between 1 and 31",
+	},
+	DetailMetricIncomplete: {
+		Japanese: "指標には基準値と目標値が必要です",
+		English:  "a metric needs a baseline and a target",
+	},
+	DetailEmptyFile: {
+		Japanese: "ファイルが空です",
+		English:  "the file is empty",
+	},
+	DetailNoDateColumn: {
+		Japanese: "日付の列がありません",
+		English:  "no date column",
+	},
+	DetailNoHabitColumn: {
+		Japanese: "習慣の列がありません",
+		English:  "no habit column",
+	},
+
+	NounAction:       {Japanese: "行動", English: "action"},
+	NounActions:      {Japanese: "行動", English: "actions"},
+	NounGoal:         {Japanese: "目標", English: "goal"},
+	NounGoals:        {Japanese: "目標", English: "goals"},
+	NounComplex:      {Japanese: "コンプレックス", English: "complex"},
+	NounComplexes:    {Japanese: "コンプレックス", English: "complexes"},
+	NounMeasurement:  {Japanese: "計測記録", English: "measurement"},
+	NounMeasurements: {Japanese: "計測記録", English: "measurements"},
+	NounMilestones:   {Japanese: "マイルストーン", English: "milestones"},
+	NounGain:         {Japanese: "Gain", English: "gain"},
+	NounGains:        {Japanese: "Gain", English: "gains"},
+	NounLoss:         {Japanese: "Loss", English: "loss"},
+	NounLosses:       {Japanese: "Loss", English: "losses"},
+	NounCheckin:      {Japanese: "実施記録", English: "check-in"},
+	NounCheckins:     {Japanese: "実施記録", English: "check-ins"},
+	NounStreak:       {Japanese: "連続記録", English: "streak"},
+	NounProgress:     {Japanese: "進捗", English: "progress"},
+	NounBadge:        {Japanese: "バッジ", English: "badge"},
+	NounBadges:       {Japanese: "バッジ", English: "badges"},
+	NounUserBadges:   {Japanese: "獲得バッジ", English: "user badges"},
+	NounHistory:      {Japanese: "行動履歴", English: "user history"},
+	NounUser:         {Japanese: "ユーザー", English: "user"},
+	NounRefreshToken: {Japanese: "リフレッシュトークン", English: "refresh token"},
+	NounIdentity:     {Japanese: "外部アカウント", English: "identity"},
+	NounLoginState:   {Japanese: "ログインの状態", English: "login state"},
+	NounTrash:        {Japanese: "ゴミ箱", English: "trash"},
+	NounRevisions:    {Japanese: "変更履歴", English: "revision history"},
+	NounExport:       {Japanese: "エクスポート", English: "export"},
+	NounExports:      {Japanese: "エクスポート", English: "exports"},
+	NounImport:       {Japanese: "インポート", English: "import"},
+}
+
+// statusTitles are the titles of problems, which name their HTTP status.
+var statusTitles = map[int]map[Lang]string{
+	http.StatusBadRequest:          {Japanese: "リクエストが正しくありません", English: "Bad Request"},
+	http.StatusUnauthorized:        {Japanese: "認証が必要です", English: "Unauthorized"},
+	http.StatusForbidden:           {Japanese: "権限がありません", English: "Forbidden"},
+	http.StatusNotFound:            {Japanese: "見つかりません", English: "Not Found"},
+	http.StatusConflict:            {Japanese: "既存のデータと競合しています", English: "Conflict"},
+	http.StatusInternalServerError: {Japanese: "サーバー内部エラー", English: "Internal Server Error"},
+	http.StatusBadGateway:          {Japanese: "外部サービスのエラー", English: "Bad Gateway"},
+}
@@ -0,0 +1,140 @@
+// Package i18n holds the messages the API returns to people, in Japanese
+// and English, keyed by stable codes, and negotiates which language a
+// request gets. Codes are part of the API: clients match on them instead of
+// on the translated text, so a code must never change once released.
+package i18n
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Lang is a supported language, named by its ISO 639-1 code.
+type Lang string
+
+// Supported languages.
+const (
+	Japanese Lang = "ja"
+	English  Lang = "en"
+)
+
+// Default is the language used when a request asks for none that is
+// supported, matching the frontend's fallback.
+const Default = Japanese
+
+// Parse returns the supported language a BCP 47 tag such as "en-US" is in.
+func Parse(tag string) (Lang, bool) {
+	primary, _, _ := strings.Cut(strings.TrimSpace(tag), "-")
+	switch lang := Lang(strings.ToLower(primary)); lang {
+	case Japanese, English:
+		return lang, true
+	}
+	return "", false
+}
+
+// Negotiate picks the supported language an Accept-Language header value
+// prefers most. It reports false if the header names none of them; a
+// wildcard counts as asking for Default.
+func Negotiate(acceptLanguage string) (Lang, bool) {
+	type choice struct {
+		tag string
+		q   float64
+	}
+	var choices []choice
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if tag == "" {
+			continue
+		}
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if q > 0 {
+			choices = append(choices, choice{tag, q})
+		}
+	}
+	sort.SliceStable(choices, func(i, j int) bool { return choices[i].q > choices[j].q })
+	for _, c := range choices {
+		if c.tag == "*" {
+			return Default, true
+		}
+		if lang, ok := Parse(c.tag); ok {
+			return lang, true
+		}
+	}
+	return "", false
+}
+
+// Code identifies a message of the catalog.
+type Code string
+
+// T returns the message for code in lang, formatted with args as by
+// fmt.Sprintf. Args that are themselves codes or errors worded by an Error
+// are translated first, so a message can name a resource or quote a detail
+// in the same language. A code missing from the catalog is returned as it
+// is.
+func T(lang Lang, code Code, args ...interface{}) string {
+	texts, ok := catalog[code]
+	if !ok {
+		return string(code)
+	}
+	text, ok := texts[lang]
+	if !ok {
+		text = texts[Default]
+	}
+	if len(args) == 0 {
+		return text
+	}
+	translated := make([]interface{}, len(args))
+	for i, arg := range args {
+		var msg *Error
+		switch a := arg.(type) {
+		case Code:
+			arg = T(lang, a)
+		case error:
+			if errors.As(a, &msg) {
+				arg = T(lang, msg.Code, msg.Args...)
+			}
+		}
+		translated[i] = arg
+	}
+	return fmt.Sprintf(text, translated...)
+}
+
+// Error is an error worded by a catalog message, so that it can be shown to
+// people in their language when it ends up in a response. Error returns the
+// English message, for logs.
+type Error struct {
+	Code Code
+	Args []interface{}
+}
+
+// NewError returns the error worded by the message for code with args.
+func NewError(code Code, args ...interface{}) *Error {
+	return &Error{Code: code, Args: args}
+}
+
+func (e *Error) Error() string {
+	return T(English, e.Code, e.Args...)
+}
+
+// StatusTitle returns the title of a problem with the HTTP status in lang.
+func StatusTitle(lang Lang, status int) string {
+	titles, ok := statusTitles[status]
+	if !ok {
+		return http.StatusText(status)
+	}
+	if title, ok := titles[lang]; ok {
+		return title
+	}
+	return titles[Default]
+}
@@ -0,0 +1,83 @@
+package i18n
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"testing"
+)
+
+func TestCatalogComplete(t *testing.T) {
+	verbs := regexp.MustCompile(`%[vsdgq]`)
+	for code, texts := range catalog {
+		ja, en := texts[Japanese], texts[English]
+		if ja == "" || en == "" {
+			t.Errorf("%s: got ja %q and en %q, want both", code, ja, en)
+			continue
+		}
+		// Both languages must take the same arguments.
+		if got, want := verbs.FindAllString(en, -1), verbs.FindAllString(ja, -1); len(got) != len(want) {
+			t.Errorf("%s: en takes %d arguments, ja %d", code, len(got), len(want))
+		}
+	}
+}
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		header string
+		want   Lang
+		ok     bool
+	}{
+		{"en", English, true},
+		{"en-US,en;q=0.9", English, true},
+		{"ja-JP", Japanese, true},
+		{"fr;q=1.0, en;q=0.5, ja;q=0.8", Japanese, true},
+		{"fr, *;q=0.1", Default, true},
+		{"en;q=0", "", false},
+		{"fr, de", "", false},
+		{"", "", false},
+		{"en;q=abc, ja", Japanese, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			got, ok := Negotiate(tt.header)
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("got %q, %v, want %q, %v", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestT(t *testing.T) {
+	tests := []struct {
+		name string
+		lang Lang
+		code Code
+		args []interface{}
+		want string
+	}{
+		{"plain", English, MissingBearerToken, nil, "Missing bearer token"},
+		{"code argument in English", English, FetchFailed, []interface{}{NounGoal}, "Failed to fetch goal"},
+		{"code argument in Japanese", Japanese, FetchFailed, []interface{}{NounGoal}, "目標の取得に失敗しました"},
+		{"unsupported language", "fr", MissingBearerToken, nil, catalog[MissingBearerToken][Default]},
+		{"unknown code", English, "no_such_code", nil, "no_such_code"},
+		{"error argument in English", English, InvalidUnit, []interface{}{NewError(DetailUnknownBucket, "year")}, `Invalid unit: unknown bucket "year": use day, week or month`},
+		{"error argument in Japanese", Japanese, InvalidUnit, []interface{}{NewError(DetailUnknownBucket, "year")}, `単位が正しくありません: "year" は使えない集計単位です。day、week、month のいずれかを指定してください`},
+		{"wrapped error argument", Japanese, InvalidMetric, []interface{}{fmt.Errorf("metric: %w", NewError(DetailUnknownDirection, "up"))}, `指標が正しくありません: "up" は使えない方向です。increase か decrease を指定してください`},
+		{"plain error argument", English, InvalidMetric, []interface{}{errors.New("broken")}, "Invalid metric: broken"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := T(tt.lang, tt.code, tt.args...); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestError(t *testing.T) {
+	err := NewError(DetailMilestoneCount, 20)
+	if got, want := err.Error(), "count must be between 1 and 20"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
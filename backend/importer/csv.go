@@ -0,0 +1,264 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"refuel/backend/export"
+	"refuel/backend/i18n"
+	"refuel/backend/models"
+	"refuel/backend/recurrence"
+)
+
+// habitComplexID is the ID the complex chosen for a habit log has in the
+// data read from it.
+const habitComplexID = 1
+
+// habitTimeOfDay is when the daily actions made for habits are scheduled.
+const habitTimeOfDay = "09:00"
+
+// Column names habit trackers use, lower-cased and without spaces,
+// underscores or hyphens.
+var (
+	dateColumns  = []string{"date", "day", "日付", "日時"}
+	habitColumns = []string{"habit", "habitname", "name", "task", "title", "習慣", "習慣名"}
+	doneColumns  = []string{"done", "completed", "complete", "status", "value", "result", "checked", "達成", "完了", "実施", "状態"}
+)
+
+// Values of a done column. A number counts as done when above zero.
+var (
+	doneValues    = []string{"1", "true", "yes", "y", "done", "completed", "complete", "x", "✓", "✔", "○", "済", "達成", "完了"}
+	notDoneValues = []string{"", "0", "false", "no", "n", "skipped", "missed", "-", "×", "未", "未達成", "未完了", "未実施"}
+)
+
+// dateLayouts and dateTimeLayouts are the ways habit trackers write the
+// date of a row. A time of day is taken for the time the habit was done.
+var (
+	dateLayouts     = []string{time.DateOnly, "2006/01/02", "2006/1/2"}
+	dateTimeLayouts = []string{time.RFC3339, "2006-01-02T15:04:05", time.DateTime, "2006/01/02 15:04:05", "2006/01/02 15:04"}
+)
+
+// habitLog is a CSV habit log read as the data of an archive: a goal with
+// a daily action for each habit, under the complex with ID habitComplexID,
+// and a check-in for each day a habit was done, with the row's line as its
+// ID. Rows that were not done add nothing.
+type habitLog struct {
+	data       *export.Data
+	rejections []Rejection
+	habits     []string
+}
+
+// ref names the habit of a goal or action and the line of a check-in.
+func (l *habitLog) ref(entity string, id uint) string {
+	if entity == export.EntityCompletions {
+		return "line " + strconv.FormatUint(uint64(id), 10)
+	}
+	return l.habits[id-1]
+}
+
+// parseHabitLog reads a log with a row for each habit and date, in columns
+// such as "date,habit,done", or with a row for each date and a column for
+// each habit. Without a done column, every row of the first layout is a
+// day the habit was done.
+func parseHabitLog(content []byte, now time.Time) (*habitLog, error) {
+	r := csv.NewReader(bytes.NewReader(content))
+	r.Comma = delimiter(content)
+	r.FieldsPerRecord = -1
+	r.LazyQuotes = true
+	r.TrimLeadingSpace = true
+
+	header, err := r.Read()
+	if err == io.EOF {
+		return nil, i18n.NewError(i18n.DetailEmptyFile)
+	}
+	if err != nil {
+		return nil, err
+	}
+	dateCol, habitCol, doneCol := -1, -1, -1
+	for i, name := range header {
+		switch name = columnName(name); {
+		case dateCol < 0 && contains(dateColumns, name):
+			dateCol = i
+		case habitCol < 0 && contains(habitColumns, name):
+			habitCol = i
+		case doneCol < 0 && contains(doneColumns, name):
+			doneCol = i
+		}
+	}
+	if dateCol < 0 {
+		return nil, i18n.NewError(i18n.DetailNoDateColumn)
+	}
+	// A log without a habit column has one for each habit.
+	var habitCols []int
+	if habitCol < 0 {
+		for i, name := range header {
+			if i != dateCol && strings.TrimSpace(name) != "" {
+				habitCols = append(habitCols, i)
+			}
+		}
+		if len(habitCols) == 0 {
+			return nil, i18n.NewError(i18n.DetailNoHabitColumn)
+		}
+	}
+
+	l := &habitLog{data: &export.Data{}}
+	actions := map[string]uint{}
+	reject := func(line int, code i18n.Code, args ...interface{}) {
+		l.rejections = append(l.rejections, Rejection{Entity: export.EntityCompletions, Ref: fmt.Sprintf("line %d", line), Code: code, Args: args})
+	}
+	add := func(line int, habit string, date time.Time, completedAt *time.Time, value string, hasValue bool) {
+		habit = strings.TrimSpace(habit)
+		if habit == "" {
+			reject(line, i18n.FieldRequired, "habit")
+			return
+		}
+		done := true
+		if hasValue {
+			var ok bool
+			if done, ok = parseDone(value); !ok {
+				reject(line, i18n.ImportUnknownDone, value)
+				return
+			}
+		}
+		actionID := l.habit(actions, habit, date, now)
+		if !done {
+			return
+		}
+		if completedAt == nil {
+			completedAt = &date
+		}
+		l.data.Completions = append(l.data.Completions, export.Completion{
+			ID: uint(line), ActionID: actionID, OccurrenceDate: date.Format(time.DateOnly), Status: models.CompletionDone,
+			CompletedAt: completedAt, CreatedAt: now, UpdatedAt: now,
+		})
+	}
+
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := r.FieldPos(0)
+		if blank(record) {
+			continue
+		}
+		date, completedAt, ok := parseDate(field(record, dateCol))
+		if !ok {
+			reject(line, i18n.ImportInvalidDate, field(record, dateCol))
+			continue
+		}
+		if habitCol >= 0 {
+			add(line, field(record, habitCol), date, completedAt, field(record, doneCol), doneCol >= 0)
+			continue
+		}
+		for _, col := range habitCols {
+			add(line, header[col], date, completedAt, field(record, col), true)
+		}
+	}
+	return l, nil
+}
+
+// habit returns the ID of the action made for a habit, making it and its
+// goal the first time. Both are taken to be created on the habit's first
+// day in the log.
+func (l *habitLog) habit(actions map[string]uint, name string, date, now time.Time) uint {
+	if id, ok := actions[name]; ok {
+		if date.Before(l.data.Actions[id-1].CreatedAt) {
+			l.data.Actions[id-1].CreatedAt = date
+			l.data.Goals[id-1].CreatedAt = date
+		}
+		return id
+	}
+	l.habits = append(l.habits, name)
+	id := uint(len(l.habits))
+	l.data.Goals = append(l.data.Goals, export.Goal{ID: id, ComplexID: habitComplexID, Content: name, CreatedAt: date, UpdatedAt: now})
+	l.data.Actions = append(l.data.Actions, export.Action{
+		ID: id, GoalID: id, Content: name,
+		RecurrencePattern: &recurrence.Pattern{Frequency: recurrence.Daily, Interval: 1, TimeOfDay: habitTimeOfDay},
+		CreatedAt:         date, UpdatedAt: now,
+	})
+	actions[name] = id
+	return id
+}
+
+// delimiter guesses the delimiter of a CSV file from its first line: a
+// comma, a semicolon or a tab.
+func delimiter(content []byte) rune {
+	first, _, _ := bytes.Cut(content, []byte("\n"))
+	comma, best := ',', bytes.Count(first, []byte(","))
+	for _, d := range []rune{';', '\t'} {
+		if n := bytes.Count(first, []byte(string(d))); n > best {
+			comma, best = d, n
+		}
+	}
+	return comma
+}
+
+func columnName(name string) string {
+	return strings.NewReplacer(" ", "", "_", "", "-", "").Replace(strings.ToLower(strings.TrimSpace(name)))
+}
+
+func contains(values []string, v string) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
+
+func field(record []string, i int) string {
+	if i < 0 || i >= len(record) {
+		return ""
+	}
+	return strings.TrimSpace(record[i])
+}
+
+func blank(record []string) bool {
+	for _, v := range record {
+		if strings.TrimSpace(v) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// parseDate returns the calendar date a row was written for, as midnight
+// UTC, and the time of day it was done if the row gives one.
+func parseDate(s string) (time.Time, *time.Time, bool) {
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil, true
+		}
+	}
+	for _, layout := range dateTimeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC), &t, true
+		}
+	}
+	return time.Time{}, nil, false
+}
+
+// parseDone reports whether a done column's value means the habit was
+// done, and whether the value was understood.
+func parseDone(v string) (done, ok bool) {
+	v = strings.ToLower(strings.TrimSpace(v))
+	switch {
+	case contains(doneValues, v):
+		return true, true
+	case contains(notDoneValues, v):
+		return false, true
+	}
+	if n, err := strconv.ParseFloat(v, 64); err == nil {
+		return n > 0, true
+	}
+	return false, false
+}
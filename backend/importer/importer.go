@@ -0,0 +1,601 @@
+// Package importer plans the import of an uploaded file into a user's
+// records: an archive written by the export package, the same data as a
+// single JSON document, or the CSV log of a habit tracker. Records the
+// user already has are deduplicated and invalid ones are rejected with a
+// reason, so that the outcome can be reported before anything is written.
+// Imports add records only: nothing the user has is changed or removed.
+package importer
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"refuel/backend/checkin"
+	"refuel/backend/export"
+	"refuel/backend/i18n"
+	"refuel/backend/models"
+	"refuel/backend/progress"
+	"refuel/backend/quantity"
+	"refuel/backend/repository"
+)
+
+// Formats of the files an import reads.
+const (
+	FormatArchive = "archive"
+	FormatJSON    = "json"
+	FormatCSV     = "csv"
+)
+
+// utf8BOM is skipped at the start of a file, as spreadsheet programs
+// write it before CSV.
+const utf8BOM = "\ufeff"
+
+// Entities lists the kinds of records an import creates, in the order
+// they are planned and reported. Milestones, badges and revisions are not
+// imported: badges are earned again from the imported records.
+var Entities = []string{
+	export.EntityComplexes,
+	export.EntityGoals,
+	export.EntityMeasurements,
+	export.EntityActions,
+	export.EntityGains,
+	export.EntityLosses,
+	export.EntityCompletions,
+}
+
+// Error is a problem with the file as a whole, which rejects the import.
+type Error struct {
+	Code i18n.Code
+	Args []interface{}
+}
+
+func (e *Error) Error() string {
+	return i18n.T(i18n.English, e.Code, e.Args...)
+}
+
+// Options are the settings of an import.
+type Options struct {
+	// ComplexID is the complex the goals of a CSV habit log are created
+	// under. It must be one of the user's complexes outside the trash.
+	ComplexID uint
+	// Now is the time of the import: check-ins done on a later day are
+	// rejected.
+	Now time.Time
+}
+
+// Counts are the outcome of an import for one kind of record.
+type Counts struct {
+	Created      int
+	Deduplicated int
+	Rejected     int
+}
+
+// Rejection tells why a record of the file is not imported.
+type Rejection struct {
+	Entity string
+	// Ref locates the record in the file: "id 3" for the record with ID 3
+	// in an archive, "line 12" or the habit's name in a CSV log.
+	Ref  string
+	Code i18n.Code
+	Args []interface{}
+}
+
+// Plan is the outcome of an import, and the records to create for it.
+type Plan struct {
+	Format     string
+	Batch      repository.ImportBatch
+	Counts     map[string]*Counts
+	Rejections []Rejection
+}
+
+// Creates reports whether the plan creates any record.
+func (p *Plan) Creates() bool {
+	for _, c := range p.Counts {
+		if c.Created > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// NewPlan reads content and plans its import for the user, whose current
+// records are existing. It returns an *Error if the file cannot be read.
+func NewPlan(userID string, content []byte, existing *repository.UserData, opts Options) (*Plan, error) {
+	content = bytes.TrimPrefix(content, []byte(utf8BOM))
+	p := newPlanner(userID, existing, opts.Now)
+	switch {
+	case bytes.HasPrefix(content, []byte("PK\x03\x04")):
+		_, data, err := export.Read(content)
+		if err != nil {
+			return nil, &Error{Code: i18n.InvalidImportFile, Args: []interface{}{err}}
+		}
+		p.plan.Format = FormatArchive
+		p.run(data)
+	case bytes.HasPrefix(bytes.TrimSpace(content), []byte("{")):
+		var data export.Data
+		if err := json.Unmarshal(content, &data); err != nil {
+			return nil, &Error{Code: i18n.InvalidImportFile, Args: []interface{}{err}}
+		}
+		p.plan.Format = FormatJSON
+		p.run(&data)
+	default:
+		if opts.ComplexID == 0 {
+			return nil, &Error{Code: i18n.ImportComplexRequired}
+		}
+		target := p.existingComplexes[opts.ComplexID]
+		if target == nil {
+			return nil, &Error{Code: i18n.ReferencedComplexNotFound}
+		}
+		log, err := parseHabitLog(content, opts.Now)
+		if err != nil {
+			return nil, &Error{Code: i18n.InvalidImportFile, Args: []interface{}{err}}
+		}
+		p.plan.Format = FormatCSV
+		p.plan.Rejections = append(p.plan.Rejections, log.rejections...)
+		p.counts(export.EntityCompletions).Rejected += len(log.rejections)
+		p.ref = log.ref
+		p.complexes[habitComplexID] = p.existingComplex(*target)
+		p.run(log.data)
+	}
+	return p.plan, nil
+}
+
+// complexNode, goalNode and actionNode are the records of the batch, with
+// what their children are deduplicated against: the children already
+// stored and those planned so far.
+type complexNode struct {
+	*repository.ImportComplex
+	goals map[string]*goalNode
+}
+
+type goalNode struct {
+	*repository.ImportGoal
+	actions  map[string]*actionNode
+	measured map[string]bool
+}
+
+type actionNode struct {
+	*repository.ImportAction
+	gains  map[string]bool
+	losses map[string]bool
+	dates  map[string]bool
+}
+
+type planner struct {
+	userID string
+	plan   *Plan
+	// today is the last date a check-in can be done on: today anywhere.
+	today time.Time
+	// ref names a record of the file in rejections.
+	ref func(entity string, id uint) string
+
+	existing          *repository.UserData
+	existingComplexes map[uint]*models.Complex
+
+	// byContent finds the complex with a content and category.
+	byContent map[string]*complexNode
+	// complexes, goals and actions are the nodes by their ID in the file.
+	complexes map[uint]*complexNode
+	goals     map[uint]*goalNode
+	actions   map[uint]*actionNode
+}
+
+func newPlanner(userID string, existing *repository.UserData, now time.Time) *planner {
+	p := &planner{
+		userID:            userID,
+		plan:              &Plan{Counts: map[string]*Counts{}},
+		today:             checkin.Civil(now, time.FixedZone("UTC+14", 14*60*60)),
+		ref:               func(entity string, id uint) string { return "id " + strconv.FormatUint(uint64(id), 10) },
+		existing:          existing,
+		existingComplexes: map[uint]*models.Complex{},
+		byContent:         map[string]*complexNode{},
+		complexes:         map[uint]*complexNode{},
+		goals:             map[uint]*goalNode{},
+		actions:           map[uint]*actionNode{},
+	}
+	for _, entity := range Entities {
+		p.plan.Counts[entity] = &Counts{}
+	}
+	for i, c := range existing.Complexes {
+		if !c.DeletedAt.Valid {
+			p.existingComplexes[c.ID] = &existing.Complexes[i]
+		}
+	}
+	return p
+}
+
+func (p *planner) counts(entity string) *Counts {
+	return p.plan.Counts[entity]
+}
+
+func (p *planner) reject(entity string, id uint, code i18n.Code, args ...interface{}) {
+	p.counts(entity).Rejected++
+	p.plan.Rejections = append(p.plan.Rejections, Rejection{Entity: entity, Ref: p.ref(entity, id), Code: code, Args: args})
+}
+
+func (p *planner) created(entity string) {
+	p.counts(entity).Created++
+}
+
+func (p *planner) deduplicated(entity string) {
+	p.counts(entity).Deduplicated++
+}
+
+// run plans the records of data, parents first.
+func (p *planner) run(data *export.Data) {
+	for _, c := range data.Complexes {
+		p.complex(c)
+	}
+	for _, g := range data.Goals {
+		p.goal(g)
+	}
+	for _, m := range data.Measurements {
+		p.measurement(m)
+	}
+	for _, a := range data.Actions {
+		p.action(a)
+	}
+	for _, g := range data.Gains {
+		p.outcome(export.EntityGains, g)
+	}
+	for _, l := range data.Losses {
+		p.outcome(export.EntityLosses, l)
+	}
+	for _, c := range data.Completions {
+		p.completion(c)
+	}
+}
+
+func contentKey(content, category string) string {
+	return content + "\x00" + category
+}
+
+func (p *planner) complex(c export.Complex) {
+	entity := export.EntityComplexes
+	if c.DeletedAt != nil {
+		p.reject(entity, c.ID, i18n.ImportInTrash)
+		return
+	}
+	content, category := strings.TrimSpace(c.Content), strings.TrimSpace(c.Category)
+	if content == "" || category == "" {
+		p.reject(entity, c.ID, i18n.FieldRequired, "content, category")
+		return
+	}
+	key := contentKey(content, category)
+	node := p.byContent[key]
+	if node == nil {
+		for _, stored := range p.existingComplexes {
+			if contentKey(stored.Content, stored.Category) == key {
+				node = p.existingComplex(*stored)
+				break
+			}
+		}
+	}
+	if node != nil {
+		p.deduplicated(entity)
+		p.complexes[c.ID] = node
+		return
+	}
+	node = p.newComplex(models.Complex{
+		UserID: p.userID, Content: content, TriggerEpisode: c.TriggerEpisode, Category: category,
+		CreatedAt: c.CreatedAt, UpdatedAt: c.UpdatedAt,
+	})
+	p.created(entity)
+	p.complexes[c.ID] = node
+}
+
+// existingComplex returns the node of a stored complex, adding it to the
+// batch the first time.
+func (p *planner) existingComplex(c models.Complex) *complexNode {
+	key := contentKey(c.Content, c.Category)
+	if node, ok := p.byContent[key]; ok && node.Complex.ID == c.ID {
+		return node
+	}
+	node := p.newComplex(c)
+	for i, g := range p.existing.Goals {
+		if g.ComplexID == c.ID && !g.DeletedAt.Valid {
+			if _, dup := node.goals[g.Content]; !dup {
+				node.goals[g.Content] = p.existingGoal(node, &p.existing.Goals[i])
+			}
+		}
+	}
+	return node
+}
+
+func (p *planner) newComplex(c models.Complex) *complexNode {
+	node := &complexNode{ImportComplex: &repository.ImportComplex{Complex: c}, goals: map[string]*goalNode{}}
+	p.plan.Batch.Complexes = append(p.plan.Batch.Complexes, node.ImportComplex)
+	if _, taken := p.byContent[contentKey(c.Content, c.Category)]; !taken {
+		p.byContent[contentKey(c.Content, c.Category)] = node
+	}
+	return node
+}
+
+func (p *planner) existingGoal(parent *complexNode, g *models.Goal) *goalNode {
+	node := p.newGoal(parent, *g)
+	for _, m := range p.existing.Measurements {
+		if m.GoalID == g.ID {
+			node.measured[measurementKey(m.MeasuredAt, m.Value)] = true
+		}
+	}
+	for i, a := range p.existing.Actions {
+		if a.GoalID == g.ID && !a.DeletedAt.Valid {
+			if _, dup := node.actions[a.Content]; !dup {
+				node.actions[a.Content] = p.existingAction(node, &p.existing.Actions[i])
+			}
+		}
+	}
+	return node
+}
+
+func (p *planner) newGoal(parent *complexNode, g models.Goal) *goalNode {
+	node := &goalNode{ImportGoal: &repository.ImportGoal{Goal: g}, actions: map[string]*actionNode{}, measured: map[string]bool{}}
+	parent.Goals = append(parent.Goals, node.ImportGoal)
+	return node
+}
+
+func (p *planner) existingAction(parent *goalNode, a *models.Action) *actionNode {
+	node := p.newAction(parent, *a)
+	for _, g := range p.existing.Gains {
+		if g.ActionID == a.ID {
+			node.gains[outcomeKey(g.Type, g.Description, g.Value, g.Unit)] = true
+		}
+	}
+	for _, l := range p.existing.Losses {
+		if l.ActionID == a.ID {
+			node.losses[outcomeKey(l.Type, l.Description, l.Value, l.Unit)] = true
+		}
+	}
+	for _, c := range p.existing.Completions {
+		if c.ActionID == a.ID {
+			node.dates[checkin.Key(c.OccurrenceDate)] = true
+		}
+	}
+	return node
+}
+
+func (p *planner) newAction(parent *goalNode, a models.Action) *actionNode {
+	node := &actionNode{
+		ImportAction: &repository.ImportAction{Action: a},
+		gains:        map[string]bool{}, losses: map[string]bool{}, dates: map[string]bool{},
+	}
+	parent.Actions = append(parent.Actions, node.ImportAction)
+	return node
+}
+
+func (p *planner) goal(g export.Goal) {
+	entity := export.EntityGoals
+	if g.DeletedAt != nil {
+		p.reject(entity, g.ID, i18n.ImportInTrash)
+		return
+	}
+	parent := p.complexes[g.ComplexID]
+	if parent == nil {
+		p.reject(entity, g.ID, i18n.ImportParentMissing, i18n.NounComplex, g.ComplexID)
+		return
+	}
+	content := strings.TrimSpace(g.Content)
+	if content == "" {
+		p.reject(entity, g.ID, i18n.FieldRequired, "content")
+		return
+	}
+	if node, ok := parent.goals[content]; ok {
+		p.deduplicated(entity)
+		p.goals[g.ID] = node
+		return
+	}
+	goal := models.Goal{UserID: p.userID, Content: content, CreatedAt: g.CreatedAt, UpdatedAt: g.UpdatedAt}
+	if g.MetricDirection != "" {
+		if err := applyMetric(&goal, g); err != nil {
+			p.reject(entity, g.ID, i18n.InvalidMetric, err)
+			return
+		}
+	}
+	node := p.newGoal(parent, goal)
+	parent.goals[content] = node
+	p.created(entity)
+	p.goals[g.ID] = node
+}
+
+// applyMetric validates the exported metric of a goal and sets it on goal,
+// as the API does when a goal is created.
+func applyMetric(goal *models.Goal, g export.Goal) error {
+	unit, err := quantity.NormalizeUnit(g.MetricUnit)
+	if err != nil {
+		return err
+	}
+	if g.MetricBaseline == nil || g.MetricTarget == nil {
+		return i18n.NewError(i18n.DetailMetricIncomplete)
+	}
+	metric := progress.Metric{Baseline: *g.MetricBaseline, Target: *g.MetricTarget, Direction: progress.Direction(g.MetricDirection)}
+	if err := metric.Validate(); err != nil {
+		return err
+	}
+	if g.MetricDeadline != "" {
+		deadline, err := checkin.ParseDate(g.MetricDeadline)
+		if err != nil {
+			return i18n.NewError(i18n.DetailDeadline, g.MetricDeadline)
+		}
+		goal.MetricDeadline = &deadline
+	}
+	goal.MetricUnit = unit
+	goal.MetricBaseline, goal.MetricTarget = &metric.Baseline, &metric.Target
+	goal.MetricDirection = g.MetricDirection
+	return nil
+}
+
+func measurementKey(at time.Time, value float64) string {
+	return at.UTC().Format(time.RFC3339Nano) + "\x00" + strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+func (p *planner) measurement(m export.Measurement) {
+	entity := export.EntityMeasurements
+	parent := p.goals[m.GoalID]
+	if parent == nil {
+		p.reject(entity, m.ID, i18n.ImportParentMissing, i18n.NounGoal, m.GoalID)
+		return
+	}
+	if !parent.Goal.HasMetric() {
+		p.reject(entity, m.ID, i18n.GoalHasNoMetric)
+		return
+	}
+	if m.MeasuredAt.IsZero() {
+		p.reject(entity, m.ID, i18n.FieldRequired, "measured_at")
+		return
+	}
+	key := measurementKey(m.MeasuredAt, m.Value)
+	if parent.measured[key] {
+		p.deduplicated(entity)
+		return
+	}
+	parent.measured[key] = true
+	parent.Measurements = append(parent.Measurements, models.GoalMeasurement{
+		UserID: p.userID, Value: m.Value, MeasuredAt: m.MeasuredAt, Note: m.Note, CreatedAt: m.CreatedAt,
+	})
+	p.created(entity)
+}
+
+func (p *planner) action(a export.Action) {
+	entity := export.EntityActions
+	if a.DeletedAt != nil {
+		p.reject(entity, a.ID, i18n.ImportInTrash)
+		return
+	}
+	parent := p.goals[a.GoalID]
+	if parent == nil {
+		p.reject(entity, a.ID, i18n.ImportParentMissing, i18n.NounGoal, a.GoalID)
+		return
+	}
+	content := strings.TrimSpace(a.Content)
+	if content == "" {
+		p.reject(entity, a.ID, i18n.FieldRequired, "content")
+		return
+	}
+	if node, ok := parent.actions[content]; ok {
+		p.deduplicated(entity)
+		p.actions[a.ID] = node
+		return
+	}
+	action := models.Action{UserID: p.userID, Content: content, CompletedAt: a.CompletedAt, CreatedAt: a.CreatedAt, UpdatedAt: a.UpdatedAt}
+	if a.RecurrencePattern != nil {
+		pattern := a.RecurrencePattern.Normalize()
+		if err := pattern.Validate(); err != nil {
+			p.reject(entity, a.ID, i18n.InvalidRecurrencePattern, err)
+			return
+		}
+		action.RecurrencePattern = &pattern
+	}
+	node := p.newAction(parent, action)
+	parent.actions[content] = node
+	p.created(entity)
+	p.actions[a.ID] = node
+}
+
+func outcomeKey(outcomeType, description string, value *float64, unit string) string {
+	v := ""
+	if value != nil {
+		v = strconv.FormatFloat(*value, 'g', -1, 64)
+	}
+	return strings.Join([]string{outcomeType, description, v, unit}, "\x00")
+}
+
+// outcome plans a gain or loss, validated as the API validates them.
+func (p *planner) outcome(entity string, o export.Outcome) {
+	parent := p.actions[o.ActionID]
+	if parent == nil {
+		p.reject(entity, o.ID, i18n.ImportParentMissing, i18n.NounAction, o.ActionID)
+		return
+	}
+	kind := i18n.NounGain
+	if entity == export.EntityLosses {
+		kind = i18n.NounLoss
+	}
+	if err := checkOutcome(kind, &o); err != nil {
+		p.reject(entity, o.ID, i18n.ValidationFailed, err)
+		return
+	}
+	seen := parent.gains
+	if entity == export.EntityLosses {
+		seen = parent.losses
+	}
+	key := outcomeKey(o.Type, o.Description, o.Value, o.Unit)
+	if seen[key] {
+		p.deduplicated(entity)
+		return
+	}
+	seen[key] = true
+	if entity == export.EntityLosses {
+		parent.Losses = append(parent.Losses, models.Loss{Type: o.Type, Description: o.Description, Value: o.Value, Unit: o.Unit})
+	} else {
+		parent.Gains = append(parent.Gains, models.Gain{Type: o.Type, Description: o.Description, Value: o.Value, Unit: o.Unit})
+	}
+	p.created(entity)
+}
+
+// checkOutcome validates a gain or loss, which kind names, and stores its
+// unit in its canonical spelling.
+func checkOutcome(kind i18n.Code, o *export.Outcome) error {
+	o.Description = strings.TrimSpace(o.Description)
+	if o.Description == "" {
+		return i18n.NewError(i18n.DetailDescriptionRequired, kind)
+	}
+	switch o.Type {
+	case models.Quantitative:
+		if o.Value == nil || *o.Value <= 0 {
+			return i18n.NewError(i18n.DetailOutcomeValueRequired, kind)
+		}
+		if o.Unit == "" {
+			return i18n.NewError(i18n.DetailOutcomeUnitRequired, kind)
+		}
+		unit, err := quantity.NormalizeUnit(o.Unit)
+		if err != nil {
+			return err
+		}
+		o.Unit = unit
+	case models.Qualitative:
+		if o.Value != nil || o.Unit != "" {
+			return i18n.NewError(i18n.DetailQualitativeOutcome, kind)
+		}
+	default:
+		return i18n.NewError(i18n.DetailOutcomeType, kind, models.Quantitative, models.Qualitative)
+	}
+	return nil
+}
+
+func (p *planner) completion(c export.Completion) {
+	entity := export.EntityCompletions
+	parent := p.actions[c.ActionID]
+	if parent == nil {
+		p.reject(entity, c.ID, i18n.ImportParentMissing, i18n.NounAction, c.ActionID)
+		return
+	}
+	switch c.Status {
+	case models.CompletionDone, models.CompletionSkipped, models.CompletionMissed:
+	default:
+		p.reject(entity, c.ID, i18n.InvalidChoice, "status", "done, skipped, missed")
+		return
+	}
+	date, err := checkin.ParseDate(c.OccurrenceDate)
+	if err != nil {
+		p.reject(entity, c.ID, i18n.InvalidDate, "occurrence_date")
+		return
+	}
+	if c.Status == models.CompletionDone && date.After(p.today) {
+		p.reject(entity, c.ID, i18n.FutureCompletion)
+		return
+	}
+	key := checkin.Key(date)
+	if parent.dates[key] {
+		p.deduplicated(entity)
+		return
+	}
+	parent.dates[key] = true
+	parent.Completions = append(parent.Completions, models.ActionCompletion{
+		UserID: p.userID, OccurrenceDate: date, Status: c.Status, CompletedAt: c.CompletedAt, Note: c.Note,
+		CreatedAt: c.CreatedAt, UpdatedAt: c.UpdatedAt,
+	})
+	p.created(entity)
+}
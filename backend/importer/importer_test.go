@@ -0,0 +1,521 @@
+package importer
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+
+	"refuel/backend/export"
+	"refuel/backend/i18n"
+	"refuel/backend/models"
+	"refuel/backend/recurrence"
+	"refuel/backend/repository"
+)
+
+// now is noon UTC on April 10, 2025: April 11 has begun in UTC+14.
+var now = time.Date(2025, 4, 10, 12, 0, 0, 0, time.UTC)
+
+func date(day int) time.Time {
+	return time.Date(2025, 4, day, 0, 0, 0, 0, time.UTC)
+}
+
+func float(v float64) *float64 {
+	return &v
+}
+
+// existing returns a user's records: a complex with a goal and an action
+// checked in on April 1, a habit "読書" checked in on the same day, and a
+// complex in the trash.
+func existing() *repository.UserData {
+	return &repository.UserData{
+		Complexes: []models.Complex{
+			{ID: 1, UserID: "u1", Content: "人前で話すのが怖い", Category: "仕事"},
+			{ID: 2, UserID: "u1", Content: "ゴミ箱", Category: "仕事", DeletedAt: gorm.DeletedAt{Time: now, Valid: true}},
+		},
+		Goals: []models.Goal{
+			{ID: 10, UserID: "u1", ComplexID: 1, Content: "発表する"},
+			{ID: 11, UserID: "u1", ComplexID: 1, Content: "読書"},
+		},
+		Actions: []models.Action{
+			{ID: 100, UserID: "u1", GoalID: 10, Content: "練習する"},
+			{ID: 101, UserID: "u1", GoalID: 11, Content: "読書"},
+		},
+		Gains: []models.Gain{
+			{ActionID: 100, Type: models.Qualitative, Description: "自信"},
+		},
+		Completions: []models.ActionCompletion{
+			{ActionID: 100, OccurrenceDate: date(1), Status: models.CompletionDone},
+			{ActionID: 101, OccurrenceDate: date(1), Status: models.CompletionDone},
+		},
+	}
+}
+
+// counts returns the plan's counts, leaving out the entities with none.
+func counts(p *Plan) map[string]Counts {
+	got := map[string]Counts{}
+	for entity, c := range p.Counts {
+		if *c != (Counts{}) {
+			got[entity] = *c
+		}
+	}
+	return got
+}
+
+// rejections returns the plan's rejections as "entity ref code".
+func rejections(p *Plan) []string {
+	var got []string
+	for _, r := range p.Rejections {
+		got = append(got, r.Entity+" "+r.Ref+" "+string(r.Code))
+	}
+	return got
+}
+
+func checkPlan(t *testing.T, p *Plan, wantCounts map[string]Counts, wantRejections []string) {
+	t.Helper()
+	got := counts(p)
+	for _, entity := range Entities {
+		if got[entity] != wantCounts[entity] {
+			t.Errorf("%s: got %+v, want %+v", entity, got[entity], wantCounts[entity])
+		}
+	}
+	if strings.Join(rejections(p), "\n") != strings.Join(wantRejections, "\n") {
+		t.Errorf("got rejections %q, want %q", rejections(p), wantRejections)
+	}
+}
+
+func TestNewPlanJSON(t *testing.T) {
+	// complex, goal and action are records matching the existing ones.
+	complex := export.Complex{ID: 5, Content: " 人前で話すのが怖い ", Category: "仕事"}
+	goal := export.Goal{ID: 6, ComplexID: 5, Content: "発表する"}
+	action := export.Action{ID: 7, GoalID: 6, Content: "練習する"}
+	trashed := now
+	tests := []struct {
+		name       string
+		data       export.Data
+		counts     map[string]Counts
+		rejections []string
+	}{
+		{
+			name: "existing records are deduplicated",
+			data: export.Data{
+				Complexes:   []export.Complex{complex},
+				Goals:       []export.Goal{goal},
+				Actions:     []export.Action{action},
+				Gains:       []export.Outcome{{ID: 8, ActionID: 7, Type: models.Qualitative, Description: "自信"}},
+				Completions: []export.Completion{{ID: 9, ActionID: 7, OccurrenceDate: "2025-04-01", Status: models.CompletionDone}},
+			},
+			counts: map[string]Counts{
+				export.EntityComplexes:   {Deduplicated: 1},
+				export.EntityGoals:       {Deduplicated: 1},
+				export.EntityActions:     {Deduplicated: 1},
+				export.EntityGains:       {Deduplicated: 1},
+				export.EntityCompletions: {Deduplicated: 1},
+			},
+		},
+		{
+			name: "new records under existing ones",
+			data: export.Data{
+				Complexes: []export.Complex{complex},
+				Goals:     []export.Goal{goal, {ID: 16, ComplexID: 5, Content: "会議で発言する"}},
+				Actions: []export.Action{action, {
+					ID: 17, GoalID: 16, Content: "一回発言する",
+					RecurrencePattern: &recurrence.Pattern{Frequency: recurrence.Weekly, TimeOfDay: "10:00", DaysOfWeek: []string{"mon"}},
+				}},
+				Losses: []export.Outcome{{ID: 8, ActionID: 17, Type: models.Quantitative, Description: "時間", Value: float(30), Unit: "分"}},
+				Completions: []export.Completion{
+					{ID: 9, ActionID: 7, OccurrenceDate: "2025-04-02", Status: models.CompletionDone},
+					{ID: 10, ActionID: 17, OccurrenceDate: "2025-04-07", Status: models.CompletionMissed},
+				},
+			},
+			counts: map[string]Counts{
+				export.EntityComplexes:   {Deduplicated: 1},
+				export.EntityGoals:       {Created: 1, Deduplicated: 1},
+				export.EntityActions:     {Created: 1, Deduplicated: 1},
+				export.EntityLosses:      {Created: 1},
+				export.EntityCompletions: {Created: 2},
+			},
+		},
+		{
+			name: "duplicates within the file",
+			data: export.Data{
+				Complexes: []export.Complex{
+					{ID: 1, Content: "雑談が苦手", Category: "人間関係"},
+					{ID: 2, Content: "雑談が苦手", Category: "人間関係"},
+					{ID: 3, Content: "雑談が苦手", Category: "仕事"},
+				},
+				Goals: []export.Goal{
+					{ID: 1, ComplexID: 1, Content: "話しかける"},
+					{ID: 2, ComplexID: 2, Content: "話しかける"},
+				},
+				Actions: []export.Action{{ID: 1, GoalID: 2, Content: "挨拶する"}},
+				Completions: []export.Completion{
+					{ID: 1, ActionID: 1, OccurrenceDate: "2025-04-03", Status: models.CompletionDone},
+					{ID: 2, ActionID: 1, OccurrenceDate: "2025-04-03", Status: models.CompletionSkipped},
+				},
+			},
+			counts: map[string]Counts{
+				export.EntityComplexes:   {Created: 2, Deduplicated: 1},
+				export.EntityGoals:       {Created: 1, Deduplicated: 1},
+				export.EntityActions:     {Created: 1},
+				export.EntityCompletions: {Created: 1, Deduplicated: 1},
+			},
+		},
+		{
+			name: "metrics and measurements",
+			data: export.Data{
+				Complexes: []export.Complex{complex},
+				Goals: []export.Goal{
+					{ID: 1, ComplexID: 5, Content: "痩せる", MetricUnit: "kgs", MetricBaseline: float(70), MetricTarget: float(65), MetricDirection: "decrease", MetricDeadline: "2025-12-31"},
+					{ID: 2, ComplexID: 5, Content: "走る", MetricUnit: "km", MetricBaseline: float(5), MetricTarget: float(3), MetricDirection: "increase"},
+				},
+				Measurements: []export.Measurement{
+					{ID: 1, GoalID: 1, Value: 69.5, MeasuredAt: date(2)},
+					{ID: 2, GoalID: 1, Value: 69.5, MeasuredAt: date(2)},
+					{ID: 3, GoalID: 1, Value: 69},
+					{ID: 4, GoalID: 10, Value: 1, MeasuredAt: date(2)},
+				},
+			},
+			counts: map[string]Counts{
+				export.EntityComplexes:    {Deduplicated: 1},
+				export.EntityGoals:        {Created: 1, Rejected: 1},
+				export.EntityMeasurements: {Created: 1, Deduplicated: 1, Rejected: 2},
+			},
+			rejections: []string{
+				"goals id 2 " + string(i18n.InvalidMetric),
+				"measurements id 3 " + string(i18n.FieldRequired),
+				"measurements id 4 " + string(i18n.ImportParentMissing),
+			},
+		},
+		{
+			name: "invalid records are rejected",
+			data: export.Data{
+				Complexes: []export.Complex{
+					complex,
+					{ID: 2, Content: "消した", Category: "仕事", DeletedAt: &trashed},
+					{ID: 3, Content: "  ", Category: "仕事"},
+				},
+				Goals: []export.Goal{
+					goal,
+					{ID: 2, ComplexID: 2, Content: "消した複合の目標"},
+					{ID: 3, ComplexID: 5, Content: ""},
+				},
+				Measurements: []export.Measurement{{ID: 1, GoalID: 6, Value: 1, MeasuredAt: date(2)}},
+				Actions: []export.Action{
+					action,
+					{ID: 2, GoalID: 6, Content: "消した", DeletedAt: &trashed},
+					{ID: 3, GoalID: 6, Content: "毎年", RecurrencePattern: &recurrence.Pattern{Frequency: "yearly", TimeOfDay: "09:00"}},
+				},
+				Gains: []export.Outcome{
+					{ID: 1, ActionID: 7, Type: models.Quantitative, Description: "体重"},
+					{ID: 2, ActionID: 7, Type: models.Qualitative, Description: "自信", Unit: "kg"},
+					{ID: 3, ActionID: 3, Type: models.Qualitative, Description: "満足"},
+				},
+				Completions: []export.Completion{
+					{ID: 1, ActionID: 7, OccurrenceDate: "2025-04-02", Status: "maybe"},
+					{ID: 2, ActionID: 7, OccurrenceDate: "04/02/2025", Status: models.CompletionDone},
+					{ID: 3, ActionID: 7, OccurrenceDate: "2025-04-12", Status: models.CompletionDone},
+				},
+			},
+			counts: map[string]Counts{
+				export.EntityComplexes:    {Deduplicated: 1, Rejected: 2},
+				export.EntityGoals:        {Deduplicated: 1, Rejected: 2},
+				export.EntityMeasurements: {Rejected: 1},
+				export.EntityActions:      {Deduplicated: 1, Rejected: 2},
+				export.EntityGains:        {Rejected: 3},
+				export.EntityCompletions:  {Rejected: 3},
+			},
+			rejections: []string{
+				"complexes id 2 " + string(i18n.ImportInTrash),
+				"complexes id 3 " + string(i18n.FieldRequired),
+				"goals id 2 " + string(i18n.ImportParentMissing),
+				"goals id 3 " + string(i18n.FieldRequired),
+				"measurements id 1 " + string(i18n.GoalHasNoMetric),
+				"actions id 2 " + string(i18n.ImportInTrash),
+				"actions id 3 " + string(i18n.InvalidRecurrencePattern),
+				"gains id 1 " + string(i18n.ValidationFailed),
+				"gains id 2 " + string(i18n.ValidationFailed),
+				"gains id 3 " + string(i18n.ImportParentMissing),
+				"completions id 1 " + string(i18n.InvalidChoice),
+				"completions id 2 " + string(i18n.InvalidDate),
+				"completions id 3 " + string(i18n.FutureCompletion),
+			},
+		},
+		{
+			name: "check-ins done today anywhere",
+			data: export.Data{
+				Complexes: []export.Complex{complex},
+				Goals:     []export.Goal{goal},
+				Actions:   []export.Action{action},
+				Completions: []export.Completion{
+					{ID: 1, ActionID: 7, OccurrenceDate: "2025-04-11", Status: models.CompletionDone},
+					{ID: 2, ActionID: 7, OccurrenceDate: "2025-05-01", Status: models.CompletionSkipped},
+				},
+			},
+			counts: map[string]Counts{
+				export.EntityComplexes:   {Deduplicated: 1},
+				export.EntityGoals:       {Deduplicated: 1},
+				export.EntityActions:     {Deduplicated: 1},
+				export.EntityCompletions: {Created: 2},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content, err := json.Marshal(tt.data)
+			if err != nil {
+				t.Fatalf("Marshal: %v", err)
+			}
+			p, err := NewPlan("u1", content, existing(), Options{Now: now})
+			if err != nil {
+				t.Fatalf("NewPlan: %v", err)
+			}
+			if p.Format != FormatJSON {
+				t.Errorf("got format %q, want %q", p.Format, FormatJSON)
+			}
+			checkPlan(t, p, tt.counts, tt.rejections)
+		})
+	}
+}
+
+func TestNewPlanArchive(t *testing.T) {
+	// Re-importing a user's own export creates nothing.
+	content, err := export.Build("u1", export.FromUserData(existing()), now)
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	p, err := NewPlan("u1", content, existing(), Options{Now: now})
+	if err != nil {
+		t.Fatalf("NewPlan: %v", err)
+	}
+	if p.Format != FormatArchive {
+		t.Errorf("got format %q, want %q", p.Format, FormatArchive)
+	}
+	if p.Creates() {
+		t.Errorf("got counts %+v, want nothing created", counts(p))
+	}
+	checkPlan(t, p, map[string]Counts{
+		export.EntityComplexes:   {Deduplicated: 1, Rejected: 1},
+		export.EntityGoals:       {Deduplicated: 2},
+		export.EntityActions:     {Deduplicated: 2},
+		export.EntityGains:       {Deduplicated: 1},
+		export.EntityCompletions: {Deduplicated: 2},
+	}, []string{"complexes id 2 " + string(i18n.ImportInTrash)})
+}
+
+func TestNewPlanCSV(t *testing.T) {
+	tests := []struct {
+		name       string
+		content    string
+		counts     map[string]Counts
+		rejections []string
+	}{
+		{
+			name:    "a row for each habit and date",
+			content: "date,habit,done\n2025-04-01,運動,1\n2025-04-02,運動,no\n2025-04-02,瞑想,✓\n",
+			counts: map[string]Counts{
+				export.EntityGoals:       {Created: 2},
+				export.EntityActions:     {Created: 2},
+				export.EntityCompletions: {Created: 2},
+			},
+		},
+		{
+			name:    "without a done column",
+			content: "\ufeffDate,Habit Name\n2025/4/1,運動\n\n2025/04/02 07:30,運動\n",
+			counts: map[string]Counts{
+				export.EntityGoals:       {Created: 1},
+				export.EntityActions:     {Created: 1},
+				export.EntityCompletions: {Created: 2},
+			},
+		},
+		{
+			name:    "a column for each habit",
+			content: "日付;運動;瞑想\n2025-04-01;1;0\n2025-04-02;x;2\n",
+			counts: map[string]Counts{
+				export.EntityGoals:       {Created: 2},
+				export.EntityActions:     {Created: 2},
+				export.EntityCompletions: {Created: 3},
+			},
+		},
+		{
+			name:    "tab-separated",
+			content: "day\ttask\tstatus\n2025-04-01\t運動\tcompleted\n",
+			counts: map[string]Counts{
+				export.EntityGoals:       {Created: 1},
+				export.EntityActions:     {Created: 1},
+				export.EntityCompletions: {Created: 1},
+			},
+		},
+		{
+			name:    "existing habits are deduplicated",
+			content: "date,habit\n2025-04-01,読書\n2025-04-02,読書\n",
+			counts: map[string]Counts{
+				export.EntityGoals:       {Deduplicated: 1},
+				export.EntityActions:     {Deduplicated: 1},
+				export.EntityCompletions: {Created: 1, Deduplicated: 1},
+			},
+		},
+		{
+			name:    "invalid rows are rejected by line",
+			content: "date,habit,done\n2025-13-01,運動,1\n2025-04-01,,1\n2025-04-01,運動,maybe\n2025-04-12,運動,1\n",
+			counts: map[string]Counts{
+				export.EntityGoals:       {Created: 1},
+				export.EntityActions:     {Created: 1},
+				export.EntityCompletions: {Rejected: 4},
+			},
+			rejections: []string{
+				"completions line 2 " + string(i18n.ImportInvalidDate),
+				"completions line 3 " + string(i18n.FieldRequired),
+				"completions line 4 " + string(i18n.ImportUnknownDone),
+				"completions line 5 " + string(i18n.FutureCompletion),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := NewPlan("u1", []byte(tt.content), existing(), Options{ComplexID: 1, Now: now})
+			if err != nil {
+				t.Fatalf("NewPlan: %v", err)
+			}
+			if p.Format != FormatCSV {
+				t.Errorf("got format %q, want %q", p.Format, FormatCSV)
+			}
+			checkPlan(t, p, tt.counts, tt.rejections)
+			if len(p.Batch.Complexes) != 1 || p.Batch.Complexes[0].Complex.ID != 1 {
+				t.Errorf("got complexes %+v, want the chosen complex only", p.Batch.Complexes)
+			}
+		})
+	}
+}
+
+func TestNewPlanError(t *testing.T) {
+	tests := []struct {
+		name      string
+		content   string
+		complexID uint
+		code      i18n.Code
+	}{
+		{"malformed JSON", `{"complexes": [`, 0, i18n.InvalidImportFile},
+		{"malformed archive", "PK\x03\x04garbage", 0, i18n.InvalidImportFile},
+		{"CSV without a complex", "date,habit\n", 0, i18n.ImportComplexRequired},
+		{"CSV for a complex in the trash", "date,habit\n", 2, i18n.ReferencedComplexNotFound},
+		{"CSV for an unknown complex", "date,habit\n", 99, i18n.ReferencedComplexNotFound},
+		{"empty CSV", "", 1, i18n.InvalidImportFile},
+		{"CSV without a date column", "habit,done\n", 1, i18n.InvalidImportFile},
+		{"CSV without a habit column", "date\n2025-04-01\n", 1, i18n.InvalidImportFile},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewPlan("u1", []byte(tt.content), existing(), Options{ComplexID: tt.complexID, Now: now})
+			var importErr *Error
+			if !errors.As(err, &importErr) {
+				t.Fatalf("got %v, want an *Error", err)
+			}
+			if importErr.Code != tt.code {
+				t.Errorf("got code %q, want %q", importErr.Code, tt.code)
+			}
+		})
+	}
+}
+
+func TestRejectionDetailLanguage(t *testing.T) {
+	data := export.Data{
+		Complexes: []export.Complex{{ID: 5, Content: "人前で話すのが怖い", Category: "仕事"}},
+		Goals:     []export.Goal{{ID: 6, ComplexID: 5, Content: "発表する"}},
+		Actions:   []export.Action{{ID: 7, GoalID: 6, Content: "練習する"}},
+		Losses:    []export.Outcome{{ID: 8, ActionID: 7, Type: "mixed", Description: "緊張"}},
+	}
+	content, err := json.Marshal(data)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	p, err := NewPlan("u1", content, existing(), Options{Now: now})
+	if err != nil {
+		t.Fatalf("NewPlan: %v", err)
+	}
+	if len(p.Rejections) != 1 {
+		t.Fatalf("got rejections %v, want one", rejections(p))
+	}
+	r := p.Rejections[0]
+	for lang, want := range map[i18n.Lang]string{
+		i18n.Japanese: `入力内容に誤りがあります: Lossの type は "quantitative" か "qualitative" のいずれかです`,
+		i18n.English:  `Validation failed: loss type must be "quantitative" or "qualitative"`,
+	} {
+		if got := i18n.T(lang, r.Code, r.Args...); got != want {
+			t.Errorf("%s: got %q, want %q", lang, got, want)
+		}
+	}
+
+	_, err = NewPlan("u1", []byte("habit,done\n"), existing(), Options{ComplexID: 1, Now: now})
+	var importErr *Error
+	if !errors.As(err, &importErr) {
+		t.Fatalf("got %v, want an *Error", err)
+	}
+	if got, want := i18n.T(i18n.Japanese, importErr.Code, importErr.Args...), "ファイルを読み込めません: 日付の列がありません"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestParseDate(t *testing.T) {
+	at := time.Date(2025, 4, 1, 21, 30, 0, 0, time.UTC)
+	tests := []struct {
+		s           string
+		ok          bool
+		completedAt *time.Time
+	}{
+		{"2025-04-01", true, nil},
+		{"2025/04/01", true, nil},
+		{"2025/4/1", true, nil},
+		{"2025-04-01 21:30:00", true, &at},
+		{"2025-04-01T21:30:00Z", true, &at},
+		{"2025/04/01 21:30", true, &at},
+		{"04/01/2025", false, nil},
+		{"", false, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.s, func(t *testing.T) {
+			d, completedAt, ok := parseDate(tt.s)
+			if ok != tt.ok {
+				t.Fatalf("got ok %v, want %v", ok, tt.ok)
+			}
+			if !ok {
+				return
+			}
+			if !d.Equal(date(1)) {
+				t.Errorf("got date %v, want %v", d, date(1))
+			}
+			if (completedAt == nil) != (tt.completedAt == nil) || completedAt != nil && !completedAt.Equal(*tt.completedAt) {
+				t.Errorf("got completed at %v, want %v", completedAt, tt.completedAt)
+			}
+		})
+	}
+}
+
+func TestParseDone(t *testing.T) {
+	tests := []struct {
+		v        string
+		done, ok bool
+	}{
+		{"1", true, true},
+		{" TRUE ", true, true},
+		{"✓", true, true},
+		{"済", true, true},
+		{"2.5", true, true},
+		{"", false, true},
+		{"0", false, true},
+		{"未実施", false, true},
+		{"-1", false, true},
+		{"maybe", false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.v, func(t *testing.T) {
+			done, ok := parseDone(tt.v)
+			if done != tt.done || ok != tt.ok {
+				t.Errorf("got (%v, %v), want (%v, %v)", done, ok, tt.done, tt.ok)
+			}
+		})
+	}
+}
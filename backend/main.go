@@ -1,7 +1,6 @@
 package main
 
 import (
-	"fmt"
 	"log"
 	"net/http"
 	"os"
@@ -10,71 +9,45 @@ import (
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
-	"github.com/golang-migrate/migrate/v4"
-	_ "github.com/golang-migrate/migrate/v4/database/mysql"
-	_ "github.com/golang-migrate/migrate/v4/source/file"
-	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
+
+	"refuel/backend/database"
+	"refuel/backend/models"
 )
 
 /* Global Variables */
 var (
-	db      *gorm.DB
+	db       *gorm.DB
 	validate *validator.Validate
 )
 
-/* --- Models (実際には models/ ディレクトリなどに分離推奨) --- */
+/* --- Models --- */
+// The tables are owned by the SQL migrations and mapped by package models;
+// this server shares those definitions instead of keeping its own copies.
 
 // Complex represents the complex entity.
-type Complex struct {
-	ID        uint      `gorm:"primarykey" json:"id"`
-	UserID    string    `json:"user_id" gorm:"type:varchar(36);not null;index"` // UUID from AuthMiddleware
-	Content   string    `json:"content" gorm:"not null" validate:"required"`
-	Category  string    `json:"category" gorm:"not null" validate:"required"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
-	Goals     []Goal    `json:"goals,omitempty"`
-}
+type Complex = models.Complex
+
+// Goal represents the goal entity.
+type Goal = models.Goal
+
+// Action represents the action entity.
+type Action = models.Action
 
 // ComplexInput defines the expected input for creating a new complex.
 type ComplexInput struct {
-	Content        string `json:"content" validate:"required,min=1,max=255"`
-	Category       string `json:"category" validate:"required,min=1,max=100"`
-	SurfaceGoal    string `json:"surface_goal,omitempty" validate:"omitempty,min=1,max=255"`    // Optional: Goal to be created with complex
-	UnderlyingGoal string `json:"underlying_goal,omitempty" validate:"omitempty,min=1,max=255"` // Optional: Goal to be created with complex
-	Actions []ActionInput `json:"actions,omitempty" validate:"omitempty,dive"` // Optional: Actions for the new goal
-}
-
-// Goal represents the goal entity.
-type Goal struct {
-	ID             uint      `gorm:"primarykey" json:"id"`
-	UserID         string    `json:"user_id" gorm:"type:varchar(36);not null;index"`
-	ComplexID      uint      `json:"complex_id" gorm:"not null;index"` // Foreign key to Complex
-	SurfaceGoal    string    `json:"surface_goal" gorm:"not null"`
-	UnderlyingGoal string    `json:"underlying_goal" gorm:"not null"`
-	CreatedAt      time.Time `json:"created_at"`
-	UpdatedAt      time.Time `json:"updated_at"`
-	Complex        Complex   `gorm:"foreignKey:ComplexID"` // Belongs to Complex
+	Content        string        `json:"content" validate:"required,min=1,max=255"`
+	TriggerEpisode string        `json:"trigger_episode,omitempty" validate:"omitempty,max=2000"`
+	Category       string        `json:"category" validate:"required,min=1,max=100"`
+	Goal           string        `json:"goal,omitempty" validate:"omitempty,min=1,max=1000"` // Optional: Goal to be created with complex
+	Actions        []ActionInput `json:"actions,omitempty" validate:"omitempty,dive"`        // Optional: Actions for the new goal
 }
 
 // GoalInput defines the expected input for creating or updating a goal.
 type GoalInput struct {
-	ComplexID      uint   `json:"complex_id" validate:"required"`
-	SurfaceGoal    string `json:"surface_goal" validate:"required,min=1,max=255"`
-	UnderlyingGoal string `json:"underlying_goal" validate:"required,min=1,max=255"`
-}
-
-// Action represents the action entity.
-type Action struct {
-	ID          uint       `gorm:"primarykey" json:"id"`
-	UserID      string     `json:"user_id" gorm:"type:varchar(36);not null;index"`
-	GoalID      uint       `json:"goal_id" gorm:"not null;index"` // Foreign key to Goal
-	Content     string     `json:"content" gorm:"not null"`
-	CompletedAt *time.Time `json:"completed_at,omitempty"` // Pointer to allow null
-	CreatedAt   time.Time  `json:"created_at"`
-	UpdatedAt   time.Time  `json:"updated_at"`
-	Goal        Goal       `gorm:"foreignKey:GoalID"` // Belongs to Goal
+	ComplexID uint   `json:"complex_id" validate:"required"`
+	Content   string `json:"content" validate:"required,min=1,max=1000"`
 }
 
 // ActionInput defines the expected input for creating a new action.
@@ -84,10 +57,6 @@ type ActionInput struct {
 	CompletedAt string `json:"completed_at,omitempty" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"` // ISO8601 format
 }
 
-// --- GORM AutoMigrate Helper ---
-// (This can be moved to a more appropriate place like a db setup function later)
-var modelsToMigrate = []interface{}{&Complex{}, &Goal{}, &Action{}}
-
 // ErrorResponse Helper
 type ErrorResponse struct {
 	Code    int    `json:"code"`
@@ -149,9 +118,10 @@ func CreateComplexHandler(c *gin.Context) {
 
 	// Create Complex
 	complex := Complex{
-		UserID:   userID.(string),
-		Content:  input.Content,
-		Category: input.Category,
+		UserID:         userID.(string),
+		Content:        input.Content,
+		TriggerEpisode: input.TriggerEpisode,
+		Category:       input.Category,
 	}
 
 	if result := tx.Create(&complex); result.Error != nil {
@@ -160,13 +130,12 @@ func CreateComplexHandler(c *gin.Context) {
 		return
 	}
 
-	// If goal fields are provided, create a goal associated with this complex
-	if input.SurfaceGoal != "" && input.UnderlyingGoal != "" {
+	// If a goal is provided, create it associated with this complex
+	if input.Goal != "" {
 		goal := Goal{
-			UserID:         userID.(string),
-			ComplexID:      complex.ID, // Link to the newly created complex
-			SurfaceGoal:    input.SurfaceGoal,
-			UnderlyingGoal: input.UnderlyingGoal,
+			UserID:    userID.(string),
+			ComplexID: complex.ID, // Link to the newly created complex
+			Content:   input.Goal,
 		}
 		if result := tx.Create(&goal); result.Error != nil {
 			//lint:ignore ST1005 Error message is clear
@@ -174,11 +143,6 @@ func CreateComplexHandler(c *gin.Context) {
 			c.JSON(http.StatusInternalServerError, NewErrorResponse(http.StatusInternalServerError, "Failed to create associated goal: "+result.Error.Error()))
 			return
 		}
-	} else if input.SurfaceGoal != "" || input.UnderlyingGoal != "" {
-		// If only one of the goal fields is provided, it's an invalid request for creating a goal
-		tx.Rollback()
-		c.JSON(http.StatusBadRequest, NewErrorResponse(http.StatusBadRequest, "Both surface_goal and underlying_goal are required to create a goal"))
-		return
 	}
 
 	// Commit the transaction
@@ -279,6 +243,7 @@ func UpdateComplexHandler(c *gin.Context) {
 
 	// Update Complex fields
 	existingComplex.Content = input.Content
+	existingComplex.TriggerEpisode = input.TriggerEpisode
 	existingComplex.Category = input.Category
 
 	if err := tx.Save(&existingComplex).Error; err != nil {
@@ -288,7 +253,7 @@ func UpdateComplexHandler(c *gin.Context) {
 	}
 
 	// Handle associated Goal (create or update)
-	if input.SurfaceGoal != "" && input.UnderlyingGoal != "" {
+	if input.Goal != "" {
 		var goal Goal
 		err := tx.Where("complex_id = ? AND user_id = ?", existingComplex.ID, userID.(string)).First(&goal).Error
 
@@ -300,10 +265,9 @@ func UpdateComplexHandler(c *gin.Context) {
 
 		if err == gorm.ErrRecordNotFound { // Goal does not exist, create it
 			goal = Goal{
-				UserID:         userID.(string),
-				ComplexID:      existingComplex.ID,
-				SurfaceGoal:    input.SurfaceGoal,
-				UnderlyingGoal: input.UnderlyingGoal,
+				UserID:    userID.(string),
+				ComplexID: existingComplex.ID,
+				Content:   input.Goal,
 			}
 			if err := tx.Create(&goal).Error; err != nil {
 				tx.Rollback()
@@ -338,18 +302,13 @@ func UpdateComplexHandler(c *gin.Context) {
 				}
 			}
 		} else { // Goal exists, update it
-			goal.SurfaceGoal = input.SurfaceGoal
-			goal.UnderlyingGoal = input.UnderlyingGoal
+			goal.Content = input.Goal
 			if err := tx.Save(&goal).Error; err != nil {
 				tx.Rollback()
 				c.JSON(http.StatusInternalServerError, NewErrorResponse(http.StatusInternalServerError, "Failed to update associated goal: "+err.Error()))
 				return
 			}
 		}
-	} else if input.SurfaceGoal != "" || input.UnderlyingGoal != "" {
-		tx.Rollback()
-		c.JSON(http.StatusBadRequest, NewErrorResponse(http.StatusBadRequest, "Both surface_goal and underlying_goal are required to create or update a goal via complex endpoint"))
-		return
 	}
 
 	if err := tx.Commit().Error; err != nil {
@@ -408,10 +367,9 @@ func CreateGoalHandler(c *gin.Context) {
 	}
 
 	goal := Goal{
-		UserID:         userID.(string),
-		ComplexID:      input.ComplexID,
-		SurfaceGoal:    input.SurfaceGoal,
-		UnderlyingGoal: input.UnderlyingGoal,
+		UserID:    userID.(string),
+		ComplexID: input.ComplexID,
+		Content:   input.Content,
 	}
 
 	if result := db.Create(&goal); result.Error != nil {
@@ -490,8 +448,7 @@ func UpdateGoalHandler(c *gin.Context) {
 
 	// Note: ComplexID update is not allowed here for simplicity, but can be added if needed.
 	// If ComplexID is updated, ensure the new ComplexID also belongs to the user.
-	goal.SurfaceGoal = input.SurfaceGoal
-	goal.UnderlyingGoal = input.UnderlyingGoal
+	goal.Content = input.Content
 
 	if err := db.Save(&goal).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, NewErrorResponse(http.StatusInternalServerError, "Failed to update goal: "+err.Error()))
@@ -608,40 +565,21 @@ func GetActionsHandler(c *gin.Context) {
 }
 
 func main() {
-	// --- 環境変数からの設定読み込み (例) ---
-	dbUser := os.Getenv("DB_USER")
-	dbPassword := os.Getenv("DB_PASSWORD")
-	dbHost := os.Getenv("DB_HOST")
-	dbPort := os.Getenv("DB_PORT")
-	dbName := os.Getenv("DB_NAME")
-	migrationPath := os.Getenv("MIGRATION_PATH") // e.g., "file://./db/migrations"
+	// --- 環境変数からの設定読み込み ---
+	dbConfig, err := database.ConfigFromEnv() // DB_DRIVER (mysql / postgres / sqlite) と接続情報
+	if err != nil {
+		log.Fatalf("🚨 Invalid database configuration: %v", err)
+	}
+	migrationPath := os.Getenv("MIGRATION_PATH") // e.g., "file://./db/migrations"; the driver's subdirectory is used
 	serverPort := os.Getenv("SERVER_PORT")
 	if serverPort == "" {
 		serverPort = "8080" // デフォルトポート
 	}
 
-	// 必須環境変数のチェック
-	requiredEnvVars := map[string]string{
-		"DB_USER":    dbUser,
-		"DB_PASSWORD": dbPassword,
-		"DB_HOST":    dbHost,
-		"DB_PORT":    dbPort,
-		"DB_NAME":    dbName,
-	}
-	for key, value := range requiredEnvVars {
-		if value == "" {
-			log.Fatalf("🚨 Missing required environment variable: %s", key)
-		}
-	}
-
 	// --- Validatorの初期化 ---
 	validate = validator.New()
 
 	// --- データベース接続 (GORM) ---
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=True&loc=Local",
-		dbUser, dbPassword, dbHost, dbPort, dbName,
-	)
-
 	gormLogger := logger.New(
 		log.New(os.Stdout, "\r\n", log.LstdFlags),
 		logger.Config{
@@ -652,8 +590,7 @@ func main() {
 		},
 	)
 
-	var err error
-	db, err = gorm.Open(mysql.Open(dsn), &gorm.Config{
+	db, err = database.Open(dbConfig, &gorm.Config{
 		Logger: gormLogger,
 	})
 	if err != nil {
@@ -662,30 +599,17 @@ func main() {
 	log.Println("🎉 Database connected successfully!")
 
 	// --- データベースマイグレーション (golang-migrate/migrate) ---
+	// スキーマはマイグレーションだけが管理する。AutoMigrate は使わない。
 	if migrationPath != "" {
-		dbURL := fmt.Sprintf("mysql://%s:%s@tcp(%s:%s)/%s",
-			dbUser, dbPassword, dbHost, dbPort, dbName,
-		)
-		m, err := migrate.New(migrationPath, dbURL)
-		if err != nil { // マイグレーションの初期化失敗は致命的エラーとする場合
-			log.Fatalf("🚨 Failed to initialize migrations: %v.", err)
+		if err := database.Migrate(dbConfig, migrationPath); err != nil {
+			log.Fatalf("🚨 %v", err)
 		}
-		if err := m.Up(); err != nil && err != migrate.ErrNoChange {
-				log.Fatalf("🚨 Failed to run migrations: %v", err)
-			}
-			log.Println("🎉 Migrations applied successfully or no changes.")
-			srcErr, dbErr := m.Close()
-			if srcErr != nil {
-				log.Printf("⚠️ Error closing migration source: %v", srcErr)
-			}
-			if dbErr != nil {
-				log.Printf("⚠️ Error closing migration database connection: %v", dbErr)
-			}
 	} else {
 		log.Println("ℹ️ MIGRATION_PATH not set, skipping automated migrations. Ensure DB schema is up to date.")
-		// 開発初期段階ではGORMのAutoMigrateも便利です
-		log.Println("Running GORM AutoMigrate for initial schema setup...")
-		db.AutoMigrate(modelsToMigrate...) // Use the slice for AutoMigrate
+	}
+	// モデルと適用済みマイグレーションが食い違っていれば起動しない
+	if err := database.CheckSchema(db); err != nil {
+		log.Fatalf("🚨 Database schema check failed: %v", err)
 	}
 
 	// --- Ginルーターの初期化 ---
@@ -703,7 +627,7 @@ func main() {
 		ExposeHeaders:    []string{"Content-Length"},
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
-}))
+	}))
 	r.Use(AuthMiddleware())
 
 	// --- ルーティング ---
@@ -0,0 +1,59 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	"gorm.io/gorm/schema"
+)
+
+func init() {
+	schema.RegisterSerializer("date", DateSerializer{})
+}
+
+// DateSerializer stores a time.Time or *time.Time field as a YYYY-MM-DD
+// calendar date and reads it back as midnight UTC; a nil pointer is NULL.
+// Drivers disagree on DATE columns: MySQL returns them in the connection's
+// location, PostgreSQL in UTC and SQLite as text, so the date is never
+// converted through a time zone on the way.
+type DateSerializer struct{}
+
+// Scan implements schema.SerializerInterface.
+func (DateSerializer) Scan(ctx context.Context, field *schema.Field, dst reflect.Value, dbValue interface{}) error {
+	var date time.Time
+	switch v := dbValue.(type) {
+	case nil:
+		return nil
+	case time.Time:
+		date = time.Date(v.Year(), v.Month(), v.Day(), 0, 0, 0, 0, time.UTC)
+	case []byte:
+		return DateSerializer{}.Scan(ctx, field, dst, string(v))
+	case string:
+		if len(v) < len(time.DateOnly) {
+			return fmt.Errorf("invalid date %q", v)
+		}
+		var err error
+		if date, err = time.Parse(time.DateOnly, v[:len(time.DateOnly)]); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unsupported date value %T", dbValue)
+	}
+	return field.Set(ctx, dst, date)
+}
+
+// Value implements schema.SerializerValuerInterface.
+func (DateSerializer) Value(ctx context.Context, field *schema.Field, dst reflect.Value, fieldValue interface{}) (interface{}, error) {
+	switch t := fieldValue.(type) {
+	case time.Time:
+		return t.Format(time.DateOnly), nil
+	case *time.Time:
+		if t == nil {
+			return nil, nil
+		}
+		return t.Format(time.DateOnly), nil
+	}
+	return nil, fmt.Errorf("date serializer expects time.Time, got %T", fieldValue)
+}
@@ -0,0 +1,193 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// EncryptedPrefix begins every value a FieldCipher encrypts. A column
+// value without it is plaintext, written before encryption was configured,
+// and is read as it is.
+const EncryptedPrefix = "enc:v1:"
+
+// FieldCipher encrypts the sensitive text fields of users' records:
+// complex contents and trigger episodes, gain and loss descriptions, and
+// the revision snapshots, feedback messages and export archives that copy
+// them. db is the
+// session the record is saved or loaded in, so a cipher looking up keys
+// stays in its transaction.
+type FieldCipher interface {
+	// Encrypt returns the ciphertext of plaintext for the user, beginning
+	// with EncryptedPrefix.
+	Encrypt(db *gorm.DB, userID, plaintext string) (string, error)
+	// Decrypt returns the plaintext of a value returned by Encrypt.
+	Decrypt(db *gorm.DB, ciphertext string) (string, error)
+}
+
+// fieldCipher is the cipher the models' hooks use; nil leaves fields in
+// plaintext.
+var fieldCipher FieldCipher
+
+// SetFieldCipher sets the cipher the sensitive fields are encrypted with
+// when saved and decrypted with when loaded. Call it once at startup,
+// before the database is used.
+func SetFieldCipher(c FieldCipher) {
+	fieldCipher = c
+}
+
+// encryptFields encrypts the user's non-empty fields in place.
+func encryptFields(tx *gorm.DB, userID string, fields ...*string) error {
+	if fieldCipher == nil {
+		return nil
+	}
+	for _, f := range fields {
+		if *f == "" {
+			continue
+		}
+		ciphertext, err := fieldCipher.Encrypt(tx, userID, *f)
+		if err != nil {
+			return err
+		}
+		*f = ciphertext
+	}
+	return nil
+}
+
+// decryptFields decrypts the encrypted fields in place, leaving plaintext
+// ones as they are.
+func decryptFields(tx *gorm.DB, fields ...*string) error {
+	for _, f := range fields {
+		if !strings.HasPrefix(*f, EncryptedPrefix) {
+			continue
+		}
+		if fieldCipher == nil {
+			return errors.New("cannot decrypt a field: no encryption keys are configured")
+		}
+		plaintext, err := fieldCipher.Decrypt(tx, *f)
+		if err != nil {
+			return err
+		}
+		*f = plaintext
+	}
+	return nil
+}
+
+// actionOwner returns the ID of the user owning an action, which gains and
+// losses, having no user ID of their own, are encrypted for. The action may
+// be in the trash.
+func actionOwner(tx *gorm.DB, actionID uint) (string, error) {
+	if fieldCipher == nil {
+		return "", nil
+	}
+	var userIDs []string
+	if err := tx.Unscoped().Model(&Action{}).Where("id = ?", actionID).Pluck("user_id", &userIDs).Error; err != nil {
+		return "", err
+	}
+	if len(userIDs) == 0 {
+		return "", fmt.Errorf("cannot encrypt for action %d: action not found", actionID)
+	}
+	return userIDs[0], nil
+}
+
+// BeforeSave encrypts the complex's content and trigger episode.
+func (c *Complex) BeforeSave(tx *gorm.DB) error {
+	return encryptFields(tx, c.UserID, &c.Content, &c.TriggerEpisode)
+}
+
+// AfterSave decrypts what BeforeSave encrypted, so the caller keeps the plaintext.
+func (c *Complex) AfterSave(tx *gorm.DB) error {
+	return decryptFields(tx, &c.Content, &c.TriggerEpisode)
+}
+
+// AfterFind decrypts the complex's content and trigger episode.
+func (c *Complex) AfterFind(tx *gorm.DB) error {
+	return decryptFields(tx, &c.Content, &c.TriggerEpisode)
+}
+
+// BeforeSave encrypts the gain's description for the owner of its action.
+func (g *Gain) BeforeSave(tx *gorm.DB) error {
+	userID, err := actionOwner(tx, g.ActionID)
+	if err != nil {
+		return err
+	}
+	return encryptFields(tx, userID, &g.Description)
+}
+
+// AfterSave decrypts what BeforeSave encrypted, so the caller keeps the plaintext.
+func (g *Gain) AfterSave(tx *gorm.DB) error {
+	return decryptFields(tx, &g.Description)
+}
+
+// AfterFind decrypts the gain's description.
+func (g *Gain) AfterFind(tx *gorm.DB) error {
+	return decryptFields(tx, &g.Description)
+}
+
+// BeforeSave encrypts the loss's description for the owner of its action.
+func (l *Loss) BeforeSave(tx *gorm.DB) error {
+	userID, err := actionOwner(tx, l.ActionID)
+	if err != nil {
+		return err
+	}
+	return encryptFields(tx, userID, &l.Description)
+}
+
+// AfterSave decrypts what BeforeSave encrypted, so the caller keeps the plaintext.
+func (l *Loss) AfterSave(tx *gorm.DB) error {
+	return decryptFields(tx, &l.Description)
+}
+
+// AfterFind decrypts the loss's description.
+func (l *Loss) AfterFind(tx *gorm.DB) error {
+	return decryptFields(tx, &l.Description)
+}
+
+// BeforeSave encrypts the revision's snapshots, which copy the fields
+// encrypted above.
+func (r *Revision) BeforeSave(tx *gorm.DB) error {
+	return encryptFields(tx, r.UserID, &r.Before, &r.After)
+}
+
+// AfterSave decrypts what BeforeSave encrypted, so the caller keeps the plaintext.
+func (r *Revision) AfterSave(tx *gorm.DB) error {
+	return decryptFields(tx, &r.Before, &r.After)
+}
+
+// AfterFind decrypts the revision's snapshots.
+func (r *Revision) AfterFind(tx *gorm.DB) error {
+	return decryptFields(tx, &r.Before, &r.After)
+}
+
+// BeforeSave encrypts the feedback message, whose text may quote the
+// complex and its trigger episode.
+func (f *FeedbackMessage) BeforeSave(tx *gorm.DB) error {
+	return encryptFields(tx, f.UserID, &f.Message)
+}
+
+// AfterSave decrypts what BeforeSave encrypted, so the caller keeps the plaintext.
+func (f *FeedbackMessage) AfterSave(tx *gorm.DB) error {
+	return decryptFields(tx, &f.Message)
+}
+
+// AfterFind decrypts the feedback message.
+func (f *FeedbackMessage) AfterFind(tx *gorm.DB) error {
+	return decryptFields(tx, &f.Message)
+}
+
+// BeforeSave encrypts the job's archive.
+func (j *ExportJob) BeforeSave(tx *gorm.DB) error {
+	return encryptFields(tx, j.UserID, &j.Archive)
+}
+
+// AfterSave decrypts what BeforeSave encrypted, so the caller keeps the plaintext.
+func (j *ExportJob) AfterSave(tx *gorm.DB) error {
+	return decryptFields(tx, &j.Archive)
+}
+
+// AfterFind decrypts the job's archive.
+func (j *ExportJob) AfterFind(tx *gorm.DB) error {
+	return decryptFields(tx, &j.Archive)
+}
@@ -1,57 +1,295 @@
 package models
 
-import "time"
+import (
+	"time"
+
+	"gorm.io/gorm"
+
+	"refuel/backend/recurrence"
+)
 
 // Complex represents the complex entity for GORM.
 type Complex struct {
-	ID             uint   `gorm:"primarykey" json:"id"`
-	UserID         string `json:"user_id" gorm:"type:varchar(36);not null;index"`
-	Content        string `json:"content" gorm:"not null" validate:"required"`
-	TriggerEpisode string `json:"trigger_episode" gorm:"type:text"`
-	Category       string `json:"category" gorm:"not null" validate:"required"`
+	ID             uint      `gorm:"primarykey" json:"id"`
+	UserID         string    `json:"user_id" gorm:"type:varchar(36);not null;index"`
+	Content        string    `json:"content" gorm:"not null" validate:"required"`
+	TriggerEpisode string    `json:"trigger_episode" gorm:"type:text"`
+	Category       string    `json:"category" gorm:"not null" validate:"required"`
 	CreatedAt      time.Time `json:"created_at"`
 	UpdatedAt      time.Time `json:"updated_at"`
-	Goals          []Goal `json:"goals,omitempty" gorm:"foreignKey:ComplexID"`
+	// DeletedAt is set while the complex is in the trash.
+	DeletedAt gorm.DeletedAt `json:"deleted_at,omitempty" gorm:"index"`
+	Goals     []Goal         `json:"goals,omitempty" gorm:"foreignKey:ComplexID"`
 }
 
 // Goal represents the goal entity for GORM.
 type Goal struct {
-	ID        uint   `gorm:"primarykey" json:"id"`
-	UserID    string `json:"user_id" gorm:"type:varchar(36);not null;index"`
-	ComplexID uint   `json:"complex_id" gorm:"not null;index"`
-	Content   string `json:"content" gorm:"type:varchar(255);not null"`
+	ID        uint      `gorm:"primarykey" json:"id"`
+	UserID    string    `json:"user_id" gorm:"type:varchar(36);not null;index"`
+	ComplexID uint      `json:"complex_id" gorm:"not null;index"`
+	Content   string    `json:"content" gorm:"type:text;not null"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
-	Complex Complex `gorm:"foreignKey:ComplexID"`
+	// DeletedAt is set while the goal is in the trash.
+	DeletedAt  gorm.DeletedAt `json:"deleted_at,omitempty" gorm:"index"`
+	Complex    Complex        `gorm:"foreignKey:ComplexID"`
+	Milestones []Milestone    `json:"milestones,omitempty" gorm:"foreignKey:GoalID"`
+
+	// The metric columns are set together when the goal is measurable;
+	// MetricDirection is empty otherwise. MetricDeadline is optional.
+	MetricUnit      string     `json:"metric_unit,omitempty" gorm:"type:varchar(20)"`
+	MetricBaseline  *float64   `json:"metric_baseline,omitempty"`
+	MetricTarget    *float64   `json:"metric_target,omitempty"`
+	MetricDirection string     `json:"metric_direction,omitempty" gorm:"type:varchar(10)"`
+	MetricDeadline  *time.Time `json:"metric_deadline,omitempty" gorm:"type:date;serializer:date"`
+}
+
+// HasMetric reports whether the goal declares a metric to measure.
+func (g *Goal) HasMetric() bool {
+	return g.MetricDirection != ""
+}
+
+// Milestone bases: what a milestone's threshold is compared with.
+const (
+	MilestoneMetric           = "metric"
+	MilestoneCompletedActions = "completed_actions"
+)
+
+// Milestone is an intermediate threshold on the way to a goal's final
+// target. AchievedAt is set once the goal's measurements, or the completions
+// of its actions, cross the threshold.
+type Milestone struct {
+	ID         uint       `gorm:"primarykey" json:"id"`
+	GoalID     uint       `gorm:"not null;index" json:"goal_id"`
+	UserID     string     `gorm:"type:varchar(36);not null;index" json:"user_id"`
+	Position   int        `gorm:"not null" json:"position"`
+	Title      string     `gorm:"type:varchar(255);not null" json:"title"`
+	Basis      string     `gorm:"type:varchar(20);not null" json:"basis"`
+	Threshold  float64    `gorm:"not null" json:"threshold"`
+	AchievedAt *time.Time `json:"achieved_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// GoalMeasurement is one recorded value of a measurable goal's metric.
+type GoalMeasurement struct {
+	ID         uint      `gorm:"primarykey" json:"id"`
+	GoalID     uint      `gorm:"not null;index" json:"goal_id"`
+	UserID     string    `gorm:"type:varchar(36);not null;index" json:"user_id"`
+	Value      float64   `gorm:"not null" json:"value"`
+	MeasuredAt time.Time `gorm:"not null" json:"measured_at"`
+	Note       string    `gorm:"type:text" json:"note,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
 }
 
 // Action represents the action entity for GORM.
 type Action struct {
-	ID uint `gorm:"primarykey" json:"id"`
-	UserID string `json:"user_id" gorm:"type:varchar(36);not null;index"`
-	GoalID uint `json:"goal_id" gorm:"not null;index"`
-	Content string `json:"content" gorm:"type:text;not null"`
-	CompletedAt *time.Time `json:"completed_at,omitempty" gorm:"index"`
-	RecurrencePattern string `json:"recurrence_pattern,omitempty" gorm:"type:json"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
-	Goal Goal `gorm:"foreignKey:GoalID"`
-	Gains []Gain `json:"gains,omitempty" gorm:"foreignKey:ActionID"`
-	Losses []Loss `json:"losses,omitempty" gorm:"foreignKey:ActionID"`
+	ID                uint                `gorm:"primarykey" json:"id"`
+	UserID            string              `json:"user_id" gorm:"type:varchar(36);not null;index"`
+	GoalID            uint                `json:"goal_id" gorm:"not null;index"`
+	Content           string              `json:"content" gorm:"type:text;not null"`
+	CompletedAt       *time.Time          `json:"completed_at,omitempty" gorm:"index"`
+	RecurrencePattern *recurrence.Pattern `json:"recurrence_pattern,omitempty" gorm:"type:json;serializer:json"`
+	CreatedAt         time.Time           `json:"created_at"`
+	UpdatedAt         time.Time           `json:"updated_at"`
+	// DeletedAt is set while the action is in the trash.
+	DeletedAt   gorm.DeletedAt     `json:"deleted_at,omitempty" gorm:"index"`
+	Goal        Goal               `gorm:"foreignKey:GoalID"`
+	Gains       []Gain             `json:"gains,omitempty" gorm:"foreignKey:ActionID"`
+	Losses      []Loss             `json:"losses,omitempty" gorm:"foreignKey:ActionID"`
+	Completions []ActionCompletion `json:"completions,omitempty" gorm:"foreignKey:ActionID"`
+}
+
+// Completion statuses recorded in action_completions.status.
+const (
+	CompletionDone    = "done"
+	CompletionSkipped = "skipped"
+	CompletionMissed  = "missed"
+)
+
+// ActionCompletion represents a check-in for one occurrence of an action for GORM.
+type ActionCompletion struct {
+	ID             uint       `gorm:"primarykey" json:"id"`
+	ActionID       uint       `gorm:"not null;uniqueIndex:uq_action_occurrence" json:"action_id"`
+	UserID         string     `gorm:"type:varchar(36);not null;index" json:"user_id"`
+	OccurrenceDate time.Time  `gorm:"type:date;not null;uniqueIndex:uq_action_occurrence;serializer:date" json:"occurrence_date"`
+	Status         string     `gorm:"type:varchar(10);not null" json:"status"`
+	CompletedAt    *time.Time `json:"completed_at,omitempty"`
+	Note           string     `gorm:"type:text" json:"note,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
 }
 
 // Gain represents the gain entity for GORM.
+// Value and Unit are set only when Type is quantitative.
 type Gain struct {
-	ID          uint   `gorm:"primarykey" json:"id"`
-	ActionID    uint   `gorm:"not null;index" json:"action_id"`
-	Type        string `gorm:"type:varchar(20);not null" json:"type"`
-	Description string `gorm:"type:text;not null" json:"description"`
+	ID          uint     `gorm:"primarykey" json:"id"`
+	ActionID    uint     `gorm:"not null;index" json:"action_id"`
+	Type        string   `gorm:"type:varchar(20);not null" json:"type"`
+	Description string   `gorm:"type:text;not null" json:"description"`
+	Value       *float64 `json:"value,omitempty"`
+	Unit        string   `gorm:"type:varchar(20)" json:"unit,omitempty"`
 }
 
+// Gain and loss types recorded in gains.type and losses.type.
+const (
+	Quantitative = "quantitative"
+	Qualitative  = "qualitative"
+)
+
 // Loss represents the loss entity for GORM.
+// Value and Unit are set only when Type is quantitative.
 type Loss struct {
-	ID          uint   `gorm:"primarykey" json:"id"`
-	ActionID    uint   `gorm:"not null;index" json:"action_id"`
-	Type        string `gorm:"type:varchar(20);not null" json:"type"`
-	Description string `gorm:"type:text;not null" json:"description"`
-}
\ No newline at end of file
+	ID          uint     `gorm:"primarykey" json:"id"`
+	ActionID    uint     `gorm:"not null;index" json:"action_id"`
+	Type        string   `gorm:"type:varchar(20);not null" json:"type"`
+	Description string   `gorm:"type:text;not null" json:"description"`
+	Value       *float64 `json:"value,omitempty"`
+	Unit        string   `gorm:"type:varchar(20)" json:"unit,omitempty"`
+}
+
+// FeedbackMessage records a feedback message shown to a user for
+// completing an action, so the same template is not repeated too soon.
+type FeedbackMessage struct {
+	ID           uint      `gorm:"primarykey" json:"id"`
+	UserID       string    `gorm:"type:varchar(36);not null;index" json:"user_id"`
+	ActionID     uint      `gorm:"not null;index" json:"action_id"`
+	TemplateCode string    `gorm:"type:varchar(64);not null" json:"template_code"`
+	Message      string    `gorm:"type:text;not null" json:"message"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// Revision records one change to a user's record: who made it, when, and
+// the record's JSON snapshots before and after. Before is empty for a
+// created record and After for a deleted one.
+type Revision struct {
+	ID         uint      `gorm:"primarykey" json:"id"`
+	UserID     string    `gorm:"type:varchar(36);not null;index:idx_user_id_entity_revision" json:"user_id"`
+	EntityType string    `gorm:"type:varchar(20);not null;index:idx_user_id_entity_revision" json:"entity_type"`
+	EntityID   uint      `gorm:"not null;index:idx_user_id_entity_revision" json:"entity_id"`
+	Action     string    `gorm:"type:varchar(10);not null" json:"action"`
+	Actor      string    `gorm:"type:varchar(36);not null" json:"actor"`
+	Before     string    `gorm:"column:snapshot_before;type:text" json:"before,omitempty"`
+	After      string    `gorm:"column:snapshot_after;type:text" json:"after,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// Export job statuses recorded in export_jobs.status.
+const (
+	ExportPending   = "pending"
+	ExportRunning   = "running"
+	ExportCompleted = "completed"
+	ExportFailed    = "failed"
+)
+
+// ExportJob is a request to export everything a user owns into an archive.
+// Archive is set once the job has completed and is kept until ExpiresAt.
+type ExportJob struct {
+	ID     uint   `gorm:"primarykey" json:"id"`
+	UserID string `gorm:"type:varchar(36);not null;index" json:"user_id"`
+	Status string `gorm:"type:varchar(10);not null;index" json:"status"`
+	// Error tells why a failed job failed.
+	Error string `gorm:"type:text" json:"error,omitempty"`
+	// Archive is the zip archive, base64-encoded so that it is stored and
+	// encrypted like the other text columns.
+	Archive     string     `gorm:"type:longtext" json:"-"`
+	CreatedAt   time.Time  `json:"created_at"`
+	StartedAt   *time.Time `json:"started_at,omitempty"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+}
+
+// Badge represents a badge definition for GORM.
+type Badge struct {
+	ID          uint      `gorm:"primarykey" json:"id"`
+	Code        string    `gorm:"type:varchar(64);not null;uniqueIndex" json:"code"`
+	Name        string    `gorm:"type:varchar(255);not null" json:"name"`
+	Description string    `gorm:"type:text;not null" json:"description"`
+	IconURL     string    `gorm:"type:varchar(255)" json:"icon_url,omitempty"`
+	Rule        string    `gorm:"type:text;not null" json:"rule"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// UserBadge represents a badge awarded to a user for GORM.
+type UserBadge struct {
+	ID      uint   `gorm:"primarykey" json:"id"`
+	UserID  string `gorm:"type:varchar(36);not null;uniqueIndex:uq_user_badge" json:"user_id"`
+	BadgeID uint   `gorm:"not null;uniqueIndex:uq_user_badge" json:"badge_id"`
+	// MilestoneID is the goal milestone a milestone badge was awarded for;
+	// it is 0 for badges awarded by their rule.
+	MilestoneID uint       `gorm:"not null;default:0;uniqueIndex:uq_user_badge" json:"milestone_id,omitempty"`
+	AchievedAt  time.Time  `gorm:"not null" json:"achieved_at"`
+	Badge       Badge      `gorm:"foreignKey:BadgeID"`
+	Milestone   *Milestone `gorm:"foreignKey:MilestoneID"`
+}
+
+// User represents a local account for GORM.
+type User struct {
+	ID           string `gorm:"type:varchar(36);primarykey" json:"id"`
+	Email        string `gorm:"type:varchar(255);not null;uniqueIndex" json:"email"`
+	PasswordHash string `gorm:"type:varchar(255);not null" json:"-"`
+	DisplayName  string `gorm:"type:varchar(255)" json:"display_name,omitempty"`
+	// Language is the language the user wants messages in, overriding
+	// Accept-Language, or empty to follow it.
+	Language  string    `gorm:"type:varchar(10)" json:"language,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// RefreshToken represents an issued refresh token for GORM.
+// Only the SHA-256 hash of the token is stored.
+type RefreshToken struct {
+	ID        uint       `gorm:"primarykey" json:"id"`
+	UserID    string     `gorm:"type:varchar(36);not null;index" json:"user_id"`
+	TokenHash string     `gorm:"type:char(64);not null;uniqueIndex" json:"-"`
+	ExpiresAt time.Time  `gorm:"not null" json:"expires_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// UserIdentity links an external OpenID Connect subject to a user for GORM.
+type UserIdentity struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	UserID    string    `gorm:"type:varchar(36);not null;index" json:"user_id"`
+	Issuer    string    `gorm:"type:varchar(255);not null;uniqueIndex:uq_identity_subject" json:"issuer"`
+	Subject   string    `gorm:"type:varchar(255);not null;uniqueIndex:uq_identity_subject" json:"subject"`
+	Email     string    `gorm:"type:varchar(255)" json:"email,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// OIDCLoginState holds an in-flight OIDC authorization request for GORM.
+// It is deleted when the callback consumes it.
+type OIDCLoginState struct {
+	State        string    `gorm:"type:varchar(64);primarykey" json:"state"`
+	CodeVerifier string    `gorm:"type:varchar(128);not null" json:"-"`
+	Nonce        string    `gorm:"type:varchar(64);not null" json:"-"`
+	ExpiresAt    time.Time `gorm:"not null" json:"expires_at"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// TableName overrides GORM's default, which would split the acronym into "o_id_c".
+func (OIDCLoginState) TableName() string {
+	return "oidc_login_states"
+}
+
+// UserDataKey holds the key a user's sensitive text fields are encrypted
+// with, itself encrypted (wrapped) by the master key named MasterKeyID.
+type UserDataKey struct {
+	UserID      string    `gorm:"type:varchar(36);primaryKey" json:"user_id"`
+	MasterKeyID string    `gorm:"type:varchar(64);not null;index" json:"master_key_id"`
+	WrappedKey  string    `gorm:"type:text;not null" json:"-"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// All lists every model backed by a table created by the SQL migrations.
+// The startup schema check compares each of them against the database.
+func All() []interface{} {
+	return []interface{}{
+		&Complex{}, &Goal{}, &GoalMeasurement{}, &Milestone{}, &Action{}, &ActionCompletion{}, &Gain{}, &Loss{},
+		&FeedbackMessage{}, &Revision{}, &ExportJob{},
+		&Badge{}, &UserBadge{},
+		&User{}, &RefreshToken{}, &UserIdentity{}, &OIDCLoginState{}, &UserDataKey{},
+	}
+}
@@ -0,0 +1,71 @@
+package progress
+
+import (
+	"math"
+
+	"refuel/backend/i18n"
+)
+
+// MaxMilestones bounds how many milestones a goal is split into.
+const MaxMilestones = 20
+
+// Thresholds places milestones on the way from one value to another,
+// working backwards from the final target: the last threshold is always to.
+// Without fractions it spaces count thresholds evenly; otherwise every
+// fraction in (0, 1] puts one at that share of the way, the fractions must
+// increase, and to is appended if they stop short of 1. Thresholds other
+// than to are rounded to two decimals.
+func Thresholds(from, to float64, count int, fractions []float64) ([]float64, error) {
+	if from == to {
+		return nil, i18n.NewError(i18n.DetailTargetIsStart)
+	}
+	if len(fractions) == 0 {
+		if count < 1 || count > MaxMilestones {
+			return nil, i18n.NewError(i18n.DetailMilestoneCount, MaxMilestones)
+		}
+		fractions = make([]float64, count)
+		for i := range fractions {
+			fractions[i] = float64(i+1) / float64(count)
+		}
+	}
+
+	thresholds := make([]float64, 0, len(fractions)+1)
+	for i, f := range fractions {
+		if f <= 0 || f > 1 {
+			return nil, i18n.NewError(i18n.DetailFractionRange, f)
+		}
+		if i > 0 && f <= fractions[i-1] {
+			return nil, i18n.NewError(i18n.DetailFractionsIncrease, f, fractions[i-1])
+		}
+		if f < 1 {
+			thresholds = append(thresholds, math.Round((from+(to-from)*f)*100)/100)
+		}
+	}
+	thresholds = append(thresholds, to)
+	if len(thresholds) > MaxMilestones {
+		return nil, i18n.NewError(i18n.DetailTooManyMilestones, MaxMilestones)
+	}
+	return thresholds, nil
+}
+
+// WholeThresholds rounds thresholds on an increasing count up to whole
+// numbers, dropping those that collapse onto the previous one.
+func WholeThresholds(thresholds []float64) []float64 {
+	var out []float64
+	for _, t := range thresholds {
+		t = math.Ceil(t)
+		if len(out) > 0 && t <= out[len(out)-1] {
+			continue
+		}
+		out = append(out, t)
+	}
+	return out
+}
+
+// Crossed reports whether value has reached threshold moving in direction d.
+func (d Direction) Crossed(value, threshold float64) bool {
+	if d == Decrease {
+		return value <= threshold
+	}
+	return value >= threshold
+}
@@ -0,0 +1,151 @@
+// Package progress measures how far a goal's metric has moved from its
+// baseline toward its target and projects when the target will be reached.
+package progress
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"refuel/backend/i18n"
+)
+
+// Direction is the way a metric has to move to reach its target.
+type Direction string
+
+// Supported directions.
+const (
+	Increase Direction = "increase"
+	Decrease Direction = "decrease"
+)
+
+// maxHorizon bounds projections: a trend that needs longer than this to
+// reach the target is too flat to give a meaningful date.
+const maxHorizon = 100 * 365 * 24 * time.Hour
+
+// Metric is what a measurable goal declares.
+type Metric struct {
+	Baseline  float64
+	Target    float64
+	Direction Direction
+	// Deadline is the calendar date the target should be reached by, as
+	// midnight UTC, or zero if there is none.
+	Deadline time.Time
+}
+
+// Validate checks that the direction is known and that the target lies
+// beyond the baseline in that direction.
+func (m Metric) Validate() error {
+	switch m.Direction {
+	case Increase:
+		if m.Target <= m.Baseline {
+			return i18n.NewError(i18n.DetailTargetAboveBaseline, m.Target, m.Baseline)
+		}
+	case Decrease:
+		if m.Target >= m.Baseline {
+			return i18n.NewError(i18n.DetailTargetBelowBaseline, m.Target, m.Baseline)
+		}
+	default:
+		return i18n.NewError(i18n.DetailUnknownDirection, m.Direction)
+	}
+	return nil
+}
+
+// reached reports whether value is at or beyond the target.
+func (m Metric) reached(value float64) bool {
+	return m.Direction.Crossed(value, m.Target)
+}
+
+// Point is one measurement of the metric.
+type Point struct {
+	Value float64
+	At    time.Time
+}
+
+// Summary is the progress of a metric at a point in time.
+type Summary struct {
+	// Current is the latest measured value, or the baseline before the
+	// first measurement.
+	Current float64
+	// Percent is how much of the way from baseline to target Current has
+	// covered, between 0 and 100.
+	Percent  float64
+	Achieved bool
+	// Projected is the calendar date, as midnight UTC, on which the linear
+	// trend of the measurements reaches the target. It is zero when the
+	// target is achieved, there are fewer than two measurements or the
+	// trend does not head toward the target.
+	Projected time.Time
+	// OnTrack is true when the target is achieved, or when it is projected
+	// to be reached no later than the deadline (or at all, without one).
+	OnTrack bool
+	Count   int
+	// LastAt is when the latest measurement was taken, or zero.
+	LastAt time.Time
+}
+
+// Compute summarizes the progress of points toward m as of now. Calendar
+// dates are taken in loc.
+func Compute(m Metric, points []Point, now time.Time, loc *time.Location) Summary {
+	points = append([]Point(nil), points...)
+	sort.SliceStable(points, func(i, j int) bool { return points[i].At.Before(points[j].At) })
+
+	s := Summary{Current: m.Baseline, Count: len(points)}
+	if len(points) > 0 {
+		last := points[len(points)-1]
+		s.Current, s.LastAt = last.Value, last.At
+	}
+	if span := m.Target - m.Baseline; span != 0 {
+		s.Percent = math.Max(0, math.Min(100, (s.Current-m.Baseline)/span*100))
+	}
+	s.Achieved = m.reached(s.Current)
+	if s.Achieved {
+		s.OnTrack = true
+		return s
+	}
+
+	if at, ok := project(m, points); ok {
+		if at.Before(now) {
+			// The trend has passed the target but the measurements have
+			// not caught up yet: the earliest it can happen is today.
+			at = now
+		}
+		t := at.In(loc)
+		s.Projected = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+		s.OnTrack = m.Deadline.IsZero() || !s.Projected.After(m.Deadline)
+	}
+	return s
+}
+
+// project fits a least-squares line through the points and returns when it
+// crosses the target. It reports false if the line is flat, heads away from
+// the target or crosses it beyond maxHorizon.
+func project(m Metric, points []Point) (time.Time, bool) {
+	if len(points) < 2 {
+		return time.Time{}, false
+	}
+	origin := points[0].At
+	n := float64(len(points))
+	var sumX, sumY, sumXX, sumXY float64
+	for _, p := range points {
+		x := p.At.Sub(origin).Hours()
+		sumX += x
+		sumY += p.Value
+		sumXX += x * x
+		sumXY += x * p.Value
+	}
+	denom := n*sumXX - sumX*sumX
+	if denom == 0 {
+		return time.Time{}, false
+	}
+	slope := (n*sumXY - sumX*sumY) / denom
+	if (m.Direction == Increase && slope <= 0) || (m.Direction == Decrease && slope >= 0) {
+		return time.Time{}, false
+	}
+	intercept := (sumY - slope*sumX) / n
+	hours := (m.Target - intercept) / slope
+	if hours > maxHorizon.Hours() {
+		return time.Time{}, false
+	}
+	return origin.Add(time.Duration(hours * float64(time.Hour))), true
+}
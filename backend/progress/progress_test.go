@@ -0,0 +1,98 @@
+package progress
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestMetricValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		metric  Metric
+		wantErr bool
+	}{
+		{"increase", Metric{Baseline: 0, Target: 10, Direction: Increase}, false},
+		{"decrease", Metric{Baseline: 70, Target: 60, Direction: Decrease}, false},
+		{"increase below baseline", Metric{Baseline: 10, Target: 5, Direction: Increase}, true},
+		{"decrease above baseline", Metric{Baseline: 60, Target: 70, Direction: Decrease}, true},
+		{"target equals baseline", Metric{Baseline: 5, Target: 5, Direction: Increase}, true},
+		{"unknown direction", Metric{Baseline: 0, Target: 10, Direction: "up"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.metric.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("got %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCompute(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Fatalf("loading location: %v", err)
+	}
+	at := func(day, hour int) time.Time { return time.Date(2025, 4, day, hour, 0, 0, 0, time.UTC) }
+	point := func(value float64, day int) Point { return Point{Value: value, At: at(day, 0)} }
+	weight := Metric{Baseline: 70, Target: 60, Direction: Decrease}
+	withDeadline := func(m Metric, deadline time.Time) Metric {
+		m.Deadline = deadline
+		return m
+	}
+	format := func(s Summary) string {
+		projected := "-"
+		if !s.Projected.IsZero() {
+			projected = s.Projected.Format("2006-01-02")
+		}
+		return fmt.Sprintf("current=%g percent=%.4g achieved=%v projected=%s on_track=%v count=%d", s.Current, s.Percent, s.Achieved, projected, s.OnTrack, s.Count)
+	}
+
+	tests := []struct {
+		name   string
+		metric Metric
+		points []Point
+		now    time.Time
+		loc    *time.Location
+		want   string
+	}{
+		{"no measurements", weight, nil, at(21, 0), time.UTC,
+			"current=70 percent=0 achieved=false projected=- on_track=false count=0"},
+		{"one measurement", weight, []Point{point(68, 1)}, at(21, 0), time.UTC,
+			"current=68 percent=20 achieved=false projected=- on_track=false count=1"},
+		// 0.2 a day from 70 reaches 60 fifty days after April 1.
+		{"steady trend", weight, []Point{point(70, 1), point(68, 11), point(66, 21)}, at(21, 0), time.UTC,
+			"current=66 percent=40 achieved=false projected=2025-05-21 on_track=true count=3"},
+		{"unsorted measurements", weight, []Point{point(66, 21), point(70, 1), point(68, 11)}, at(21, 0), time.UTC,
+			"current=66 percent=40 achieved=false projected=2025-05-21 on_track=true count=3"},
+		{"before the deadline", withDeadline(weight, time.Date(2025, 5, 21, 0, 0, 0, 0, time.UTC)), []Point{point(70, 1), point(68, 11), point(66, 21)}, at(21, 0), time.UTC,
+			"current=66 percent=40 achieved=false projected=2025-05-21 on_track=true count=3"},
+		{"after the deadline", withDeadline(weight, time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC)), []Point{point(70, 1), point(68, 11), point(66, 21)}, at(21, 0), time.UTC,
+			"current=66 percent=40 achieved=false projected=2025-05-21 on_track=false count=3"},
+		{"achieved", weight, []Point{point(65, 1), point(59, 11)}, at(21, 0), time.UTC,
+			"current=59 percent=100 achieved=true projected=- on_track=true count=2"},
+		{"heading away", weight, []Point{point(70, 1), point(71, 11)}, at(21, 0), time.UTC,
+			"current=71 percent=0 achieved=false projected=- on_track=false count=2"},
+		{"flat", weight, []Point{point(68, 1), point(68, 11)}, at(21, 0), time.UTC,
+			"current=68 percent=20 achieved=false projected=- on_track=false count=2"},
+		{"same instant", weight, []Point{point(69, 1), point(68, 1)}, at(21, 0), time.UTC,
+			"current=68 percent=20 achieved=false projected=- on_track=false count=2"},
+		// The trend crossed the target on April 2, but the last measurement has not.
+		{"trend already past the target", weight, []Point{point(70, 1), point(61, 2)}, at(21, 0), time.UTC,
+			"current=61 percent=90 achieved=false projected=2025-04-21 on_track=true count=2"},
+		// 5 every 10 hours reaches 10 at 20:00 UTC, which is the next day in Tokyo.
+		{"projected in UTC", Metric{Baseline: 0, Target: 10, Direction: Increase}, []Point{{0, at(1, 0)}, {5, at(1, 10)}}, at(1, 10), time.UTC,
+			"current=5 percent=50 achieved=false projected=2025-04-01 on_track=true count=2"},
+		{"projected in Tokyo", Metric{Baseline: 0, Target: 10, Direction: Increase}, []Point{{0, at(1, 0)}, {5, at(1, 10)}}, at(1, 10), tokyo,
+			"current=5 percent=50 achieved=false projected=2025-04-02 on_track=true count=2"},
+		{"too flat to project", Metric{Baseline: 0, Target: 1e9, Direction: Increase}, []Point{point(0, 1), point(1, 2)}, at(2, 0), time.UTC,
+			"current=1 percent=1e-07 achieved=false projected=- on_track=false count=2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := format(Compute(tt.metric, tt.points, tt.now, tt.loc)); got != tt.want {
+				t.Errorf("got  %s\nwant %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,156 @@
+// Package quantity validates the units of quantitative gains and losses and
+// aggregates their values per unit, goal and time bucket.
+package quantity
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"refuel/backend/i18n"
+)
+
+// Units lists the accepted units by their canonical name.
+var Units = []string{
+	"kg", "g",
+	"km", "m",
+	"minutes", "hours",
+	"kcal",
+	"steps", "reps", "pages", "times",
+	"yen",
+}
+
+// aliases maps other spellings to a canonical unit.
+var aliases = map[string]string{
+	"kgs": "kg", "kilogram": "kg", "kilograms": "kg",
+	"gram": "g", "grams": "g",
+	"kilometer": "km", "kilometers": "km",
+	"meter": "m", "meters": "m",
+	"min": "minutes", "mins": "minutes", "minute": "minutes", "分": "minutes",
+	"h": "hours", "hr": "hours", "hrs": "hours", "hour": "hours", "時間": "hours",
+	"cal": "kcal", "kcals": "kcal", "calories": "kcal",
+	"step": "steps", "歩": "steps",
+	"rep": "reps", "回": "times", "time": "times",
+	"page": "pages", "ページ": "pages",
+	"円": "yen", "jpy": "yen",
+}
+
+// NormalizeUnit returns the canonical name of unit, or an error if it is not
+// one of Units or a known alias of one.
+func NormalizeUnit(unit string) (string, error) {
+	u := strings.ToLower(strings.TrimSpace(unit))
+	if canonical, ok := aliases[u]; ok {
+		return canonical, nil
+	}
+	for _, known := range Units {
+		if u == known {
+			return u, nil
+		}
+	}
+	return "", i18n.NewError(i18n.DetailUnknownUnit, unit, strings.Join(Units, ", "))
+}
+
+// Bucket is the time span values are grouped by.
+type Bucket string
+
+// Supported buckets. Weeks start on Monday.
+const (
+	Day   Bucket = "day"
+	Week  Bucket = "week"
+	Month Bucket = "month"
+)
+
+// ParseBucket accepts day, week or month; empty means Month.
+func ParseBucket(s string) (Bucket, error) {
+	switch b := Bucket(s); b {
+	case "":
+		return Month, nil
+	case Day, Week, Month:
+		return b, nil
+	}
+	return "", i18n.NewError(i18n.DetailUnknownBucket, s)
+}
+
+// Start returns the first day of the bucket containing t in loc, as
+// midnight UTC like the other calendar dates in the backend.
+func (b Bucket) Start(t time.Time, loc *time.Location) time.Time {
+	t = t.In(loc)
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	switch b {
+	case Week:
+		return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+	case Month:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+	return day
+}
+
+// Kinds of entries.
+const (
+	KindGain = "gain"
+	KindLoss = "loss"
+)
+
+// Entry is one quantitative gain or loss.
+type Entry struct {
+	// Kind is KindGain or KindLoss.
+	Kind   string
+	GoalID uint
+	Unit   string
+	Value  float64
+	// At places the entry in a bucket.
+	At time.Time
+}
+
+// Row is the aggregate of the entries sharing a kind, goal, unit and bucket.
+type Row struct {
+	Kind        string
+	GoalID      uint
+	Unit        string
+	BucketStart time.Time
+	Count       int
+	Total       float64
+	Average     float64
+}
+
+// Aggregate totals and averages the entries per kind, goal, unit and bucket.
+// Rows are ordered by bucket, then goal, kind and unit.
+func Aggregate(entries []Entry, bucket Bucket, loc *time.Location) []Row {
+	type key struct {
+		kind   string
+		goalID uint
+		unit   string
+		start  time.Time
+	}
+	rows := map[key]*Row{}
+	for _, e := range entries {
+		k := key{e.Kind, e.GoalID, e.Unit, bucket.Start(e.At, loc)}
+		row, ok := rows[k]
+		if !ok {
+			row = &Row{Kind: k.kind, GoalID: k.goalID, Unit: k.unit, BucketStart: k.start}
+			rows[k] = row
+		}
+		row.Count++
+		row.Total += e.Value
+	}
+
+	out := make([]Row, 0, len(rows))
+	for _, row := range rows {
+		row.Average = row.Total / float64(row.Count)
+		out = append(out, *row)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		a, b := out[i], out[j]
+		if !a.BucketStart.Equal(b.BucketStart) {
+			return a.BucketStart.Before(b.BucketStart)
+		}
+		if a.GoalID != b.GoalID {
+			return a.GoalID < b.GoalID
+		}
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		return a.Unit < b.Unit
+	})
+	return out
+}
@@ -0,0 +1,147 @@
+package quantity
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNormalizeUnit(t *testing.T) {
+	tests := []struct {
+		unit    string
+		want    string
+		wantErr bool
+	}{
+		{"kg", "kg", false},
+		{" KG ", "kg", false},
+		{"kilograms", "kg", false},
+		{"分", "minutes", false},
+		{"Hrs", "hours", false},
+		{"円", "yen", false},
+		{"回", "times", false},
+		{"stone", "", true},
+		{"", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.unit, func(t *testing.T) {
+			got, err := NormalizeUnit(tt.unit)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("got %q, want an error", got)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("got %q, %v, want %q", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestAliasesAreCanonical(t *testing.T) {
+	for alias, unit := range aliases {
+		if got, err := NormalizeUnit(unit); err != nil || got != unit {
+			t.Errorf("alias %q maps to %q, which is not one of Units", alias, unit)
+		}
+	}
+}
+
+func TestParseBucket(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Bucket
+		wantErr bool
+	}{
+		{"", Month, false},
+		{"day", Day, false},
+		{"week", Week, false},
+		{"month", Month, false},
+		{"year", "", true},
+		{"Week", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseBucket(tt.in)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("got %q, %v, want %q (error %v)", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBucketStart(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Fatalf("loading location: %v", err)
+	}
+	// Sunday 2025-03-30 20:00 UTC is Monday 2025-03-31 05:00 in Tokyo.
+	at := time.Date(2025, 3, 30, 20, 0, 0, 0, time.UTC)
+	tests := []struct {
+		bucket Bucket
+		loc    *time.Location
+		want   string
+	}{
+		{Day, time.UTC, "2025-03-30"},
+		{Day, tokyo, "2025-03-31"},
+		{Week, time.UTC, "2025-03-24"},
+		{Week, tokyo, "2025-03-31"},
+		{Month, time.UTC, "2025-03-01"},
+		{Month, tokyo, "2025-03-01"},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s in %s", tt.bucket, tt.loc), func(t *testing.T) {
+			got := tt.bucket.Start(at, tt.loc)
+			if got.Format("2006-01-02") != tt.want || got.Location() != time.UTC || got.Hour() != 0 {
+				t.Errorf("got %v, want %s 00:00 UTC", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAggregate(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 4, d, 12, 0, 0, 0, time.UTC) }
+	entries := []Entry{
+		{Kind: KindGain, GoalID: 1, Unit: "km", Value: 3, At: day(1)},
+		{Kind: KindGain, GoalID: 1, Unit: "km", Value: 5, At: day(2)},
+		{Kind: KindGain, GoalID: 1, Unit: "minutes", Value: 30, At: day(2)},
+		{Kind: KindLoss, GoalID: 1, Unit: "yen", Value: 500, At: day(3)},
+		{Kind: KindGain, GoalID: 2, Unit: "km", Value: 10, At: day(8)},
+	}
+	format := func(rows []Row) string {
+		var b strings.Builder
+		for _, r := range rows {
+			fmt.Fprintf(&b, "%s goal%d %s %s n=%d total=%g avg=%g\n", r.BucketStart.Format("01-02"), r.GoalID, r.Kind, r.Unit, r.Count, r.Total, r.Average)
+		}
+		return b.String()
+	}
+
+	tests := []struct {
+		bucket Bucket
+		want   string
+	}{
+		{Week, "03-31 goal1 gain km n=2 total=8 avg=4\n" +
+			"03-31 goal1 gain minutes n=1 total=30 avg=30\n" +
+			"03-31 goal1 loss yen n=1 total=500 avg=500\n" +
+			"04-07 goal2 gain km n=1 total=10 avg=10\n"},
+		{Day, "04-01 goal1 gain km n=1 total=3 avg=3\n" +
+			"04-02 goal1 gain km n=1 total=5 avg=5\n" +
+			"04-02 goal1 gain minutes n=1 total=30 avg=30\n" +
+			"04-03 goal1 loss yen n=1 total=500 avg=500\n" +
+			"04-08 goal2 gain km n=1 total=10 avg=10\n"},
+		{Month, "04-01 goal1 gain km n=2 total=8 avg=4\n" +
+			"04-01 goal1 gain minutes n=1 total=30 avg=30\n" +
+			"04-01 goal1 loss yen n=1 total=500 avg=500\n" +
+			"04-01 goal2 gain km n=1 total=10 avg=10\n"},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.bucket), func(t *testing.T) {
+			if got := format(Aggregate(entries, tt.bucket, time.UTC)); got != tt.want {
+				t.Errorf("got\n%swant\n%s", got, tt.want)
+			}
+		})
+	}
+	if got := Aggregate(nil, Month, time.UTC); len(got) != 0 {
+		t.Errorf("got %v for no entries, want none", got)
+	}
+}
@@ -0,0 +1,123 @@
+// Package recurrence validates action recurrence patterns and expands them
+// into concrete occurrences.
+package recurrence
+
+import (
+	"strings"
+	"time"
+
+	"refuel/backend/i18n"
+)
+
+// Frequency is the unit a pattern repeats in.
+type Frequency string
+
+const (
+	Daily   Frequency = "daily"
+	Weekly  Frequency = "weekly"
+	Monthly Frequency = "monthly"
+)
+
+// weekdayCodes maps the API's day codes to time.Weekday.
+var weekdayCodes = map[string]time.Weekday{
+	"SUN": time.Sunday,
+	"MON": time.Monday,
+	"TUE": time.Tuesday,
+	"WED": time.Wednesday,
+	"THU": time.Thursday,
+	"FRI": time.Friday,
+	"SAT": time.Saturday,
+}
+
+// Pattern is the persisted form of refuelapi.RecurrencePattern.
+// It is stored as JSON in actions.recurrence_pattern.
+type Pattern struct {
+	Frequency  Frequency `json:"frequency"`
+	Interval   int       `json:"interval"`
+	TimeOfDay  string    `json:"time_of_day"`
+	DaysOfWeek []string  `json:"days_of_week,omitempty"`
+	DayOfMonth int       `json:"day_of_month,omitempty"`
+}
+
+// ValidationError reports an invalid pattern field. Err says what is wrong
+// with it, naming the field as recurrence_pattern.<Field>.
+type ValidationError struct {
+	Field string
+	Err   *i18n.Error
+}
+
+// invalid returns the error for field with the catalog message for code,
+// which takes the field's name before args.
+func invalid(field string, code i18n.Code, args ...interface{}) *ValidationError {
+	args = append([]interface{}{"recurrence_pattern." + field}, args...)
+	return &ValidationError{Field: field, Err: i18n.NewError(code, args...)}
+}
+
+func (e *ValidationError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// Normalize fills in defaults: an interval of 0 becomes 1 and day codes are upper-cased.
+func (p Pattern) Normalize() Pattern {
+	if p.Interval == 0 {
+		p.Interval = 1
+	}
+	if len(p.DaysOfWeek) > 0 {
+		days := make([]string, len(p.DaysOfWeek))
+		for i, d := range p.DaysOfWeek {
+			days[i] = strings.ToUpper(strings.TrimSpace(d))
+		}
+		p.DaysOfWeek = days
+	}
+	return p
+}
+
+// Validate checks that the pattern can be expanded into a schedule.
+func (p Pattern) Validate() error {
+	switch p.Frequency {
+	case Daily, Weekly, Monthly:
+	default:
+		return invalid("frequency", i18n.DetailFrequency)
+	}
+	if p.Interval < 1 {
+		return invalid("interval", i18n.DetailInterval)
+	}
+	if _, _, err := p.clock(); err != nil {
+		return invalid("time_of_day", i18n.DetailTimeOfDay)
+	}
+	if len(p.DaysOfWeek) > 0 && p.Frequency != Weekly {
+		return invalid("days_of_week", i18n.DetailWeeklyOnly)
+	}
+	seen := map[string]bool{}
+	for _, d := range p.DaysOfWeek {
+		if _, ok := weekdayCodes[d]; !ok {
+			return invalid("days_of_week", i18n.DetailUnknownDay, d)
+		}
+		if seen[d] {
+			return invalid("days_of_week", i18n.DetailDuplicateDay, d)
+		}
+		seen[d] = true
+	}
+	if p.DayOfMonth != 0 {
+		if p.Frequency != Monthly {
+			return invalid("day_of_month", i18n.DetailMonthlyOnly)
+		}
+		if p.DayOfMonth < 1 || p.DayOfMonth > 31 {
+			return invalid("day_of_month", i18n.DetailDayOfMonth)
+		}
+	}
+	return nil
+}
+
+// clock parses TimeOfDay into hour and minute.
+func (p Pattern) clock() (int, int, error) {
+	t, err := time.Parse("15:04", p.TimeOfDay)
+	if err != nil {
+		return 0, 0, err
+	}
+	return t.Hour(), t.Minute(), nil
+}
@@ -0,0 +1,198 @@
+package recurrence
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern Pattern
+		field   string
+	}{
+		{"daily", Pattern{Frequency: Daily, TimeOfDay: "09:00"}, ""},
+		{"weekly days", Pattern{Frequency: Weekly, Interval: 2, TimeOfDay: "20:30", DaysOfWeek: []string{" mon", "fri"}}, ""},
+		{"monthly day", Pattern{Frequency: Monthly, TimeOfDay: "07:15", DayOfMonth: 31}, ""},
+		{"unknown frequency", Pattern{Frequency: "yearly", TimeOfDay: "09:00"}, "frequency"},
+		{"negative interval", Pattern{Frequency: Daily, Interval: -1, TimeOfDay: "09:00"}, "interval"},
+		{"bad time", Pattern{Frequency: Daily, TimeOfDay: "9pm"}, "time_of_day"},
+		{"hour out of range", Pattern{Frequency: Daily, TimeOfDay: "24:00"}, "time_of_day"},
+		{"days on daily", Pattern{Frequency: Daily, TimeOfDay: "09:00", DaysOfWeek: []string{"MON"}}, "days_of_week"},
+		{"unknown day", Pattern{Frequency: Weekly, TimeOfDay: "09:00", DaysOfWeek: []string{"MONDAY"}}, "days_of_week"},
+		{"duplicate day", Pattern{Frequency: Weekly, TimeOfDay: "09:00", DaysOfWeek: []string{"MON", "mon"}}, "days_of_week"},
+		{"day of month on weekly", Pattern{Frequency: Weekly, TimeOfDay: "09:00", DayOfMonth: 1}, "day_of_month"},
+		{"day of month out of range", Pattern{Frequency: Monthly, TimeOfDay: "09:00", DayOfMonth: 32}, "day_of_month"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.pattern.Normalize().Validate()
+			if tt.field == "" {
+				if err != nil {
+					t.Fatalf("got %v, want no error", err)
+				}
+				return
+			}
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("got %v, want a ValidationError", err)
+			}
+			if validationErr.Field != tt.field {
+				t.Errorf("got field %q, want %q", validationErr.Field, tt.field)
+			}
+		})
+	}
+}
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestDates(t *testing.T) {
+	tests := []struct {
+		name     string
+		pattern  Pattern
+		start    time.Time
+		from, to time.Time
+		want     []time.Time
+	}{
+		{
+			name:    "every other day",
+			pattern: Pattern{Frequency: Daily, Interval: 2, TimeOfDay: "09:00"},
+			start:   date(2025, 4, 1), from: date(2025, 3, 25), to: date(2025, 4, 7),
+			want: []time.Time{date(2025, 4, 1), date(2025, 4, 3), date(2025, 4, 5), date(2025, 4, 7)},
+		},
+		{
+			name:    "weekly on the start's weekday",
+			pattern: Pattern{Frequency: Weekly, TimeOfDay: "09:00"},
+			start:   date(2025, 4, 1), from: date(2025, 4, 1), to: date(2025, 4, 21),
+			want: []time.Time{date(2025, 4, 1), date(2025, 4, 8), date(2025, 4, 15)},
+		},
+		{
+			name:    "every other week on given days",
+			pattern: Pattern{Frequency: Weekly, Interval: 2, TimeOfDay: "09:00", DaysOfWeek: []string{"MON", "FRI"}},
+			start:   date(2025, 4, 1), from: date(2025, 4, 1), to: date(2025, 4, 20),
+			want: []time.Time{date(2025, 4, 4), date(2025, 4, 14), date(2025, 4, 18)},
+		},
+		{
+			name:    "day of month past the month's end",
+			pattern: Pattern{Frequency: Monthly, TimeOfDay: "09:00", DayOfMonth: 31},
+			start:   date(2025, 1, 15), from: date(2025, 1, 1), to: date(2025, 4, 30),
+			want: []time.Time{date(2025, 1, 31), date(2025, 2, 28), date(2025, 3, 31), date(2025, 4, 30)},
+		},
+		{
+			name:    "monthly on the start's day in a leap year",
+			pattern: Pattern{Frequency: Monthly, TimeOfDay: "09:00"},
+			start:   date(2024, 1, 31), from: date(2024, 1, 1), to: date(2024, 3, 31),
+			want: []time.Time{date(2024, 1, 31), date(2024, 2, 29), date(2024, 3, 31)},
+		},
+		{
+			name:    "quarterly",
+			pattern: Pattern{Frequency: Monthly, Interval: 3, TimeOfDay: "09:00", DayOfMonth: 1},
+			start:   date(2025, 1, 10), from: date(2025, 1, 1), to: date(2025, 7, 31),
+			want: []time.Time{date(2025, 4, 1), date(2025, 7, 1)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := NewSchedule(tt.pattern, tt.start, nil)
+			if err != nil {
+				t.Fatalf("NewSchedule: %v", err)
+			}
+			got := s.Dates(tt.from, tt.to)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if !got[i].Equal(tt.want[i]) {
+					t.Errorf("date %d: got %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestNext(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Skip("no time zone data")
+	}
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("no time zone data")
+	}
+	tests := []struct {
+		name    string
+		pattern Pattern
+		start   time.Time
+		loc     *time.Location
+		after   time.Time
+		n       int
+		want    []time.Time
+	}{
+		{
+			name:    "skips today's occurrence once past",
+			pattern: Pattern{Frequency: Daily, TimeOfDay: "20:00"},
+			start:   time.Date(2025, 4, 1, 8, 0, 0, 0, tokyo), loc: tokyo,
+			after: time.Date(2025, 4, 1, 11, 30, 0, 0, time.UTC), n: 2,
+			want: []time.Time{time.Date(2025, 4, 2, 11, 0, 0, 0, time.UTC), time.Date(2025, 4, 3, 11, 0, 0, 0, time.UTC)},
+		},
+		{
+			name:    "starts on the schedule's first day",
+			pattern: Pattern{Frequency: Daily, TimeOfDay: "20:00"},
+			start:   time.Date(2025, 4, 10, 8, 0, 0, 0, tokyo), loc: tokyo,
+			after: time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC), n: 1,
+			want: []time.Time{time.Date(2025, 4, 10, 11, 0, 0, 0, time.UTC)},
+		},
+		{
+			name:    "keeps the local time across a DST change",
+			pattern: Pattern{Frequency: Daily, TimeOfDay: "09:00"},
+			start:   time.Date(2025, 3, 8, 0, 0, 0, 0, newYork), loc: newYork,
+			after: time.Date(2025, 3, 8, 0, 0, 0, 0, newYork), n: 2,
+			want: []time.Time{time.Date(2025, 3, 8, 14, 0, 0, 0, time.UTC), time.Date(2025, 3, 9, 13, 0, 0, 0, time.UTC)},
+		},
+		{
+			name:    "none past the search horizon",
+			pattern: Pattern{Frequency: Monthly, Interval: 12 * 11, TimeOfDay: "09:00"},
+			start:   date(2025, 1, 1), loc: time.UTC,
+			after: date(2025, 1, 2), n: 1,
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := NewSchedule(tt.pattern, tt.start, tt.loc)
+			if err != nil {
+				t.Fatalf("NewSchedule: %v", err)
+			}
+			got := s.Next(tt.after, tt.n)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if !got[i].Equal(tt.want[i]) {
+					t.Errorf("occurrence %d: got %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestOccursOnUsesScheduleLocation(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Skip("no time zone data")
+	}
+	s, err := NewSchedule(Pattern{Frequency: Weekly, TimeOfDay: "09:00", DaysOfWeek: []string{"WED"}}, date(2025, 4, 1), tokyo)
+	if err != nil {
+		t.Fatalf("NewSchedule: %v", err)
+	}
+	// 16:00 UTC on Tuesday is already Wednesday in Tokyo.
+	if !s.OccursOn(time.Date(2025, 4, 1, 16, 0, 0, 0, time.UTC)) {
+		t.Error("got no occurrence, want one on Wednesday in Tokyo")
+	}
+	if s.OccursOn(time.Date(2025, 4, 2, 16, 0, 0, 0, time.UTC)) {
+		t.Error("got an occurrence on Thursday in Tokyo, want none")
+	}
+}
@@ -0,0 +1,147 @@
+package recurrence
+
+import (
+	"time"
+)
+
+// maxSearchDays bounds how far ahead Next scans for occurrences.
+const maxSearchDays = 366 * 10
+
+// Schedule expands a Pattern into occurrences, starting on the day the
+// action was created. Calendar math is done in the schedule's location, so
+// a "20:00 daily" action is due at 20:00 wherever the user lives.
+type Schedule struct {
+	Pattern Pattern
+	start   time.Time
+	loc     *time.Location
+}
+
+// NewSchedule validates the pattern and anchors it at start. A nil location means UTC.
+func NewSchedule(p Pattern, start time.Time, loc *time.Location) (Schedule, error) {
+	p = p.Normalize()
+	if err := p.Validate(); err != nil {
+		return Schedule{}, err
+	}
+	if loc == nil {
+		loc = time.UTC
+	}
+	return Schedule{Pattern: p, start: dateOf(start, loc), loc: loc}, nil
+}
+
+// Location returns the time zone the schedule is evaluated in.
+func (s Schedule) Location() *time.Location {
+	return s.loc
+}
+
+// Start returns midnight of the first day the schedule is active.
+func (s Schedule) Start() time.Time {
+	return s.start
+}
+
+// OccursOn reports whether the calendar day containing t has an occurrence.
+func (s Schedule) OccursOn(t time.Time) bool {
+	day := dateOf(t, s.loc)
+	if day.Before(s.start) {
+		return false
+	}
+	p := s.Pattern
+	switch p.Frequency {
+	case Daily:
+		return daysBetween(s.start, day)%p.Interval == 0
+	case Weekly:
+		weeks := daysBetween(weekStart(s.start), weekStart(day)) / 7
+		if weeks%p.Interval != 0 {
+			return false
+		}
+		if len(p.DaysOfWeek) == 0 {
+			return day.Weekday() == s.start.Weekday()
+		}
+		for _, code := range p.DaysOfWeek {
+			if weekdayCodes[code] == day.Weekday() {
+				return true
+			}
+		}
+		return false
+	case Monthly:
+		months := (day.Year()*12 + int(day.Month())) - (s.start.Year()*12 + int(s.start.Month()))
+		if months%p.Interval != 0 {
+			return false
+		}
+		want := p.DayOfMonth
+		if want == 0 {
+			want = s.start.Day()
+		}
+		// Months shorter than the requested day fall back to their last day.
+		if last := daysInMonth(day.Year(), day.Month()); want > last {
+			want = last
+		}
+		return day.Day() == want
+	}
+	return false
+}
+
+// At returns the occurrence time on the calendar day containing t.
+// It does not check whether the day actually has an occurrence.
+func (s Schedule) At(t time.Time) time.Time {
+	day := dateOf(t, s.loc)
+	hour, minute, _ := s.Pattern.clock()
+	return time.Date(day.Year(), day.Month(), day.Day(), hour, minute, 0, 0, s.loc)
+}
+
+// Next returns up to n occurrences strictly after the given time.
+func (s Schedule) Next(after time.Time, n int) []time.Time {
+	var out []time.Time
+	day := dateOf(after, s.loc)
+	if day.Before(s.start) {
+		day = s.start
+	}
+	for i := 0; i < maxSearchDays && len(out) < n; i++ {
+		if s.OccursOn(day) {
+			if at := s.At(day); at.After(after) {
+				out = append(out, at)
+			}
+		}
+		day = day.AddDate(0, 0, 1)
+	}
+	return out
+}
+
+// Dates returns midnight of every day between from and to (inclusive,
+// compared by calendar day) that has an occurrence.
+func (s Schedule) Dates(from, to time.Time) []time.Time {
+	var out []time.Time
+	day := dateOf(from, s.loc)
+	if day.Before(s.start) {
+		day = s.start
+	}
+	last := dateOf(to, s.loc)
+	for !day.After(last) {
+		if s.OccursOn(day) {
+			out = append(out, day)
+		}
+		day = day.AddDate(0, 0, 1)
+	}
+	return out
+}
+
+// dateOf returns midnight of the calendar day containing t in loc.
+func dateOf(t time.Time, loc *time.Location) time.Time {
+	t = t.In(loc)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+}
+
+// daysBetween counts calendar days from a to b, ignoring DST shifts.
+func daysBetween(a, b time.Time) int {
+	ua := time.Date(a.Year(), a.Month(), a.Day(), 0, 0, 0, 0, time.UTC)
+	ub := time.Date(b.Year(), b.Month(), b.Day(), 0, 0, 0, 0, time.UTC)
+	return int(ub.Sub(ua).Hours() / 24)
+}
+
+// weekStart returns the Sunday that starts the week containing day.
+func weekStart(day time.Time) time.Time {
+	return day.AddDate(0, 0, -int(day.Weekday()))
+}
+
+func daysInMonth(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
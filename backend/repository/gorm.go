@@ -0,0 +1,922 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"refuel/backend/checkin"
+	"refuel/backend/models"
+)
+
+// NewGorm returns repositories backed by db. Cascading deletes are left to
+// the foreign keys declared in the migrations.
+func NewGorm(db *gorm.DB) *Repositories {
+	return &Repositories{
+		Complexes: gormComplexes{db},
+		Goals:     gormGoals{db},
+		Actions:   gormActions{db},
+		Trash:     gormTrash{db},
+		Badges:    gormBadges{db},
+		Feedback:  gormFeedback{db},
+		Revisions: gormRevisions{db},
+		Exports:   gormExports{db},
+		Imports:   gormImports{db},
+		DataKeys:  NewGormDataKeys(db),
+		Users:     gormUsers{db},
+	}
+}
+
+// first loads the first row matching the query into dest, mapping a missing row to ErrNotFound.
+func first(tx *gorm.DB, dest interface{}) error {
+	err := tx.First(dest).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrNotFound
+	}
+	return err
+}
+
+// deleted maps a delete that matched no rows to ErrNotFound.
+func deleted(result *gorm.DB) error {
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// trash moves the rows tx selects to the trash at the given time. Rows
+// already in the trash are left alone, keeping the time they were moved at.
+func trash(tx *gorm.DB, model interface{}, at time.Time) *gorm.DB {
+	return tx.Model(model).UpdateColumn("deleted_at", at)
+}
+
+type gormComplexes struct{ db *gorm.DB }
+
+func (r gormComplexes) List(ctx context.Context, userID string) ([]models.Complex, error) {
+	complexes := []models.Complex{}
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Find(&complexes).Error
+	return complexes, err
+}
+
+func (r gormComplexes) ListPage(ctx context.Context, userID string, filter ComplexFilter, page Page) ([]models.Complex, *Cursor, error) {
+	complexes := []models.Complex{}
+	tx := filter.scope(r.db.WithContext(ctx).Where("user_id = ?", userID))
+	if err := paged(tx, page).Find(&complexes).Error; err != nil {
+		return nil, nil, err
+	}
+	complexes, next := nextPage(complexes, page, complexKey)
+	return complexes, next, nil
+}
+
+func (r gormComplexes) Get(ctx context.Context, userID string, id uint) (*models.Complex, error) {
+	var complex models.Complex
+	if err := first(r.db.WithContext(ctx).Where("id = ? AND user_id = ?", id, userID), &complex); err != nil {
+		return nil, err
+	}
+	return &complex, nil
+}
+
+func (r gormComplexes) Create(ctx context.Context, complex *models.Complex) error {
+	return r.db.WithContext(ctx).Create(complex).Error
+}
+
+func (r gormComplexes) Update(ctx context.Context, complex *models.Complex) error {
+	return r.db.WithContext(ctx).Save(complex).Error
+}
+
+func (r gormComplexes) Delete(ctx context.Context, userID string, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := tx.NowFunc()
+		if err := deleted(trash(tx.Where("id = ? AND user_id = ?", id, userID), &models.Complex{}, now)); err != nil {
+			return err
+		}
+		goals := tx.Model(&models.Goal{}).Select("id").Where("complex_id = ?", id)
+		if err := trash(tx.Where("goal_id IN (?)", goals), &models.Action{}, now).Error; err != nil {
+			return err
+		}
+		return trash(tx.Where("complex_id = ?", id), &models.Goal{}, now).Error
+	})
+}
+
+type gormGoals struct{ db *gorm.DB }
+
+func (r gormGoals) List(ctx context.Context, userID string) ([]models.Goal, error) {
+	goals := []models.Goal{}
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Find(&goals).Error
+	return goals, err
+}
+
+func (r gormGoals) ListPage(ctx context.Context, userID string, filter GoalFilter, page Page) ([]models.Goal, *Cursor, error) {
+	goals := []models.Goal{}
+	tx := filter.scope(r.db.WithContext(ctx).Where("user_id = ?", userID))
+	if err := paged(tx, page).Find(&goals).Error; err != nil {
+		return nil, nil, err
+	}
+	goals, next := nextPage(goals, page, goalKey)
+	return goals, next, nil
+}
+
+func (r gormGoals) Get(ctx context.Context, userID string, id uint) (*models.Goal, error) {
+	var goal models.Goal
+	if err := first(r.db.WithContext(ctx).Where("id = ? AND user_id = ?", id, userID), &goal); err != nil {
+		return nil, err
+	}
+	return &goal, nil
+}
+
+func (r gormGoals) Create(ctx context.Context, goal *models.Goal) error {
+	return r.db.WithContext(ctx).Create(goal).Error
+}
+
+func (r gormGoals) Update(ctx context.Context, goal *models.Goal) error {
+	return r.db.WithContext(ctx).Save(goal).Error
+}
+
+func (r gormGoals) Delete(ctx context.Context, userID string, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := tx.NowFunc()
+		if err := deleted(trash(tx.Where("id = ? AND user_id = ?", id, userID), &models.Goal{}, now)); err != nil {
+			return err
+		}
+		return trash(tx.Where("goal_id = ?", id), &models.Action{}, now).Error
+	})
+}
+
+func (r gormGoals) ListMeasurements(ctx context.Context, userID string, goalID uint) ([]models.GoalMeasurement, error) {
+	measurements := []models.GoalMeasurement{}
+	err := r.db.WithContext(ctx).
+		Where("goal_id = ? AND user_id = ?", goalID, userID).
+		Order("measured_at, id").
+		Find(&measurements).Error
+	return measurements, err
+}
+
+func (r gormGoals) GetMeasurement(ctx context.Context, userID string, goalID, id uint) (*models.GoalMeasurement, error) {
+	var measurement models.GoalMeasurement
+	if err := first(r.db.WithContext(ctx).Where("id = ? AND goal_id = ? AND user_id = ?", id, goalID, userID), &measurement); err != nil {
+		return nil, err
+	}
+	return &measurement, nil
+}
+
+func (r gormGoals) CreateMeasurement(ctx context.Context, measurement *models.GoalMeasurement) error {
+	return r.db.WithContext(ctx).Create(measurement).Error
+}
+
+func (r gormGoals) DeleteMeasurement(ctx context.Context, userID string, goalID, id uint) error {
+	return deleted(r.db.WithContext(ctx).
+		Where("id = ? AND goal_id = ? AND user_id = ?", id, goalID, userID).
+		Delete(&models.GoalMeasurement{}))
+}
+
+func (r gormGoals) ListMilestones(ctx context.Context, userID string, goalID uint) ([]models.Milestone, error) {
+	milestones := []models.Milestone{}
+	err := r.db.WithContext(ctx).
+		Where("goal_id = ? AND user_id = ?", goalID, userID).
+		Order("position").
+		Find(&milestones).Error
+	return milestones, err
+}
+
+func (r gormGoals) ReplaceMilestones(ctx context.Context, userID string, goalID uint, milestones []models.Milestone) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("goal_id = ? AND user_id = ? AND achieved_at IS NULL", goalID, userID).Delete(&models.Milestone{}).Error; err != nil {
+			return err
+		}
+		if len(milestones) == 0 {
+			return nil
+		}
+		return tx.Create(&milestones).Error
+	})
+}
+
+func (r gormGoals) AchieveMilestone(ctx context.Context, id uint, at time.Time) error {
+	return r.db.WithContext(ctx).Model(&models.Milestone{}).
+		Where("id = ? AND achieved_at IS NULL", id).
+		Update("achieved_at", at).Error
+}
+
+type gormActions struct{ db *gorm.DB }
+
+// withOutcomes loads the gains and losses of the actions a query returns.
+func (r gormActions) withOutcomes(ctx context.Context) *gorm.DB {
+	byID := func(tx *gorm.DB) *gorm.DB { return tx.Order("id") }
+	return r.db.WithContext(ctx).Preload("Gains", byID).Preload("Losses", byID)
+}
+
+func (r gormActions) List(ctx context.Context, userID string) ([]models.Action, error) {
+	actions := []models.Action{}
+	err := r.withOutcomes(ctx).Where("user_id = ?", userID).Find(&actions).Error
+	return actions, err
+}
+
+func (r gormActions) ListByGoal(ctx context.Context, userID string, goalID uint) ([]models.Action, error) {
+	actions := []models.Action{}
+	err := r.withOutcomes(ctx).Where("goal_id = ? AND user_id = ?", goalID, userID).Order("created_at DESC").Find(&actions).Error
+	return actions, err
+}
+
+func (r gormActions) ListPage(ctx context.Context, userID string, filter ActionFilter, page Page) ([]models.Action, *Cursor, error) {
+	actions := []models.Action{}
+	tx := filter.scope(r.withOutcomes(ctx).Where("user_id = ?", userID))
+	if err := paged(tx, page).Find(&actions).Error; err != nil {
+		return nil, nil, err
+	}
+	actions, next := nextPage(actions, page, actionKey)
+	return actions, next, nil
+}
+
+func (r gormActions) Get(ctx context.Context, userID string, id uint) (*models.Action, error) {
+	var action models.Action
+	if err := first(r.withOutcomes(ctx).Where("id = ? AND user_id = ?", id, userID), &action); err != nil {
+		return nil, err
+	}
+	return &action, nil
+}
+
+func (r gormActions) Create(ctx context.Context, action *models.Action) error {
+	return r.db.WithContext(ctx).Create(action).Error
+}
+
+func (r gormActions) Update(ctx context.Context, action *models.Action) error {
+	return r.db.WithContext(ctx).Omit(clause.Associations).Save(action).Error
+}
+
+func (r gormActions) Delete(ctx context.Context, userID string, id uint) error {
+	tx := r.db.WithContext(ctx)
+	return deleted(trash(tx.Where("id = ? AND user_id = ?", id, userID), &models.Action{}, tx.NowFunc()))
+}
+
+func (r gormActions) ListGains(ctx context.Context, actionID uint) ([]models.Gain, error) {
+	return listByAction[models.Gain](r.db.WithContext(ctx), actionID)
+}
+
+func (r gormActions) GetGain(ctx context.Context, actionID, id uint) (*models.Gain, error) {
+	return getByAction[models.Gain](r.db.WithContext(ctx), actionID, id)
+}
+
+func (r gormActions) SaveGain(ctx context.Context, gain *models.Gain) error {
+	return r.db.WithContext(ctx).Save(gain).Error
+}
+
+func (r gormActions) DeleteGain(ctx context.Context, actionID, id uint) error {
+	return deleteByAction[models.Gain](r.db.WithContext(ctx), actionID, id)
+}
+
+func (r gormActions) SaveGains(ctx context.Context, actionID uint, gains []models.Gain, replace bool) error {
+	return saveBatch(r.db.WithContext(ctx), actionID, gains, gainKeys, replace)
+}
+
+func (r gormActions) ListLosses(ctx context.Context, actionID uint) ([]models.Loss, error) {
+	return listByAction[models.Loss](r.db.WithContext(ctx), actionID)
+}
+
+func (r gormActions) GetLoss(ctx context.Context, actionID, id uint) (*models.Loss, error) {
+	return getByAction[models.Loss](r.db.WithContext(ctx), actionID, id)
+}
+
+func (r gormActions) SaveLoss(ctx context.Context, loss *models.Loss) error {
+	return r.db.WithContext(ctx).Save(loss).Error
+}
+
+func (r gormActions) DeleteLoss(ctx context.Context, actionID, id uint) error {
+	return deleteByAction[models.Loss](r.db.WithContext(ctx), actionID, id)
+}
+
+func (r gormActions) SaveLosses(ctx context.Context, actionID uint, losses []models.Loss, replace bool) error {
+	return saveBatch(r.db.WithContext(ctx), actionID, losses, lossKeys, replace)
+}
+
+func listByAction[T any](tx *gorm.DB, actionID uint) ([]T, error) {
+	rows := []T{}
+	err := tx.Where("action_id = ?", actionID).Order("id").Find(&rows).Error
+	return rows, err
+}
+
+func getByAction[T any](tx *gorm.DB, actionID, id uint) (*T, error) {
+	var row T
+	if err := first(tx.Where("id = ? AND action_id = ?", id, actionID), &row); err != nil {
+		return nil, err
+	}
+	return &row, nil
+}
+
+func deleteByAction[T any](tx *gorm.DB, actionID, id uint) error {
+	return deleted(tx.Where("id = ? AND action_id = ?", id, actionID).Delete(new(T)))
+}
+
+func saveBatch[T any](db *gorm.DB, actionID uint, batch []T, keys keysFunc[T], replace bool) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		existing, err := listByAction[T](tx, actionID)
+		if err != nil {
+			return err
+		}
+		listed, err := checkBatch(existing, batch, keys)
+		if err != nil {
+			return err
+		}
+		if replace {
+			del := tx.Where("action_id = ?", actionID)
+			if len(listed) > 0 {
+				del = del.Where("id NOT IN ?", listed)
+			}
+			if err := del.Delete(new(T)).Error; err != nil {
+				return err
+			}
+		}
+		for i := range batch {
+			_, owner := keys(&batch[i])
+			*owner = actionID
+			if err := tx.Save(&batch[i]).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r gormActions) ListCompletions(ctx context.Context, actionIDs []uint, from, to time.Time) ([]models.ActionCompletion, error) {
+	completions := []models.ActionCompletion{}
+	if len(actionIDs) == 0 {
+		return completions, nil
+	}
+	tx := r.db.WithContext(ctx).Where("action_id IN ?", actionIDs)
+	if !from.IsZero() {
+		tx = tx.Where("occurrence_date >= ?", checkin.Key(from))
+	}
+	if !to.IsZero() {
+		tx = tx.Where("occurrence_date <= ?", checkin.Key(to))
+	}
+	err := tx.Order("occurrence_date").Find(&completions).Error
+	return completions, err
+}
+
+func (r gormActions) ListUserCompletions(ctx context.Context, userID string) ([]models.ActionCompletion, error) {
+	completions := []models.ActionCompletion{}
+	actions := r.db.Model(&models.Action{}).Select("id").Where("user_id = ?", userID)
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND action_id IN (?)", userID, actions).
+		Order("occurrence_date").
+		Find(&completions).Error
+	return completions, err
+}
+
+func (r gormActions) GetCompletion(ctx context.Context, actionID uint, date time.Time) (*models.ActionCompletion, error) {
+	var completion models.ActionCompletion
+	if err := first(r.db.WithContext(ctx).Where("action_id = ? AND occurrence_date = ?", actionID, checkin.Key(date)), &completion); err != nil {
+		return nil, err
+	}
+	return &completion, nil
+}
+
+func (r gormActions) SaveCompletion(ctx context.Context, completion *models.ActionCompletion) error {
+	return r.db.WithContext(ctx).Save(completion).Error
+}
+
+func (r gormActions) DeleteCompletion(ctx context.Context, userID string, actionID uint, date time.Time) error {
+	return deleted(r.db.WithContext(ctx).
+		Where("action_id = ? AND user_id = ? AND occurrence_date = ?", actionID, userID, checkin.Key(date)).
+		Delete(&models.ActionCompletion{}))
+}
+
+type gormTrash struct{ db *gorm.DB }
+
+// unscoped starts a query that sees the rows in the trash as well.
+func (r gormTrash) unscoped(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).Unscoped()
+}
+
+func (r gormTrash) List(ctx context.Context, userID string) ([]TrashItem, error) {
+	inTrash := func() *gorm.DB {
+		return r.unscoped(ctx).Where("user_id = ? AND deleted_at IS NOT NULL", userID)
+	}
+	complexes := []models.Complex{}
+	if err := inTrash().Find(&complexes).Error; err != nil {
+		return nil, err
+	}
+	goals := []models.Goal{}
+	if err := inTrash().Find(&goals).Error; err != nil {
+		return nil, err
+	}
+	actions := []models.Action{}
+	if err := inTrash().Find(&actions).Error; err != nil {
+		return nil, err
+	}
+	return trashItems(complexes, goals, actions), nil
+}
+
+func (r gormTrash) Restore(ctx context.Context, userID, kind string, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		inTrash := tx.Unscoped().Where("id = ? AND user_id = ? AND deleted_at IS NOT NULL", id, userID)
+		switch kind {
+		case TrashComplex:
+			var complex models.Complex
+			if err := first(inTrash, &complex); err != nil {
+				return err
+			}
+			goals := []models.Goal{}
+			if err := tx.Unscoped().Where("complex_id = ? AND deleted_at IS NOT NULL", id).Find(&goals).Error; err != nil {
+				return err
+			}
+			goalIDs := []uint{}
+			for _, g := range goals {
+				if deletedTogether(complex.DeletedAt, g.DeletedAt) {
+					goalIDs = append(goalIDs, g.ID)
+				}
+			}
+			if err := restoreActions(tx, goalIDs, complex.DeletedAt); err != nil {
+				return err
+			}
+			if err := restore(tx.Where("id IN ?", goalIDs), &models.Goal{}); err != nil {
+				return err
+			}
+			return restore(tx.Where("id = ?", id), &models.Complex{})
+		case TrashGoal:
+			var goal models.Goal
+			if err := first(inTrash, &goal); err != nil {
+				return err
+			}
+			var complex models.Complex
+			if err := first(tx.Unscoped().Where("id = ?", goal.ComplexID), &complex); err != nil {
+				return err
+			}
+			if complex.DeletedAt.Valid {
+				return ErrParentDeleted
+			}
+			if err := restoreActions(tx, []uint{id}, goal.DeletedAt); err != nil {
+				return err
+			}
+			return restore(tx.Where("id = ?", id), &models.Goal{})
+		case TrashAction:
+			var action models.Action
+			if err := first(inTrash, &action); err != nil {
+				return err
+			}
+			var goal models.Goal
+			if err := first(tx.Unscoped().Where("id = ?", action.GoalID), &goal); err != nil {
+				return err
+			}
+			if goal.DeletedAt.Valid {
+				return ErrParentDeleted
+			}
+			return restore(tx.Where("id = ?", id), &models.Action{})
+		}
+		return ErrNotFound
+	})
+}
+
+// restoreActions takes the actions of the goals deleted at the given time
+// out of the trash. The times are compared in Go rather than in SQL, where
+// how a timestamp compares with a bound parameter depends on the driver.
+func restoreActions(tx *gorm.DB, goalIDs []uint, at gorm.DeletedAt) error {
+	if len(goalIDs) == 0 {
+		return nil
+	}
+	actions := []models.Action{}
+	if err := tx.Unscoped().Where("goal_id IN ? AND deleted_at IS NOT NULL", goalIDs).Find(&actions).Error; err != nil {
+		return err
+	}
+	actionIDs := []uint{}
+	for _, a := range actions {
+		if deletedTogether(at, a.DeletedAt) {
+			actionIDs = append(actionIDs, a.ID)
+		}
+	}
+	if len(actionIDs) == 0 {
+		return nil
+	}
+	return restore(tx.Where("id IN ?", actionIDs), &models.Action{})
+}
+
+// restore takes the rows tx selects out of the trash.
+func restore(tx *gorm.DB, model interface{}) error {
+	return tx.Unscoped().Model(model).UpdateColumn("deleted_at", nil).Error
+}
+
+func (r gormTrash) Purge(ctx context.Context, before time.Time) (int64, error) {
+	// Complexes first: the foreign keys take their goals and actions along.
+	var purged int64
+	for _, model := range []interface{}{&models.Complex{}, &models.Goal{}, &models.Action{}} {
+		result := r.unscoped(ctx).Where("deleted_at < ?", before).Delete(model)
+		if result.Error != nil {
+			return purged, result.Error
+		}
+		purged += result.RowsAffected
+	}
+	return purged, nil
+}
+
+type gormBadges struct{ db *gorm.DB }
+
+func (r gormBadges) List(ctx context.Context) ([]models.Badge, error) {
+	badges := []models.Badge{}
+	err := r.db.WithContext(ctx).Order("id ASC").Find(&badges).Error
+	return badges, err
+}
+
+func (r gormBadges) Create(ctx context.Context, badge *models.Badge) error {
+	// The unique index on code decides between concurrent creates.
+	return createUnique(r.db.WithContext(ctx), badge)
+}
+
+func (r gormBadges) Upsert(ctx context.Context, badge *models.Badge) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "code"}},
+		DoUpdates: clause.AssignmentColumns([]string{"name", "description", "icon_url", "rule", "updated_at"}),
+	}).Create(badge).Error
+}
+
+func (r gormBadges) ListUserBadges(ctx context.Context, userID string) ([]models.UserBadge, error) {
+	userBadges := []models.UserBadge{}
+	err := r.db.WithContext(ctx).Preload("Badge").Preload("Milestone").Where("user_id = ?", userID).Order("achieved_at DESC").Find(&userBadges).Error
+	return userBadges, err
+}
+
+func (r gormBadges) Award(ctx context.Context, userBadge *models.UserBadge) (bool, error) {
+	// The unique (user_id, badge_id, milestone_id) index turns a concurrent award into a no-op.
+	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(userBadge)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+type gormFeedback struct{ db *gorm.DB }
+
+func (r gormFeedback) Record(ctx context.Context, message *models.FeedbackMessage) error {
+	return r.db.WithContext(ctx).Create(message).Error
+}
+
+func (r gormFeedback) ListSince(ctx context.Context, userID string, since time.Time) ([]models.FeedbackMessage, error) {
+	messages := []models.FeedbackMessage{}
+	err := r.db.WithContext(ctx).Where("user_id = ? AND created_at >= ?", userID, since).Order("created_at DESC, id DESC").Find(&messages).Error
+	return messages, err
+}
+
+type gormRevisions struct{ db *gorm.DB }
+
+func (r gormRevisions) Create(ctx context.Context, revision *models.Revision) error {
+	return r.db.WithContext(ctx).Create(revision).Error
+}
+
+func (r gormRevisions) List(ctx context.Context, userID, entityType string, entityID uint) ([]models.Revision, error) {
+	revisions := []models.Revision{}
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND entity_type = ? AND entity_id = ?", userID, entityType, entityID).
+		Order("id").
+		Find(&revisions).Error
+	return revisions, err
+}
+
+type gormExports struct{ db *gorm.DB }
+
+func (r gormExports) CreateJob(ctx context.Context, job *models.ExportJob) error {
+	return r.db.WithContext(ctx).Create(job).Error
+}
+
+func (r gormExports) GetJob(ctx context.Context, userID string, id uint) (*models.ExportJob, error) {
+	var job models.ExportJob
+	if err := first(r.db.WithContext(ctx).Omit("archive").Where("id = ? AND user_id = ?", id, userID), &job); err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+func (r gormExports) ListJobs(ctx context.Context, userID string) ([]models.ExportJob, error) {
+	jobs := []models.ExportJob{}
+	err := r.db.WithContext(ctx).Omit("archive").Where("user_id = ?", userID).Order("id DESC").Find(&jobs).Error
+	return jobs, err
+}
+
+func (r gormExports) GetArchive(ctx context.Context, userID string, id uint) (*models.ExportJob, error) {
+	var job models.ExportJob
+	if err := first(r.db.WithContext(ctx).Where("id = ? AND user_id = ?", id, userID), &job); err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+func (r gormExports) ClaimJob(ctx context.Context, staleBefore, now time.Time) (*models.ExportJob, error) {
+	const claimable = "status = ? OR (status = ? AND started_at < ?)"
+	for {
+		var job models.ExportJob
+		err := first(r.db.WithContext(ctx).Omit("archive").Where(claimable, models.ExportPending, models.ExportRunning, staleBefore).Order("id"), &job)
+		if err != nil {
+			return nil, err
+		}
+		// Another worker may claim the job in between; the update then
+		// matches no row and the next job is tried.
+		result := r.db.WithContext(ctx).Model(&models.ExportJob{}).
+			Where("id = ?", job.ID).
+			Where(claimable, models.ExportPending, models.ExportRunning, staleBefore).
+			Updates(map[string]interface{}{"status": models.ExportRunning, "started_at": now})
+		if result.Error != nil {
+			return nil, result.Error
+		}
+		if result.RowsAffected > 0 {
+			job.Status, job.StartedAt = models.ExportRunning, &now
+			return &job, nil
+		}
+	}
+}
+
+func (r gormExports) FinishJob(ctx context.Context, job *models.ExportJob) error {
+	return r.db.WithContext(ctx).Model(job).Select("status", "error", "archive", "completed_at", "expires_at").Updates(job).Error
+}
+
+func (r gormExports) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Where("expires_at < ?", now).Delete(&models.ExportJob{})
+	return result.RowsAffected, result.Error
+}
+
+func (r gormExports) UserData(ctx context.Context, userID string) (*UserData, error) {
+	data := &UserData{}
+	owned := func(dest interface{}) error {
+		return r.db.WithContext(ctx).Unscoped().Where("user_id = ?", userID).Order("id").Find(dest).Error
+	}
+	for _, dest := range []interface{}{&data.Complexes, &data.Goals, &data.Measurements, &data.Milestones, &data.Actions, &data.Completions, &data.Revisions} {
+		if err := owned(dest); err != nil {
+			return nil, err
+		}
+	}
+	if err := r.db.WithContext(ctx).Preload("Badge").Preload("Milestone").Where("user_id = ?", userID).Order("id").Find(&data.Badges).Error; err != nil {
+		return nil, err
+	}
+	actionIDs := make([]uint, len(data.Actions))
+	for i, a := range data.Actions {
+		actionIDs[i] = a.ID
+	}
+	if len(actionIDs) > 0 {
+		ofActions := func(dest interface{}) error {
+			return r.db.WithContext(ctx).Where("action_id IN ?", actionIDs).Order("id").Find(dest).Error
+		}
+		if err := ofActions(&data.Gains); err != nil {
+			return nil, err
+		}
+		if err := ofActions(&data.Losses); err != nil {
+			return nil, err
+		}
+	}
+	return data, nil
+}
+
+// importBatchSize is how many rows of a kind Import inserts per statement.
+const importBatchSize = 500
+
+type gormImports struct{ db *gorm.DB }
+
+func (r gormImports) Import(ctx context.Context, batch *ImportBatch) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		create := func(value interface{}) error {
+			return tx.Omit(clause.Associations).Create(value).Error
+		}
+		createAll := func(n int, values interface{}) error {
+			if n == 0 {
+				return nil
+			}
+			return tx.Omit(clause.Associations).CreateInBatches(values, importBatchSize).Error
+		}
+		for _, c := range batch.Complexes {
+			if c.Complex.ID == 0 {
+				if err := create(&c.Complex); err != nil {
+					return err
+				}
+			}
+			for _, g := range c.Goals {
+				if g.Goal.ID == 0 {
+					g.Goal.ComplexID = c.Complex.ID
+					if err := create(&g.Goal); err != nil {
+						return err
+					}
+				}
+				for i := range g.Measurements {
+					g.Measurements[i].GoalID = g.Goal.ID
+				}
+				if err := createAll(len(g.Measurements), &g.Measurements); err != nil {
+					return err
+				}
+				for _, a := range g.Actions {
+					if a.Action.ID == 0 {
+						a.Action.GoalID = g.Goal.ID
+						if err := create(&a.Action); err != nil {
+							return err
+						}
+					}
+					for i := range a.Gains {
+						a.Gains[i].ActionID = a.Action.ID
+					}
+					for i := range a.Losses {
+						a.Losses[i].ActionID = a.Action.ID
+					}
+					for i := range a.Completions {
+						a.Completions[i].ActionID = a.Action.ID
+					}
+					if err := createAll(len(a.Gains), &a.Gains); err != nil {
+						return err
+					}
+					if err := createAll(len(a.Losses), &a.Losses); err != nil {
+						return err
+					}
+					if err := createAll(len(a.Completions), &a.Completions); err != nil {
+						return err
+					}
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// NewGormDataKeys returns the data key repository of db alone. Given a
+// transaction, it reads and writes in that transaction.
+func NewGormDataKeys(db *gorm.DB) DataKeyRepository {
+	return gormDataKeys{db}
+}
+
+type gormDataKeys struct{ db *gorm.DB }
+
+func (r gormDataKeys) Get(ctx context.Context, userID string) (*models.UserDataKey, error) {
+	var key models.UserDataKey
+	if err := first(r.db.WithContext(ctx).Where("user_id = ?", userID), &key); err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+func (r gormDataKeys) Create(ctx context.Context, key *models.UserDataKey) error {
+	return createUnique(r.db.WithContext(ctx), key)
+}
+
+func (r gormDataKeys) ListNotWrappedBy(ctx context.Context, masterKeyID string) ([]models.UserDataKey, error) {
+	keys := []models.UserDataKey{}
+	err := r.db.WithContext(ctx).Where("master_key_id <> ?", masterKeyID).Order("user_id").Find(&keys).Error
+	return keys, err
+}
+
+func (r gormDataKeys) Rewrap(ctx context.Context, key *models.UserDataKey, previousMasterKeyID string) error {
+	tx := r.db.WithContext(ctx)
+	return deleted(tx.Model(&models.UserDataKey{}).
+		Where("user_id = ? AND master_key_id = ?", key.UserID, previousMasterKeyID).
+		Updates(map[string]interface{}{"master_key_id": key.MasterKeyID, "wrapped_key": key.WrappedKey, "updated_at": tx.NowFunc()}))
+}
+
+// createUnique inserts value, returning ErrConflict if it would violate a
+// unique index. Doing nothing on conflict keeps a transaction usable on
+// PostgreSQL, which aborts it on a failed insert.
+func createUnique(tx *gorm.DB, value interface{}) error {
+	result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(value)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrConflict
+	}
+	return nil
+}
+
+type gormUsers struct{ db *gorm.DB }
+
+func (r gormUsers) Get(ctx context.Context, id string) (*models.User, error) {
+	var user models.User
+	if err := first(r.db.WithContext(ctx).Where("id = ?", id), &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (r gormUsers) GetByEmail(ctx context.Context, email string) (*models.User, error) {
+	var user models.User
+	if err := first(r.db.WithContext(ctx).Where("email = ?", email), &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (r gormUsers) Create(ctx context.Context, user *models.User) error {
+	// The unique index on email decides between concurrent registrations.
+	return createUnique(r.db.WithContext(ctx), user)
+}
+
+func (r gormUsers) SetLanguage(ctx context.Context, id, language string) error {
+	result := r.db.WithContext(ctx).Model(&models.User{}).Where("id = ?", id).Update("language", language)
+	if result.Error != nil || result.RowsAffected > 0 {
+		return result.Error
+	}
+	// MySQL reports rows whose values did not change as unaffected.
+	var existing int64
+	if err := r.db.WithContext(ctx).Model(&models.User{}).Where("id = ?", id).Count(&existing).Error; err != nil {
+		return err
+	}
+	if existing == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r gormUsers) CreateRefreshToken(ctx context.Context, token *models.RefreshToken) error {
+	return r.db.WithContext(ctx).Create(token).Error
+}
+
+func (r gormUsers) GetRefreshToken(ctx context.Context, tokenHash string) (*models.RefreshToken, error) {
+	var token models.RefreshToken
+	if err := first(r.db.WithContext(ctx).Where("token_hash = ?", tokenHash), &token); err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+func (r gormUsers) RevokeRefreshToken(ctx context.Context, id uint, at time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&models.RefreshToken{}).Where("id = ? AND revoked_at IS NULL", id).Update("revoked_at", at)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r gormUsers) RevokeRefreshTokenByHash(ctx context.Context, tokenHash string, at time.Time) error {
+	return r.db.WithContext(ctx).Model(&models.RefreshToken{}).
+		Where("token_hash = ? AND revoked_at IS NULL", tokenHash).
+		Update("revoked_at", at).Error
+}
+
+func (r gormUsers) RevokeUserRefreshTokens(ctx context.Context, userID string, at time.Time) error {
+	return r.db.WithContext(ctx).Model(&models.RefreshToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", at).Error
+}
+
+func (r gormUsers) GetIdentity(ctx context.Context, issuer, subject string) (*models.UserIdentity, error) {
+	var identity models.UserIdentity
+	if err := first(r.db.WithContext(ctx).Where("issuer = ? AND subject = ?", issuer, subject), &identity); err != nil {
+		return nil, err
+	}
+	return &identity, nil
+}
+
+func (r gormUsers) LinkIdentity(ctx context.Context, identity *models.UserIdentity, newUser models.User, linkExisting bool) (*models.User, error) {
+	var user models.User
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := first(tx.Where("email = ?", identity.Email), &user)
+		switch {
+		case err == ErrNotFound:
+			user = newUser
+			if err := createUnique(tx, &user); err != nil {
+				return err
+			}
+		case err != nil:
+			return err
+		case !linkExisting:
+			return ErrConflict
+		}
+		identity.UserID = user.ID
+		return createUnique(tx, identity)
+	})
+	if err == ErrConflict {
+		// A concurrent login may have linked the same subject, creating the
+		// user or the identity first.
+		return r.linkedUser(ctx, identity.Issuer, identity.Subject)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// linkedUser returns the user the identity is linked to, or ErrConflict
+// when it is not linked.
+func (r gormUsers) linkedUser(ctx context.Context, issuer, subject string) (*models.User, error) {
+	existing, err := r.GetIdentity(ctx, issuer, subject)
+	if err == ErrNotFound {
+		return nil, ErrConflict
+	}
+	if err != nil {
+		return nil, err
+	}
+	return r.Get(ctx, existing.UserID)
+}
+
+func (r gormUsers) CreateLoginState(ctx context.Context, state *models.OIDCLoginState) error {
+	return r.db.WithContext(ctx).Create(state).Error
+}
+
+func (r gormUsers) ConsumeLoginState(ctx context.Context, state string, now time.Time) (*models.OIDCLoginState, error) {
+	var loginState models.OIDCLoginState
+	if err := first(r.db.WithContext(ctx).Where("state = ? AND expires_at > ?", state, now), &loginState); err != nil {
+		return nil, err
+	}
+	// Whoever deletes the row owns the login.
+	if err := deleted(r.db.WithContext(ctx).Where("state = ?", state).Delete(&models.OIDCLoginState{})); err != nil {
+		return nil, err
+	}
+	return &loginState, nil
+}
+
+func (r gormUsers) DeleteExpiredLoginStates(ctx context.Context, now time.Time) error {
+	return r.db.WithContext(ctx).Where("expires_at < ?", now).Delete(&models.OIDCLoginState{}).Error
+}
@@ -0,0 +1,93 @@
+package repository_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"refuel/backend/database"
+	"refuel/backend/models"
+	"refuel/backend/repository"
+)
+
+// newSQLite returns repositories backed by a migrated SQLite database in a
+// temporary directory.
+func newSQLite(t *testing.T) *repository.Repositories {
+	t.Helper()
+	cfg := database.Config{Driver: database.SQLite, Path: filepath.Join(t.TempDir(), "refuel.db")}
+	if err := database.Migrate(cfg, "file://../db/migrations"); err != nil {
+		t.Fatalf("migrating: %v", err)
+	}
+	db, err := database.Open(cfg, &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("opening database: %v", err)
+	}
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	return repository.NewGorm(db)
+}
+
+func TestLinkIdentity(t *testing.T) {
+	ctx := context.Background()
+	users := newSQLite(t).Users
+	linked, err := users.LinkIdentity(ctx,
+		&models.UserIdentity{Issuer: "https://idp.example.com", Subject: "s1", Email: "first@example.com"},
+		models.User{ID: "u1", Email: "first@example.com"}, true)
+	if err != nil {
+		t.Fatalf("linking: %v", err)
+	}
+	if linked.ID != "u1" {
+		t.Fatalf("got user %s, want the new user u1", linked.ID)
+	}
+	if err := users.Create(ctx, &models.User{ID: "u2", Email: "other@example.com"}); err != nil {
+		t.Fatalf("creating user: %v", err)
+	}
+
+	// The subject linked by a concurrent login resolves to its user, whether
+	// the email is new, belongs to that user or to someone else.
+	tests := []struct {
+		name         string
+		subject      string
+		email        string
+		linkExisting bool
+		want         string
+		wantErr      error
+	}{
+		{"linked, new email", "s1", "changed@example.com", true, "u1", nil},
+		{"linked, same email", "s1", "first@example.com", true, "u1", nil},
+		{"linked, unverified email", "s1", "first@example.com", false, "u1", nil},
+		{"unlinked, verified email of another user", "s2", "other@example.com", true, "u2", nil},
+		{"unlinked, unverified email of another user", "s3", "other@example.com", false, "", repository.ErrConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user, err := users.LinkIdentity(ctx,
+				&models.UserIdentity{Issuer: "https://idp.example.com", Subject: tt.subject, Email: tt.email},
+				models.User{ID: "new-" + tt.subject, Email: tt.email}, tt.linkExisting)
+			if tt.wantErr != nil {
+				if err != tt.wantErr {
+					t.Fatalf("got %+v, %v, want %v", user, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("got error %v", err)
+			}
+			if user.ID != tt.want {
+				t.Errorf("got user %s, want %s", user.ID, tt.want)
+			}
+		})
+	}
+
+	// The user created for a subject that turned out to be linked is rolled
+	// back with the failed link.
+	if _, err := users.GetByEmail(ctx, "changed@example.com"); err != repository.ErrNotFound {
+		t.Errorf("getting the user of the lost link: got error %v, want %v", err, repository.ErrNotFound)
+	}
+}
@@ -0,0 +1,1315 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+
+	"refuel/backend/checkin"
+	"refuel/backend/models"
+)
+
+// NewMemory returns repositories that keep everything in process memory.
+// They mirror the GORM implementation, including ID assignment, timestamps,
+// unique constraints, the trash and cascading deletes, and are safe for
+// concurrent use.
+func NewMemory() *Repositories {
+	s := &memoryStore{
+		complexes:    map[uint]models.Complex{},
+		goals:        map[uint]models.Goal{},
+		measurements: map[uint]models.GoalMeasurement{},
+		milestones:   map[uint]models.Milestone{},
+		actions:      map[uint]models.Action{},
+		gains:        map[uint]models.Gain{},
+		losses:       map[uint]models.Loss{},
+		completions:  map[uint]models.ActionCompletion{},
+		badges:       map[uint]models.Badge{},
+		userBadges:   map[uint]models.UserBadge{},
+		feedback:     map[uint]models.FeedbackMessage{},
+		revisions:    map[uint]models.Revision{},
+		exportJobs:   map[uint]models.ExportJob{},
+		dataKeys:     map[string]models.UserDataKey{},
+		users:        map[string]models.User{},
+		tokens:       map[uint]models.RefreshToken{},
+		identities:   map[uint]models.UserIdentity{},
+		loginStates:  map[string]models.OIDCLoginState{},
+	}
+	return &Repositories{
+		Complexes: memoryComplexes{s},
+		Goals:     memoryGoals{s},
+		Actions:   memoryActions{s},
+		Trash:     memoryTrash{s},
+		Badges:    memoryBadges{s},
+		Feedback:  memoryFeedback{s},
+		Revisions: memoryRevisions{s},
+		Exports:   memoryExports{s},
+		Imports:   memoryImports{s},
+		DataKeys:  memoryDataKeys{s},
+		Users:     memoryUsers{s},
+	}
+}
+
+// memoryStore holds every table so deletes can cascade across repositories.
+type memoryStore struct {
+	mu     sync.Mutex
+	lastID uint
+
+	complexes    map[uint]models.Complex
+	goals        map[uint]models.Goal
+	measurements map[uint]models.GoalMeasurement
+	milestones   map[uint]models.Milestone
+	actions      map[uint]models.Action
+	gains        map[uint]models.Gain
+	losses       map[uint]models.Loss
+	completions  map[uint]models.ActionCompletion
+	badges       map[uint]models.Badge
+	userBadges   map[uint]models.UserBadge
+	feedback     map[uint]models.FeedbackMessage
+	revisions    map[uint]models.Revision
+	exportJobs   map[uint]models.ExportJob
+	dataKeys     map[string]models.UserDataKey
+	users        map[string]models.User
+	tokens       map[uint]models.RefreshToken
+	identities   map[uint]models.UserIdentity
+	loginStates  map[string]models.OIDCLoginState
+}
+
+// nextID returns a new primary key. IDs are unique across tables, which
+// keeps them increasing within each one.
+func (s *memoryStore) nextID() uint {
+	s.lastID++
+	return s.lastID
+}
+
+// sortedByID returns the values of m ordered by key.
+func sortedByID[T any](m map[uint]T, keep func(T) bool) []T {
+	ids := make([]uint, 0, len(m))
+	for id, v := range m {
+		if keep(v) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	out := make([]T, len(ids))
+	for i, id := range ids {
+		out[i] = m[id]
+	}
+	return out
+}
+
+// deleteWhere removes the values of m that match.
+func deleteWhere[T any](m map[uint]T, match func(T) bool) {
+	for id, v := range m {
+		if match(v) {
+			delete(m, id)
+		}
+	}
+}
+
+func (s *memoryStore) deleteComplex(id uint) {
+	delete(s.complexes, id)
+	for goalID, g := range s.goals {
+		if g.ComplexID == id {
+			s.deleteGoal(goalID)
+		}
+	}
+}
+
+func (s *memoryStore) deleteGoal(id uint) {
+	delete(s.goals, id)
+	deleteWhere(s.measurements, func(m models.GoalMeasurement) bool { return m.GoalID == id })
+	deleteWhere(s.milestones, func(m models.Milestone) bool { return m.GoalID == id })
+	for actionID, a := range s.actions {
+		if a.GoalID == id {
+			s.deleteAction(actionID)
+		}
+	}
+}
+
+func (s *memoryStore) deleteAction(id uint) {
+	delete(s.actions, id)
+	deleteWhere(s.gains, func(g models.Gain) bool { return g.ActionID == id })
+	deleteWhere(s.losses, func(l models.Loss) bool { return l.ActionID == id })
+	deleteWhere(s.feedback, func(f models.FeedbackMessage) bool { return f.ActionID == id })
+	for completionID, c := range s.completions {
+		if c.ActionID == id {
+			delete(s.completions, completionID)
+		}
+	}
+}
+
+// trashGoal moves the goal and its actions not in the trash yet to the trash at the given time.
+func (s *memoryStore) trashGoal(id uint, at time.Time) {
+	g := s.goals[id]
+	g.DeletedAt = gorm.DeletedAt{Time: at, Valid: true}
+	s.goals[id] = g
+	for actionID, a := range s.actions {
+		if a.GoalID == id && !a.DeletedAt.Valid {
+			a.DeletedAt = g.DeletedAt
+			s.actions[actionID] = a
+		}
+	}
+}
+
+type memoryComplexes struct{ s *memoryStore }
+
+func (r memoryComplexes) List(ctx context.Context, userID string) ([]models.Complex, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return sortedByID(r.s.complexes, func(c models.Complex) bool { return c.UserID == userID && !c.DeletedAt.Valid }), nil
+}
+
+func (r memoryComplexes) ListPage(ctx context.Context, userID string, filter ComplexFilter, page Page) ([]models.Complex, *Cursor, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	complexes, next := pageOf(sortedByID(r.s.complexes, func(c models.Complex) bool { return c.UserID == userID && !c.DeletedAt.Valid && filter.match(c) }), page, complexKey)
+	return complexes, next, nil
+}
+
+func (r memoryComplexes) Get(ctx context.Context, userID string, id uint) (*models.Complex, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	c, ok := r.s.complexes[id]
+	if !ok || c.UserID != userID || c.DeletedAt.Valid {
+		return nil, ErrNotFound
+	}
+	return &c, nil
+}
+
+func (r memoryComplexes) Create(ctx context.Context, complex *models.Complex) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	complex.ID = r.s.nextID()
+	complex.CreatedAt = time.Now()
+	complex.UpdatedAt = complex.CreatedAt
+	r.s.complexes[complex.ID] = *complex
+	return nil
+}
+
+func (r memoryComplexes) Update(ctx context.Context, complex *models.Complex) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if c, ok := r.s.complexes[complex.ID]; !ok || c.DeletedAt.Valid {
+		return ErrNotFound
+	}
+	complex.UpdatedAt = time.Now()
+	r.s.complexes[complex.ID] = *complex
+	return nil
+}
+
+func (r memoryComplexes) Delete(ctx context.Context, userID string, id uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	c, ok := r.s.complexes[id]
+	if !ok || c.UserID != userID || c.DeletedAt.Valid {
+		return ErrNotFound
+	}
+	now := time.Now()
+	c.DeletedAt = gorm.DeletedAt{Time: now, Valid: true}
+	r.s.complexes[id] = c
+	for goalID, g := range r.s.goals {
+		if g.ComplexID == id && !g.DeletedAt.Valid {
+			r.s.trashGoal(goalID, now)
+		}
+	}
+	return nil
+}
+
+type memoryGoals struct{ s *memoryStore }
+
+func (r memoryGoals) List(ctx context.Context, userID string) ([]models.Goal, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return sortedByID(r.s.goals, func(g models.Goal) bool { return g.UserID == userID && !g.DeletedAt.Valid }), nil
+}
+
+func (r memoryGoals) ListPage(ctx context.Context, userID string, filter GoalFilter, page Page) ([]models.Goal, *Cursor, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	goals, next := pageOf(sortedByID(r.s.goals, func(g models.Goal) bool { return g.UserID == userID && !g.DeletedAt.Valid && filter.match(g) }), page, goalKey)
+	return goals, next, nil
+}
+
+func (r memoryGoals) Get(ctx context.Context, userID string, id uint) (*models.Goal, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	g, ok := r.s.goals[id]
+	if !ok || g.UserID != userID || g.DeletedAt.Valid {
+		return nil, ErrNotFound
+	}
+	return &g, nil
+}
+
+func (r memoryGoals) Create(ctx context.Context, goal *models.Goal) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	goal.ID = r.s.nextID()
+	goal.CreatedAt = time.Now()
+	goal.UpdatedAt = goal.CreatedAt
+	r.s.goals[goal.ID] = *goal
+	return nil
+}
+
+func (r memoryGoals) Update(ctx context.Context, goal *models.Goal) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if g, ok := r.s.goals[goal.ID]; !ok || g.DeletedAt.Valid {
+		return ErrNotFound
+	}
+	goal.UpdatedAt = time.Now()
+	r.s.goals[goal.ID] = *goal
+	return nil
+}
+
+func (r memoryGoals) Delete(ctx context.Context, userID string, id uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if g, ok := r.s.goals[id]; !ok || g.UserID != userID || g.DeletedAt.Valid {
+		return ErrNotFound
+	}
+	r.s.trashGoal(id, time.Now())
+	return nil
+}
+
+func (r memoryGoals) ListMeasurements(ctx context.Context, userID string, goalID uint) ([]models.GoalMeasurement, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	measurements := sortedByID(r.s.measurements, func(m models.GoalMeasurement) bool {
+		return m.GoalID == goalID && m.UserID == userID
+	})
+	sort.SliceStable(measurements, func(i, j int) bool {
+		return measurements[i].MeasuredAt.Before(measurements[j].MeasuredAt)
+	})
+	return measurements, nil
+}
+
+func (r memoryGoals) GetMeasurement(ctx context.Context, userID string, goalID, id uint) (*models.GoalMeasurement, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	m, ok := r.s.measurements[id]
+	if !ok || m.GoalID != goalID || m.UserID != userID {
+		return nil, ErrNotFound
+	}
+	return &m, nil
+}
+
+func (r memoryGoals) CreateMeasurement(ctx context.Context, measurement *models.GoalMeasurement) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if _, ok := r.s.goals[measurement.GoalID]; !ok {
+		return ErrNotFound
+	}
+	measurement.ID = r.s.nextID()
+	measurement.CreatedAt = time.Now()
+	r.s.measurements[measurement.ID] = *measurement
+	return nil
+}
+
+func (r memoryGoals) DeleteMeasurement(ctx context.Context, userID string, goalID, id uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if m, ok := r.s.measurements[id]; !ok || m.GoalID != goalID || m.UserID != userID {
+		return ErrNotFound
+	}
+	delete(r.s.measurements, id)
+	return nil
+}
+
+func (r memoryGoals) ListMilestones(ctx context.Context, userID string, goalID uint) ([]models.Milestone, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	milestones := sortedByID(r.s.milestones, func(m models.Milestone) bool {
+		return m.GoalID == goalID && m.UserID == userID
+	})
+	sort.SliceStable(milestones, func(i, j int) bool { return milestones[i].Position < milestones[j].Position })
+	return milestones, nil
+}
+
+func (r memoryGoals) ReplaceMilestones(ctx context.Context, userID string, goalID uint, milestones []models.Milestone) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if _, ok := r.s.goals[goalID]; !ok {
+		return ErrNotFound
+	}
+	deleteWhere(r.s.milestones, func(m models.Milestone) bool {
+		return m.GoalID == goalID && m.UserID == userID && m.AchievedAt == nil
+	})
+	now := time.Now()
+	for i := range milestones {
+		milestones[i].ID = r.s.nextID()
+		milestones[i].CreatedAt = now
+		milestones[i].UpdatedAt = now
+		r.s.milestones[milestones[i].ID] = milestones[i]
+	}
+	return nil
+}
+
+func (r memoryGoals) AchieveMilestone(ctx context.Context, id uint, at time.Time) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if m, ok := r.s.milestones[id]; ok && m.AchievedAt == nil {
+		m.AchievedAt = &at
+		m.UpdatedAt = time.Now()
+		r.s.milestones[id] = m
+	}
+	return nil
+}
+
+type memoryActions struct{ s *memoryStore }
+
+func (r memoryActions) List(ctx context.Context, userID string) ([]models.Action, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return r.withOutcomes(sortedByID(r.s.actions, func(a models.Action) bool { return a.UserID == userID && !a.DeletedAt.Valid })), nil
+}
+
+func (r memoryActions) ListByGoal(ctx context.Context, userID string, goalID uint) ([]models.Action, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	actions := sortedByID(r.s.actions, func(a models.Action) bool { return a.UserID == userID && a.GoalID == goalID && !a.DeletedAt.Valid })
+	sort.SliceStable(actions, func(i, j int) bool { return actions[i].CreatedAt.After(actions[j].CreatedAt) })
+	return r.withOutcomes(actions), nil
+}
+
+func (r memoryActions) ListPage(ctx context.Context, userID string, filter ActionFilter, page Page) ([]models.Action, *Cursor, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	actions, next := pageOf(sortedByID(r.s.actions, func(a models.Action) bool { return a.UserID == userID && !a.DeletedAt.Valid && filter.match(a) }), page, actionKey)
+	return r.withOutcomes(actions), next, nil
+}
+
+func (r memoryActions) Get(ctx context.Context, userID string, id uint) (*models.Action, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	a, ok := r.s.actions[id]
+	if !ok || a.UserID != userID || a.DeletedAt.Valid {
+		return nil, ErrNotFound
+	}
+	return &r.withOutcomes([]models.Action{a})[0], nil
+}
+
+// withOutcomes attaches the stored gains and losses to the actions, which
+// are kept without them.
+func (r memoryActions) withOutcomes(actions []models.Action) []models.Action {
+	for i := range actions {
+		id := actions[i].ID
+		actions[i].Gains = sortedByID(r.s.gains, func(g models.Gain) bool { return g.ActionID == id })
+		actions[i].Losses = sortedByID(r.s.losses, func(l models.Loss) bool { return l.ActionID == id })
+	}
+	return actions
+}
+
+func (r memoryActions) Create(ctx context.Context, action *models.Action) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	action.ID = r.s.nextID()
+	action.CreatedAt = time.Now()
+	action.UpdatedAt = action.CreatedAt
+	for i := range action.Gains {
+		action.Gains[i].ID = r.s.nextID()
+		action.Gains[i].ActionID = action.ID
+		r.s.gains[action.Gains[i].ID] = action.Gains[i]
+	}
+	for i := range action.Losses {
+		action.Losses[i].ID = r.s.nextID()
+		action.Losses[i].ActionID = action.ID
+		r.s.losses[action.Losses[i].ID] = action.Losses[i]
+	}
+	stored := *action
+	stored.Gains, stored.Losses = nil, nil
+	r.s.actions[action.ID] = stored
+	return nil
+}
+
+func (r memoryActions) Update(ctx context.Context, action *models.Action) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if a, ok := r.s.actions[action.ID]; !ok || a.DeletedAt.Valid {
+		return ErrNotFound
+	}
+	action.UpdatedAt = time.Now()
+	stored := *action
+	stored.Gains, stored.Losses = nil, nil
+	r.s.actions[action.ID] = stored
+	return nil
+}
+
+func (r memoryActions) Delete(ctx context.Context, userID string, id uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	a, ok := r.s.actions[id]
+	if !ok || a.UserID != userID || a.DeletedAt.Valid {
+		return ErrNotFound
+	}
+	a.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+	r.s.actions[id] = a
+	return nil
+}
+
+func (r memoryActions) ListGains(ctx context.Context, actionID uint) ([]models.Gain, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return sortedByID(r.s.gains, func(g models.Gain) bool { return g.ActionID == actionID }), nil
+}
+
+func (r memoryActions) GetGain(ctx context.Context, actionID, id uint) (*models.Gain, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return getOutcome(r.s.gains, gainKeys, actionID, id)
+}
+
+func (r memoryActions) SaveGain(ctx context.Context, gain *models.Gain) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return saveOutcome(r.s, r.s.gains, gainKeys, gain)
+}
+
+func (r memoryActions) DeleteGain(ctx context.Context, actionID, id uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return deleteOutcome(r.s.gains, gainKeys, actionID, id)
+}
+
+func (r memoryActions) SaveGains(ctx context.Context, actionID uint, gains []models.Gain, replace bool) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return saveOutcomes(r.s, r.s.gains, gainKeys, actionID, gains, replace)
+}
+
+func (r memoryActions) ListLosses(ctx context.Context, actionID uint) ([]models.Loss, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return sortedByID(r.s.losses, func(l models.Loss) bool { return l.ActionID == actionID }), nil
+}
+
+func (r memoryActions) GetLoss(ctx context.Context, actionID, id uint) (*models.Loss, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return getOutcome(r.s.losses, lossKeys, actionID, id)
+}
+
+func (r memoryActions) SaveLoss(ctx context.Context, loss *models.Loss) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return saveOutcome(r.s, r.s.losses, lossKeys, loss)
+}
+
+func (r memoryActions) DeleteLoss(ctx context.Context, actionID, id uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return deleteOutcome(r.s.losses, lossKeys, actionID, id)
+}
+
+func (r memoryActions) SaveLosses(ctx context.Context, actionID uint, losses []models.Loss, replace bool) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return saveOutcomes(r.s, r.s.losses, lossKeys, actionID, losses, replace)
+}
+
+func getOutcome[T any](rows map[uint]T, keys keysFunc[T], actionID, id uint) (*T, error) {
+	row, ok := rows[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	if _, owner := keys(&row); *owner != actionID {
+		return nil, ErrNotFound
+	}
+	return &row, nil
+}
+
+func deleteOutcome[T any](rows map[uint]T, keys keysFunc[T], actionID, id uint) error {
+	if _, err := getOutcome(rows, keys, actionID, id); err != nil {
+		return err
+	}
+	delete(rows, id)
+	return nil
+}
+
+// saveOutcome inserts or updates row, checking the foreign key like the database would.
+func saveOutcome[T any](s *memoryStore, rows map[uint]T, keys keysFunc[T], row *T) error {
+	id, owner := keys(row)
+	if _, ok := s.actions[*owner]; !ok {
+		return ErrNotFound
+	}
+	if *id == 0 {
+		*id = s.nextID()
+	}
+	rows[*id] = *row
+	return nil
+}
+
+func saveOutcomes[T any](s *memoryStore, rows map[uint]T, keys keysFunc[T], actionID uint, batch []T, replace bool) error {
+	if _, ok := s.actions[actionID]; !ok {
+		return ErrNotFound
+	}
+	existing := sortedByID(rows, func(row T) bool {
+		_, owner := keys(&row)
+		return *owner == actionID
+	})
+	listed, err := checkBatch(existing, batch, keys)
+	if err != nil {
+		return err
+	}
+	if replace {
+		keep := make(map[uint]bool, len(listed))
+		for _, id := range listed {
+			keep[id] = true
+		}
+		for i := range existing {
+			if id, _ := keys(&existing[i]); !keep[*id] {
+				delete(rows, *id)
+			}
+		}
+	}
+	for i := range batch {
+		_, owner := keys(&batch[i])
+		*owner = actionID
+		if err := saveOutcome(s, rows, keys, &batch[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r memoryActions) ListCompletions(ctx context.Context, actionIDs []uint, from, to time.Time) ([]models.ActionCompletion, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	wanted := make(map[uint]bool, len(actionIDs))
+	for _, id := range actionIDs {
+		wanted[id] = true
+	}
+	completions := sortedByID(r.s.completions, func(c models.ActionCompletion) bool {
+		key := checkin.Key(c.OccurrenceDate)
+		return wanted[c.ActionID] &&
+			(from.IsZero() || key >= checkin.Key(from)) &&
+			(to.IsZero() || key <= checkin.Key(to))
+	})
+	sortByOccurrence(completions)
+	return completions, nil
+}
+
+func (r memoryActions) ListUserCompletions(ctx context.Context, userID string) ([]models.ActionCompletion, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	completions := sortedByID(r.s.completions, func(c models.ActionCompletion) bool {
+		a, ok := r.s.actions[c.ActionID]
+		return c.UserID == userID && ok && a.UserID == userID && !a.DeletedAt.Valid
+	})
+	sortByOccurrence(completions)
+	return completions, nil
+}
+
+// sortByOccurrence orders check-ins by date, as the GORM queries do.
+func sortByOccurrence(completions []models.ActionCompletion) {
+	sort.SliceStable(completions, func(i, j int) bool {
+		return checkin.Key(completions[i].OccurrenceDate) < checkin.Key(completions[j].OccurrenceDate)
+	})
+}
+
+func (r memoryActions) GetCompletion(ctx context.Context, actionID uint, date time.Time) (*models.ActionCompletion, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	for _, c := range r.s.completions {
+		if c.ActionID == actionID && checkin.Key(c.OccurrenceDate) == checkin.Key(date) {
+			return &c, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (r memoryActions) SaveCompletion(ctx context.Context, completion *models.ActionCompletion) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	for id, c := range r.s.completions {
+		if id != completion.ID && c.ActionID == completion.ActionID && checkin.Key(c.OccurrenceDate) == checkin.Key(completion.OccurrenceDate) {
+			return ErrConflict
+		}
+	}
+	now := time.Now()
+	if completion.ID == 0 {
+		completion.ID = r.s.nextID()
+		completion.CreatedAt = now
+	}
+	completion.UpdatedAt = now
+	r.s.completions[completion.ID] = *completion
+	return nil
+}
+
+func (r memoryActions) DeleteCompletion(ctx context.Context, userID string, actionID uint, date time.Time) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	for id, c := range r.s.completions {
+		if c.ActionID == actionID && c.UserID == userID && checkin.Key(c.OccurrenceDate) == checkin.Key(date) {
+			delete(r.s.completions, id)
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+type memoryTrash struct{ s *memoryStore }
+
+func (r memoryTrash) List(ctx context.Context, userID string) ([]TrashItem, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	complexes := sortedByID(r.s.complexes, func(c models.Complex) bool { return c.UserID == userID && c.DeletedAt.Valid })
+	goals := sortedByID(r.s.goals, func(g models.Goal) bool { return g.UserID == userID && g.DeletedAt.Valid })
+	actions := sortedByID(r.s.actions, func(a models.Action) bool { return a.UserID == userID && a.DeletedAt.Valid })
+	return trashItems(complexes, goals, actions), nil
+}
+
+func (r memoryTrash) Restore(ctx context.Context, userID, kind string, id uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	switch kind {
+	case TrashComplex:
+		c, ok := r.s.complexes[id]
+		if !ok || c.UserID != userID || !c.DeletedAt.Valid {
+			return ErrNotFound
+		}
+		for goalID, g := range r.s.goals {
+			if g.ComplexID == id && deletedTogether(c.DeletedAt, g.DeletedAt) {
+				r.s.restoreGoal(goalID)
+			}
+		}
+		c.DeletedAt = gorm.DeletedAt{}
+		r.s.complexes[id] = c
+		return nil
+	case TrashGoal:
+		g, ok := r.s.goals[id]
+		if !ok || g.UserID != userID || !g.DeletedAt.Valid {
+			return ErrNotFound
+		}
+		if r.s.complexes[g.ComplexID].DeletedAt.Valid {
+			return ErrParentDeleted
+		}
+		r.s.restoreGoal(id)
+		return nil
+	case TrashAction:
+		a, ok := r.s.actions[id]
+		if !ok || a.UserID != userID || !a.DeletedAt.Valid {
+			return ErrNotFound
+		}
+		if r.s.goals[a.GoalID].DeletedAt.Valid {
+			return ErrParentDeleted
+		}
+		a.DeletedAt = gorm.DeletedAt{}
+		r.s.actions[id] = a
+		return nil
+	}
+	return ErrNotFound
+}
+
+// restoreGoal takes the goal out of the trash with the actions deleted along with it.
+func (s *memoryStore) restoreGoal(id uint) {
+	g := s.goals[id]
+	for actionID, a := range s.actions {
+		if a.GoalID == id && deletedTogether(g.DeletedAt, a.DeletedAt) {
+			a.DeletedAt = gorm.DeletedAt{}
+			s.actions[actionID] = a
+		}
+	}
+	g.DeletedAt = gorm.DeletedAt{}
+	s.goals[id] = g
+}
+
+func (r memoryTrash) Purge(ctx context.Context, before time.Time) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	expired := func(at gorm.DeletedAt) bool { return at.Valid && at.Time.Before(before) }
+	var purged int64
+	for id, c := range r.s.complexes {
+		if expired(c.DeletedAt) {
+			r.s.deleteComplex(id)
+			purged++
+		}
+	}
+	for id, g := range r.s.goals {
+		if expired(g.DeletedAt) {
+			r.s.deleteGoal(id)
+			purged++
+		}
+	}
+	for id, a := range r.s.actions {
+		if expired(a.DeletedAt) {
+			r.s.deleteAction(id)
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+type memoryBadges struct{ s *memoryStore }
+
+func (r memoryBadges) List(ctx context.Context) ([]models.Badge, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return sortedByID(r.s.badges, func(models.Badge) bool { return true }), nil
+}
+
+func (r memoryBadges) byCode(code string) (models.Badge, bool) {
+	for _, b := range r.s.badges {
+		if b.Code == code {
+			return b, true
+		}
+	}
+	return models.Badge{}, false
+}
+
+func (r memoryBadges) Create(ctx context.Context, badge *models.Badge) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if _, ok := r.byCode(badge.Code); ok {
+		return ErrConflict
+	}
+	badge.ID = r.s.nextID()
+	badge.CreatedAt = time.Now()
+	badge.UpdatedAt = badge.CreatedAt
+	r.s.badges[badge.ID] = *badge
+	return nil
+}
+
+func (r memoryBadges) Upsert(ctx context.Context, badge *models.Badge) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	now := time.Now()
+	if existing, ok := r.byCode(badge.Code); ok {
+		badge.ID = existing.ID
+		badge.CreatedAt = existing.CreatedAt
+	} else {
+		badge.ID = r.s.nextID()
+		badge.CreatedAt = now
+	}
+	badge.UpdatedAt = now
+	r.s.badges[badge.ID] = *badge
+	return nil
+}
+
+func (r memoryBadges) ListUserBadges(ctx context.Context, userID string) ([]models.UserBadge, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	userBadges := sortedByID(r.s.userBadges, func(ub models.UserBadge) bool { return ub.UserID == userID })
+	for i := range userBadges {
+		userBadges[i].Badge = r.s.badges[userBadges[i].BadgeID]
+		if m, ok := r.s.milestones[userBadges[i].MilestoneID]; ok {
+			userBadges[i].Milestone = &m
+		}
+	}
+	sort.SliceStable(userBadges, func(i, j int) bool { return userBadges[i].AchievedAt.After(userBadges[j].AchievedAt) })
+	return userBadges, nil
+}
+
+func (r memoryBadges) Award(ctx context.Context, userBadge *models.UserBadge) (bool, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	for _, ub := range r.s.userBadges {
+		if ub.UserID == userBadge.UserID && ub.BadgeID == userBadge.BadgeID && ub.MilestoneID == userBadge.MilestoneID {
+			return false, nil
+		}
+	}
+	userBadge.ID = r.s.nextID()
+	stored := *userBadge
+	stored.Badge = models.Badge{}
+	stored.Milestone = nil
+	r.s.userBadges[userBadge.ID] = stored
+	return true, nil
+}
+
+type memoryFeedback struct{ s *memoryStore }
+
+func (r memoryFeedback) Record(ctx context.Context, message *models.FeedbackMessage) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	message.ID = r.s.nextID()
+	message.CreatedAt = time.Now()
+	r.s.feedback[message.ID] = *message
+	return nil
+}
+
+func (r memoryFeedback) ListSince(ctx context.Context, userID string, since time.Time) ([]models.FeedbackMessage, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	messages := sortedByID(r.s.feedback, func(f models.FeedbackMessage) bool {
+		return f.UserID == userID && !f.CreatedAt.Before(since)
+	})
+	sort.SliceStable(messages, func(i, j int) bool { return messages[i].CreatedAt.After(messages[j].CreatedAt) })
+	return messages, nil
+}
+
+type memoryRevisions struct{ s *memoryStore }
+
+func (r memoryRevisions) Create(ctx context.Context, revision *models.Revision) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	revision.ID = r.s.nextID()
+	revision.CreatedAt = time.Now()
+	r.s.revisions[revision.ID] = *revision
+	return nil
+}
+
+func (r memoryRevisions) List(ctx context.Context, userID, entityType string, entityID uint) ([]models.Revision, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return sortedByID(r.s.revisions, func(rev models.Revision) bool {
+		return rev.UserID == userID && rev.EntityType == entityType && rev.EntityID == entityID
+	}), nil
+}
+
+type memoryExports struct{ s *memoryStore }
+
+func (r memoryExports) CreateJob(ctx context.Context, job *models.ExportJob) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	job.ID = r.s.nextID()
+	job.CreatedAt = time.Now()
+	r.s.exportJobs[job.ID] = *job
+	return nil
+}
+
+func (r memoryExports) GetJob(ctx context.Context, userID string, id uint) (*models.ExportJob, error) {
+	job, err := r.GetArchive(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	job.Archive = ""
+	return job, nil
+}
+
+func (r memoryExports) ListJobs(ctx context.Context, userID string) ([]models.ExportJob, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	jobs := sortedByID(r.s.exportJobs, func(j models.ExportJob) bool { return j.UserID == userID })
+	for i, j := 0, len(jobs)-1; i < j; i, j = i+1, j-1 {
+		jobs[i], jobs[j] = jobs[j], jobs[i]
+	}
+	for i := range jobs {
+		jobs[i].Archive = ""
+	}
+	return jobs, nil
+}
+
+func (r memoryExports) GetArchive(ctx context.Context, userID string, id uint) (*models.ExportJob, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	job, ok := r.s.exportJobs[id]
+	if !ok || job.UserID != userID {
+		return nil, ErrNotFound
+	}
+	return &job, nil
+}
+
+func (r memoryExports) ClaimJob(ctx context.Context, staleBefore, now time.Time) (*models.ExportJob, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	claimable := sortedByID(r.s.exportJobs, func(j models.ExportJob) bool {
+		return j.Status == models.ExportPending ||
+			(j.Status == models.ExportRunning && j.StartedAt != nil && j.StartedAt.Before(staleBefore))
+	})
+	if len(claimable) == 0 {
+		return nil, ErrNotFound
+	}
+	job := claimable[0]
+	job.Status, job.StartedAt = models.ExportRunning, &now
+	r.s.exportJobs[job.ID] = job
+	job.Archive = ""
+	return &job, nil
+}
+
+func (r memoryExports) FinishJob(ctx context.Context, job *models.ExportJob) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	stored, ok := r.s.exportJobs[job.ID]
+	if !ok {
+		return nil
+	}
+	stored.Status, stored.Error, stored.Archive = job.Status, job.Error, job.Archive
+	stored.CompletedAt, stored.ExpiresAt = job.CompletedAt, job.ExpiresAt
+	r.s.exportJobs[job.ID] = stored
+	return nil
+}
+
+func (r memoryExports) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	var deleted int64
+	for id, j := range r.s.exportJobs {
+		if j.ExpiresAt != nil && j.ExpiresAt.Before(now) {
+			delete(r.s.exportJobs, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func (r memoryExports) UserData(ctx context.Context, userID string) (*UserData, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	data := &UserData{
+		Complexes:    sortedByID(r.s.complexes, func(c models.Complex) bool { return c.UserID == userID }),
+		Goals:        sortedByID(r.s.goals, func(g models.Goal) bool { return g.UserID == userID }),
+		Measurements: sortedByID(r.s.measurements, func(m models.GoalMeasurement) bool { return m.UserID == userID }),
+		Milestones:   sortedByID(r.s.milestones, func(m models.Milestone) bool { return m.UserID == userID }),
+		Actions:      sortedByID(r.s.actions, func(a models.Action) bool { return a.UserID == userID }),
+		Completions:  sortedByID(r.s.completions, func(c models.ActionCompletion) bool { return c.UserID == userID }),
+		Badges:       sortedByID(r.s.userBadges, func(ub models.UserBadge) bool { return ub.UserID == userID }),
+		Revisions:    sortedByID(r.s.revisions, func(rev models.Revision) bool { return rev.UserID == userID }),
+	}
+	owned := make(map[uint]bool, len(data.Actions))
+	for _, a := range data.Actions {
+		owned[a.ID] = true
+	}
+	data.Gains = sortedByID(r.s.gains, func(g models.Gain) bool { return owned[g.ActionID] })
+	data.Losses = sortedByID(r.s.losses, func(l models.Loss) bool { return owned[l.ActionID] })
+	for i := range data.Badges {
+		data.Badges[i].Badge = r.s.badges[data.Badges[i].BadgeID]
+		if m, ok := r.s.milestones[data.Badges[i].MilestoneID]; ok {
+			data.Badges[i].Milestone = &m
+		}
+	}
+	return data, nil
+}
+
+type memoryImports struct{ s *memoryStore }
+
+func (r memoryImports) Import(ctx context.Context, batch *ImportBatch) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	// Check everything first, so that a failing import creates nothing.
+	occurrences := map[uint]map[string]bool{}
+	for _, c := range batch.Complexes {
+		if _, ok := r.s.complexes[c.Complex.ID]; c.Complex.ID != 0 && !ok {
+			return ErrNotFound
+		}
+		for _, g := range c.Goals {
+			if _, ok := r.s.goals[g.Goal.ID]; g.Goal.ID != 0 && !ok {
+				return ErrNotFound
+			}
+			for _, a := range g.Actions {
+				if _, ok := r.s.actions[a.Action.ID]; a.Action.ID != 0 && !ok {
+					return ErrNotFound
+				}
+				dates := map[string]bool{}
+				if a.Action.ID != 0 {
+					dates = occurrences[a.Action.ID]
+					if dates == nil {
+						dates = map[string]bool{}
+						for _, stored := range r.s.completions {
+							if stored.ActionID == a.Action.ID {
+								dates[checkin.Key(stored.OccurrenceDate)] = true
+							}
+						}
+						occurrences[a.Action.ID] = dates
+					}
+				}
+				for _, completion := range a.Completions {
+					key := checkin.Key(completion.OccurrenceDate)
+					if dates[key] {
+						return ErrConflict
+					}
+					dates[key] = true
+				}
+			}
+		}
+	}
+
+	now := time.Now()
+	stamp := func(createdAt, updatedAt *time.Time) {
+		if createdAt.IsZero() {
+			*createdAt = now
+		}
+		if updatedAt != nil && updatedAt.IsZero() {
+			*updatedAt = now
+		}
+	}
+	for _, c := range batch.Complexes {
+		if c.Complex.ID == 0 {
+			c.Complex.ID = r.s.nextID()
+			stamp(&c.Complex.CreatedAt, &c.Complex.UpdatedAt)
+			r.s.complexes[c.Complex.ID] = c.Complex
+		}
+		for _, g := range c.Goals {
+			if g.Goal.ID == 0 {
+				g.Goal.ID = r.s.nextID()
+				g.Goal.ComplexID = c.Complex.ID
+				stamp(&g.Goal.CreatedAt, &g.Goal.UpdatedAt)
+				r.s.goals[g.Goal.ID] = g.Goal
+			}
+			for i := range g.Measurements {
+				m := &g.Measurements[i]
+				m.ID, m.GoalID = r.s.nextID(), g.Goal.ID
+				stamp(&m.CreatedAt, nil)
+				r.s.measurements[m.ID] = *m
+			}
+			for _, a := range g.Actions {
+				if a.Action.ID == 0 {
+					a.Action.ID = r.s.nextID()
+					a.Action.GoalID = g.Goal.ID
+					stamp(&a.Action.CreatedAt, &a.Action.UpdatedAt)
+					stored := a.Action
+					stored.Gains, stored.Losses, stored.Completions = nil, nil, nil
+					r.s.actions[a.Action.ID] = stored
+				}
+				for i := range a.Gains {
+					a.Gains[i].ID, a.Gains[i].ActionID = r.s.nextID(), a.Action.ID
+					r.s.gains[a.Gains[i].ID] = a.Gains[i]
+				}
+				for i := range a.Losses {
+					a.Losses[i].ID, a.Losses[i].ActionID = r.s.nextID(), a.Action.ID
+					r.s.losses[a.Losses[i].ID] = a.Losses[i]
+				}
+				for i := range a.Completions {
+					completion := &a.Completions[i]
+					completion.ID, completion.ActionID = r.s.nextID(), a.Action.ID
+					stamp(&completion.CreatedAt, &completion.UpdatedAt)
+					r.s.completions[completion.ID] = *completion
+				}
+			}
+		}
+	}
+	return nil
+}
+
+type memoryDataKeys struct{ s *memoryStore }
+
+func (r memoryDataKeys) Get(ctx context.Context, userID string) (*models.UserDataKey, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	key, ok := r.s.dataKeys[userID]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &key, nil
+}
+
+func (r memoryDataKeys) Create(ctx context.Context, key *models.UserDataKey) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if _, ok := r.s.dataKeys[key.UserID]; ok {
+		return ErrConflict
+	}
+	now := time.Now()
+	key.CreatedAt, key.UpdatedAt = now, now
+	r.s.dataKeys[key.UserID] = *key
+	return nil
+}
+
+func (r memoryDataKeys) ListNotWrappedBy(ctx context.Context, masterKeyID string) ([]models.UserDataKey, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	keys := []models.UserDataKey{}
+	for _, key := range r.s.dataKeys {
+		if key.MasterKeyID != masterKeyID {
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].UserID < keys[j].UserID })
+	return keys, nil
+}
+
+func (r memoryDataKeys) Rewrap(ctx context.Context, key *models.UserDataKey, previousMasterKeyID string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	stored, ok := r.s.dataKeys[key.UserID]
+	if !ok || stored.MasterKeyID != previousMasterKeyID {
+		return ErrNotFound
+	}
+	stored.MasterKeyID, stored.WrappedKey, stored.UpdatedAt = key.MasterKeyID, key.WrappedKey, time.Now()
+	r.s.dataKeys[key.UserID] = stored
+	return nil
+}
+
+type memoryUsers struct{ s *memoryStore }
+
+func (r memoryUsers) Get(ctx context.Context, id string) (*models.User, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	u, ok := r.s.users[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &u, nil
+}
+
+func (r memoryUsers) byEmail(email string) (models.User, bool) {
+	for _, u := range r.s.users {
+		if u.Email == email {
+			return u, true
+		}
+	}
+	return models.User{}, false
+}
+
+func (r memoryUsers) GetByEmail(ctx context.Context, email string) (*models.User, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	u, ok := r.byEmail(email)
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &u, nil
+}
+
+func (r memoryUsers) Create(ctx context.Context, user *models.User) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return r.create(user)
+}
+
+func (r memoryUsers) create(user *models.User) error {
+	if _, ok := r.byEmail(user.Email); ok {
+		return ErrConflict
+	}
+	if _, ok := r.s.users[user.ID]; ok {
+		return ErrConflict
+	}
+	user.CreatedAt = time.Now()
+	user.UpdatedAt = user.CreatedAt
+	r.s.users[user.ID] = *user
+	return nil
+}
+
+func (r memoryUsers) SetLanguage(ctx context.Context, id, language string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	user, ok := r.s.users[id]
+	if !ok {
+		return ErrNotFound
+	}
+	user.Language = language
+	user.UpdatedAt = time.Now()
+	r.s.users[id] = user
+	return nil
+}
+
+func (r memoryUsers) CreateRefreshToken(ctx context.Context, token *models.RefreshToken) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	for _, t := range r.s.tokens {
+		if t.TokenHash == token.TokenHash {
+			return ErrConflict
+		}
+	}
+	token.ID = r.s.nextID()
+	token.CreatedAt = time.Now()
+	r.s.tokens[token.ID] = *token
+	return nil
+}
+
+func (r memoryUsers) GetRefreshToken(ctx context.Context, tokenHash string) (*models.RefreshToken, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	for _, t := range r.s.tokens {
+		if t.TokenHash == tokenHash {
+			return &t, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (r memoryUsers) RevokeRefreshToken(ctx context.Context, id uint, at time.Time) (bool, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	t, ok := r.s.tokens[id]
+	if !ok || t.RevokedAt != nil {
+		return false, nil
+	}
+	t.RevokedAt = &at
+	r.s.tokens[id] = t
+	return true, nil
+}
+
+func (r memoryUsers) RevokeRefreshTokenByHash(ctx context.Context, tokenHash string, at time.Time) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	r.revokeWhere(func(t models.RefreshToken) bool { return t.TokenHash == tokenHash }, at)
+	return nil
+}
+
+func (r memoryUsers) RevokeUserRefreshTokens(ctx context.Context, userID string, at time.Time) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	r.revokeWhere(func(t models.RefreshToken) bool { return t.UserID == userID }, at)
+	return nil
+}
+
+func (r memoryUsers) revokeWhere(match func(models.RefreshToken) bool, at time.Time) {
+	for id, t := range r.s.tokens {
+		if t.RevokedAt == nil && match(t) {
+			t.RevokedAt = &at
+			r.s.tokens[id] = t
+		}
+	}
+}
+
+func (r memoryUsers) GetIdentity(ctx context.Context, issuer, subject string) (*models.UserIdentity, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	for _, identity := range r.s.identities {
+		if identity.Issuer == issuer && identity.Subject == subject {
+			return &identity, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (r memoryUsers) LinkIdentity(ctx context.Context, identity *models.UserIdentity, newUser models.User, linkExisting bool) (*models.User, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	for _, existing := range r.s.identities {
+		if existing.Issuer == identity.Issuer && existing.Subject == identity.Subject {
+			user := r.s.users[existing.UserID]
+			return &user, nil
+		}
+	}
+	user, ok := r.byEmail(identity.Email)
+	switch {
+	case !ok:
+		user = newUser
+		if err := r.create(&user); err != nil {
+			return nil, err
+		}
+	case !linkExisting:
+		return nil, ErrConflict
+	}
+	identity.ID = r.s.nextID()
+	identity.UserID = user.ID
+	identity.CreatedAt = time.Now()
+	r.s.identities[identity.ID] = *identity
+	return &user, nil
+}
+
+func (r memoryUsers) CreateLoginState(ctx context.Context, state *models.OIDCLoginState) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if _, ok := r.s.loginStates[state.State]; ok {
+		return ErrConflict
+	}
+	state.CreatedAt = time.Now()
+	r.s.loginStates[state.State] = *state
+	return nil
+}
+
+func (r memoryUsers) ConsumeLoginState(ctx context.Context, state string, now time.Time) (*models.OIDCLoginState, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	loginState, ok := r.s.loginStates[state]
+	if !ok || !loginState.ExpiresAt.After(now) {
+		return nil, ErrNotFound
+	}
+	delete(r.s.loginStates, state)
+	return &loginState, nil
+}
+
+func (r memoryUsers) DeleteExpiredLoginStates(ctx context.Context, now time.Time) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	for state, loginState := range r.s.loginStates {
+		if loginState.ExpiresAt.Before(now) {
+			delete(r.s.loginStates, state)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+package repository
+
+import "refuel/backend/models"
+
+// Gains and losses share one shape, so both repositories implement them with
+// generic helpers. keysFunc exposes the two columns those helpers work with.
+type keysFunc[T any] func(row *T) (id, actionID *uint)
+
+func gainKeys(g *models.Gain) (id, actionID *uint) { return &g.ID, &g.ActionID }
+
+func lossKeys(l *models.Loss) (id, actionID *uint) { return &l.ID, &l.ActionID }
+
+// checkBatch verifies that every ID in the batch belongs to one of existing
+// and returns those IDs.
+func checkBatch[T any](existing, batch []T, keys keysFunc[T]) ([]uint, error) {
+	known := make(map[uint]bool, len(existing))
+	for i := range existing {
+		id, _ := keys(&existing[i])
+		known[*id] = true
+	}
+	var listed []uint
+	for i := range batch {
+		id, _ := keys(&batch[i])
+		if *id == 0 {
+			continue
+		}
+		if !known[*id] {
+			return nil, ErrNotFound
+		}
+		listed = append(listed, *id)
+	}
+	return listed, nil
+}
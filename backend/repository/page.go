@@ -0,0 +1,297 @@
+package repository
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"refuel/backend/models"
+)
+
+// Page sizes of the paginated lists.
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
+
+// ErrInvalidCursor is returned by DecodeCursor for a cursor that was not
+// issued by Cursor.Encode.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// The fields each paginated list can be sorted by.
+var (
+	ComplexSortFields = []string{"created_at", "updated_at", "category"}
+	GoalSortFields    = []string{"created_at", "updated_at"}
+	ActionSortFields  = []string{"created_at", "updated_at"}
+)
+
+// timeSortFields are the sort fields holding timestamps; the others hold text.
+var timeSortFields = map[string]bool{"created_at": true, "updated_at": true}
+
+// Sort orders a list by one field. Records with the same value are ordered
+// by ID in the same direction, so the order is total.
+type Sort struct {
+	Field string
+	Desc  bool
+}
+
+// ParseSort parses a sort order written as the field name, prefixed with
+// "-" for descending order. It reports false for fields not in fields.
+func ParseSort(s string, fields []string) (Sort, bool) {
+	field, desc := strings.CutPrefix(s, "-")
+	for _, f := range fields {
+		if f == field {
+			return Sort{Field: field, Desc: desc}, true
+		}
+	}
+	return Sort{}, false
+}
+
+// String formats the sort order as ParseSort parses it.
+func (s Sort) String() string {
+	if s.Desc {
+		return "-" + s.Field
+	}
+	return s.Field
+}
+
+// Page selects one page of a list: at most Limit records, in Sort order,
+// starting after the record After points at, or from the first record when
+// After is nil.
+type Page struct {
+	Sort  Sort
+	After *Cursor
+	Limit int
+}
+
+// Cursor points at the last record of a page by its value of the sort
+// field and its ID. Time or Text holds the value, depending on the field.
+type Cursor struct {
+	Sort Sort
+	Time time.Time
+	Text string
+	ID   uint
+}
+
+// cursorJSON is the encoded form of a cursor, kept short as it travels in
+// URLs.
+type cursorJSON struct {
+	Sort string    `json:"s"`
+	Time time.Time `json:"t,omitempty"`
+	Text string    `json:"x,omitempty"`
+	ID   uint      `json:"i"`
+}
+
+// Encode returns the cursor as an opaque, URL-safe token.
+func (c Cursor) Encode() string {
+	data, _ := json.Marshal(cursorJSON{Sort: c.Sort.String(), Time: c.Time, Text: c.Text, ID: c.ID})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor parses a token returned by Cursor.Encode.
+func DecodeCursor(token string) (*Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var c cursorJSON
+	if err := json.Unmarshal(data, &c); err != nil || c.Sort == "" || c.ID == 0 {
+		return nil, ErrInvalidCursor
+	}
+	field, desc := strings.CutPrefix(c.Sort, "-")
+	return &Cursor{Sort: Sort{Field: field, Desc: desc}, Time: c.Time, Text: c.Text, ID: c.ID}, nil
+}
+
+func (c Cursor) value() interface{} {
+	if timeSortFields[c.Sort.Field] {
+		return c.Time
+	}
+	return c.Text
+}
+
+// newCursor returns the cursor of a record with the given value of the
+// sort field.
+func newCursor(s Sort, value interface{}, id uint) *Cursor {
+	c := &Cursor{Sort: s, ID: id}
+	switch v := value.(type) {
+	case time.Time:
+		c.Time = v
+	case string:
+		c.Text = v
+	}
+	return c
+}
+
+// sortKey returns a record's value of a sort field, a time.Time or a
+// string, and its ID.
+type sortKey[T any] func(record *T, field string) (interface{}, uint)
+
+// paged queries one page with tx, which selects the list. It fetches one
+// record beyond the page to tell whether another page follows. The sort
+// field must be one of the list's sort fields, as it is used as a column
+// name.
+func paged(tx *gorm.DB, p Page) *gorm.DB {
+	dir, cmp := "ASC", ">"
+	if p.Sort.Desc {
+		dir, cmp = "DESC", "<"
+	}
+	col := p.Sort.Field
+	if p.After != nil {
+		v := p.After.value()
+		tx = tx.Where(fmt.Sprintf("(%[1]s %[2]s ? OR (%[1]s = ? AND id %[2]s ?))", col, cmp), v, v, p.After.ID)
+	}
+	return tx.Order(col + " " + dir).Order("id " + dir).Limit(p.Limit + 1)
+}
+
+// pageOf sorts records, the whole list, and cuts the page out of them the
+// way paged does in SQL.
+func pageOf[T any](records []T, p Page, key sortKey[T]) ([]T, *Cursor) {
+	less := func(a, b *T) bool {
+		va, ida := key(a, p.Sort.Field)
+		vb, idb := key(b, p.Sort.Field)
+		c := compareKeys(va, vb)
+		if c == 0 {
+			c = compareIDs(ida, idb)
+		}
+		if p.Sort.Desc {
+			return c > 0
+		}
+		return c < 0
+	}
+	sort.SliceStable(records, func(i, j int) bool { return less(&records[i], &records[j]) })
+	if p.After != nil {
+		start := sort.Search(len(records), func(i int) bool {
+			v, id := key(&records[i], p.Sort.Field)
+			c := compareKeys(v, p.After.value())
+			if c == 0 {
+				c = compareIDs(id, p.After.ID)
+			}
+			if p.Sort.Desc {
+				return c < 0
+			}
+			return c > 0
+		})
+		records = records[start:]
+	}
+	if len(records) > p.Limit+1 {
+		records = records[:p.Limit+1]
+	}
+	return nextPage(records, p, key)
+}
+
+// nextPage drops the record fetched beyond the page, if any, and returns
+// the cursor of the page that follows, or nil on the last page.
+func nextPage[T any](records []T, p Page, key sortKey[T]) ([]T, *Cursor) {
+	if len(records) <= p.Limit {
+		return records, nil
+	}
+	records = records[:p.Limit]
+	v, id := key(&records[p.Limit-1], p.Sort.Field)
+	return records, newCursor(p.Sort, v, id)
+}
+
+func compareKeys(a, b interface{}) int {
+	switch a := a.(type) {
+	case time.Time:
+		return a.Compare(b.(time.Time))
+	case string:
+		return strings.Compare(a, b.(string))
+	}
+	return 0
+}
+
+func compareIDs(a, b uint) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func complexKey(c *models.Complex, field string) (interface{}, uint) {
+	switch field {
+	case "updated_at":
+		return c.UpdatedAt, c.ID
+	case "category":
+		return c.Category, c.ID
+	}
+	return c.CreatedAt, c.ID
+}
+
+func goalKey(g *models.Goal, field string) (interface{}, uint) {
+	if field == "updated_at" {
+		return g.UpdatedAt, g.ID
+	}
+	return g.CreatedAt, g.ID
+}
+
+func actionKey(a *models.Action, field string) (interface{}, uint) {
+	if field == "updated_at" {
+		return a.UpdatedAt, a.ID
+	}
+	return a.CreatedAt, a.ID
+}
+
+func (f ComplexFilter) scope(tx *gorm.DB) *gorm.DB {
+	if f.Category != "" {
+		tx = tx.Where("category = ?", f.Category)
+	}
+	return tx
+}
+
+func (f ComplexFilter) match(c models.Complex) bool {
+	return f.Category == "" || c.Category == f.Category
+}
+
+func (f GoalFilter) scope(tx *gorm.DB) *gorm.DB {
+	if f.ComplexID != 0 {
+		tx = tx.Where("complex_id = ?", f.ComplexID)
+	}
+	return tx
+}
+
+func (f GoalFilter) match(g models.Goal) bool {
+	return f.ComplexID == 0 || g.ComplexID == f.ComplexID
+}
+
+func (f ActionFilter) scope(tx *gorm.DB) *gorm.DB {
+	if f.GoalID != 0 {
+		tx = tx.Where("goal_id = ?", f.GoalID)
+	}
+	if f.Completed != nil {
+		if *f.Completed {
+			tx = tx.Where("completed_at IS NOT NULL")
+		} else {
+			tx = tx.Where("completed_at IS NULL")
+		}
+	}
+	if !f.CompletedFrom.IsZero() {
+		tx = tx.Where("completed_at >= ?", f.CompletedFrom)
+	}
+	if !f.CompletedTo.IsZero() {
+		tx = tx.Where("completed_at < ?", f.CompletedTo)
+	}
+	return tx
+}
+
+func (f ActionFilter) match(a models.Action) bool {
+	switch {
+	case f.GoalID != 0 && a.GoalID != f.GoalID:
+		return false
+	case f.Completed != nil && *f.Completed != (a.CompletedAt != nil):
+		return false
+	case !f.CompletedFrom.IsZero() && (a.CompletedAt == nil || a.CompletedAt.Before(f.CompletedFrom)):
+		return false
+	case !f.CompletedTo.IsZero() && (a.CompletedAt == nil || !a.CompletedAt.Before(f.CompletedTo)):
+		return false
+	}
+	return true
+}
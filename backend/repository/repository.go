@@ -0,0 +1,339 @@
+// Package repository defines the persistence interfaces the API service is
+// written against, with a GORM implementation for production and an
+// in-memory implementation for tests and local experiments.
+//
+// Every user-owned record is read and written through its owner's ID, so a
+// record that exists but belongs to someone else is reported as ErrNotFound.
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"refuel/backend/models"
+)
+
+var (
+	// ErrNotFound is returned when a record does not exist or is not owned by the user.
+	ErrNotFound = errors.New("record not found")
+	// ErrConflict is returned when a write would violate a uniqueness constraint.
+	ErrConflict = errors.New("record already exists")
+	// ErrParentDeleted is returned when restoring a record whose complex or
+	// goal is itself in the trash.
+	ErrParentDeleted = errors.New("parent record is in the trash")
+)
+
+// ComplexRepository stores complexes.
+type ComplexRepository interface {
+	List(ctx context.Context, userID string) ([]models.Complex, error)
+	// ListPage returns one page of the user's complexes matching filter and
+	// the cursor of the next page, nil on the last one.
+	ListPage(ctx context.Context, userID string, filter ComplexFilter, page Page) ([]models.Complex, *Cursor, error)
+	Get(ctx context.Context, userID string, id uint) (*models.Complex, error)
+	Create(ctx context.Context, complex *models.Complex) error
+	Update(ctx context.Context, complex *models.Complex) error
+	// Delete moves the complex to the trash together with its goals and
+	// their actions.
+	Delete(ctx context.Context, userID string, id uint) error
+}
+
+// ComplexFilter narrows a list of complexes. Zero fields match everything.
+type ComplexFilter struct {
+	Category string
+}
+
+// GoalFilter narrows a list of goals. Zero fields match everything.
+type GoalFilter struct {
+	ComplexID uint
+}
+
+// ActionFilter narrows a list of actions. Zero fields match everything.
+type ActionFilter struct {
+	GoalID uint
+	// Completed keeps only the completed actions when true and only the
+	// others when false.
+	Completed *bool
+	// CompletedFrom and CompletedTo bound CompletedAt, the start inclusive
+	// and the end exclusive. Setting either leaves out uncompleted actions.
+	CompletedFrom, CompletedTo time.Time
+}
+
+// GoalRepository stores goals with the measurements of their metrics and
+// their milestones.
+type GoalRepository interface {
+	List(ctx context.Context, userID string) ([]models.Goal, error)
+	// ListPage returns one page of the user's goals matching filter and the
+	// cursor of the next page, nil on the last one.
+	ListPage(ctx context.Context, userID string, filter GoalFilter, page Page) ([]models.Goal, *Cursor, error)
+	Get(ctx context.Context, userID string, id uint) (*models.Goal, error)
+	Create(ctx context.Context, goal *models.Goal) error
+	Update(ctx context.Context, goal *models.Goal) error
+	// Delete moves the goal to the trash together with its actions. Its
+	// measurements and milestones are kept for when it is restored.
+	Delete(ctx context.Context, userID string, id uint) error
+
+	// ListMeasurements returns the goal's measurements, oldest first.
+	ListMeasurements(ctx context.Context, userID string, goalID uint) ([]models.GoalMeasurement, error)
+	GetMeasurement(ctx context.Context, userID string, goalID, id uint) (*models.GoalMeasurement, error)
+	CreateMeasurement(ctx context.Context, measurement *models.GoalMeasurement) error
+	DeleteMeasurement(ctx context.Context, userID string, goalID, id uint) error
+
+	// ListMilestones returns the goal's milestones by position.
+	ListMilestones(ctx context.Context, userID string, goalID uint) ([]models.Milestone, error)
+	// ReplaceMilestones deletes the goal's milestones that are not achieved
+	// and creates the given ones in their place, in one transaction.
+	// Achieved milestones are kept, so the badges awarded for them stay.
+	ReplaceMilestones(ctx context.Context, userID string, goalID uint, milestones []models.Milestone) error
+	// AchieveMilestone sets the milestone's AchievedAt unless it is set already.
+	AchieveMilestone(ctx context.Context, id uint, at time.Time) error
+}
+
+// ActionRepository stores actions with their gains, losses and
+// per-occurrence check-ins. Actions are returned with their gains and losses
+// loaded.
+type ActionRepository interface {
+	List(ctx context.Context, userID string) ([]models.Action, error)
+	// ListByGoal returns the goal's actions, newest first.
+	ListByGoal(ctx context.Context, userID string, goalID uint) ([]models.Action, error)
+	// ListPage returns one page of the user's actions matching filter and
+	// the cursor of the next page, nil on the last one.
+	ListPage(ctx context.Context, userID string, filter ActionFilter, page Page) ([]models.Action, *Cursor, error)
+	Get(ctx context.Context, userID string, id uint) (*models.Action, error)
+	// Create stores the action along with its gains and losses.
+	Create(ctx context.Context, action *models.Action) error
+	// Update saves the action's own columns; its gains and losses are left
+	// alone, use SaveGains and SaveLosses for those.
+	Update(ctx context.Context, action *models.Action) error
+	// Delete moves the action to the trash. Its gains, losses and check-ins
+	// are kept for when it is restored.
+	Delete(ctx context.Context, userID string, id uint) error
+
+	// ListGains returns the action's gains in creation order.
+	ListGains(ctx context.Context, actionID uint) ([]models.Gain, error)
+	GetGain(ctx context.Context, actionID, id uint) (*models.Gain, error)
+	// SaveGain creates the gain, or updates it if it has an ID.
+	SaveGain(ctx context.Context, gain *models.Gain) error
+	DeleteGain(ctx context.Context, actionID, id uint) error
+	// SaveGains writes a batch of the action's gains in one transaction.
+	// Entries with an ID update that gain and the others are created. With
+	// replace, the action's gains not in the batch are deleted. It returns
+	// ErrNotFound if an ID is not one of the action's gains.
+	SaveGains(ctx context.Context, actionID uint, gains []models.Gain, replace bool) error
+
+	// ListLosses, GetLoss, SaveLoss, DeleteLoss and SaveLosses mirror the gain methods.
+	ListLosses(ctx context.Context, actionID uint) ([]models.Loss, error)
+	GetLoss(ctx context.Context, actionID, id uint) (*models.Loss, error)
+	SaveLoss(ctx context.Context, loss *models.Loss) error
+	DeleteLoss(ctx context.Context, actionID, id uint) error
+	SaveLosses(ctx context.Context, actionID uint, losses []models.Loss, replace bool) error
+
+	// ListCompletions returns the check-ins of the given actions, ordered by
+	// date. A zero from or to leaves that end of the date range open.
+	ListCompletions(ctx context.Context, actionIDs []uint, from, to time.Time) ([]models.ActionCompletion, error)
+	// ListUserCompletions returns the check-ins of the user's actions that
+	// are not in the trash, ordered by date.
+	ListUserCompletions(ctx context.Context, userID string) ([]models.ActionCompletion, error)
+	GetCompletion(ctx context.Context, actionID uint, date time.Time) (*models.ActionCompletion, error)
+	// SaveCompletion creates the check-in, or updates it if it has an ID.
+	SaveCompletion(ctx context.Context, completion *models.ActionCompletion) error
+	DeleteCompletion(ctx context.Context, userID string, actionID uint, date time.Time) error
+}
+
+// Kinds of records the trash holds.
+const (
+	TrashComplex = "complex"
+	TrashGoal    = "goal"
+	TrashAction  = "action"
+)
+
+// TrashItem is a record deleted by its owner. The goals and actions
+// deleted along with a complex or goal share its DeletedAt and are not
+// listed on their own: they leave the trash with it.
+type TrashItem struct {
+	Kind      string
+	ID        uint
+	Content   string
+	DeletedAt time.Time
+}
+
+// TrashRepository reads and empties the trash the Delete methods of the
+// complex, goal and action repositories move records to. The other
+// repositories do not see trashed records.
+type TrashRepository interface {
+	// List returns the user's trash, most recently deleted first.
+	List(ctx context.Context, userID string) ([]TrashItem, error)
+	// Restore takes the record of the given kind out of the trash, together
+	// with the records deleted along with it. It returns ErrNotFound if the
+	// record is not in the user's trash and ErrParentDeleted if the complex
+	// or goal it belongs to is.
+	Restore(ctx context.Context, userID, kind string, id uint) error
+	// Purge permanently deletes the records of every user that were moved
+	// to the trash before the given time, with everything belonging to them.
+	// It returns the number of trash items deleted.
+	Purge(ctx context.Context, before time.Time) (int64, error)
+}
+
+// BadgeRepository stores the badge catalog and the badges users hold.
+type BadgeRepository interface {
+	List(ctx context.Context) ([]models.Badge, error)
+	// Create returns ErrConflict if a badge with the same code exists.
+	Create(ctx context.Context, badge *models.Badge) error
+	// Upsert creates the badge or overwrites the one with the same code.
+	Upsert(ctx context.Context, badge *models.Badge) error
+	// ListUserBadges returns the user's badges with Badge, and Milestone for
+	// milestone badges, filled in, latest first.
+	ListUserBadges(ctx context.Context, userID string) ([]models.UserBadge, error)
+	// Award stores the user badge. It reports false, without an error, if
+	// the user already holds the badge for the same milestone (or for none).
+	Award(ctx context.Context, userBadge *models.UserBadge) (bool, error)
+}
+
+// FeedbackRepository stores the feedback messages shown for completed actions.
+type FeedbackRepository interface {
+	Record(ctx context.Context, message *models.FeedbackMessage) error
+	// ListSince returns the user's messages created at or after since, newest first.
+	ListSince(ctx context.Context, userID string, since time.Time) ([]models.FeedbackMessage, error)
+}
+
+// RevisionRepository stores the revision history of users' records.
+type RevisionRepository interface {
+	Create(ctx context.Context, revision *models.Revision) error
+	// List returns the revisions of one of the user's records, oldest first.
+	List(ctx context.Context, userID, entityType string, entityID uint) ([]models.Revision, error)
+}
+
+// UserData is everything a user owns, records in the trash included, each
+// kind ordered by ID. Actions are loaded without their associations.
+type UserData struct {
+	Complexes    []models.Complex
+	Goals        []models.Goal
+	Measurements []models.GoalMeasurement
+	Milestones   []models.Milestone
+	Actions      []models.Action
+	Gains        []models.Gain
+	Losses       []models.Loss
+	Completions  []models.ActionCompletion
+	// Badges hold Badge, and Milestone for milestone badges.
+	Badges    []models.UserBadge
+	Revisions []models.Revision
+}
+
+// ExportRepository stores export jobs and reads the data they export. Jobs
+// are loaded without their archive except by GetArchive.
+type ExportRepository interface {
+	CreateJob(ctx context.Context, job *models.ExportJob) error
+	GetJob(ctx context.Context, userID string, id uint) (*models.ExportJob, error)
+	// ListJobs returns the user's jobs, newest first.
+	ListJobs(ctx context.Context, userID string) ([]models.ExportJob, error)
+	GetArchive(ctx context.Context, userID string, id uint) (*models.ExportJob, error)
+	// ClaimJob marks the oldest pending job, or a job left running since
+	// before staleBefore by a worker that stopped, as running from now on
+	// and returns it. It returns ErrNotFound when there is none, and never
+	// hands the same claim to two callers.
+	ClaimJob(ctx context.Context, staleBefore, now time.Time) (*models.ExportJob, error)
+	// FinishJob stores the outcome of a claimed job: its status, error,
+	// archive and completion and expiry times.
+	FinishJob(ctx context.Context, job *models.ExportJob) error
+	// DeleteExpired deletes the jobs whose archive expired before now.
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+	UserData(ctx context.Context, userID string) (*UserData, error)
+}
+
+// ImportBatch is the tree of records an import creates, so that each one
+// is created under the IDs its parents are given. A complex, goal or
+// action with an ID already exists: only the records under it are created.
+type ImportBatch struct {
+	Complexes []*ImportComplex
+}
+
+// ImportComplex is a complex of an ImportBatch and its goals.
+type ImportComplex struct {
+	Complex models.Complex
+	Goals   []*ImportGoal
+}
+
+// ImportGoal is a goal of an ImportBatch with its new measurements and its actions.
+type ImportGoal struct {
+	Goal         models.Goal
+	Measurements []models.GoalMeasurement
+	Actions      []*ImportAction
+}
+
+// ImportAction is an action of an ImportBatch with its new gains, losses
+// and check-ins.
+type ImportAction struct {
+	Action      models.Action
+	Gains       []models.Gain
+	Losses      []models.Loss
+	Completions []models.ActionCompletion
+}
+
+// ImportRepository creates imported records.
+type ImportRepository interface {
+	// Import creates the new records of batch in one transaction, keeping
+	// the creation and update times they carry, and sets their IDs and
+	// parent IDs in place. Nothing is created if any record fails.
+	Import(ctx context.Context, batch *ImportBatch) error
+}
+
+// DataKeyRepository stores the users' data keys, wrapped by a master key.
+type DataKeyRepository interface {
+	Get(ctx context.Context, userID string) (*models.UserDataKey, error)
+	// Create returns ErrConflict if the user already has a data key.
+	Create(ctx context.Context, key *models.UserDataKey) error
+	// ListNotWrappedBy returns the data keys wrapped by any master key but masterKeyID.
+	ListNotWrappedBy(ctx context.Context, masterKeyID string) ([]models.UserDataKey, error)
+	// Rewrap stores the new wrapping of key if the stored one is still
+	// wrapped by previousMasterKeyID, and returns ErrNotFound otherwise.
+	Rewrap(ctx context.Context, key *models.UserDataKey, previousMasterKeyID string) error
+}
+
+// UserRepository stores accounts and the credentials attached to them:
+// refresh tokens, linked OIDC identities and pending OIDC logins.
+type UserRepository interface {
+	Get(ctx context.Context, id string) (*models.User, error)
+	GetByEmail(ctx context.Context, email string) (*models.User, error)
+	// Create returns ErrConflict if the email is taken.
+	Create(ctx context.Context, user *models.User) error
+	// SetLanguage stores the user's language preference; empty clears it.
+	SetLanguage(ctx context.Context, id, language string) error
+
+	CreateRefreshToken(ctx context.Context, token *models.RefreshToken) error
+	GetRefreshToken(ctx context.Context, tokenHash string) (*models.RefreshToken, error)
+	// RevokeRefreshToken revokes one token. It reports false if the token
+	// was already revoked, so only one concurrent caller can consume it.
+	RevokeRefreshToken(ctx context.Context, id uint, at time.Time) (bool, error)
+	// RevokeRefreshTokenByHash revokes the token if it exists and is active.
+	RevokeRefreshTokenByHash(ctx context.Context, tokenHash string, at time.Time) error
+	RevokeUserRefreshTokens(ctx context.Context, userID string, at time.Time) error
+
+	GetIdentity(ctx context.Context, issuer, subject string) (*models.UserIdentity, error)
+	// LinkIdentity atomically attaches identity to the user with its email,
+	// creating newUser when there is none. When the email belongs to an
+	// existing user and linkExisting is false it returns ErrConflict. When
+	// the issuer and subject are already linked, by a concurrent login, it
+	// returns the user they are linked to.
+	LinkIdentity(ctx context.Context, identity *models.UserIdentity, newUser models.User, linkExisting bool) (*models.User, error)
+
+	CreateLoginState(ctx context.Context, state *models.OIDCLoginState) error
+	// ConsumeLoginState deletes and returns the state if it has not expired
+	// by now. A state can be consumed once.
+	ConsumeLoginState(ctx context.Context, state string, now time.Time) (*models.OIDCLoginState, error)
+	DeleteExpiredLoginStates(ctx context.Context, now time.Time) error
+}
+
+// Repositories bundles one implementation of every repository.
+type Repositories struct {
+	Complexes ComplexRepository
+	Goals     GoalRepository
+	Actions   ActionRepository
+	Trash     TrashRepository
+	Badges    BadgeRepository
+	Feedback  FeedbackRepository
+	Revisions RevisionRepository
+	Exports   ExportRepository
+	Imports   ImportRepository
+	DataKeys  DataKeyRepository
+	Users     UserRepository
+}
@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"sort"
+
+	"gorm.io/gorm"
+
+	"refuel/backend/models"
+)
+
+// trashItems lists the trashed complexes, goals and actions as trash
+// items, most recently deleted first. Goals and actions deleted along with
+// their complex or goal are left out: they come back with it.
+func trashItems(complexes []models.Complex, goals []models.Goal, actions []models.Action) []TrashItem {
+	complexDeletedAt := make(map[uint]gorm.DeletedAt, len(complexes))
+	goalDeletedAt := make(map[uint]gorm.DeletedAt, len(goals))
+	items := make([]TrashItem, 0, len(complexes)+len(goals)+len(actions))
+	for _, c := range complexes {
+		complexDeletedAt[c.ID] = c.DeletedAt
+		items = append(items, TrashItem{Kind: TrashComplex, ID: c.ID, Content: c.Content, DeletedAt: c.DeletedAt.Time})
+	}
+	for _, g := range goals {
+		goalDeletedAt[g.ID] = g.DeletedAt
+		if deletedTogether(complexDeletedAt[g.ComplexID], g.DeletedAt) {
+			continue
+		}
+		items = append(items, TrashItem{Kind: TrashGoal, ID: g.ID, Content: g.Content, DeletedAt: g.DeletedAt.Time})
+	}
+	for _, a := range actions {
+		if deletedTogether(goalDeletedAt[a.GoalID], a.DeletedAt) {
+			continue
+		}
+		items = append(items, TrashItem{Kind: TrashAction, ID: a.ID, Content: a.Content, DeletedAt: a.DeletedAt.Time})
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		if !items[i].DeletedAt.Equal(items[j].DeletedAt) {
+			return items[i].DeletedAt.After(items[j].DeletedAt)
+		}
+		return items[i].ID > items[j].ID
+	})
+	return items
+}
+
+// deletedTogether reports whether a child record was moved to the trash
+// along with its parent, which gives both the same deletion time.
+func deletedTogether(parent, child gorm.DeletedAt) bool {
+	return parent.Valid && child.Valid && parent.Time.Equal(child.Time)
+}
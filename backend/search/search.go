@@ -0,0 +1,336 @@
+// Package search ranks free-text records against a query by character
+// n-grams. Matching on bigrams rather than words finds Japanese text, which
+// has no spaces to split words at, without a morphological analyser, and
+// runs in Go so it does not depend on the full-text features of a database.
+package search
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// MaxQueryLength bounds the length of a query, in characters.
+const MaxQueryLength = 100
+
+// snippetLength is the length of a snippet, in characters, not counting
+// the ellipses marking cut text.
+const snippetLength = 80
+
+// snippetLead is how many characters a snippet shows before the first match.
+const snippetLead = 20
+
+const ellipsis = "…"
+
+// BM25 parameters.
+const (
+	k1 = 1.2
+	b  = 0.75
+)
+
+// exactBonus weights a term found as it was typed against one whose
+// n-grams only appear apart.
+const exactBonus = 0.5
+
+// Document is one record to search: the fields holding its text.
+type Document struct {
+	Fields []Field
+}
+
+// Field is a named piece of a document's text.
+type Field struct {
+	Name string
+	Text string
+}
+
+// Hit is a document matching the query.
+type Hit struct {
+	// Index is the position of the document in the searched slice.
+	Index int
+	Score float64
+	// Field names the field the snippet is cut from, the one matching best.
+	Field string
+	// Snippet is the part of the field around the first match. Highlights
+	// are the spans of the snippet matching the query.
+	Snippet    string
+	Highlights []Span
+}
+
+// Span is a range of characters (Unicode code points) of a snippet, from
+// Start up to but not including End.
+type Span struct {
+	Start int
+	End   int
+}
+
+// Query is a parsed search query: its terms, split at spaces, and the
+// n-grams each term is matched by.
+type Query struct {
+	terms []term
+}
+
+type term struct {
+	text  []rune
+	grams []string
+}
+
+// ParseQuery parses q. The query is empty when q holds nothing but spaces.
+func ParseQuery(q string) Query {
+	var query Query
+	seen := map[string]bool{}
+	for _, word := range strings.FieldsFunc(string(normalize([]rune(q))), unicode.IsSpace) {
+		if seen[word] {
+			continue
+		}
+		seen[word] = true
+		text := []rune(word)
+		query.terms = append(query.terms, term{text: text, grams: grams(text)})
+	}
+	return query
+}
+
+// Empty reports whether the query has no terms.
+func (q Query) Empty() bool {
+	return len(q.terms) == 0
+}
+
+// Search returns the documents in which every term of q appears, best
+// first, at most limit of them. A term appears in a document when all of
+// its n-grams do, in any of the document's fields.
+func Search(docs []Document, q Query, limit int) []Hit {
+	if q.Empty() || limit < 1 {
+		return nil
+	}
+
+	indexed := make([]indexedDoc, len(docs))
+	df := map[string]int{}
+	totalLength := 0
+	for i, doc := range docs {
+		indexed[i] = index(doc, q)
+		for gram := range indexed[i].tf {
+			df[gram]++
+		}
+		totalLength += indexed[i].length
+	}
+	avgLength := 1.0
+	if len(docs) > 0 && totalLength > 0 {
+		avgLength = float64(totalLength) / float64(len(docs))
+	}
+	n := float64(len(docs))
+
+	var hits []Hit
+	for i, doc := range indexed {
+		score, ok := 0.0, true
+		for _, t := range q.terms {
+			termScore := 0.0
+			for _, gram := range t.grams {
+				tf := float64(doc.tf[gram])
+				if tf == 0 {
+					ok = false
+					break
+				}
+				idf := math.Log(1 + (n-float64(df[gram])+0.5)/(float64(df[gram])+0.5))
+				termScore += idf * tf * (k1 + 1) / (tf + k1*(1-b+b*float64(doc.length)/avgLength))
+			}
+			if !ok {
+				break
+			}
+			if doc.exact[string(t.text)] {
+				termScore *= 1 + exactBonus
+			}
+			score += termScore
+		}
+		if !ok {
+			continue
+		}
+		hit := Hit{Index: i, Score: score}
+		hit.Field, hit.Snippet, hit.Highlights = doc.snippet(docs[i], q)
+		hits = append(hits, hit)
+	}
+
+	sort.SliceStable(hits, func(i, j int) bool { return hits[i].Score > hits[j].Score })
+	if len(hits) > limit {
+		hits = hits[:limit]
+	}
+	return hits
+}
+
+// indexedDoc holds what scoring needs of a document: how often each
+// n-gram of the query occurs in it, which terms occur as typed, and its
+// length in characters.
+type indexedDoc struct {
+	tf     map[string]int
+	exact  map[string]bool
+	length int
+	// fields holds the normalized text of each field.
+	fields [][]rune
+}
+
+func index(doc Document, q Query) indexedDoc {
+	d := indexedDoc{tf: map[string]int{}, exact: map[string]bool{}, fields: make([][]rune, len(doc.Fields))}
+	for i, f := range doc.Fields {
+		text := normalize([]rune(f.Text))
+		d.fields[i] = text
+		d.length += len(text)
+		for _, t := range q.terms {
+			for _, gram := range t.grams {
+				d.tf[gram] += count(text, []rune(gram))
+			}
+			if count(text, t.text) > 0 {
+				d.exact[string(t.text)] = true
+			}
+		}
+	}
+	for gram, tf := range d.tf {
+		if tf == 0 {
+			delete(d.tf, gram)
+		}
+	}
+	return d
+}
+
+// snippet cuts the snippet out of the field with the most characters
+// matching the query, the first such field on a tie.
+func (d indexedDoc) snippet(doc Document, q Query) (string, string, []Span) {
+	if len(doc.Fields) == 0 {
+		return "", "", nil
+	}
+	best, bestSpans, bestCovered := 0, []Span(nil), -1
+	for i, text := range d.fields {
+		spans := matches(text, q)
+		covered := 0
+		for _, s := range spans {
+			covered += s.End - s.Start
+		}
+		if covered > bestCovered {
+			best, bestSpans, bestCovered = i, spans, covered
+		}
+	}
+
+	original := []rune(doc.Fields[best].Text)
+	start, end := 0, len(original)
+	if len(original) > snippetLength {
+		if len(bestSpans) > 0 {
+			start = max(0, bestSpans[0].Start-snippetLead)
+		}
+		start = min(start, len(original)-snippetLength)
+		end = start + snippetLength
+	}
+
+	var sb strings.Builder
+	offset := -start
+	if start > 0 {
+		sb.WriteString(ellipsis)
+		offset += len([]rune(ellipsis))
+	}
+	for _, r := range original[start:end] {
+		if unicode.IsSpace(r) {
+			r = ' '
+		}
+		sb.WriteRune(r)
+	}
+	if end < len(original) {
+		sb.WriteString(ellipsis)
+	}
+
+	var highlights []Span
+	for _, s := range bestSpans {
+		s.Start, s.End = max(s.Start, start), min(s.End, end)
+		if s.Start >= s.End {
+			continue
+		}
+		highlights = append(highlights, Span{Start: s.Start + offset, End: s.End + offset})
+	}
+	return doc.Fields[best].Name, sb.String(), highlights
+}
+
+// matches returns the spans of text covered by the n-grams of the query,
+// merged where they touch or overlap.
+func matches(text []rune, q Query) []Span {
+	covered := make([]bool, len(text))
+	for _, t := range q.terms {
+		for _, gram := range t.grams {
+			g := []rune(gram)
+			for i := 0; i+len(g) <= len(text); i++ {
+				if equal(text[i:i+len(g)], g) {
+					for j := i; j < i+len(g); j++ {
+						covered[j] = true
+					}
+				}
+			}
+		}
+	}
+	var spans []Span
+	for i := 0; i < len(text); i++ {
+		if !covered[i] {
+			continue
+		}
+		j := i
+		for j < len(text) && covered[j] {
+			j++
+		}
+		spans = append(spans, Span{Start: i, End: j})
+		i = j
+	}
+	return spans
+}
+
+// grams returns the bigrams of a term, or the term itself when it is a
+// single character.
+func grams(text []rune) []string {
+	if len(text) < 2 {
+		return []string{string(text)}
+	}
+	seen := map[string]bool{}
+	var out []string
+	for i := 0; i+2 <= len(text); i++ {
+		g := string(text[i : i+2])
+		if !seen[g] {
+			seen[g] = true
+			out = append(out, g)
+		}
+	}
+	return out
+}
+
+// normalize folds the variants a user may type a character in into one:
+// full-width ASCII to half-width, katakana to hiragana and upper case to
+// lower case. It maps character to character, so offsets into the
+// normalized text are offsets into the original as well.
+func normalize(text []rune) []rune {
+	out := make([]rune, len(text))
+	for i, r := range text {
+		switch {
+		case r >= 0xFF01 && r <= 0xFF5E:
+			r -= 0xFEE0
+		case r == 0x3000:
+			r = ' '
+		case r >= 0x30A1 && r <= 0x30F6:
+			r -= 0x60
+		}
+		out[i] = unicode.ToLower(r)
+	}
+	return out
+}
+
+// count returns the number of occurrences of sub in text, overlapping ones
+// included.
+func count(text, sub []rune) int {
+	n := 0
+	for i := 0; i+len(sub) <= len(text); i++ {
+		if equal(text[i:i+len(sub)], sub) {
+			n++
+		}
+	}
+	return n
+}
+
+func equal(a, b []rune) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return len(a) == len(b)
+}
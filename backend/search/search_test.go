@@ -0,0 +1,193 @@
+package search
+
+import (
+	"strings"
+	"testing"
+)
+
+func doc(texts ...string) Document {
+	var d Document
+	for i, text := range texts {
+		d.Fields = append(d.Fields, Field{Name: []string{"content", "trigger_episode"}[i], Text: text})
+	}
+	return d
+}
+
+func TestParseQuery(t *testing.T) {
+	tests := []struct {
+		q     string
+		empty bool
+		terms []string
+	}{
+		{"", true, nil},
+		{" \t　", true, nil},
+		{"プレゼン", false, []string{"ぷれぜん"}},
+		{"ＡＢＣ  abc", false, []string{"abc"}},
+		{"緊張 プレゼン", false, []string{"緊張", "ぷれぜん"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.q, func(t *testing.T) {
+			q := ParseQuery(tt.q)
+			if q.Empty() != tt.empty {
+				t.Errorf("got Empty() %v, want %v", q.Empty(), tt.empty)
+			}
+			var terms []string
+			for _, term := range q.terms {
+				terms = append(terms, string(term.text))
+			}
+			if strings.Join(terms, "|") != strings.Join(tt.terms, "|") {
+				t.Errorf("got terms %q, want %q", terms, tt.terms)
+			}
+		})
+	}
+}
+
+func TestGrams(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"a", []string{"a"}},
+		{"ab", []string{"ab"}},
+		{"緊張感", []string{"緊張", "張感"}},
+		{"aaa", []string{"aa"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			got := grams([]rune(tt.text))
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearch(t *testing.T) {
+	docs := []Document{
+		doc("人前で話すと緊張する", "プレゼンで声が震えた"),
+		doc("プレゼンの練習をした"),
+		doc("英語のメールを書いた"),
+		doc("ぷれぜん資料を作った ぷれぜん ぷれぜん"),
+		doc("Public speaking"),
+	}
+	tests := []struct {
+		q    string
+		want []int
+	}{
+		{"緊張", []int{0}},
+		{"緊張 プレゼン", []int{0}},
+		{"ぷれぜん", []int{3, 1, 0}},
+		{"PUBLIC", []int{4}},
+		{"ｓｐｅａｋ", []int{4}},
+		{"張緊", nil},
+		{"メール 緊張", nil},
+		{"書", []int{2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.q, func(t *testing.T) {
+			hits := Search(docs, ParseQuery(tt.q), 10)
+			var got []int
+			for _, hit := range hits {
+				got = append(got, hit.Index)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("got %v, want %v", got, tt.want)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestSearchLimit(t *testing.T) {
+	docs := []Document{doc("緊張"), doc("緊張"), doc("緊張")}
+	tests := []struct {
+		limit int
+		want  int
+	}{
+		{0, 0},
+		{2, 2},
+		{5, 3},
+	}
+	for _, tt := range tests {
+		if got := len(Search(docs, ParseQuery("緊張"), tt.limit)); got != tt.want {
+			t.Errorf("limit %d: got %d hits, want %d", tt.limit, got, tt.want)
+		}
+	}
+}
+
+func TestSnippet(t *testing.T) {
+	long := strings.Repeat("あ", 50) + "緊張" + strings.Repeat("い", 100)
+	tests := []struct {
+		name       string
+		doc        Document
+		q          string
+		field      string
+		snippet    string
+		highlights []Span
+	}{
+		{
+			name:  "short field",
+			doc:   doc("人前で緊張する"),
+			q:     "緊張",
+			field: "content", snippet: "人前で緊張する",
+			highlights: []Span{{3, 5}},
+		},
+		{
+			name:  "best field",
+			doc:   doc("プレゼン", "プレゼンで緊張した"),
+			q:     "緊張 プレゼン",
+			field: "trigger_episode", snippet: "プレゼンで緊張した",
+			highlights: []Span{{0, 4}, {5, 7}},
+		},
+		{
+			name:  "cut around the match",
+			doc:   doc(long),
+			q:     "緊張",
+			field: "content", snippet: "…" + strings.Repeat("あ", 20) + "緊張" + strings.Repeat("い", 58) + "…",
+			highlights: []Span{{21, 23}},
+		},
+		{
+			name:  "cut at the end",
+			doc:   doc(strings.Repeat("あ", 100) + "緊張"),
+			q:     "緊張",
+			field: "content", snippet: "…" + strings.Repeat("あ", 78) + "緊張",
+			highlights: []Span{{79, 81}},
+		},
+		{
+			name:  "spaces flattened",
+			doc:   doc("緊張\nした"),
+			q:     "緊張",
+			field: "content", snippet: "緊張 した",
+			highlights: []Span{{0, 2}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hits := Search([]Document{tt.doc}, ParseQuery(tt.q), 1)
+			if len(hits) != 1 {
+				t.Fatalf("got %d hits, want 1", len(hits))
+			}
+			hit := hits[0]
+			if hit.Field != tt.field {
+				t.Errorf("got field %q, want %q", hit.Field, tt.field)
+			}
+			if hit.Snippet != tt.snippet {
+				t.Errorf("got snippet %q, want %q", hit.Snippet, tt.snippet)
+			}
+			if len(hit.Highlights) != len(tt.highlights) {
+				t.Fatalf("got highlights %v, want %v", hit.Highlights, tt.highlights)
+			}
+			for i := range hit.Highlights {
+				if hit.Highlights[i] != tt.highlights[i] {
+					t.Errorf("got highlights %v, want %v", hit.Highlights, tt.highlights)
+					break
+				}
+			}
+		})
+	}
+}
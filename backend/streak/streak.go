@@ -0,0 +1,115 @@
+// Package streak computes streaks and completion rates for recurring actions
+// from their schedules and check-ins.
+package streak
+
+import (
+	"sort"
+	"time"
+
+	"refuel/backend/checkin"
+	"refuel/backend/models"
+	"refuel/backend/recurrence"
+)
+
+// DefaultWindowDays is the completion-rate window used when none is given.
+const DefaultWindowDays = 30
+
+// Track is one recurring action: its schedule and its recorded check-ins.
+type Track struct {
+	Schedule    recurrence.Schedule
+	Completions []models.ActionCompletion
+}
+
+// Summary is the streak state of one action or a group of actions.
+//
+// Streaks count scheduled days: a day extends the streak when every
+// occurrence due on it is done, and breaks it when any is missed. Days
+// without occurrences, skipped occurrences and today's pending ones leave
+// the streak as it is, so a Mon/Wed/Fri action keeps its streak on Tuesday.
+type Summary struct {
+	Current int
+	Longest int
+	// WindowDays is the number of days, ending today, the rate covers.
+	WindowDays int
+	// Scheduled counts the done and missed occurrences in the window.
+	Scheduled int
+	Completed int
+	// Rate is Completed / Scheduled, or 0 when nothing was due.
+	Rate float64
+	// LastCompleted is the date of the latest done occurrence, if any.
+	LastCompleted *time.Time
+}
+
+// day tallies the occurrences due on one calendar date.
+type day struct {
+	date   time.Time
+	done   int
+	missed int
+}
+
+// Compute summarises the tracks as of now. Calendar days are taken in loc,
+// which should be the location the schedules were built with.
+func Compute(tracks []Track, now time.Time, loc *time.Location, windowDays int) Summary {
+	if windowDays < 1 {
+		windowDays = DefaultWindowDays
+	}
+	today := checkin.Civil(now, loc)
+	windowStart := today.AddDate(0, 0, -(windowDays - 1))
+
+	byDate := map[time.Time]*day{}
+	for _, t := range tracks {
+		start := checkin.Civil(t.Schedule.Start(), loc)
+		if start.After(today) {
+			continue
+		}
+		sched := t.Schedule
+		for _, occ := range checkin.Timeline(&sched, t.Completions, start, today, now) {
+			// Check-ins on unscheduled days do not take part in streaks.
+			if occ.ScheduledAt == nil {
+				continue
+			}
+			d, ok := byDate[occ.Date]
+			if !ok {
+				d = &day{date: occ.Date}
+				byDate[occ.Date] = d
+			}
+			switch occ.Status {
+			case checkin.Done:
+				d.done++
+			case checkin.Missed:
+				d.missed++
+			}
+		}
+	}
+
+	days := make([]*day, 0, len(byDate))
+	for _, d := range byDate {
+		days = append(days, d)
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i].date.Before(days[j].date) })
+
+	s := Summary{WindowDays: windowDays}
+	run := 0
+	for _, d := range days {
+		switch {
+		case d.missed > 0:
+			run = 0
+		case d.done > 0:
+			run++
+			date := d.date
+			s.LastCompleted = &date
+		}
+		if run > s.Longest {
+			s.Longest = run
+		}
+		if !d.date.Before(windowStart) {
+			s.Completed += d.done
+			s.Scheduled += d.done + d.missed
+		}
+	}
+	s.Current = run
+	if s.Scheduled > 0 {
+		s.Rate = float64(s.Completed) / float64(s.Scheduled)
+	}
+	return s
+}
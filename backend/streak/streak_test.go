@@ -0,0 +1,159 @@
+package streak
+
+import (
+	"testing"
+	"time"
+
+	"refuel/backend/models"
+	"refuel/backend/recurrence"
+)
+
+// now is the afternoon of April 10, 2025, a Thursday.
+var now = time.Date(2025, 4, 10, 12, 0, 0, 0, time.UTC)
+
+func date(day int) time.Time {
+	return time.Date(2025, 4, day, 0, 0, 0, 0, time.UTC)
+}
+
+// days records a check-in with status on each day of April.
+func days(status string, ds ...int) []models.ActionCompletion {
+	completions := make([]models.ActionCompletion, len(ds))
+	for i, d := range ds {
+		completions[i] = models.ActionCompletion{OccurrenceDate: date(d), Status: status}
+	}
+	return completions
+}
+
+func track(t *testing.T, p recurrence.Pattern, completions ...[]models.ActionCompletion) Track {
+	t.Helper()
+	s, err := recurrence.NewSchedule(p, date(1), time.UTC)
+	if err != nil {
+		t.Fatalf("NewSchedule: %v", err)
+	}
+	var all []models.ActionCompletion
+	for _, c := range completions {
+		all = append(all, c...)
+	}
+	return Track{Schedule: s, Completions: all}
+}
+
+var (
+	daily = recurrence.Pattern{Frequency: recurrence.Daily, TimeOfDay: "09:00"}
+	mwf   = recurrence.Pattern{Frequency: recurrence.Weekly, TimeOfDay: "09:00", DaysOfWeek: []string{"MON", "WED", "FRI"}}
+)
+
+func TestCompute(t *testing.T) {
+	tests := []struct {
+		name          string
+		tracks        func(t *testing.T) []Track
+		windowDays    int
+		want          Summary
+		lastCompleted int
+	}{
+		{
+			name: "every day done, today pending",
+			tracks: func(t *testing.T) []Track {
+				return []Track{track(t, daily, days(models.CompletionDone, 1, 2, 3, 4, 5, 6, 7, 8, 9))}
+			},
+			want:          Summary{Current: 9, Longest: 9, WindowDays: DefaultWindowDays, Scheduled: 9, Completed: 9, Rate: 1},
+			lastCompleted: 9,
+		},
+		{
+			name: "today done",
+			tracks: func(t *testing.T) []Track {
+				return []Track{track(t, daily, days(models.CompletionDone, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10))}
+			},
+			want:          Summary{Current: 10, Longest: 10, WindowDays: DefaultWindowDays, Scheduled: 10, Completed: 10, Rate: 1},
+			lastCompleted: 10,
+		},
+		{
+			name: "a day without a check-in breaks the streak",
+			tracks: func(t *testing.T) []Track {
+				return []Track{track(t, daily, days(models.CompletionDone, 1, 2, 3, 5, 6, 7, 8, 9))}
+			},
+			want:          Summary{Current: 5, Longest: 5, WindowDays: DefaultWindowDays, Scheduled: 9, Completed: 8, Rate: 8.0 / 9},
+			lastCompleted: 9,
+		},
+		{
+			name: "a recorded miss breaks the streak",
+			tracks: func(t *testing.T) []Track {
+				return []Track{track(t, daily, days(models.CompletionDone, 1, 2, 3, 4, 5, 6, 8, 9), days(models.CompletionMissed, 7))}
+			},
+			want:          Summary{Current: 2, Longest: 6, WindowDays: DefaultWindowDays, Scheduled: 9, Completed: 8, Rate: 8.0 / 9},
+			lastCompleted: 9,
+		},
+		{
+			name: "a skipped day keeps the streak",
+			tracks: func(t *testing.T) []Track {
+				return []Track{track(t, daily, days(models.CompletionDone, 1, 2, 3, 5, 6, 7, 8, 9), days(models.CompletionSkipped, 4))}
+			},
+			want:          Summary{Current: 8, Longest: 8, WindowDays: DefaultWindowDays, Scheduled: 8, Completed: 8, Rate: 1},
+			lastCompleted: 9,
+		},
+		{
+			name: "nothing done",
+			tracks: func(t *testing.T) []Track {
+				return []Track{track(t, daily)}
+			},
+			want: Summary{WindowDays: DefaultWindowDays, Scheduled: 9},
+		},
+		{
+			name: "unscheduled days keep the streak",
+			tracks: func(t *testing.T) []Track {
+				// Wednesday 2, Friday 4, Monday 7 and Wednesday 9; a check-in on
+				// Tuesday 8 is not scheduled and is ignored.
+				return []Track{track(t, mwf, days(models.CompletionDone, 2, 4, 7, 8, 9))}
+			},
+			want:          Summary{Current: 4, Longest: 4, WindowDays: DefaultWindowDays, Scheduled: 4, Completed: 4, Rate: 1},
+			lastCompleted: 9,
+		},
+		{
+			name: "a day counts when every action due on it is done",
+			tracks: func(t *testing.T) []Track {
+				return []Track{
+					track(t, daily, days(models.CompletionDone, 1, 2, 3, 4, 5, 6, 7, 8, 9)),
+					track(t, mwf, days(models.CompletionDone, 2, 4, 9)),
+				}
+			},
+			want:          Summary{Current: 2, Longest: 6, WindowDays: DefaultWindowDays, Scheduled: 13, Completed: 12, Rate: 12.0 / 13},
+			lastCompleted: 9,
+		},
+		{
+			name: "the rate covers the window only",
+			tracks: func(t *testing.T) []Track {
+				return []Track{track(t, daily, days(models.CompletionDone, 1, 2, 3, 4, 5, 6, 7, 9))}
+			},
+			windowDays:    3,
+			want:          Summary{Current: 1, Longest: 7, WindowDays: 3, Scheduled: 2, Completed: 1, Rate: 0.5},
+			lastCompleted: 9,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Compute(tt.tracks(t), now, time.UTC, tt.windowDays)
+			if tt.lastCompleted == 0 {
+				if got.LastCompleted != nil {
+					t.Errorf("got last completed %v, want none", got.LastCompleted)
+				}
+			} else if got.LastCompleted == nil || !got.LastCompleted.Equal(date(tt.lastCompleted)) {
+				t.Errorf("got last completed %v, want %v", got.LastCompleted, date(tt.lastCompleted))
+			}
+			got.LastCompleted = nil
+			if got != tt.want {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestComputeIgnoresFutureSchedules(t *testing.T) {
+	s, err := recurrence.NewSchedule(daily, date(20), time.UTC)
+	if err != nil {
+		t.Fatalf("NewSchedule: %v", err)
+	}
+	got := Compute([]Track{{Schedule: s}}, now, time.UTC, 0)
+	want := Summary{WindowDays: DefaultWindowDays}
+	if got != want {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,75 @@
+// Package trash empties the trash: complexes, goals and actions deleted
+// longer ago than the retention period are purged for good.
+package trash
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"refuel/backend/repository"
+)
+
+// Defaults, overridable with TRASH_RETENTION and TRASH_PURGE_INTERVAL.
+const (
+	DefaultRetention     = 30 * 24 * time.Hour
+	DefaultPurgeInterval = time.Hour
+)
+
+// Config holds the trash settings read from the environment.
+type Config struct {
+	// Retention is how long deleted records can be restored.
+	Retention time.Duration
+	// PurgeInterval is how often records past Retention are purged.
+	PurgeInterval time.Duration
+}
+
+// ConfigFromEnv reads TRASH_RETENTION and TRASH_PURGE_INTERVAL, both Go
+// durations such as "720h".
+func ConfigFromEnv() (Config, error) {
+	cfg := Config{Retention: DefaultRetention, PurgeInterval: DefaultPurgeInterval}
+	if v := os.Getenv("TRASH_RETENTION"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			return cfg, fmt.Errorf("invalid TRASH_RETENTION %q", v)
+		}
+		cfg.Retention = d
+	}
+	if v := os.Getenv("TRASH_PURGE_INTERVAL"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			return cfg, fmt.Errorf("invalid TRASH_PURGE_INTERVAL %q", v)
+		}
+		cfg.PurgeInterval = d
+	}
+	return cfg, nil
+}
+
+// PurgeAt returns when a record deleted at deletedAt is purged, at the
+// earliest.
+func (c Config) PurgeAt(deletedAt time.Time) time.Time {
+	return deletedAt.Add(c.Retention)
+}
+
+// RunPurger purges the trash right away and then every PurgeInterval until
+// ctx is done. Failures are logged and retried at the next interval.
+func RunPurger(ctx context.Context, repo repository.TrashRepository, cfg Config) {
+	ticker := time.NewTicker(cfg.PurgeInterval)
+	defer ticker.Stop()
+	for {
+		purged, err := repo.Purge(ctx, time.Now().Add(-cfg.Retention))
+		switch {
+		case err != nil:
+			log.Printf("⚠️ Failed to purge the trash: %v", err)
+		case purged > 0:
+			log.Printf("🗑️ Purged %d item(s) deleted more than %s ago from the trash", purged, cfg.Retention)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package trash
+
+import (
+	"testing"
+	"time"
+)
+
+func TestConfigFromEnv(t *testing.T) {
+	tests := []struct {
+		name      string
+		retention string
+		interval  string
+		want      Config
+		wantErr   bool
+	}{
+		{"defaults", "", "", Config{Retention: DefaultRetention, PurgeInterval: DefaultPurgeInterval}, false},
+		{"overridden", "48h", "10m", Config{Retention: 48 * time.Hour, PurgeInterval: 10 * time.Minute}, false},
+		{"invalid retention", "30d", "", Config{}, true},
+		{"zero retention", "0s", "", Config{}, true},
+		{"negative interval", "", "-1h", Config{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("TRASH_RETENTION", tt.retention)
+			t.Setenv("TRASH_PURGE_INTERVAL", tt.interval)
+			got, err := ConfigFromEnv()
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("got %+v, want an error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("got error %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPurgeAt(t *testing.T) {
+	cfg := Config{Retention: 30 * 24 * time.Hour}
+	deletedAt := time.Date(2025, 4, 1, 9, 30, 0, 0, time.UTC)
+	want := time.Date(2025, 5, 1, 9, 30, 0, 0, time.UTC)
+	if got := cfg.PurgeAt(deletedAt); !got.Equal(want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}